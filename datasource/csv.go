@@ -17,9 +17,10 @@ import (
 )
 
 var (
-	_ schema.Source      = (*CsvDataSource)(nil)
-	_ schema.Conn        = (*CsvDataSource)(nil)
-	_ schema.ConnScanner = (*CsvDataSource)(nil)
+	_ schema.Source         = (*CsvDataSource)(nil)
+	_ schema.Conn           = (*CsvDataSource)(nil)
+	_ schema.ConnScanner    = (*CsvDataSource)(nil)
+	_ schema.ConnProjection = (*CsvDataSource)(nil)
 )
 
 // Csv DataSource, implements qlbridge schema DataSource, SourceConn, Scanner
@@ -39,6 +40,7 @@ type CsvDataSource struct {
 	rowct     uint64
 	headers   []string
 	colindex  map[string]int
+	colpos    []int // positions of the projected columns, nil for all
 	indexCol  int
 	filter    expr.Node
 }
@@ -108,6 +110,21 @@ func (m *CsvDataSource) Table(tableName string) (*schema.Table, error) {
 	return m.tblschema, nil
 }
 
+// ProjectColumns only read the given columns of each row
+func (m *CsvDataSource) ProjectColumns(cols []string) {
+	m.colpos = make([]int, 0, len(cols))
+	m.colindex = make(map[string]int, len(cols))
+	for i, key := range m.headers {
+		for _, col := range cols {
+			if col == key {
+				m.colindex[key] = len(m.colpos)
+				m.colpos = append(m.colpos, i)
+				break
+			}
+		}
+	}
+}
+
 func (m *CsvDataSource) Open(connInfo string) (schema.Conn, error) {
 	if connInfo == "stdio" || connInfo == "stdin" {
 		connInfo = "/dev/stdin"
@@ -160,6 +177,13 @@ func (m *CsvDataSource) Next() schema.Message {
 				u.Warnf("headers/cols dont match, dropping expected:%d got:%d   vals=", len(m.headers), len(row), row)
 				continue
 			}
+			if m.colpos != nil {
+				vals := make([]driver.Value, len(m.colpos))
+				for i, pos := range m.colpos {
+					vals[i] = row[pos]
+				}
+				return NewSqlDriverMessageMap(m.rowct, vals, m.colindex)
+			}
 			vals := make([]driver.Value, len(row))
 			for i, val := range row {
 				vals[i] = val
@@ -55,3 +55,20 @@ func TestCsvDataSource(t *testing.T) {
 	}
 	assert.Tf(t, iterCt == 3, "should have 3 rows: %v", iterCt)
 }
+
+func TestCsvProjectColumns(t *testing.T) {
+	csvIn, err := csvStringSource.Open("user.csv")
+	assert.Tf(t, err == nil, "should not have error: %v", err)
+	csvIn.(schema.ConnProjection).ProjectColumns([]string{"item_count", "email"})
+	csvIter := csvIn.(schema.ConnScanner)
+	msg := csvIter.Next()
+	assert.T(t, msg != nil)
+	row := msg.Body().(*datasource.SqlDriverMessageMap)
+	// in the order of the csv headers
+	assert.Equal(t, []string{"aaron@email.com", "82"}, []string{row.Vals[0].(string), row.Vals[1].(string)})
+	assert.Equal(t, 2, len(row.Vals))
+	email, ok := row.Get("email")
+	assert.Tf(t, ok && email.ToString() == "aaron@email.com", "should get projected column %v", email)
+	_, ok = row.Get("interests")
+	assert.Tf(t, !ok, "should not have un-projected column")
+}
@@ -6,6 +6,7 @@ import (
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
@@ -75,3 +76,31 @@ func KeyFromWhere(wh interface{}) schema.Key {
 	}
 	return nil
 }
+
+// Given a Where expression, find the column it seeks on, which
+//  requires form    `identity = expr`   ie the expression may be a
+//  value, or another identity (join lookup).  Returns the un-qualified
+//  column name, or empty string if not seekable.
+//
+func KeyColumnFromWhere(wh interface{}) string {
+	switch n := wh.(type) {
+	case *rel.SqlWhere:
+		return KeyColumnFromWhere(n.Expr)
+	case *expr.BinaryNode:
+		if len(n.Args) != 2 {
+			return ""
+		}
+		switch n.Operator.T {
+		case lex.TokenEqual, lex.TokenEqualEqual:
+		default:
+			return ""
+		}
+		in, ok := n.Args[0].(*expr.IdentityNode)
+		if !ok {
+			return ""
+		}
+		_, col, _ := in.LeftRight()
+		return col
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package membtree
 import (
 	"database/sql/driver"
 	"fmt"
+	"strings"
 
 	u "github.com/araddon/gou"
 	"github.com/dchest/siphash"
@@ -33,6 +34,7 @@ var (
 	_ schema.ConnSeeker        = (*StaticDataSource)(nil)
 	_ schema.ConnUpsert        = (*StaticDataSource)(nil)
 	_ schema.ConnDeletion      = (*StaticDataSource)(nil)
+	_ schema.ConnRowCount      = (*StaticDataSource)(nil)
 )
 
 type Key struct {
@@ -161,6 +163,7 @@ func (m *StaticDataSource) CreateIterator() schema.Iterator           { return m
 func (m *StaticDataSource) Tables() []string                          { return []string{m.Schema.Name} }
 func (m *StaticDataSource) Columns() []string                         { return m.tbl.Columns() }
 func (m *StaticDataSource) Length() int                               { return m.bt.Len() }
+func (m *StaticDataSource) RowCount() int64                           { return int64(m.bt.Len()) }
 func (m *StaticDataSource) SetColumns(cols []string)                  { m.tbl.SetColumns(cols) }
 
 func (m *StaticDataSource) MesgChan() <-chan schema.Message {
@@ -293,12 +296,34 @@ func (m *StaticDataSource) Put(ctx context.Context, key schema.Key, row interfac
 }
 
 func (m *StaticDataSource) PutMulti(ctx context.Context, keys []schema.Key, src interface{}) ([]schema.Key, error) {
-	return nil, fmt.Errorf("not implemented")
+	rows, ok := src.([][]driver.Value)
+	if !ok {
+		return nil, fmt.Errorf("unrecognized put object type: %T", src)
+	}
+	putKeys := make([]schema.Key, 0, len(rows))
+	for i, row := range rows {
+		var key schema.Key
+		if i < len(keys) {
+			key = keys[i]
+		}
+		putKey, err := m.Put(ctx, key, row)
+		if err != nil {
+			return nil, err
+		}
+		putKeys = append(putKeys, putKey)
+	}
+	return putKeys, nil
 }
 
 // interface for Seeker
+// CanSeek requires a where of form `indexcol = expr`
 func (m *StaticDataSource) CanSeek(sql *rel.SqlSelect) bool {
-	return true
+	if sql == nil || sql.Where == nil {
+		return false
+	}
+	col := datasource.KeyColumnFromWhere(sql.Where)
+	cols := m.Columns()
+	return col != "" && m.indexCol < len(cols) && strings.EqualFold(col, cols[m.indexCol])
 }
 
 func (m *StaticDataSource) Get(key driver.Value) (schema.Message, error) {
@@ -309,14 +334,15 @@ func (m *StaticDataSource) Get(key driver.Value) (schema.Message, error) {
 	return nil, schema.ErrNotFound // Should not found be an error?
 }
 
+// MultiGet the rows for keys, keys that are not found are skipped
 func (m *StaticDataSource) MultiGet(keys []driver.Value) ([]schema.Message, error) {
-	rows := make([]schema.Message, len(keys))
-	for i, key := range keys {
+	rows := make([]schema.Message, 0, len(keys))
+	for _, key := range keys {
 		item := m.bt.Get(NewKey(makeId(key)))
 		if item == nil {
-			return nil, schema.ErrNotFound
+			continue
 		}
-		rows[i] = item.(*DriverItem).SqlDriverMessageMap
+		rows = append(rows, item.(*DriverItem).SqlDriverMessageMap)
 	}
 	return rows, nil
 }
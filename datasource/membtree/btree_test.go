@@ -73,6 +73,11 @@ func TestStaticValues(t *testing.T) {
 	vals = rows[1].Body().(*datasource.SqlDriverMessageMap).Values()
 	assert.Tf(t, len(vals) == 1 && vals[0].(int) == 12347, "must implement seeker")
 
+	// keys not found are skipped
+	rows, err = static.MultiGet([]driver.Value{12345, 99999, 12347})
+	assert.Tf(t, err == nil, "%v", err)
+	assert.Tf(t, len(rows) == 2, "Should skip missing keys in MultiGet() but got %v", len(rows))
+
 	delCt, err := static.Delete(12345)
 	assert.T(t, err == nil)
 	assert.T(t, delCt == 1)
@@ -3,6 +3,9 @@ package memdb
 import (
 	"database/sql/driver"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 
 	u "github.com/araddon/gou"
 	"github.com/hashicorp/go-memdb"
@@ -10,6 +13,8 @@ import (
 
 	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/value"
@@ -28,12 +33,17 @@ var (
 	_ schema.SourceTableSchema = (*MemDb)(nil)
 
 	// Connection
-	_ schema.Conn         = (*dbConn)(nil)
-	_ schema.ConnColumns  = (*dbConn)(nil)
-	_ schema.ConnScanner  = (*dbConn)(nil)
-	_ schema.ConnUpsert   = (*dbConn)(nil)
-	_ schema.ConnDeletion = (*dbConn)(nil)
-	_ schema.ConnSeeker   = (*dbConn)(nil)
+	_ schema.Conn            = (*dbConn)(nil)
+	_ schema.ConnColumns     = (*dbConn)(nil)
+	_ schema.ConnScanner     = (*dbConn)(nil)
+	_ schema.ConnUpsert      = (*dbConn)(nil)
+	_ schema.ConnDeletion    = (*dbConn)(nil)
+	_ schema.ConnPatchWhere  = (*dbConn)(nil)
+	_ schema.ConnSeeker      = (*dbConn)(nil)
+	_ schema.ConnFilter      = (*dbConn)(nil)
+	_ schema.ConnProjection  = (*dbConn)(nil)
+	_ schema.ConnTransaction = (*dbConn)(nil)
+	_ schema.ConnTx          = (*memTx)(nil)
 )
 
 // MemDb implements qlbridge `Source` to allow in-memory native go data
@@ -50,12 +60,22 @@ type MemDb struct {
 	primaryIndex   string
 	db             *memdb.MemDB
 	max            int
+	keyMu          sync.Mutex
+	keyType        value.ValueType // type of the stored primary keys, UnknownType if mixed
+	hasKeys        bool            // keyType is set
 }
 type dbConn struct {
 	md     *MemDb
 	db     *memdb.MemDB
 	txn    *memdb.Txn
+	tx     *memdb.Txn // write txn of the transaction this conn is in, nil if none
 	result memdb.ResultIterator
+	ctx    *plan.Context
+	keys   []string // primary keys of rows matching pushed filters
+	keyPos int
+	byKey  bool           // scan is of keys, not the whole table
+	colPos []int          // positions of the projected columns, nil for all
+	colIdx map[string]int // col index of the projected columns
 }
 
 // NewMemDbData creates a MemDb with given indexes, columns, and values
@@ -168,10 +188,113 @@ func newDbConn(mdb *MemDb) *dbConn {
 	c := &dbConn{md: mdb, db: mdb.db}
 	return c
 }
+
+// putKey records the value type of a stored primary key
+func (m *MemDb) putKey(key driver.Value) {
+	vt := value.NewValue(key).Type()
+	m.keyMu.Lock()
+	defer m.keyMu.Unlock()
+	switch {
+	case !m.hasKeys:
+		m.keyType, m.hasKeys = vt, true
+	case m.keyType != vt:
+		m.keyType = value.UnknownType
+	}
+}
+
+// storedKeyType the value type all of the stored primary keys have, false
+// if there are none or they are of mixed types
+func (m *MemDb) storedKeyType() (value.ValueType, bool) {
+	m.keyMu.Lock()
+	defer m.keyMu.Unlock()
+	return m.keyType, m.hasKeys && m.keyType != value.UnknownType
+}
+
 func (m *dbConn) Columns() []string { return m.md.tbl.Columns() }
 func (m *dbConn) Close() error      { return nil }
+
+// Begin a transaction, its writes are not seen by other conns until
+// Commit, and writes of other conns wait until it ends
+func (m *dbConn) Begin() (schema.ConnTx, error) {
+	return &memTx{md: m.md, txn: m.db.Txn(true)}, nil
+}
+
+// memTx a transaction of a MemDb, the conns of it share its write txn
+type memTx struct {
+	md  *MemDb
+	txn *memdb.Txn
+}
+
+// Conn a new conn that reads and writes in the transaction
+func (m *memTx) Conn() (schema.Conn, error) {
+	if m.txn == nil {
+		return nil, fmt.Errorf("transaction has ended")
+	}
+	c := newDbConn(m.md)
+	c.tx = m.txn
+	return c, nil
+}
+
+// Commit the writes of the transaction
+func (m *memTx) Commit() error {
+	if m.txn == nil {
+		return fmt.Errorf("transaction has ended")
+	}
+	m.txn.Commit()
+	m.txn = nil
+	return nil
+}
+
+// Rollback discard the writes of the transaction
+func (m *memTx) Rollback() error {
+	if m.txn == nil {
+		return fmt.Errorf("transaction has ended")
+	}
+	m.txn.Abort()
+	m.txn = nil
+	return nil
+}
+
+// readTxn the txn to read with, the transaction if there is one so its
+// writes are seen
+func (m *dbConn) readTxn() *memdb.Txn {
+	if m.tx != nil {
+		return m.tx
+	}
+	return m.db.Txn(false)
+}
+
+// writeTxn the txn to write with, the transaction if there is one, else
+// a txn of its own which is ended by done
+func (m *dbConn) writeTxn() *memdb.Txn {
+	if m.tx != nil {
+		return m.tx
+	}
+	return m.db.Txn(true)
+}
+
+// done commit txn, or abort it on err, unless it is the transaction which
+// is ended by Commit/Rollback
+func (m *dbConn) done(txn *memdb.Txn, err error) {
+	if txn == m.tx {
+		return
+	}
+	if err != nil {
+		txn.Abort()
+		return
+	}
+	txn.Commit()
+}
+
+// SetContext of the query this conn is scanning for, scanning stops when
+// the query is cancelled or times out
+func (m *dbConn) SetContext(ctx *plan.Context) { m.ctx = ctx }
 func (m *dbConn) CreateIterator() schema.Iterator {
-	m.txn = m.db.Txn(false)
+	m.txn = m.readTxn()
+	m.result, m.keyPos = nil, 0
+	if m.byKey {
+		return m
+	}
 	// Attempt a row scan on the primary index
 	result, err := m.txn.Get(m.md.tbl.Name, m.md.primaryIndex)
 	if err != nil {
@@ -187,15 +310,26 @@ func (m *dbConn) MesgChan() <-chan schema.Message {
 func (m *dbConn) Next() schema.Message {
 	//u.Infof("Next()")
 	if m.txn == nil {
-		m.txn = m.db.Txn(false)
+		m.txn = m.readTxn()
 	}
 	select {
 	case <-m.md.exit:
 		return nil
+	case <-m.ctx.Done():
+		return nil
 	default:
 		for {
 			if m.result == nil {
-				result, err := m.txn.Get(m.md.tbl.Name, m.md.primaryIndex)
+				var args []interface{}
+				if m.byKey {
+					// rows of the next key of the pushed filters
+					if m.keyPos >= len(m.keys) {
+						return nil
+					}
+					args = append(args, m.keys[m.keyPos])
+					m.keyPos++
+				}
+				result, err := m.txn.Get(m.md.tbl.Name, m.md.primaryIndex, args...)
 				if err != nil {
 					u.Errorf("error %v", err)
 					return nil
@@ -204,9 +338,22 @@ func (m *dbConn) Next() schema.Message {
 			}
 			raw := m.result.Next()
 			if raw == nil {
+				if m.byKey {
+					m.result = nil
+					continue
+				}
 				return nil
 			}
 			if msg, ok := raw.(*datasource.SqlDriverMessage); ok {
+				if m.colPos != nil {
+					vals := make([]driver.Value, len(m.colPos))
+					for i, pos := range m.colPos {
+						if pos < len(msg.Vals) {
+							vals[i] = msg.Vals[pos]
+						}
+					}
+					return datasource.NewSqlDriverMessageMap(msg.IdVal, vals, m.colIdx)
+				}
 				return msg.ToMsgMap(m.md.tbl.FieldPositions)
 			}
 			u.Warnf("error, not correct type: %#v", raw)
@@ -221,13 +368,12 @@ func (m *dbConn) Put(ctx context.Context, key schema.Key, row interface{}) (sche
 	//u.Infof("%p Put(),  row:%#v", m, row)
 	switch rowVals := row.(type) {
 	case []driver.Value:
-		txn := m.db.Txn(true)
+		txn := m.writeTxn()
 		key, err := m.putValues(txn, rowVals)
+		m.done(txn, err)
 		if err != nil {
-			txn.Abort()
 			return nil, err
 		}
-		txn.Commit()
 		return key, nil
 		/*
 			case map[string]driver.Value:
@@ -292,6 +438,7 @@ func (m *dbConn) putValues(txn *memdb.Txn, row []driver.Value) (schema.Key, erro
 		return nil, fmt.Errorf("Wrong number of columns, expected %v got %v", len(m.Columns()), len(row))
 	}
 	id := makeId(row[0])
+	m.md.putKey(row[0])
 	msg := &datasource.SqlDriverMessage{Vals: row, IdVal: id}
 	txn.Insert(m.md.tbl.Name, msg)
 	//u.Debugf("%p  PUT: id:%v IdVal:%v  Id():%v vals:%#v", m, id, sdm.IdVal, sdm.Id(), rowVals)
@@ -299,39 +446,162 @@ func (m *dbConn) putValues(txn *memdb.Txn, row []driver.Value) (schema.Key, erro
 }
 
 func (m *dbConn) PutMulti(ctx context.Context, keys []schema.Key, objs interface{}) ([]schema.Key, error) {
-	txn := m.db.Txn(true)
-
 	switch rows := objs.(type) {
 	case [][]driver.Value:
+		txn := m.writeTxn()
 		keys := make([]schema.Key, 0, len(rows))
 		for _, row := range rows {
 			key, err := m.putValues(txn, row)
 			if err != nil {
-				txn.Abort()
+				m.done(txn, err)
 				return nil, err
 			}
 			keys = append(keys, key)
 		}
-		txn.Commit()
+		m.done(txn, nil)
 		return keys, nil
 	}
 	return nil, fmt.Errorf("unrecognized put object type: %T", objs)
 }
 
 // CanSeek is interface for Seeker, validate if we can perform this query
+//  which requires a where of form `primarykey = expr`
 func (m *dbConn) CanSeek(sql *rel.SqlSelect) bool {
-	return true
+	if sql == nil || sql.Where == nil {
+		return false
+	}
+	col := datasource.KeyColumnFromWhere(sql.Where)
+	return col != "" && strings.EqualFold(col, m.md.tbl.Columns()[0])
+}
+
+// ProjectColumns only return the given columns of each row
+func (m *dbConn) ProjectColumns(cols []string) {
+	m.colPos = make([]int, 0, len(cols))
+	m.colIdx = make(map[string]int, len(cols))
+	for pos, key := range m.md.tbl.Columns() {
+		for _, col := range cols {
+			if col == key {
+				m.colIdx[key] = len(m.colPos)
+				m.colPos = append(m.colPos, pos)
+				break
+			}
+		}
+	}
+}
+
+// PushFilters handles `primarykey = value` and `primarykey IN (values)`
+//  conjuncts, the rows are looked up by key instead of a scan
+func (m *dbConn) PushFilters(conjuncts []expr.Node) []bool {
+	handled := make([]bool, len(conjuncts))
+	for i, n := range conjuncts {
+		keys, ok := m.filterKeys(n)
+		if !ok {
+			continue
+		}
+		handled[i] = true
+		if !m.byKey {
+			m.keys, m.byKey = keys, true
+			continue
+		}
+		// rows must match all of the conjuncts
+		both := make([]string, 0, len(m.keys))
+		for _, key := range m.keys {
+			for _, k := range keys {
+				if k == key {
+					both = append(both, key)
+					break
+				}
+			}
+		}
+		m.keys = both
+	}
+	return handled
+}
+
+// filterKeys the primary keys of the rows a `primarykey = value` or
+// `primarykey IN (values)` filter matches.  The vm converts values of
+// other types before comparing them, `"02" = 2` is true, so only literals
+// of the same type as the stored keys are looked up by their text
+func (m *dbConn) filterKeys(n expr.Node) ([]string, bool) {
+	bn, ok := n.(*expr.BinaryNode)
+	if !ok || len(bn.Args) != 2 {
+		return nil, false
+	}
+	keyType, ok := m.md.storedKeyType()
+	if !ok {
+		return nil, false
+	}
+	isKey := func(arg expr.Node) bool {
+		in, ok := arg.(*expr.IdentityNode)
+		if !ok {
+			return false
+		}
+		_, col, _ := in.LeftRight()
+		return strings.EqualFold(col, m.md.tbl.Columns()[0])
+	}
+	var vals []expr.Node
+	switch bn.Operator.T {
+	case lex.TokenEqual, lex.TokenEqualEqual:
+		switch {
+		case isKey(bn.Args[0]):
+			vals = bn.Args[1:]
+		case isKey(bn.Args[1]):
+			vals = bn.Args[:1]
+		default:
+			return nil, false
+		}
+	case lex.TokenIN:
+		an, ok := bn.Args[1].(*expr.ArrayNode)
+		if !ok || !isKey(bn.Args[0]) {
+			return nil, false
+		}
+		vals = an.Args
+	default:
+		return nil, false
+	}
+	keys := make([]string, 0, len(vals))
+	seen := make(map[string]bool, len(vals))
+	for _, vn := range vals {
+		var key string
+		switch vt := vn.(type) {
+		case *expr.NullNode:
+			// matches no row
+			continue
+		case *expr.IdentityNode:
+			// NULL in an IN list parses as an identity
+			if strings.EqualFold(vt.Text, "null") {
+				continue
+			}
+			return nil, false
+		case *expr.StringNode:
+			if keyType != value.StringType {
+				return nil, false
+			}
+			key = vt.Text
+		case *expr.NumberNode:
+			if !vt.IsInt || keyType != value.IntType {
+				return nil, false
+			}
+			key = strconv.FormatInt(vt.Int64, 10)
+		default:
+			return nil, false
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys, true
 }
 
 func (m *dbConn) Get(key driver.Value) (schema.Message, error) {
-	txn := m.db.Txn(false)
+	txn := m.readTxn()
 	iter, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex, fmt.Sprintf("%v", key))
+	m.done(txn, err) // noop for reads
 	if err != nil {
-		txn.Abort()
 		u.Errorf("error reading %v because %v", key, err)
 		return nil, err
 	}
-	txn.Commit() // noop
 
 	if item := iter.Next(); item != nil {
 		if msg, ok := item.(schema.Message); ok {
@@ -342,48 +612,113 @@ func (m *dbConn) Get(key driver.Value) (schema.Message, error) {
 	return nil, schema.ErrNotFound // Should not found be an error?
 }
 
-// MultiGet to get multiple items by keys
+// MultiGet to get multiple items by keys, keys that are not found are skipped
 func (m *dbConn) MultiGet(keys []driver.Value) ([]schema.Message, error) {
-	return nil, schema.ErrNotImplemented
+	txn := m.readTxn()
+	defer m.done(txn, nil)
+
+	rows := make([]schema.Message, 0, len(keys))
+	for _, key := range keys {
+		iter, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex, fmt.Sprintf("%v", key))
+		if err != nil {
+			u.Errorf("error reading %v because %v", key, err)
+			return nil, err
+		}
+		if item := iter.Next(); item != nil {
+			if msg, ok := item.(schema.Message); ok {
+				rows = append(rows, msg)
+				continue
+			}
+			u.Warnf("unexpected type %T", item)
+		}
+	}
+	return rows, nil
 }
 
 // Interface for Deletion
 func (m *dbConn) Delete(key driver.Value) (int, error) {
-	txn := m.db.Txn(true)
+	txn := m.writeTxn()
 	err := txn.Delete(m.md.tbl.Name, key)
+	m.done(txn, err)
 	if err != nil {
-		txn.Abort()
 		u.Warnf("could not delete: %v  err=%v", key, err)
 		return 0, err
 	}
-	txn.Commit()
 	return 1, nil
 }
 
+// PatchWhere update the rows matching where (all rows if nil) with patch,
+//  values or expressions of columns (see vm.PatchRow)
+func (m *dbConn) PatchWhere(ctx context.Context, where expr.Node, patch interface{}) (int64, error) {
+	txn := m.writeTxn()
+	iter, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex)
+	if err != nil {
+		m.done(txn, err)
+		u.Errorf("could not get values %v", err)
+		return 0, err
+	}
+	colIndex := m.md.tbl.FieldPositions
+	// find the rows first, writing while iterating the txn is not safe
+	var patches []*datasource.SqlDriverMessage
+	for item := iter.Next(); item != nil; item = iter.Next() {
+		msg, ok := item.(*datasource.SqlDriverMessage)
+		if !ok {
+			err = fmt.Errorf("unexpected message type %T", item)
+			m.done(txn, err)
+			return 0, err
+		}
+		if vm.Matches(msg.ToMsgMap(colIndex), where) {
+			patches = append(patches, msg)
+		}
+	}
+	for _, msg := range patches {
+		var row []driver.Value
+		if row, err = vm.PatchRow(msg.Vals, colIndex, msg.ToMsgMap(colIndex), patch); err != nil {
+			break
+		}
+		if makeId(row[0]) != msg.IdVal {
+			// the key was updated, remove the row at the old key
+			if err = txn.Delete(m.md.tbl.Name, msg); err != nil {
+				break
+			}
+		}
+		if _, err = m.putValues(txn, row); err != nil {
+			break
+		}
+	}
+	m.done(txn, err)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(patches)), nil
+}
+
 // Delete using a Where Expression
 func (m *dbConn) DeleteExpression(where expr.Node) (int, error) {
 	//return 0, fmt.Errorf("not implemented")
 	evaluator := vm.Evaluator(where)
 	var deletedKeys []schema.Key
-	txn := m.db.Txn(true)
+	txn := m.writeTxn()
 	iter, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex)
 	if err != nil {
-		txn.Abort()
+		m.done(txn, err)
 		u.Errorf("could not get values %v", err)
 		return 0, err
 	}
-deleteLoop:
+	// find the rows first, deleting while iterating the txn is not safe
+	var deletes []*datasource.SqlDriverMessage
 	for {
 		item := iter.Next()
 		if item == nil {
 			break
 		}
 
-		msg, ok := item.(datasource.SqlDriverMessage)
+		msg, ok := item.(*datasource.SqlDriverMessage)
 		if !ok {
 			u.Warnf("wat?  %T   %#v", item, item)
 			err = fmt.Errorf("unexpected message type %T", item)
-			break
+			m.done(txn, err)
+			return 0, err
 		}
 		whereValue, ok := evaluator(msg.ToMsgMap(m.md.tbl.FieldPositions))
 		if !ok {
@@ -395,12 +730,7 @@ deleteLoop:
 				//this means do NOT delete
 			} else {
 				// Delete!
-				if err = txn.Delete(m.md.tbl.Name, msg); err != nil {
-					u.Errorf("could not delete %v", err)
-					break deleteLoop
-				}
-				indexVal := msg.Vals[0]
-				deletedKeys = append(deletedKeys, schema.NewKeyUint(makeId(indexVal)))
+				deletes = append(deletes, msg)
 			}
 		case nil:
 			// ??
@@ -413,10 +743,16 @@ deleteLoop:
 			}
 		}
 	}
+	for _, msg := range deletes {
+		if err = txn.Delete(m.md.tbl.Name, msg); err != nil {
+			u.Errorf("could not delete %v", err)
+			break
+		}
+		deletedKeys = append(deletedKeys, schema.NewKeyUint(makeId(msg.Vals[0])))
+	}
+	m.done(txn, err)
 	if err != nil {
-		txn.Abort()
 		return 0, err
 	}
-	txn.Commit()
 	return len(deletedKeys), nil
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/bmizerany/assert"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
 
@@ -53,4 +55,222 @@ func TestMemDb(t *testing.T) {
 	assert.Tf(t, vals2[2].(string) == "aaron@email.com", "want email=email@email.com but got %v", vals2[2])
 	assert.Equal(t, []string{"root", "admin"}, vals2[4], "Roles should match updated vals")
 	assert.Equal(t, created, vals2[3], "created date should match updated vals")
+
+	dc.Put(nil, &datasource.KeyInt{Id: 124}, []driver.Value{124, "bob", "bob@email.com", created.In(time.UTC), []string{"admin"}})
+	rows, err := dc.MultiGet([]driver.Value{124, 999, 123})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	assert.Tf(t, len(rows) == 2, "Should find 2 rows, skipping missing key, with MultiGet() but got %v", len(rows))
+	assert.Tf(t, rows[0].Body().([]driver.Value)[1] == "bob", "want bob but got %v", rows[0].Body())
+	assert.Tf(t, rows[1].Body().([]driver.Value)[1] == "aaron", "want aaron but got %v", rows[1].Body())
+
+	sel, err := rel.ParseSqlSelect("SELECT name FROM users WHERE user_id = 123")
+	assert.T(t, err == nil)
+	assert.Tf(t, dc.CanSeek(sel), "Should be able to seek on primary key")
+	sel, err = rel.ParseSqlSelect("SELECT name FROM users WHERE email = \"aaron@email.com\"")
+	assert.T(t, err == nil)
+	assert.Tf(t, !dc.CanSeek(sel), "Should not be able to seek on non key column")
+}
+
+func TestMemDbPushFilters(t *testing.T) {
+
+	db, err := NewMemDb("push_users", []string{"user_id", "name"})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c, err := db.Open("push_users")
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	dc := c.(schema.ConnAll)
+	for i, name := range []string{"aaron", "bob", "carol", "dan"} {
+		dc.Put(nil, nil, []driver.Value{i + 1, name})
+	}
+
+	scan := func(filters ...string) ([]bool, []string) {
+		conn, err := db.Open("push_users")
+		assert.Tf(t, err == nil, "wanted no error got %v", err)
+		conds := make([]expr.Node, len(filters))
+		for i, f := range filters {
+			tree, err := expr.ParseExpression(f)
+			assert.Tf(t, err == nil, "wanted no error got %v", err)
+			conds[i] = tree.Root
+		}
+		handled := conn.(schema.ConnFilter).PushFilters(conds)
+		var names []string
+		iter := conn.(schema.ConnScannerIterator).CreateIterator()
+		for msg := iter.Next(); msg != nil; msg = iter.Next() {
+			names = append(names, msg.(*datasource.SqlDriverMessageMap).Vals[1].(string))
+		}
+		return handled, names
+	}
+
+	handled, names := scan("user_id = 2", "name = \"bob\"")
+	assert.Equal(t, []bool{true, false}, handled)
+	assert.Equal(t, []string{"bob"}, names)
+
+	handled, names = scan("3 = user_id")
+	assert.Equal(t, []bool{true}, handled)
+	assert.Equal(t, []string{"carol"}, names)
+
+	handled, names = scan("user_id IN (4, 1, NULL, 4)")
+	assert.Equal(t, []bool{true}, handled)
+	assert.Equal(t, []string{"dan", "aaron"}, names)
+
+	// conjuncts on the key intersect
+	handled, names = scan("user_id IN (1, 2)", "user_id = 2")
+	assert.Equal(t, []bool{true, true}, handled)
+	assert.Equal(t, []string{"bob"}, names)
+
+	handled, names = scan("user_id IN (1, 2)", "user_id = 3")
+	assert.Equal(t, []bool{true, true}, handled)
+	assert.Equal(t, 0, len(names))
+
+	handled, names = scan("user_id NOT IN (1, 2)", "user_id > 2")
+	assert.Equal(t, []bool{false, false}, handled)
+	assert.Equal(t, 4, len(names))
+
+	// the vm converts literals of other types than the keys, "02" = 2
+	handled, names = scan("user_id = \"02\"", "user_id IN (1, \"2\")")
+	assert.Equal(t, []bool{false, false}, handled)
+	assert.Equal(t, 4, len(names))
+}
+
+func TestMemDbProjectColumns(t *testing.T) {
+
+	db, err := NewMemDb("project_users", []string{"user_id", "name", "email"})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c, err := db.Open("project_users")
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c.(schema.ConnUpsert).Put(nil, nil, []driver.Value{1, "aaron", "aaron@email.com"})
+
+	c.(schema.ConnProjection).ProjectColumns([]string{"email", "user_id"})
+	iter := c.(schema.ConnScannerIterator).CreateIterator()
+	msg := iter.Next()
+	assert.T(t, msg != nil)
+	row := msg.(*datasource.SqlDriverMessageMap)
+	assert.Equal(t, []driver.Value{1, "aaron@email.com"}, row.Vals)
+	_, ok := row.Get("name")
+	assert.Tf(t, !ok, "should not have un-projected column")
+	assert.T(t, iter.Next() == nil)
+}
+
+func TestMemDbDeleteExpression(t *testing.T) {
+
+	db, err := NewMemDb("del_users", []string{"user_id", "name"})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c, err := db.Open("del_users")
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	dc := c.(schema.ConnAll)
+	for i, name := range []string{"aaron", "bob", "carol", "dan"} {
+		dc.Put(nil, nil, []driver.Value{i + 1, name})
+	}
+
+	where, err := expr.ParseExpression("user_id > 2")
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	ct, err := dc.DeleteExpression(where.Root)
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	assert.Equal(t, 2, ct)
+
+	rows, err := dc.MultiGet([]driver.Value{1, 2, 3, 4})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	var names []string
+	for _, row := range rows {
+		names = append(names, row.Body().([]driver.Value)[1].(string))
+	}
+	assert.Equal(t, []string{"aaron", "bob"}, names)
+
+	where, err = expr.ParseExpression(`name = "nobody"`)
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	ct, err = dc.DeleteExpression(where.Root)
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	assert.Equal(t, 0, ct)
+}
+
+func TestMemDbTransaction(t *testing.T) {
+
+	db, err := NewMemDb("tx_users", []string{"user_id", "name"})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c, err := db.Open("tx_users")
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	dc2 := c.(schema.ConnAll)
+	txOpen := func() (schema.ConnTx, schema.ConnAll) {
+		tx, err := c.(schema.ConnTransaction).Begin()
+		assert.Tf(t, err == nil, "wanted no error got %v", err)
+		c1, err := tx.Conn()
+		assert.Tf(t, err == nil, "wanted no error got %v", err)
+		return tx, c1.(schema.ConnAll)
+	}
+
+	// writes are seen by the conns of the transaction, not others
+	tx, dc1 := txOpen()
+	_, err = dc1.Put(nil, nil, []driver.Value{1, "aaron"})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	row, _ := dc1.Get(1)
+	assert.Tf(t, row != nil, "transaction should read its own write")
+	row, _ = dc2.Get(1)
+	assert.Tf(t, row == nil, "uncommitted write should not be seen by other conns")
+	assert.T(t, tx.Rollback() == nil)
+	row, _ = dc2.Get(1)
+	assert.Tf(t, row == nil, "rolled back write should be discarded")
+
+	tx, dc1 = txOpen()
+	_, err = dc1.PutMulti(nil, nil, [][]driver.Value{{1, "aaron"}, {2, "bob"}})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	where, err := expr.ParseExpression(`name == "aaron"`)
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c1, err := tx.Conn()
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	ct, err := c1.(schema.ConnAll).DeleteExpression(where.Root)
+	assert.Tf(t, err == nil && ct == 1, "wanted 1 deleted got %v err=%v", ct, err)
+	assert.T(t, tx.Commit() == nil)
+	rows, err := dc2.MultiGet([]driver.Value{1, 2})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	assert.Tf(t, len(rows) == 1, "want only the committed row of bob but got %v", len(rows))
+	assert.Tf(t, tx.Commit() != nil, "transaction has ended")
+}
+
+func TestMemDbPatchWhere(t *testing.T) {
+
+	db, err := NewMemDb("patch_users", []string{"user_id", "name", "ct"})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	c, err := db.Open("patch_users")
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	dc := c.(schema.ConnAll)
+	_, err = dc.PutMulti(nil, nil, [][]driver.Value{{1, "aaron", 1}, {2, "bob", 5}, {3, "carla", 10}})
+	assert.Tf(t, err == nil, "wanted no error got %v", err)
+	patcher := c.(schema.ConnPatchWhere)
+	parse := func(s string) expr.Node {
+		tree, err := expr.ParseExpression(s)
+		assert.Tf(t, err == nil, "wanted no error got %v", err)
+		return tree.Root
+	}
+	vals := func(key int) []driver.Value {
+		row, err := dc.Get(key)
+		if err != nil {
+			return nil
+		}
+		return row.(*datasource.SqlDriverMessage).Vals
+	}
+
+	// expressions are evaluated against each matching row
+	ct, err := patcher.PatchWhere(nil, parse("ct > 2"), map[string]expr.Node{
+		"ct":   parse("ct * 2"),
+		"name": expr.NewStringNode("updated"),
+	})
+	assert.Tf(t, err == nil && ct == 2, "wanted 2 patched got %v err=%v", ct, err)
+	assert.Equal(t, []driver.Value{1, "aaron", 1}, vals(1))
+	assert.Equal(t, []driver.Value{2, "updated", int64(10)}, vals(2))
+	assert.Equal(t, []driver.Value{3, "updated", int64(20)}, vals(3))
+
+	// values, with no where every row
+	ct, err = patcher.PatchWhere(nil, nil, map[string]driver.Value{"name": "all"})
+	assert.Tf(t, err == nil && ct == 3, "wanted 3 patched got %v err=%v", ct, err)
+	assert.Equal(t, "all", vals(1)[1])
+
+	// updating the key moves the row
+	ct, err = patcher.PatchWhere(nil, parse("user_id == 3"), map[string]driver.Value{"user_id": 4})
+	assert.Tf(t, err == nil && ct == 1, "wanted 1 patched got %v err=%v", ct, err)
+	assert.Tf(t, vals(3) == nil, "row should have moved from key 3")
+	assert.Equal(t, []driver.Value{4, "all", int64(20)}, vals(4))
+
+	// nothing is written if a row can't be patched
+	_, err = patcher.PatchWhere(nil, nil, map[string]driver.Value{"name": "nope", "not_a_col": 1})
+	assert.Tf(t, err != nil, "wanted error for unknown column")
+	assert.Equal(t, "all", vals(1)[1])
 }
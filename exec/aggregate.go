@@ -0,0 +1,573 @@
+package exec
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+var (
+	_ = u.EMPTY
+
+	// the aggregate registry
+	aggMu       sync.RWMutex
+	aggregators = make(map[string]AggregatorFactory)
+)
+
+func init() {
+	AggregatorAdd("count", NewCount)
+	AggregatorAdd("sum", NewSum)
+	AggregatorAdd("avg", NewAvg)
+	AggregatorAdd("min", NewMin)
+	AggregatorAdd("max", NewMax)
+	AggregatorAdd("variance", NewVariance)
+	AggregatorAdd("stddev", NewStdDev)
+	AggregatorAdd("first", NewFirst)
+	AggregatorAdd("last", NewLast)
+	AggregatorAdd("bool_and", NewBoolAnd)
+	AggregatorAdd("bool_or", NewBoolOr)
+	AggregatorAdd("approx_count_distinct", NewApproxCountDistinct)
+	AggregatorAdd("percentile", NewPercentile)
+	AggregatorAdd("median", NewMedian)
+	AggregatorAdd("group_concat", NewGroupConcat)
+	AggregatorAdd("array_agg", NewArrayAgg)
+	AggregatorAdd("json_objectagg", NewJsonObjectAgg)
+}
+
+// Aggregator is the running state of an aggregate function for one group.
+//
+//   - Do is called with the value of the aggregate's argument for each row,
+//     ie the value of price for sum(price).  NULL values are passed as
+//     value.NilValue, it is up to the aggregate to ignore them.
+//   - Result returns the final value of the aggregate.
+//   - Partial serializes the running state, so it may be spilled to disk
+//     or sent to another node, and Merge'd into an Aggregator of same func.
+//   - Reset clears the state so the Aggregator may be re-used.
+type Aggregator interface {
+	Do(v value.Value)
+	Result() interface{}
+	Reset()
+	Partial() ([]byte, error)
+	Merge(partial []byte) error
+}
+
+// AggregatorArgs is an optional interface for Aggregators whose Do takes
+// more than the first arg of the aggregate, ie json_objectagg(k, v) or any
+// ORDER BY inside the aggregate.  The values of Args are evaluated for
+// each row and passed to Do as a value.SliceValue.
+type AggregatorArgs interface {
+	Args() []expr.Node
+}
+
+// AggregatorFactory creates a new Aggregator for a column whose expression
+// is a call to the registered aggregate function, ie  sum(price).
+type AggregatorFactory func(col *rel.Column) (Aggregator, error)
+
+// AggregatorAdd registers an aggregate function by name for use in GROUP BY
+// queries.  If there is not already an aggregate expr function of this name
+// one is registered, so the parser recognizes the function as an aggregate.
+func AggregatorAdd(name string, factory AggregatorFactory) {
+	name = strings.ToLower(name)
+	aggMu.Lock()
+	aggregators[name] = factory
+	aggMu.Unlock()
+	if !expr.IsAgg(name) {
+		expr.AggFuncAdd(name, aggArgFunc)
+	}
+}
+
+// AggregatorGet finds the registered aggregate function factory by name
+func AggregatorGet(name string) (AggregatorFactory, bool) {
+	aggMu.RLock()
+	defer aggMu.RUnlock()
+	factory, ok := aggregators[strings.ToLower(name)]
+	return factory, ok
+}
+
+// NewAggregator creates the Aggregator for a column whose expression is
+// a registered aggregate function, for  func(DISTINCT x)  only distinct
+// values are passed to the aggregate.
+func NewAggregator(col *rel.Column) (Aggregator, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok {
+		return nil, fmt.Errorf("Not an aggregate function: %s", col.Expr)
+	}
+	factory, ok := AggregatorGet(fn.Name)
+	if !ok {
+		return nil, fmt.Errorf("Not impelemneted groupby for column: %s", col.Expr)
+	}
+	if fn.Distinct {
+		return newDistinctAgg(factory, col)
+	}
+	return factory(col)
+}
+
+// aggArgFunc is the expr function registered for aggregates, evaluated
+// on a single row it is just its argument
+func aggArgFunc(ctx expr.EvalContext, vals ...value.Value) (value.Value, bool) {
+	if len(vals) == 0 || vals[0] == nil {
+		return value.NewNilValue(), false
+	}
+	return vals[0], true
+}
+
+// EncodeAggState serializes an aggregates partial state (gob), a helper for
+// Aggregator.Partial implementations
+func EncodeAggState(state interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeAggState reads partial state written by EncodeAggState, a helper
+// for Aggregator.Merge implementations
+func DecodeAggState(partial []byte, state interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(partial)).Decode(state)
+}
+
+// buildAggs creates an Aggregator for each column of the group by
+func buildAggs(p *plan.GroupBy) ([]Aggregator, error) {
+	//u.Debugf("build aggs: sql:%s", p.Stmt)
+	aggs := make([]Aggregator, len(p.Stmt.Columns))
+colLoop:
+	for colIdx, col := range p.Stmt.Columns {
+		for _, gb := range p.Stmt.GroupBy {
+			if gb.As == col.As {
+				// simple Non Aggregate Value
+				aggs[colIdx] = NewGroupByValue(col)
+				continue colLoop
+			}
+		}
+		// Since we made it here, an aggregate func
+		switch col.Expr.(type) {
+		case *expr.FuncNode:
+			agg, err := NewAggregator(col)
+			if err != nil {
+				return nil, err
+			}
+			aggs[colIdx] = agg
+		case *expr.IdentityNode:
+			// column not in group by, any value of the group
+			aggs[colIdx] = NewGroupByValue(col)
+		case *expr.CaseNode:
+			if expr.HasAggFunc(col.Expr) {
+				return nil, fmt.Errorf("aggregates inside CASE are not supported, aggregate the CASE instead: %s", col.Expr)
+			}
+			return nil, fmt.Errorf("Not impelemneted groupby for column: %s", col.Expr)
+		default:
+			// binary logic?
+			return nil, fmt.Errorf("Not impelemneted groupby for column: %s", col.Expr)
+		}
+	}
+	return aggs, nil
+}
+
+// aggInput is what is evaluated for each row and passed to a column's
+// Aggregator.Do, the first arg of the aggregate or all of AggregatorArgs
+type aggInput struct {
+	node expr.Node
+	args []expr.Node
+}
+
+// aggInputs are the inputs to Aggregator.Do for each column
+func aggInputs(aggs []Aggregator, cols rel.Columns) []aggInput {
+	inputs := make([]aggInput, len(cols))
+	for i, col := range cols {
+		if _, isGroupBy := aggs[i].(*groupByFunc); isGroupBy {
+			inputs[i].node = col.Expr
+			continue
+		}
+		inputs[i] = newAggInput(aggs[i], col)
+	}
+	return inputs
+}
+
+// newAggInput the input to Aggregator.Do of an aggregate func column
+func newAggInput(agg Aggregator, col *rel.Column) aggInput {
+	if aa, ok := agg.(AggregatorArgs); ok && len(aa.Args()) > 0 {
+		return aggInput{args: aa.Args()}
+	}
+	if fn, ok := col.Expr.(*expr.FuncNode); ok && len(fn.Args) > 0 {
+		return aggInput{node: fn.Args[0]}
+	}
+	return aggInput{}
+}
+
+// eval the input for a row, and its estimated size in memory if the
+// aggregate keeps all of its values
+func (m *aggInput) eval(ctx expr.EvalContext) (value.Value, int64) {
+	if len(m.args) == 0 {
+		return evalAggArg(ctx, m.node), 0
+	}
+	vals := make([]value.Value, len(m.args))
+	row := make([]driver.Value, len(m.args))
+	for i, arg := range m.args {
+		vals[i] = evalAggArg(ctx, arg)
+		row[i] = vals[i].Value()
+	}
+	return value.NewSliceValues(vals), rowSize(row)
+}
+
+func evalAggArg(ctx expr.EvalContext, arg expr.Node) value.Value {
+	if arg == nil {
+		return value.NewNilValue()
+	}
+	v, ok := vm.Eval(ctx, arg)
+	if !ok || v == nil {
+		return value.NewNilValue()
+	}
+	return v
+}
+
+// aggFloat coerces a value to float for numeric aggregates, false if
+// null or not numeric
+func aggFloat(v value.Value) (float64, bool) {
+	if v == nil || v.Nil() || v.Err() {
+		return 0, false
+	}
+	switch vt := v.(type) {
+	case value.IntValue:
+		return vt.Float(), true
+	case value.NumberValue:
+		return vt.Val(), true
+	}
+	f, ok := value.ToFloat64(v.Rv())
+	if !ok || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// isNull is true for the sql NULL, see value.IsNull, or a value which
+// could not be evaluated
+func isNull(v value.Value) bool {
+	return value.IsNull(v) || v.Err()
+}
+
+// AggPartial is the partial state of count, sum, avg
+type AggPartial struct {
+	Ct int64
+	N  float64
+}
+
+type groupByFunc struct {
+	last interface{}
+}
+
+func (m *groupByFunc) Do(v value.Value)    { m.last = v.Value() }
+func (m *groupByFunc) Result() interface{} { return m.last }
+func (m *groupByFunc) Reset()              { m.last = nil }
+func (m *groupByFunc) Partial() ([]byte, error) {
+	return EncodeAggState(&aggValueState{Val: m.last})
+}
+func (m *groupByFunc) Merge(partial []byte) error {
+	st := aggValueState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	m.last = st.Val
+	return nil
+}
+func NewGroupByValue(col *rel.Column) Aggregator {
+	return &groupByFunc{}
+}
+
+type sum struct {
+	AggPartial
+}
+
+func (m *sum) Do(v value.Value) {
+	if f, ok := aggFloat(v); ok {
+		m.Ct++
+		m.N += f
+	}
+}
+func (m *sum) Result() interface{} {
+	if m.Ct == 0 {
+		return nil
+	}
+	return m.N
+}
+func (m *sum) Reset()                   { m.AggPartial = AggPartial{} }
+func (m *sum) Partial() ([]byte, error) { return EncodeAggState(&m.AggPartial) }
+func (m *sum) Merge(partial []byte) error {
+	st := AggPartial{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	m.Ct += st.Ct
+	m.N += st.N
+	return nil
+}
+func NewSum(col *rel.Column) (Aggregator, error) {
+	return &sum{}, nil
+}
+
+type avg struct {
+	sum
+}
+
+func (m *avg) Result() interface{} {
+	if m.Ct == 0 {
+		return nil
+	}
+	return m.N / float64(m.Ct)
+}
+func NewAvg(col *rel.Column) (Aggregator, error) {
+	return &avg{}, nil
+}
+
+type count struct {
+	n int64
+}
+
+// count of non-null values, count(*) is all rows
+func (m *count) Do(v value.Value) {
+	if !isNull(v) {
+		m.n++
+	}
+}
+func (m *count) Result() interface{} {
+	return m.n
+}
+func (m *count) Reset()                   { m.n = 0 }
+func (m *count) Partial() ([]byte, error) { return EncodeAggState(&AggPartial{Ct: m.n}) }
+func (m *count) Merge(partial []byte) error {
+	st := AggPartial{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	m.n += st.Ct
+	return nil
+}
+func NewCount(col *rel.Column) (Aggregator, error) {
+	return &count{}, nil
+}
+
+// partial state of aggregates holding a single value
+type aggValueState struct {
+	Val driver.Value
+}
+
+// minMax keeps the min (or max) non-null value, compared typed
+// see value.Compare
+type minMax struct {
+	max bool
+	val value.Value
+}
+
+func (m *minMax) Do(v value.Value) {
+	if isNull(v) {
+		return
+	}
+	if m.val == nil {
+		m.val = v
+		return
+	}
+	c, err := value.Compare(v, m.val)
+	if err != nil {
+		u.Warnf("could not compare %v to %v: %v", v, m.val, err)
+		return
+	}
+	if (m.max && c > 0) || (!m.max && c < 0) {
+		m.val = v
+	}
+}
+func (m *minMax) Result() interface{} {
+	if m.val == nil {
+		return nil
+	}
+	return m.val.Value()
+}
+func (m *minMax) Reset() { m.val = nil }
+func (m *minMax) Partial() ([]byte, error) {
+	return EncodeAggState(&aggValueState{Val: m.Result()})
+}
+func (m *minMax) Merge(partial []byte) error {
+	st := aggValueState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	if st.Val != nil {
+		m.Do(value.NewValue(st.Val))
+	}
+	return nil
+}
+func NewMin(col *rel.Column) (Aggregator, error) {
+	return &minMax{}, nil
+}
+func NewMax(col *rel.Column) (Aggregator, error) {
+	return &minMax{max: true}, nil
+}
+
+// partial state of variance, welford running mean and sum of squares
+// of differences from the mean
+type varianceState struct {
+	Ct   int64
+	Mean float64
+	M2   float64
+}
+
+// variance is the sample variance of non-null numeric values, stddev
+// the sample standard deviation, both NULL for less than 2 values
+type variance struct {
+	stddev bool
+	varianceState
+}
+
+func (m *variance) Do(v value.Value) {
+	f, ok := aggFloat(v)
+	if !ok {
+		return
+	}
+	m.Ct++
+	delta := f - m.Mean
+	m.Mean += delta / float64(m.Ct)
+	m.M2 += delta * (f - m.Mean)
+}
+func (m *variance) Result() interface{} {
+	if m.Ct < 2 {
+		return nil
+	}
+	vr := m.M2 / float64(m.Ct-1)
+	if m.stddev {
+		return math.Sqrt(vr)
+	}
+	return vr
+}
+func (m *variance) Reset()                   { m.varianceState = varianceState{} }
+func (m *variance) Partial() ([]byte, error) { return EncodeAggState(&m.varianceState) }
+func (m *variance) Merge(partial []byte) error {
+	st := varianceState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	if st.Ct == 0 {
+		return nil
+	}
+	// combine the two sets (Chan et al parallel algorithm)
+	ct := m.Ct + st.Ct
+	delta := st.Mean - m.Mean
+	m.M2 += st.M2 + delta*delta*float64(m.Ct)*float64(st.Ct)/float64(ct)
+	m.Mean += delta * float64(st.Ct) / float64(ct)
+	m.Ct = ct
+	return nil
+}
+func NewVariance(col *rel.Column) (Aggregator, error) {
+	return &variance{}, nil
+}
+func NewStdDev(col *rel.Column) (Aggregator, error) {
+	return &variance{stddev: true}, nil
+}
+
+// firstLast keeps the first (or last) non-null value in input order,
+// partials must be merged in input order
+type firstLast struct {
+	last bool
+	val  driver.Value
+}
+
+func (m *firstLast) Do(v value.Value) {
+	if isNull(v) {
+		return
+	}
+	m.set(v.Value())
+}
+func (m *firstLast) set(v driver.Value) {
+	if m.val == nil || m.last {
+		m.val = v
+	}
+}
+func (m *firstLast) Result() interface{} { return m.val }
+func (m *firstLast) Reset()              { m.val = nil }
+func (m *firstLast) Partial() ([]byte, error) {
+	return EncodeAggState(&aggValueState{Val: m.val})
+}
+func (m *firstLast) Merge(partial []byte) error {
+	st := aggValueState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	if st.Val != nil {
+		m.set(st.Val)
+	}
+	return nil
+}
+func NewFirst(col *rel.Column) (Aggregator, error) {
+	return &firstLast{}, nil
+}
+func NewLast(col *rel.Column) (Aggregator, error) {
+	return &firstLast{last: true}, nil
+}
+
+// partial state of bool_and, bool_or
+type boolState struct {
+	Set bool
+	Val bool
+}
+
+// boolAgg is logical and (or) of non-null values, NULL if none
+type boolAgg struct {
+	or bool
+	boolState
+}
+
+func (m *boolAgg) Do(v value.Value) {
+	if isNull(v) {
+		return
+	}
+	b, ok := value.ToBool(v.Rv())
+	if !ok {
+		return
+	}
+	m.set(b)
+}
+func (m *boolAgg) set(b bool) {
+	switch {
+	case !m.Set:
+		m.Val = b
+	case m.or:
+		m.Val = m.Val || b
+	default:
+		m.Val = m.Val && b
+	}
+	m.Set = true
+}
+func (m *boolAgg) Result() interface{} {
+	if !m.Set {
+		return nil
+	}
+	return m.Val
+}
+func (m *boolAgg) Reset()                   { m.boolState = boolState{} }
+func (m *boolAgg) Partial() ([]byte, error) { return EncodeAggState(&m.boolState) }
+func (m *boolAgg) Merge(partial []byte) error {
+	st := boolState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	if st.Set {
+		m.set(st.Val)
+	}
+	return nil
+}
+func NewBoolAnd(col *rel.Column) (Aggregator, error) {
+	return &boolAgg{}, nil
+}
+func NewBoolOr(col *rel.Column) (Aggregator, error) {
+	return &boolAgg{or: true}, nil
+}
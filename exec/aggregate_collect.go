@@ -0,0 +1,228 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+// collectEntry is one collected value of a group, and the values of the
+// ORDER BY inside of the aggregate if any
+type collectEntry struct {
+	Key  string // json_objectagg key
+	Val  driver.Value
+	Sort []driver.Value
+}
+
+// partial state of collecting aggregates
+type collectState struct {
+	Entries []collectEntry
+}
+
+// collect is the shared state of the aggregates that collect all values
+// of a group  group_concat, array_agg, json_objectagg.  The values of the
+// args, followed by those of the ORDER BY, are passed to Do as a SliceValue.
+type collect struct {
+	nargs   int // number of args before the ORDER BY args
+	args    []expr.Node
+	desc    []bool
+	entries []collectEntry
+}
+
+func newCollect(fn *expr.FuncNode, args ...expr.Node) collect {
+	m := collect{nargs: len(args), args: args}
+	for _, o := range fn.OrderBy {
+		m.args = append(m.args, o.Node)
+		m.desc = append(m.desc, o.Desc)
+	}
+	return m
+}
+
+func (m *collect) Args() []expr.Node { return m.args }
+
+// row splits the values passed to Do into the args, and sort values
+func (m *collect) row(v value.Value) ([]value.Value, []driver.Value) {
+	sv, ok := v.(value.SliceValue)
+	if !ok || len(sv.Val()) != len(m.args) {
+		u.Warnf("expected %d aggregate args got %v", len(m.args), v)
+		return nil, nil
+	}
+	vals := sv.Val()
+	var sortVals []driver.Value
+	if len(vals) > m.nargs {
+		sortVals = make([]driver.Value, 0, len(vals)-m.nargs)
+		for _, sortVal := range vals[m.nargs:] {
+			sortVals = append(sortVals, sortVal.Value())
+		}
+	}
+	return vals[:m.nargs], sortVals
+}
+
+// sorted returns the entries in ORDER BY order, else in the order seen
+func (m *collect) sorted() []collectEntry {
+	if len(m.desc) > 0 {
+		sort.Stable(&collectSorter{entries: m.entries, desc: m.desc})
+	}
+	return m.entries
+}
+func (m *collect) Reset() { m.entries = nil }
+func (m *collect) Partial() ([]byte, error) {
+	return EncodeAggState(&collectState{Entries: m.entries})
+}
+func (m *collect) Merge(partial []byte) error {
+	st := collectState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	m.entries = append(m.entries, st.Entries...)
+	return nil
+}
+
+// groupConcat   group_concat(x [, sep] [ORDER BY y])  concatenates the
+// non-null values as strings, seperated by sep (default ",")
+type groupConcat struct {
+	collect
+	sep string
+}
+
+func (m *groupConcat) Do(v value.Value) {
+	vals, sortVals := m.row(v)
+	if len(vals) == 0 || isNull(vals[0]) {
+		return
+	}
+	m.entries = append(m.entries, collectEntry{Val: vals[0].ToString(), Sort: sortVals})
+}
+func (m *groupConcat) Result() interface{} {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	strs := make([]string, 0, len(m.entries))
+	for _, e := range m.sorted() {
+		strs = append(strs, fmt.Sprintf("%v", e.Val))
+	}
+	return strings.Join(strs, m.sep)
+}
+func NewGroupConcat(col *rel.Column) (Aggregator, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok || len(fn.Args) < 1 || len(fn.Args) > 2 {
+		return nil, fmt.Errorf("group_concat requires 1 or 2 args  group_concat(x, \",\"): %s", col.Expr)
+	}
+	m := &groupConcat{collect: newCollect(fn, fn.Args[0]), sep: ","}
+	if len(fn.Args) == 2 {
+		sn, ok := fn.Args[1].(*expr.StringNode)
+		if !ok {
+			return nil, fmt.Errorf("group_concat seperator must be a string: %s", col.Expr)
+		}
+		m.sep = sn.Text
+	}
+	return m, nil
+}
+
+// arrayAgg   array_agg(x [ORDER BY y])  collects the values, including
+// nulls, into a value.SliceValue
+type arrayAgg struct {
+	collect
+}
+
+func (m *arrayAgg) Do(v value.Value) {
+	vals, sortVals := m.row(v)
+	if len(vals) == 0 {
+		return
+	}
+	m.entries = append(m.entries, collectEntry{Val: vals[0].Value(), Sort: sortVals})
+}
+func (m *arrayAgg) Result() interface{} {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	vals := make([]value.Value, 0, len(m.entries))
+	for _, e := range m.sorted() {
+		vals = append(vals, value.NewValue(e.Val))
+	}
+	return value.NewSliceValues(vals)
+}
+func NewArrayAgg(col *rel.Column) (Aggregator, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok || len(fn.Args) != 1 {
+		return nil, fmt.Errorf("array_agg requires 1 arg  array_agg(x): %s", col.Expr)
+	}
+	return &arrayAgg{collect: newCollect(fn, fn.Args[0])}, nil
+}
+
+// jsonObjectAgg   json_objectagg(k, v [ORDER BY y])  collects the key/values
+// into a value.MapValue, rows with null keys are skipped and for duplicate
+// keys the last value (in ORDER BY order) wins
+type jsonObjectAgg struct {
+	collect
+}
+
+func (m *jsonObjectAgg) Do(v value.Value) {
+	vals, sortVals := m.row(v)
+	if len(vals) != 2 || isNull(vals[0]) {
+		return
+	}
+	m.entries = append(m.entries, collectEntry{Key: vals[0].ToString(), Val: vals[1].Value(), Sort: sortVals})
+}
+func (m *jsonObjectAgg) Result() interface{} {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	obj := make(map[string]interface{}, len(m.entries))
+	for _, e := range m.sorted() {
+		obj[e.Key] = e.Val
+	}
+	return value.NewMapValue(obj)
+}
+func NewJsonObjectAgg(col *rel.Column) (Aggregator, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok || len(fn.Args) != 2 {
+		return nil, fmt.Errorf("json_objectagg requires 2 args  json_objectagg(k, v): %s", col.Expr)
+	}
+	return &jsonObjectAgg{collect: newCollect(fn, fn.Args[0], fn.Args[1])}, nil
+}
+
+// sort collected entries by the ORDER BY values, nulls first
+type collectSorter struct {
+	entries []collectEntry
+	desc    []bool
+}
+
+func (m *collectSorter) Len() int      { return len(m.entries) }
+func (m *collectSorter) Swap(i, j int) { m.entries[i], m.entries[j] = m.entries[j], m.entries[i] }
+func (m *collectSorter) Less(i, j int) bool {
+	for k, desc := range m.desc {
+		c := compareSortVals(m.entries[i].Sort[k], m.entries[j].Sort[k])
+		if c == 0 {
+			continue
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return false
+}
+
+func compareSortVals(a, b driver.Value) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	c, err := value.Compare(value.NewValue(a), value.NewValue(b))
+	if err != nil {
+		// not comparable types, order by their string form
+		return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+	}
+	return c
+}
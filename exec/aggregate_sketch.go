@@ -0,0 +1,328 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+const (
+	// hyperloglog precision, 2^12 registers for ~1.6% standard error
+	hllPrecision = 12
+	hllRegisters = 1 << hllPrecision
+
+	// t-digest compression, higher is more accurate and more centroids
+	tdigestCompression = 100
+)
+
+// distinctAgg feeds only the distinct (typed) non-null values to an
+// aggregate  ie count(DISTINCT x).  Its partial state is the set of
+// distinct values so partials merge exactly.
+//
+// For aggregates with AggregatorArgs, ie group_concat(DISTINCT x ORDER BY x)
+// the distinct values are the tuples of all args.
+type distinctAgg struct {
+	col     *rel.Column
+	factory AggregatorFactory
+	args    []expr.Node
+	seen    map[string]struct{}
+	vals    [][]driver.Value
+}
+
+// partial state of distinct aggregates
+type distinctState struct {
+	Vals [][]driver.Value
+}
+
+func newDistinctAgg(factory AggregatorFactory, col *rel.Column) (Aggregator, error) {
+	// ensure the wrapped aggregate can be built
+	agg, err := factory(col)
+	if err != nil {
+		return nil, err
+	}
+	m := &distinctAgg{col: col, factory: factory, seen: make(map[string]struct{})}
+	if aa, ok := agg.(AggregatorArgs); ok {
+		m.args = aa.Args()
+	}
+	return m, nil
+}
+
+func (m *distinctAgg) Args() []expr.Node { return m.args }
+func (m *distinctAgg) Do(v value.Value) {
+	if isNull(v) {
+		return
+	}
+	if sv, ok := v.(value.SliceValue); ok && len(m.args) > 0 {
+		row := make([]driver.Value, 0, sv.Len())
+		for _, arg := range sv.Val() {
+			row = append(row, arg.Value())
+		}
+		if row[0] == nil {
+			return
+		}
+		m.add(row)
+		return
+	}
+	m.add([]driver.Value{v.Value()})
+}
+func (m *distinctAgg) add(row []driver.Value) {
+	key := distinctKey(row)
+	if _, exists := m.seen[key]; exists {
+		return
+	}
+	m.seen[key] = struct{}{}
+	m.vals = append(m.vals, row)
+}
+func (m *distinctAgg) Result() interface{} {
+	agg, err := m.factory(m.col)
+	if err != nil {
+		return nil
+	}
+	for _, row := range m.vals {
+		if len(m.args) == 0 {
+			agg.Do(value.NewValue(row[0]))
+			continue
+		}
+		vals := make([]value.Value, len(row))
+		for i, v := range row {
+			vals[i] = value.NewValue(v)
+		}
+		agg.Do(value.NewSliceValues(vals))
+	}
+	return agg.Result()
+}
+func (m *distinctAgg) Reset() {
+	m.seen = make(map[string]struct{})
+	m.vals = nil
+}
+func (m *distinctAgg) Partial() ([]byte, error) {
+	return EncodeAggState(&distinctState{Vals: m.vals})
+}
+func (m *distinctAgg) Merge(partial []byte) error {
+	st := distinctState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	for _, row := range st.Vals {
+		m.add(row)
+	}
+	return nil
+}
+
+// approxCountDistinct estimates the number of distinct non-null values
+// with a HyperLogLog sketch, values are compared typed as for DISTINCT
+type approxCountDistinct struct {
+	regs []byte
+}
+
+func (m *approxCountDistinct) Do(v value.Value) {
+	if isNull(v) {
+		return
+	}
+	h := fnv.New64a()
+	h.Write([]byte(distinctKey([]driver.Value{v.Value()})))
+	x := fmix64(h.Sum64())
+	idx := x >> (64 - hllPrecision)
+	rho := byte(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	if rho > m.regs[idx] {
+		m.regs[idx] = rho
+	}
+}
+func (m *approxCountDistinct) Result() interface{} {
+	sum, zeros := 0.0, 0
+	for _, r := range m.regs {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	n := float64(hllRegisters)
+	est := 0.7213 / (1 + 1.079/n) * n * n / sum
+	if est <= 2.5*n && zeros > 0 {
+		// small range correction, linear counting
+		est = n * math.Log(n/float64(zeros))
+	}
+	return int64(est + 0.5)
+}
+func (m *approxCountDistinct) Reset() {
+	m.regs = make([]byte, hllRegisters)
+}
+func (m *approxCountDistinct) Partial() ([]byte, error) {
+	return EncodeAggState(m.regs)
+}
+func (m *approxCountDistinct) Merge(partial []byte) error {
+	var regs []byte
+	if err := DecodeAggState(partial, &regs); err != nil {
+		return err
+	}
+	if len(regs) != len(m.regs) {
+		return fmt.Errorf("approx_count_distinct partial has %d registers, expected %d", len(regs), len(m.regs))
+	}
+	for i, r := range regs {
+		if r > m.regs[i] {
+			m.regs[i] = r
+		}
+	}
+	return nil
+}
+func NewApproxCountDistinct(col *rel.Column) (Aggregator, error) {
+	return &approxCountDistinct{regs: make([]byte, hllRegisters)}, nil
+}
+
+// fmix64 is the murmur3 finalizer, fnv alone mixes the high bits poorly
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
+// centroid of a t-digest
+type centroid struct {
+	Mean  float64
+	Count float64
+}
+
+// partial state of percentile, the t-digest centroids
+type tdigestState struct {
+	Centroids []centroid
+	Min       float64
+	Max       float64
+}
+
+// percentile estimates the q quantile of non-null numeric values with a
+// merging t-digest, which is exact (linearly interpolated) for small sets
+type percentile struct {
+	q         float64
+	centroids []centroid // sorted by mean
+	buf       []centroid // not yet merged
+	total     float64
+	min       float64
+	max       float64
+}
+
+func (m *percentile) Do(v value.Value) {
+	f, ok := aggFloat(v)
+	if !ok {
+		return
+	}
+	m.add(centroid{f, 1}, f, f)
+}
+func (m *percentile) add(c centroid, min, max float64) {
+	if m.total == 0 || min < m.min {
+		m.min = min
+	}
+	if m.total == 0 || max > m.max {
+		m.max = max
+	}
+	m.total += c.Count
+	m.buf = append(m.buf, c)
+	if len(m.buf) > 4*tdigestCompression {
+		m.compress()
+	}
+}
+
+// compress merges the buffered points into centroids, a centroid may only
+// grow to a size proportional to q(1-q), keeping the tails accurate
+func (m *percentile) compress() {
+	if len(m.buf) == 0 {
+		return
+	}
+	all := append(m.centroids, m.buf...)
+	sort.Sort(centroidsByMean(all))
+	merged := make([]centroid, 0, len(all))
+	cur := all[0]
+	soFar := 0.0
+	for _, c := range all[1:] {
+		q0 := soFar / m.total
+		q2 := (soFar + cur.Count + c.Count) / m.total
+		limit := 4 * m.total * math.Min(q0*(1-q0), q2*(1-q2)) / tdigestCompression
+		if cur.Count+c.Count <= limit {
+			cur.Mean += (c.Mean - cur.Mean) * c.Count / (cur.Count + c.Count)
+			cur.Count += c.Count
+			continue
+		}
+		soFar += cur.Count
+		merged = append(merged, cur)
+		cur = c
+	}
+	m.centroids = append(merged, cur)
+	m.buf = nil
+}
+
+func (m *percentile) Result() interface{} {
+	if m.total == 0 {
+		return nil
+	}
+	m.compress()
+	if len(m.centroids) == 1 {
+		return m.centroids[0].Mean
+	}
+	// interpolate between the centers of the centroids either side of
+	// target rank, and the min, max at the ends
+	target := m.q * m.total
+	cum := 0.0
+	prevMid, prevMean := 0.0, m.min
+	for _, c := range m.centroids {
+		mid := cum + c.Count/2
+		if target < mid {
+			return prevMean + (target-prevMid)/(mid-prevMid)*(c.Mean-prevMean)
+		}
+		prevMid, prevMean = mid, c.Mean
+		cum += c.Count
+	}
+	if m.total == prevMid {
+		return m.max
+	}
+	return prevMean + (target-prevMid)/(m.total-prevMid)*(m.max-prevMean)
+}
+func (m *percentile) Reset() {
+	m.centroids, m.buf, m.total, m.min, m.max = nil, nil, 0, 0, 0
+}
+func (m *percentile) Partial() ([]byte, error) {
+	m.compress()
+	return EncodeAggState(&tdigestState{Centroids: m.centroids, Min: m.min, Max: m.max})
+}
+func (m *percentile) Merge(partial []byte) error {
+	st := tdigestState{}
+	if err := DecodeAggState(partial, &st); err != nil {
+		return err
+	}
+	for _, c := range st.Centroids {
+		m.add(c, st.Min, st.Max)
+	}
+	return nil
+}
+
+// NewPercentile   percentile(x, 0.95)
+func NewPercentile(col *rel.Column) (Aggregator, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok || len(fn.Args) != 2 {
+		return nil, fmt.Errorf("percentile requires 2 args  percentile(x, 0.95): %s", col.Expr)
+	}
+	qn, ok := fn.Args[1].(*expr.NumberNode)
+	if !ok || qn.Float64 < 0 || qn.Float64 > 1 {
+		return nil, fmt.Errorf("percentile requires a fraction 0 to 1: %s", col.Expr)
+	}
+	return &percentile{q: qn.Float64}, nil
+}
+
+// NewMedian   median(x) is percentile(x, 0.5)
+func NewMedian(col *rel.Column) (Aggregator, error) {
+	return &percentile{q: 0.5}, nil
+}
+
+type centroidsByMean []centroid
+
+func (m centroidsByMean) Len() int           { return len(m) }
+func (m centroidsByMean) Less(i, j int) bool { return m[i].Mean < m[j].Mean }
+func (m centroidsByMean) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
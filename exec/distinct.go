@@ -0,0 +1,391 @@
+package exec
+
+import (
+	"bytes"
+	"container/heap"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Distinct)(nil)
+
+	// DistinctMemoryLimit is the approximate number of bytes of row keys a
+	// Distinct task will hold in memory before spilling rows to temp files.
+	DistinctMemoryLimit int64 = 32 * 1024 * 1024
+)
+
+const (
+	// number of hash partitions rows are spilled into once over memory limit
+	distinctPartitions = 16
+)
+
+// Distinct:   Sql Select Distinct Operator
+//   removes duplicate rows (all projected values equal, typed) and
+//   then applies offset, limit to the de-duplicated rows
+//
+//   - keeps a hash set of row keys in memory, emitting rows the first time
+//     they are seen so order of input (order by) is preserved.
+//   - once the set exceeds DistinctMemoryLimit (or the query's memory
+//     account is exhausted), un-seen rows are spilled
+//     into hash partitions, each partition is de-duplicated on its own
+//     and the survivors merged back in input order.
+//
+//   projection   ->  distinct  -->
+//
+type Distinct struct {
+	*TaskBase
+	closed bool
+	p      *plan.Distinct
+}
+
+func NewDistinct(ctx *plan.Context, p *plan.Distinct) *Distinct {
+	m := &Distinct{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+	}
+	return m
+}
+
+func (m *Distinct) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	return m.TaskBase.Close()
+}
+
+func (m *Distinct) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	inCh := m.MessageIn()
+	colIndex := m.p.Stmt.ColIndexes()
+
+	d := newRowDeduper(m.Ctx)
+	defer d.Close()
+
+	lim := newOffsetLimit(m.p.Stmt.Offset, m.p.Stmt.Limit)
+	seq := uint64(0)
+
+msgReadLoop:
+	for {
+
+		select {
+		case <-m.SigChan():
+			u.Warnf("got signal quit")
+			return nil
+		case msg, ok := <-inCh:
+			if !ok || msg == nil {
+				break msgReadLoop
+			}
+
+			var sdm *datasource.SqlDriverMessageMap
+
+			switch mt := msg.(type) {
+			case *datasource.SqlDriverMessageMap:
+				sdm = mt
+			default:
+				msgReader, isContextReader := msg.(expr.ContextReader)
+				if !isContextReader {
+					err := fmt.Errorf("To use Distinct must use SqlDriverMessageMap but got %T", msg)
+					u.Errorf("unrecognized msg %T", msg)
+					close(m.TaskBase.sigCh)
+					return err
+				}
+				sdm = datasource.NewSqlDriverMessageMapCtx(msg.Id(), msgReader, colIndex)
+			}
+
+			isNew, err := d.add(seq, sdm)
+			seq++
+			if err != nil {
+				u.Errorf("could not de-dupe %v", err)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+			if !isNew {
+				continue
+			}
+			if !lim.next(m, sdm) {
+				return nil
+			}
+		}
+	}
+
+	return d.emit(m, lim, colIndex)
+}
+
+// send a msg downstream, false if we have been told to quit
+func (m *Distinct) send(msg *datasource.SqlDriverMessageMap) bool {
+	select {
+	case m.msgOutCh <- msg:
+		return true
+	case <-m.SigChan():
+		return false
+	}
+}
+
+// rowSender is a task that sends rows downstream, returns false if the
+// task has been told to quit
+type rowSender interface {
+	send(msg *datasource.SqlDriverMessageMap) bool
+}
+
+// offsetLimit skips the first offset rows, and then sends up to limit rows
+type offsetLimit struct {
+	offset int
+	limit  int
+	ct     int
+}
+
+func newOffsetLimit(offset, limit int) *offsetLimit {
+	if limit == 0 {
+		limit = math.MaxInt32
+	}
+	return &offsetLimit{offset: offset, limit: limit}
+}
+
+// next sends msg unless it falls within offset, returns false once
+// limit has been reached or task has been told to quit
+func (m *offsetLimit) next(task rowSender, msg *datasource.SqlDriverMessageMap) bool {
+	m.ct++
+	if m.ct <= m.offset {
+		return true
+	}
+	if !task.send(msg) {
+		return false
+	}
+	return m.ct < m.offset+m.limit
+}
+
+// serialized row for distinct spill files
+type distinctRecord struct {
+	Seq  uint64
+	Id   uint64
+	Key  string
+	Vals []driver.Value
+}
+
+// rowDeduper holds the in-memory set of row keys, and hash partitioned
+// spill files of rows once that set got too large
+type rowDeduper struct {
+	seen    map[string]struct{}
+	size    int64
+	parts   []*spillFile
+	results []*spillFile
+	mem     *memReservation
+}
+
+func newRowDeduper(ctx *plan.Context) *rowDeduper {
+	return &rowDeduper{seen: make(map[string]struct{}), mem: newMemReservation(ctx)}
+}
+
+// add a row, returns true if it has not been seen and should be
+// sent now.  Once spilled rows are written out for later instead.
+func (m *rowDeduper) add(seq uint64, msg *datasource.SqlDriverMessageMap) (bool, error) {
+	key := distinctKey(msg.Vals)
+	if _, exists := m.seen[key]; exists {
+		return false, nil
+	}
+	if m.parts != nil {
+		rec := distinctRecord{Seq: seq, Id: msg.IdVal, Key: key, Vals: msg.Vals}
+		return false, m.parts[partitionOf(key)].Write(&rec)
+	}
+	m.seen[key] = struct{}{}
+	m.size += int64(len(key) + 48)
+	if m.size > DistinctMemoryLimit || !m.mem.tryResize(m.size) {
+		if err := m.spill(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// spill switches to partitioned mode, rows already in seen set have
+// been sent, everything new goes to a partition file
+func (m *rowDeduper) spill() error {
+	m.parts = make([]*spillFile, distinctPartitions)
+	for i := range m.parts {
+		f, err := newSpillFile("distinct")
+		if err != nil {
+			return err
+		}
+		m.parts[i] = f
+	}
+	return nil
+}
+
+// emit the spilled rows, de-duplicate each partition in turn then
+// merge the surviving rows back in their original order
+func (m *rowDeduper) emit(task rowSender, lim *offsetLimit, colIndex map[string]int) error {
+	if m.parts == nil {
+		return nil
+	}
+	// the pre-spill keys are no longer needed, partitions are disjoint
+	m.seen = nil
+
+	mh := &distinctMergeHeap{}
+	for _, part := range m.parts {
+		rdr, err := part.Reader()
+		if err != nil {
+			return err
+		}
+		out, err := newSpillFile("distinct")
+		if err != nil {
+			return err
+		}
+		m.results = append(m.results, out)
+		seen := make(map[string]struct{})
+		for {
+			rec := distinctRecord{}
+			if err := rdr.Read(&rec); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			if _, exists := seen[rec.Key]; exists {
+				continue
+			}
+			seen[rec.Key] = struct{}{}
+			if err := out.Write(&rec); err != nil {
+				return err
+			}
+		}
+		part.Close()
+
+		outRdr, err := out.Reader()
+		if err != nil {
+			return err
+		}
+		if err := mh.pushNext(outRdr); err != nil {
+			return err
+		}
+	}
+
+	for mh.Len() > 0 {
+		head := mh.heads[0]
+		msg := datasource.NewSqlDriverMessageMap(head.rec.Id, head.rec.Vals, colIndex)
+		if !lim.next(task, msg) {
+			return nil
+		}
+		heap.Pop(mh)
+		if err := mh.pushNext(head.rdr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close removes any spill files
+func (m *rowDeduper) Close() {
+	m.mem.release()
+	for _, f := range m.parts {
+		f.Close()
+	}
+	for _, f := range m.results {
+		f.Close()
+	}
+}
+
+func partitionOf(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % distinctPartitions)
+}
+
+// distinctKey creates a typed key for a row of values, such that only
+// values of same type and value are equal.  ie int 1 != string "1"
+func distinctKey(vals []driver.Value) string {
+	var buf bytes.Buffer
+	for _, v := range vals {
+		switch vt := v.(type) {
+		case nil:
+			buf.WriteByte('N')
+		case string:
+			buf.WriteByte('s')
+			buf.WriteString(strconv.Itoa(len(vt)))
+			buf.WriteByte(':')
+			buf.WriteString(vt)
+		case []byte:
+			buf.WriteByte('b')
+			buf.WriteString(strconv.Itoa(len(vt)))
+			buf.WriteByte(':')
+			buf.Write(vt)
+		case int64:
+			buf.WriteByte('i')
+			buf.WriteString(strconv.FormatInt(vt, 10))
+		case int:
+			buf.WriteByte('i')
+			buf.WriteString(strconv.Itoa(vt))
+		case float64:
+			buf.WriteByte('f')
+			buf.WriteString(strconv.FormatFloat(vt, 'g', -1, 64))
+		case bool:
+			if vt {
+				buf.WriteString("t1")
+			} else {
+				buf.WriteString("t0")
+			}
+		case time.Time:
+			buf.WriteByte('d')
+			buf.WriteString(strconv.FormatInt(vt.UnixNano(), 10))
+		default:
+			s := fmt.Sprintf("%T:%v", v, v)
+			buf.WriteByte('x')
+			buf.WriteString(strconv.Itoa(len(s)))
+			buf.WriteByte(':')
+			buf.WriteString(s)
+		}
+		buf.WriteByte(0)
+	}
+	return buf.String()
+}
+
+type distinctHead struct {
+	rec *distinctRecord
+	rdr *spillReader
+}
+
+// distinctMergeHeap merges partition survivors back into input (seq) order
+type distinctMergeHeap struct {
+	heads []*distinctHead
+}
+
+func (m *distinctMergeHeap) Len() int { return len(m.heads) }
+func (m *distinctMergeHeap) Less(i, j int) bool {
+	return m.heads[i].rec.Seq < m.heads[j].rec.Seq
+}
+func (m *distinctMergeHeap) Swap(i, j int)      { m.heads[i], m.heads[j] = m.heads[j], m.heads[i] }
+func (m *distinctMergeHeap) Push(x interface{}) { m.heads = append(m.heads, x.(*distinctHead)) }
+func (m *distinctMergeHeap) Pop() interface{} {
+	n := len(m.heads)
+	h := m.heads[n-1]
+	m.heads = m.heads[:n-1]
+	return h
+}
+
+// read next record from reader and push onto heap, if not exhausted
+func (m *distinctMergeHeap) pushNext(rdr *spillReader) error {
+	rec := &distinctRecord{}
+	if err := rdr.Read(rec); err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	heap.Push(m, &distinctHead{rec: rec, rdr: rdr})
+	return nil
+}
@@ -79,7 +79,11 @@ type (
 		NewTask(p plan.Task) Task
 		WalkPlan(p plan.Task) (Task, error)
 		WalkSelect(p *plan.Select) (Task, error)
+		WalkSetOperation(p *plan.SetOperation) (Task, error)
+		WalkWith(p *plan.With) (Task, error)
+		WalkExplain(p *plan.Explain) (Task, error)
 		WalkInsert(p *plan.Insert) (Task, error)
+		WalkInto(p *plan.Into) (Task, error)
 		WalkUpsert(p *plan.Upsert) (Task, error)
 		WalkUpdate(p *plan.Update) (Task, error)
 		WalkDelete(p *plan.Delete) (Task, error)
@@ -90,9 +94,14 @@ type (
 		WalkSource(p *plan.Source) (Task, error)
 		WalkJoin(p *plan.JoinMerge) (Task, error)
 		WalkJoinKey(p *plan.JoinKey) (Task, error)
+		WalkSemiJoin(p *plan.SemiJoin) (Task, error)
+		WalkPartitionScan(p *plan.PartitionScan) (Task, error)
 		WalkWhere(p *plan.Where) (Task, error)
 		WalkHaving(p *plan.Having) (Task, error)
 		WalkGroupBy(p *plan.GroupBy) (Task, error)
+		WalkOrderBy(p *plan.OrderBy) (Task, error)
+		WalkDistinct(p *plan.Distinct) (Task, error)
+		WalkWindow(p *plan.Window) (Task, error)
 		WalkProjection(p *plan.Projection) (Task, error)
 	}
 
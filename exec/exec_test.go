@@ -1,8 +1,13 @@
 package exec_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,11 +15,15 @@ import (
 	"github.com/bmizerany/assert"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/memdb"
 	"github.com/araddon/qlbridge/datasource/mockcsv"
 	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
 	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/testutil"
+	"github.com/araddon/qlbridge/value"
 )
 
 func init() {
@@ -34,17 +43,17 @@ func TestStatements(t *testing.T) {
 	// - ensure we can evaluate against "NULL"
 	// - extra paren in where
 	// - `db`.`col` syntax
-	testutil.TestSelect(t, "SELECT user_id FROM users WHERE (`users.user_id` != NULL)",
+	testutil.TestSelect(t, "SELECT user_id FROM users WHERE (`users.user_id` IS NOT NULL)",
 		[][]driver.Value{{"hT2impsabc345c"}, {"9Ip1aKbeZe2njCDM"}, {"hT2impsOPUREcVPc"}},
 	)
-	testutil.TestSelect(t, "SELECT email FROM users WHERE interests != NULL)",
+	testutil.TestSelect(t, "SELECT email FROM users WHERE interests IS NOT NULL)",
 		[][]driver.Value{{"aaron@email.com"}, {"bob@email.com"}},
 	)
 
 	return
 	// TODO:
 
-	testutil.TestSelect(t, "SELECT COUNT(*) AS count FROM users WHERE (`users.user_id` != NULL)",
+	testutil.TestSelect(t, "SELECT COUNT(*) AS count FROM users WHERE (`users.user_id` IS NOT NULL)",
 		[][]driver.Value{{3}},
 	)
 	// requires aggregations, note also lack of group-by
@@ -149,7 +158,6 @@ func TestExecSelectWhere(t *testing.T) {
 }
 
 func TestExecGroupBy(t *testing.T) {
-	// TODO:  this test is bad, it occasionally fails
 	sqlText := `
 		select 
 	        user_id, count(user_id), avg(price)
@@ -206,6 +214,211 @@ func TestExecGroupBy(t *testing.T) {
 	assert.Tf(t, int(row[0].(float64)) == 13, "expected avg(len(email))=15 for %v", int(row[0].(float64)))
 }
 
+func TestExecGroupByStreaming(t *testing.T) {
+	// groups are output in order first seen
+	testutil.TestSelect(t, `select user_id, count(user_id), avg(price), sum(price) FROM orders GROUP BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", int64(2), float64(30), float64(60)},
+			{"abcabcabc", int64(1), float64(22.5), float64(22.5)},
+		},
+	)
+	testutil.TestSelect(t, `select interests, count(*) FROM users GROUP BY interests`,
+		[][]driver.Value{
+			{"", int64(1)},
+			{"fishing", int64(1)},
+			{"swimming", int64(1)},
+		},
+	)
+
+	// force partial aggregate state to spill after every row
+	origLimit := exec.GroupByMemoryLimit
+	exec.GroupByMemoryLimit = 1
+	defer func() { exec.GroupByMemoryLimit = origLimit }()
+	testutil.TestSelect(t, `select user_id, count(user_id), avg(price), sum(price) FROM orders GROUP BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", int64(2), float64(30), float64(60)},
+			{"abcabcabc", int64(1), float64(22.5), float64(22.5)},
+		},
+	)
+	testutil.TestSelect(t, `select interests, count(*) FROM users GROUP BY interests`,
+		[][]driver.Value{
+			{"", int64(1)},
+			{"fishing", int64(1)},
+			{"swimming", int64(1)},
+		},
+	)
+}
+
+// maxLen is a custom aggregate, the length of the longest string
+type maxLen struct {
+	n int64
+}
+
+func (m *maxLen) Do(v value.Value) {
+	if sv, ok := v.(value.StringValue); ok && int64(len(sv.Val())) > m.n {
+		m.n = int64(len(sv.Val()))
+	}
+}
+func (m *maxLen) Result() interface{}      { return m.n }
+func (m *maxLen) Reset()                   { m.n = 0 }
+func (m *maxLen) Partial() ([]byte, error) { return exec.EncodeAggState(m.n) }
+func (m *maxLen) Merge(partial []byte) error {
+	var n int64
+	if err := exec.DecodeAggState(partial, &n); err != nil {
+		return err
+	}
+	if n > m.n {
+		m.n = n
+	}
+	return nil
+}
+
+func TestExecAggregates(t *testing.T) {
+	exec.AggregatorAdd("max_len", func(col *rel.Column) (exec.Aggregator, error) {
+		return &maxLen{}, nil
+	})
+
+	aggTests := func() {
+		// min/max compare typed values, these are csv strings
+		testutil.TestSelect(t, `select user_id, min(price), max(price), first(order_id), last(order_id) FROM orders GROUP BY user_id`,
+			[][]driver.Value{
+				{"9Ip1aKbeZe2njCDM", "22.50", "37.50", "1", "2"},
+				{"abcabcabc", "22.50", "22.50", "3", "3"},
+			},
+		)
+		testutil.TestSelect(t, `select user_id, variance(price), stddev(price) FROM orders GROUP BY user_id`,
+			[][]driver.Value{
+				{"9Ip1aKbeZe2njCDM", float64(112.5), math.Sqrt(112.5)},
+				{"abcabcabc", nil, nil},
+			},
+		)
+		testutil.TestSelect(t, `select user_id, bool_and(eq(item_id, 1)), bool_or(eq(item_id,1)) FROM orders GROUP BY user_id`,
+			[][]driver.Value{
+				{"9Ip1aKbeZe2njCDM", false, true},
+				{"abcabcabc", true, true},
+			},
+		)
+		// count of a column skips nulls
+		testutil.TestSelect(t, `select count(*), count(interests), sum(referral_count), max_len(email) FROM users`,
+			[][]driver.Value{
+				{int64(3), int64(2), float64(106), int64(15)},
+			},
+		)
+	}
+	aggTests()
+
+	// merging of spilled partial state
+	origLimit := exec.GroupByMemoryLimit
+	exec.GroupByMemoryLimit = 1
+	defer func() { exec.GroupByMemoryLimit = origLimit }()
+	aggTests()
+}
+
+func TestExecAggregatesDistinct(t *testing.T) {
+	testutil.TestSelect(t, `select count(distinct user_id), approx_count_distinct(user_id), median(price), percentile(price, 0.95), sum(DISTINCT price) FROM orders`,
+		[][]driver.Value{
+			{int64(2), int64(2), float64(22.5), float64(37.5), float64(60)},
+		},
+	)
+	testutil.TestSelect(t, `select user_id, count(distinct item_id), median(price) FROM orders GROUP BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", int64(2), float64(30)},
+			{"abcabcabc", int64(1), float64(22.5)},
+		},
+	)
+}
+
+// aggregate over 1..10000 split across 2 partial aggregators, as GroupBy
+// partials would be, then merged as in GroupByFinal
+func TestAggregatorPartialMerge(t *testing.T) {
+	sel, err := rel.ParseSqlSelect(`SELECT count(DISTINCT x), approx_count_distinct(x), percentile(x, 0.95), median(x) FROM t`)
+	assert.Tf(t, err == nil, "no error %v", err)
+
+	for i, col := range sel.Columns {
+		a, err := exec.NewAggregator(col)
+		assert.Tf(t, err == nil, "no error %v", err)
+		b, err := exec.NewAggregator(col)
+		assert.Tf(t, err == nil, "no error %v", err)
+		for n := 1; n <= 10000; n++ {
+			v := value.NewIntValue(int64(n))
+			if i < 2 {
+				// 5000 distinct values, each seen twice
+				v = value.NewIntValue(int64(n%5000 + 1))
+			}
+			if n%2 == 0 {
+				a.Do(v)
+			} else {
+				b.Do(v)
+			}
+		}
+		partial, err := b.Partial()
+		assert.Tf(t, err == nil, "no error %v", err)
+		assert.Tf(t, a.Merge(partial) == nil, "should merge")
+
+		switch i {
+		case 0:
+			assert.Tf(t, a.Result() == int64(5000), "%s expected 5000 got %v", col.Expr, a.Result())
+		case 1:
+			ct := a.Result().(int64)
+			assert.Tf(t, ct > 4750 && ct < 5250, "%s expected ~5000 got %v", col.Expr, ct)
+		case 2:
+			p := a.Result().(float64)
+			assert.Tf(t, p > 9400 && p < 9600, "%s expected ~9500 got %v", col.Expr, p)
+		case 3:
+			p := a.Result().(float64)
+			assert.Tf(t, p > 4900 && p < 5100, "%s expected ~5000 got %v", col.Expr, p)
+		}
+	}
+}
+
+func TestExecAggregatesCollect(t *testing.T) {
+	collectTests := func() {
+		testutil.TestSelect(t, `select user_id, group_concat(item_id, "|" ORDER BY price DESC), group_concat(order_id ORDER BY order_id) FROM orders GROUP BY user_id`,
+			[][]driver.Value{
+				{"9Ip1aKbeZe2njCDM", "2|1", "1,2"},
+				{"abcabcabc", "1", "3"},
+			},
+		)
+		testutil.TestSelect(t, `select group_concat(DISTINCT user_id ORDER BY user_id DESC) FROM orders`,
+			[][]driver.Value{
+				{"abcabcabc,9Ip1aKbeZe2njCDM"},
+			},
+		)
+	}
+	collectTests()
+
+	// merging of spilled partial state
+	origLimit := exec.GroupByMemoryLimit
+	exec.GroupByMemoryLimit = 1
+	collectTests()
+	exec.GroupByMemoryLimit = origLimit
+
+	sqlDb, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer sqlDb.Close()
+
+	rows, err := sqlDb.Query(`
+		select user_id, array_agg(order_id ORDER BY price DESC, order_id), json_objectagg(item_id, price)
+		FROM orders
+		WHERE user_id = "9Ip1aKbeZe2njCDM"
+		GROUP BY user_id`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer rows.Close()
+	ct := 0
+	for rows.Next() {
+		var userId string
+		var orders, items interface{}
+		err = rows.Scan(&userId, &orders, &items)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		ct++
+
+		// the driver returns the go values of the SliceValue, MapValue
+		assert.Equal(t, []interface{}{"2", "1"}, orders)
+		assert.Equal(t, map[string]interface{}{"1": "22.50", "2": "37.50"}, items)
+	}
+	assert.Tf(t, ct == 1, "should have 1 row %v", ct)
+}
+
 func TestExecHaving(t *testing.T) {
 	sqlText := `
 		select 
@@ -237,6 +450,183 @@ func TestExecHaving(t *testing.T) {
 	assert.Tf(t, int(row[1].(int64)) == 2, "expected 2 orders for %v", row)
 }
 
+func TestExecOrderBy(t *testing.T) {
+	testutil.TestSelect(t, `SELECT user_id, referral_count FROM users ORDER BY referral_count DESC, user_id ASC`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "82"},
+			{"hT2impsOPUREcVPc", "12"},
+			{"hT2impsabc345c", "12"},
+		},
+	)
+	// top-n with limit
+	testutil.TestSelect(t, `SELECT email FROM users ORDER BY email DESC LIMIT 2`,
+		[][]driver.Value{{"not_an_email"}, {"bob@email.com"}},
+	)
+	// order by select alias
+	testutil.TestSelect(t, `SELECT email AS e FROM users ORDER BY e`,
+		[][]driver.Value{{"aaron@email.com"}, {"bob@email.com"}, {"not_an_email"}},
+	)
+	// order by aggregate alias, post group-by
+	testutil.TestSelect(t, `SELECT user_id, count(user_id) AS ct FROM orders GROUP BY user_id ORDER BY ct DESC`,
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM", int64(2)}, {"abcabcabc", int64(1)}},
+	)
+
+	// force every row to spill to its own sorted run, then merge
+	origLimit := exec.SortMemoryLimit
+	exec.SortMemoryLimit = 1
+	defer func() { exec.SortMemoryLimit = origLimit }()
+	testutil.TestSelect(t, `SELECT order_id, price FROM orders ORDER BY price DESC, order_id DESC`,
+		[][]driver.Value{
+			{"2", "37.50"},
+			{"3", "22.50"},
+			{"1", "22.50"},
+		},
+	)
+	exec.SortMemoryLimit = origLimit
+
+	// keys of mixed types are ordered by type, ints and numbers before
+	// strings, then by value, whatever order the rows are read in
+	mockcsv.LoadTable("sort_mixed", "k,v\n1,10\n2,abc\n3,9\n4,2.5\n5,100x\n6,\n7,2")
+	mockcsv.LoadTable("sort_mixed_rev", "k,v\n7,2\n6,\n5,100x\n4,2.5\n3,9\n2,abc\n1,10")
+	mixed := [][]driver.Value{
+		{"6", nil},
+		{"7", float64(2)},
+		{"4", float64(2.5)},
+		{"3", float64(9)},
+		{"1", float64(10)},
+		{"5", "100x"},
+		{"2", "abc"},
+	}
+	for _, limit := range []int64{1, exec.SortMemoryLimit} {
+		exec.SortMemoryLimit = limit
+		for _, tbl := range []string{"sort_mixed", "sort_mixed_rev"} {
+			sql := fmt.Sprintf(`SELECT k, CASE WHEN v IS NULL THEN NULL WHEN tonumber(v) IS NOT NULL THEN tonumber(v) ELSE v END AS s
+				FROM %s ORDER BY s`, tbl)
+			testutil.TestSelect(t, sql, mixed)
+			testutil.TestSelect(t, sql+" LIMIT 3", mixed[:3])
+		}
+	}
+}
+
+func TestExecDistinct(t *testing.T) {
+	testutil.TestSelect(t, `SELECT DISTINCT user_id FROM orders`,
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	testutil.TestSelect(t, `SELECT DISTINCT referral_count FROM users ORDER BY referral_count DESC`,
+		[][]driver.Value{{"82"}, {"12"}},
+	)
+	// limit, offset apply to the de-duplicated rows
+	testutil.TestSelect(t, `SELECT DISTINCT price FROM orders ORDER BY price LIMIT 1 OFFSET 1`,
+		[][]driver.Value{{"37.50"}},
+	)
+	testutil.TestSelect(t, `SELECT DISTINCT user_id, count(*) AS ct FROM orders GROUP BY user_id ORDER BY user_id LIMIT 1 OFFSET 1`,
+		[][]driver.Value{{"abcabcabc", int64(1)}},
+	)
+
+	// offset without distinct
+	testutil.TestSelect(t, `SELECT user_id FROM users ORDER BY user_id LIMIT 1 OFFSET 1`,
+		[][]driver.Value{{"hT2impsOPUREcVPc"}},
+	)
+
+	// force spilling to hash partitions after first row
+	origLimit := exec.DistinctMemoryLimit
+	exec.DistinctMemoryLimit = 1
+	defer func() { exec.DistinctMemoryLimit = origLimit }()
+	testutil.TestSelect(t, `SELECT DISTINCT item_count, price FROM orders ORDER BY price DESC`,
+		[][]driver.Value{{"82", "37.50"}, {"82", "22.50"}},
+	)
+}
+
+func TestExecJoinTypes(t *testing.T) {
+	// left (probe) side rows come out in source order, un-matched right
+	// side rows after
+	testutil.TestSelect(t, `SELECT u.user_id, o.order_id FROM users AS u INNER JOIN orders AS o ON u.user_id = o.user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "1"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT u.user_id, o.order_id FROM users AS u LEFT JOIN orders AS o ON u.user_id = o.user_id`,
+		[][]driver.Value{
+			{"hT2impsabc345c", nil},
+			{"9Ip1aKbeZe2njCDM", "1"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+			{"hT2impsOPUREcVPc", nil},
+		},
+	)
+	testutil.TestSelect(t, `SELECT u.user_id, o.order_id FROM users AS u RIGHT OUTER JOIN orders AS o ON u.user_id = o.user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "1"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+			{nil, "3"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT u.user_id, o.order_id FROM users AS u FULL OUTER JOIN orders AS o ON u.user_id = o.user_id`,
+		[][]driver.Value{
+			{"hT2impsabc345c", nil},
+			{"9Ip1aKbeZe2njCDM", "1"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+			{"hT2impsOPUREcVPc", nil},
+			{nil, "3"},
+		},
+	)
+	// typed key equality, string "82" coerces to match int 82
+	testutil.TestSelect(t, `SELECT u.user_id, o.order_id FROM users AS u INNER JOIN orders AS o ON u.referral_count = toint(o.item_count)`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "1"},
+			{"9Ip1aKbeZe2njCDM", "3"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+		},
+	)
+	// null keys never match, not even themselves
+	testutil.TestSelect(t, `SELECT a.user_id, b.user_id FROM users AS a LEFT JOIN users AS b ON a.interests = b.interests`,
+		[][]driver.Value{
+			{"hT2impsabc345c", nil},
+			{"9Ip1aKbeZe2njCDM", "9Ip1aKbeZe2njCDM"},
+			{"hT2impsOPUREcVPc", "hT2impsOPUREcVPc"},
+		},
+	)
+	// a where on the outer joined side is evaluated after the join, not
+	// pushed down to filter its source
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	joined := func(where string) []string {
+		rows, err := db.Query(`SELECT u.user_id, o.order_id FROM users AS u ` + where)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		pairs := make([]string, 0)
+		for rows.Next() {
+			var user, order sql.NullString
+			err = rows.Scan(&user, &order)
+			assert.Tf(t, err == nil, "no error: %v", err)
+			pairs = append(pairs, user.String+"-"+order.String)
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		sort.Strings(pairs)
+		return pairs
+	}
+	assert.Equal(t, []string{"hT2impsOPUREcVPc-", "hT2impsabc345c-"},
+		joined(`LEFT JOIN orders AS o ON u.user_id = o.user_id WHERE o.order_id IS NULL`))
+	assert.Equal(t, []string{"-3"},
+		joined(`RIGHT JOIN orders AS o ON u.user_id = o.user_id WHERE u.user_id IS NULL`))
+	assert.Equal(t, []string{"-3", "9Ip1aKbeZe2njCDM-2"},
+		joined(`FULL OUTER JOIN orders AS o ON u.user_id = o.user_id WHERE o.order_id != "1"`))
+	assert.Equal(t, []string{"hT2impsabc345c-"},
+		joined(`LEFT JOIN orders AS o ON u.user_id = o.user_id WHERE u.user_id = "hT2impsabc345c"`))
+
+	// force build side to spill into partitions
+	origLimit := exec.JoinMemoryLimit
+	exec.JoinMemoryLimit = 1
+	defer func() { exec.JoinMemoryLimit = origLimit }()
+	testutil.TestSelect(t, `SELECT u.user_id, o.order_id FROM users AS u INNER JOIN orders AS o ON u.user_id = o.user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "1"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+		},
+	)
+}
+
 type UserEvent struct {
 	Id     string
 	UserId string
@@ -244,6 +634,647 @@ type UserEvent struct {
 	Date   time.Time
 }
 
+func TestExecJoinLookup(t *testing.T) {
+	// users are keyed on user_id, but mockcsv doesn't declare the type of
+	// its columns so they are hash joined (keys can't be converted for MultiGet)
+	testutil.TestSelect(t, `SELECT o.order_id, u.email FROM orders AS o INNER JOIN users AS u ON o.user_id = u.user_id`,
+		[][]driver.Value{
+			{"1", "aaron@email.com"},
+			{"2", "aaron@email.com"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT o.order_id, u.email FROM orders AS o LEFT JOIN users AS u ON o.user_id = u.user_id`,
+		[][]driver.Value{
+			{"1", "aaron@email.com"},
+			{"3", nil},
+			{"2", "aaron@email.com"},
+		},
+	)
+
+	// one left row per MultiGet
+	origBatch := exec.JoinLookupBatchSize
+	exec.JoinLookupBatchSize = 1
+	defer func() { exec.JoinLookupBatchSize = origBatch }()
+	testutil.TestSelect(t, `SELECT o.order_id, u.email FROM orders AS o LEFT JOIN users AS u ON o.user_id = u.user_id`,
+		[][]driver.Value{
+			{"1", "aaron@email.com"},
+			{"3", nil},
+			{"2", "aaron@email.com"},
+		},
+	)
+}
+
+func TestExecJoinLookupKeyType(t *testing.T) {
+	// the same categories, lookup_cats declares the (string) type of its key id
+	// so is looked up by key (MultiGet), hash_cats is hash joined
+	cats := "id,parent_id,name\n1,,a\n2,1,b\n3,1,c\n4,2,d\n5,2,e\n6,3,f"
+	mockcsv.LoadTable("lookup_cats", cats)
+	mockcsv.LoadTable("hash_cats", cats)
+	tbl, err := mockcsv.MockCsvGlobal.Table("lookup_cats")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	tbl.AddFieldType("id", value.StringType)
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	origBatch := exec.JoinLookupBatchSize
+	exec.JoinLookupBatchSize = 2
+	defer func() { exec.JoinLookupBatchSize = origBatch }()
+
+	// int keys, toint(parent_id), must be converted to the string ids
+	join := func(tableName, op string) []string {
+		sqlText := fmt.Sprintf(`SELECT c.name, p.name FROM %s AS c INNER JOIN %s AS p ON toint(c.parent_id) = p.id`,
+			tableName, tableName)
+
+		rows, err := db.Query("EXPLAIN " + sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		ops := make([]string, 0)
+		cols, _ := rows.Columns()
+		for rows.Next() {
+			vals := make([]interface{}, len(cols))
+			var rowOp string
+			vals[2] = &rowOp
+			for i := range vals {
+				if i != 2 {
+					vals[i] = new(interface{})
+				}
+			}
+			err = rows.Scan(vals...)
+			assert.Tf(t, err == nil, "no error: %v", err)
+			ops = append(ops, strings.TrimSpace(rowOp))
+		}
+		rows.Close()
+		sort.Strings(ops)
+		i := sort.SearchStrings(ops, op)
+		assert.Tf(t, i < len(ops) && ops[i] == op, "expected %s for %s but got %v", op, tableName, ops)
+
+		rows, err = db.Query(sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		names := make([]string, 0)
+		for rows.Next() {
+			var child, parent string
+			err = rows.Scan(&child, &parent)
+			assert.Tf(t, err == nil, "no error: %v", err)
+			names = append(names, child+"-"+parent)
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		sort.Strings(names)
+		return names
+	}
+	looked := join("lookup_cats", "JoinLookup")
+	hashed := join("hash_cats", "Join")
+	assert.Equal(t, []string{"b-a", "c-a", "d-b", "e-b", "f-c"}, hashed)
+	assert.Equal(t, hashed, looked)
+}
+
+func TestExecSubQuery(t *testing.T) {
+	testutil.TestSelect(t, `SELECT user_id, email FROM users WHERE user_id IN (SELECT user_id FROM orders)`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "aaron@email.com"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE user_id NOT IN (SELECT user_id FROM orders WHERE price > 30) AND referral_count < 50`,
+		[][]driver.Value{
+			{"hT2impsabc345c"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE user_id NOT IN (SELECT user_id FROM orders) ORDER BY user_id DESC LIMIT 1`,
+		[][]driver.Value{
+			{"hT2impsabc345c"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT count(*) FROM users WHERE tolower(user_id) IN (SELECT tolower(user_id) FROM orders)`,
+		[][]driver.Value{
+			{int64(1)},
+		},
+	)
+	// NOT IN a set with a NULL is never true, NULL IN a set is never true
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE user_id NOT IN (SELECT interests FROM users)`,
+		[][]driver.Value{},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE interests IN (SELECT interests FROM users)`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE interests NOT IN (SELECT interests FROM users WHERE interests = "x")`,
+		[][]driver.Value{
+			{"hT2impsabc345c"},
+			{"9Ip1aKbeZe2njCDM"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+	// nested sub-queries
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE user_id IN
+			(SELECT user_id FROM orders WHERE user_id IN (SELECT user_id FROM users WHERE referral_count > 50))`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+		},
+	)
+	// un-correlated EXISTS, correlated rewritten as semi/anti join
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE EXISTS (SELECT order_id FROM orders WHERE price > 100)`,
+		[][]driver.Value{},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users AS u WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id)`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users AS u WHERE NOT EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id AND o.price > 30)`,
+		[][]driver.Value{
+			{"hT2impsabc345c"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users AS u WHERE email IN (SELECT email FROM users AS u2 WHERE u2.user_id = u.user_id AND referral_count > 50)`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT u.user_id, o.item_id FROM users AS u INNER JOIN orders AS o ON u.user_id = o.user_id
+			WHERE o.item_id IN (SELECT item_id FROM orders WHERE price > 30)`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "2"},
+		},
+	)
+
+	// scalar sub-queries, non-equality correlation not supported
+	testutil.TestSelectErr(t, `SELECT user_id FROM users WHERE user_id = (SELECT max(user_id) FROM orders)`, nil)
+	testutil.TestSelectErr(t, `SELECT user_id FROM users AS u WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.price > u.referral_count)`, nil)
+	testutil.TestSelectErr(t, `SELECT user_id FROM users WHERE user_id IN (SELECT user_id, price FROM orders)`, nil)
+}
+
+func TestExecSetOperation(t *testing.T) {
+	testutil.TestSelect(t, `SELECT user_id FROM orders UNION ALL SELECT user_id FROM users`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+			{"abcabcabc"},
+			{"9Ip1aKbeZe2njCDM"},
+			{"hT2impsabc345c"},
+			{"9Ip1aKbeZe2njCDM"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM orders UNION SELECT user_id FROM users`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+			{"abcabcabc"},
+			{"hT2impsabc345c"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM orders INTERSECT SELECT user_id FROM users`,
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM orders INTERSECT ALL SELECT user_id FROM orders`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+			{"abcabcabc"},
+			{"9Ip1aKbeZe2njCDM"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM orders EXCEPT SELECT user_id FROM users`,
+		[][]driver.Value{{"abcabcabc"}},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM orders EXCEPT ALL SELECT user_id FROM users`,
+		[][]driver.Value{{"abcabcabc"}, {"9Ip1aKbeZe2njCDM"}},
+	)
+	// union of literals, left to right
+	testutil.TestSelect(t, `SELECT 1 UNION SELECT 2 UNION ALL SELECT 1`,
+		[][]driver.Value{{int64(1)}, {int64(2)}, {int64(1)}},
+	)
+	// order by, limit, offset apply to the combined rows using left column names
+	testutil.TestSelect(t, `SELECT user_id AS id FROM orders UNION SELECT user_id FROM users ORDER BY id DESC LIMIT 2`,
+		[][]driver.Value{{"hT2impsabc345c"}, {"hT2impsOPUREcVPc"}},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM orders UNION ALL SELECT user_id FROM users ORDER BY user_id LIMIT 2 OFFSET 1`,
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"9Ip1aKbeZe2njCDM"}},
+	)
+
+	testutil.TestSelectErr(t, `SELECT user_id, email FROM users UNION SELECT user_id FROM orders`, nil)
+	testutil.TestSelectErr(t, `SELECT user_id FROM users ORDER BY user_id UNION SELECT user_id FROM orders`, nil)
+
+	// types known to not match are rejected when planned, not known when run
+	for _, sqlText := range []string{
+		`SELECT toint(referral_count) FROM users UNION SELECT "x" FROM orders`,
+		`SELECT tolower(user_id) FROM users UNION ALL SELECT toint(price) FROM orders`,
+		`SELECT 1 UNION SELECT 2 EXCEPT SELECT "a"`,
+	} {
+		_, err := exec.BuildSqlJob(td.TestContext(sqlText))
+		assert.Tf(t, err != nil, "expected type mismatch for %s", sqlText)
+	}
+	ctx := td.TestContext(`SELECT user_id FROM users UNION SELECT toint(price) FROM orders`)
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	assert.T(t, job.Setup() == nil)
+	err = job.Run()
+	assert.Tf(t, err != nil && strings.Contains(err.Error(), "types do not match"), "expected type mismatch but got %v", err)
+}
+
+func TestExecWindow(t *testing.T) {
+	testutil.TestSelect(t, `SELECT order_id, row_number() OVER (PARTITION BY user_id ORDER BY order_id) AS rn FROM orders`,
+		[][]driver.Value{
+			{"1", int64(1)},
+			{"3", int64(1)},
+			{"2", int64(2)},
+		},
+	)
+	// peers share a rank, rank has gaps after them dense_rank does not
+	testutil.TestSelect(t, `SELECT order_id, rank() OVER (ORDER BY price) AS r, dense_rank() OVER (ORDER BY price) AS dr FROM orders`,
+		[][]driver.Value{
+			{"1", int64(1), int64(1)},
+			{"3", int64(1), int64(1)},
+			{"2", int64(3), int64(2)},
+		},
+	)
+	// running total, default frame is start of partition to current row
+	testutil.TestSelect(t, `SELECT order_id, sum(price) OVER (ORDER BY order_id) AS total FROM orders`,
+		[][]driver.Value{
+			{"1", float64(22.5)},
+			{"3", float64(82.5)},
+			{"2", float64(60)},
+		},
+	)
+	testutil.TestSelect(t, `SELECT order_id, sum(price) OVER (ORDER BY order_id ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) AS s2 FROM orders`,
+		[][]driver.Value{
+			{"1", float64(22.5)},
+			{"3", float64(60)},
+			{"2", float64(60)},
+		},
+	)
+	testutil.TestSelect(t, `SELECT order_id, count(*) OVER () AS ct FROM orders`,
+		[][]driver.Value{
+			{"1", int64(3)},
+			{"3", int64(3)},
+			{"2", int64(3)},
+		},
+	)
+	testutil.TestSelect(t, `SELECT order_id, lag(order_id) OVER (ORDER BY order_id) AS prev, lead(order_id, 1, "none") OVER (ORDER BY order_id) AS next FROM orders`,
+		[][]driver.Value{
+			{"1", nil, "2"},
+			{"3", "2", "none"},
+			{"2", "1", "3"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT order_id, first_value(order_id) OVER (PARTITION BY user_id ORDER BY order_id DESC) AS fv FROM orders WHERE price > 1`,
+		[][]driver.Value{
+			{"1", "2"},
+			{"3", "3"},
+			{"2", "2"},
+		},
+	)
+	// order by a window column
+	testutil.TestSelect(t, `SELECT order_id, sum(price) OVER (ORDER BY order_id ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) AS s2 FROM orders ORDER BY s2 DESC LIMIT 2`,
+		[][]driver.Value{
+			{"3", float64(60)},
+			{"2", float64(60)},
+		},
+	)
+
+	testutil.TestSelectErr(t, `SELECT user_id, rank() OVER (ORDER BY user_id) FROM orders GROUP BY user_id`, nil)
+	testutil.TestSelectErr(t, `SELECT sum(price) OVER (ORDER BY order_id RANGE BETWEEN 1 PRECEDING AND CURRENT ROW) FROM orders`, nil)
+	testutil.TestSelectErr(t, `SELECT tolower(user_id) OVER () FROM orders`, nil)
+	testutil.TestSelectErr(t, `SELECT rank(order_id) OVER () FROM orders`, nil)
+}
+
+func TestExecWith(t *testing.T) {
+	testutil.TestSelect(t, `WITH o AS (SELECT user_id, order_id FROM orders WHERE order_id > 1) SELECT user_id, order_id FROM o`,
+		[][]driver.Value{
+			{"abcabcabc", "3"},
+			{"9Ip1aKbeZe2njCDM", "2"},
+		},
+	)
+	// later ctes can read the earlier ones, column names from WITH
+	testutil.TestSelect(t, `WITH o (uid, oid) AS (SELECT user_id, order_id FROM orders), p AS (SELECT uid FROM o WHERE oid = 3) SELECT uid FROM p`,
+		[][]driver.Value{
+			{"abcabcabc"},
+		},
+	)
+	testutil.TestSelect(t, `WITH o AS (SELECT user_id FROM orders) SELECT user_id, count(*) AS ct FROM o GROUP BY user_id ORDER BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", int64(2)},
+			{"abcabcabc", int64(1)},
+		},
+	)
+	testutil.TestSelect(t, `WITH o AS (SELECT user_id FROM orders WHERE order_id = 3) SELECT order_id FROM orders WHERE user_id IN (SELECT user_id FROM o)`,
+		[][]driver.Value{
+			{"3"},
+		},
+	)
+	testutil.TestSelect(t, `WITH o AS (SELECT user_id, order_id FROM orders) SELECT order_id FROM o WHERE order_id = 1 UNION ALL SELECT order_id FROM o WHERE order_id = 2`,
+		[][]driver.Value{
+			{"1"},
+			{"2"},
+		},
+	)
+
+	mockcsv.LoadTable("categories", "id,parent_id,name\n1,0,root\n2,1,books\n3,1,music\n4,2,fiction\n5,4,scifi\n6,3,jazz\n7,9,orphan")
+
+	// walk the hierarchy below a category, children of each level are found
+	// by joining to the rows of the previous level
+	testutil.TestSelect(t, `
+		WITH RECURSIVE tree (id, name) AS (
+			SELECT id, name FROM categories WHERE id = 2
+			UNION ALL
+			SELECT c.id, c.name FROM categories AS c INNER JOIN tree AS t ON c.parent_id = t.id
+		) SELECT id, name FROM tree`,
+		[][]driver.Value{
+			{"2", "books"},
+			{"4", "fiction"},
+			{"5", "scifi"},
+		},
+	)
+	testutil.TestSelect(t, `
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = 1
+			UNION ALL
+			SELECT c.id FROM categories AS c INNER JOIN tree AS t ON c.parent_id = t.id
+		) SELECT count(*) AS ct FROM tree`,
+		[][]driver.Value{
+			{int64(6)},
+		},
+	)
+	// ancestors of a category
+	testutil.TestSelect(t, `
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id, name FROM categories WHERE name = "scifi"
+			UNION
+			SELECT c.id, c.parent_id, c.name FROM categories AS c INNER JOIN up AS u ON c.id = u.parent_id
+		) SELECT name FROM up`,
+		[][]driver.Value{
+			{"scifi"},
+			{"fiction"},
+			{"books"},
+			{"root"},
+		},
+	)
+
+	testutil.TestSelectErr(t, `WITH o (a, b, c) AS (SELECT user_id FROM orders) SELECT a FROM o`, nil)
+	testutil.TestSelectErr(t, `WITH o AS (SELECT user_id FROM orders), o AS (SELECT user_id FROM orders) SELECT user_id FROM o`, nil)
+	testutil.TestSelectErr(t, `WITH RECURSIVE t AS (SELECT id FROM categories INTERSECT SELECT c.id FROM categories AS c INNER JOIN t ON c.parent_id = t.id) SELECT id FROM t`, nil)
+	testutil.TestSelectErr(t, `WITH RECURSIVE t AS (SELECT id FROM categories UNION ALL SELECT c.id, c.name FROM categories AS c INNER JOIN t ON c.parent_id = t.id) SELECT id FROM t`, nil)
+}
+
+func TestExecCase(t *testing.T) {
+	testutil.TestSelect(t, `SELECT order_id,
+			CASE WHEN order_id > 2 THEN "late" WHEN order_id = 2 THEN "mid" ELSE "early" END AS period,
+			CASE user_id WHEN "abcabcabc" THEN "abc" END AS abc
+		FROM orders ORDER BY order_id`,
+		[][]driver.Value{
+			{"1", "early", nil},
+			{"2", "mid", nil},
+			{"3", "late", "abc"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT order_id FROM orders WHERE CASE user_id WHEN "abcabcabc" THEN true ELSE order_id = 1 END ORDER BY order_id`,
+		[][]driver.Value{
+			{"1"},
+			{"3"},
+		},
+	)
+	// conditional aggregate
+	testutil.TestSelect(t, `SELECT user_id, sum(CASE WHEN order_id > 1 THEN 1 ELSE 0 END) AS ct FROM orders GROUP BY user_id ORDER BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", float64(1)},
+			{"abcabcabc", float64(1)},
+		},
+	)
+	// an aggregate inside the CASE makes it an aggregate query, which is
+	// not supported, rather than a CASE evaluated for each row
+	testutil.TestSelectErr(t, `SELECT CASE WHEN count(*) > 2 THEN "many" ELSE "few" END AS c FROM orders`, nil)
+	testutil.TestSelectErr(t, `SELECT user_id, CASE WHEN count(*) > 1 THEN "many" END AS c FROM orders GROUP BY user_id`, nil)
+}
+
+func TestExecNullHandling(t *testing.T) {
+	testutil.TestSelect(t, `SELECT user_id, coalesce(nullif(interests, "fishing"), "fisher") AS i, nullif(referral_count, 82) AS rc
+		FROM users ORDER BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM", "fisher", nil},
+			{"hT2impsOPUREcVPc", "swimming", "12"},
+			{"hT2impsabc345c", "fisher", "12"},
+		},
+	)
+	// NOT NULL is NULL, so does not match
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE NOT (nullif(referral_count, 82) > 50) ORDER BY user_id`,
+		[][]driver.Value{
+			{"hT2impsOPUREcVPc"},
+			{"hT2impsabc345c"},
+		},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE interests IS NULL OR nullif(referral_count, 82) IS NULL ORDER BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+			{"hT2impsabc345c"},
+		},
+	)
+	// = NULL and != NULL are NULL, so never match
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE interests = NULL OR referral_count != NULL`,
+		[][]driver.Value{},
+	)
+	testutil.TestSelect(t, `SELECT user_id FROM users WHERE interests IS NOT NULL ORDER BY user_id`,
+		[][]driver.Value{
+			{"9Ip1aKbeZe2njCDM"},
+			{"hT2impsOPUREcVPc"},
+		},
+	)
+
+	// empty values are NULL to every operator, function and join
+	mockcsv.LoadTable("null_nums", "k,a,b\n1,1,\n2,,x\n3,3,y")
+	mockcsv.LoadTable("null_names", "b,name\nx,ex\n,empty")
+	tests := []struct {
+		sql  string
+		rows [][]driver.Value
+	}{
+		{`SELECT k FROM null_nums WHERE b IS NULL`, [][]driver.Value{{"1"}}},
+		{`SELECT k FROM null_nums WHERE a IS NOT NULL ORDER BY k`, [][]driver.Value{{"1"}, {"3"}}},
+		{`SELECT k FROM null_nums WHERE b IS NULL AND coalesce(b, "d") = "d"`, [][]driver.Value{{"1"}}},
+		{`SELECT k FROM null_nums WHERE a != 3`, [][]driver.Value{{"1"}}},
+		{`SELECT k FROM null_nums WHERE NOT (a = 3)`, [][]driver.Value{{"1"}}},
+		{`SELECT k FROM null_nums WHERE b = ""`, [][]driver.Value{}},
+		{`SELECT k, coalesce(b, "d") AS b, ifnull(a, "0") AS a, nullif(b, "y") AS n FROM null_nums ORDER BY k`,
+			[][]driver.Value{{"1", "d", "1", nil}, {"2", "x", "0", "x"}, {"3", "y", "3", nil}}},
+		{`SELECT k, CASE b WHEN "" THEN "e" WHEN "x" THEN "x" ELSE "o" END AS c FROM null_nums ORDER BY k`,
+			[][]driver.Value{{"1", "o"}, {"2", "x"}, {"3", "o"}}},
+		{`SELECT count(b), count(*) FROM null_nums`, [][]driver.Value{{int64(2), int64(3)}}},
+		// empty join keys match nothing, the right side columns are missing
+		{`SELECT n.k, m.name FROM null_nums AS n LEFT JOIN null_names AS m ON n.b = m.b`,
+			[][]driver.Value{{"1", nil}, {"3", nil}, {"2", "ex"}}},
+		{`SELECT n.k, m.name FROM null_nums AS n INNER JOIN null_names AS m ON n.b = m.b`,
+			[][]driver.Value{{"2", "ex"}}},
+		// an empty value in the sub-query makes NOT IN unknown
+		{`SELECT k FROM null_nums WHERE b IN (SELECT b FROM null_names)`, [][]driver.Value{{"2"}}},
+		{`SELECT k FROM null_nums WHERE b NOT IN (SELECT b FROM null_names)`, [][]driver.Value{}},
+	}
+	for _, test := range tests {
+		testutil.TestSelect(t, test.sql, test.rows)
+	}
+}
+
+func TestExecContextCancel(t *testing.T) {
+	// own table, as a scan stopped part way leaves the mockcsv cursor there
+	mockcsv.LoadTable("cancel_event", "id,user_id,event\n1,abcabcabc,signup\n2,abcabcabc,logon\n3,9Ip1aKbeZe2njCDM,signup")
+
+	// a cancelled query stops, and returns the context error
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx := td.TestContext("SELECT user_id, event FROM cancel_event WHERE user_id IS NOT NULL")
+	ctx.Context = c
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Tf(t, err == nil, "%v", err)
+
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	err = job.Setup()
+	assert.T(t, err == nil)
+	err = job.Run()
+	assert.Tf(t, err == context.Canceled, "expected cancelled but got %v", err)
+
+	// past deadline
+	c, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	ctx = td.TestContext("SELECT user_id, count(*) FROM cancel_event GROUP BY user_id")
+	ctx.Context = c
+	job, err = exec.BuildSqlJob(ctx)
+	assert.Tf(t, err == nil, "%v", err)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	err = job.Setup()
+	assert.T(t, err == nil)
+	err = job.Run()
+	assert.Tf(t, err == context.DeadlineExceeded, "expected deadline exceeded but got %v", err)
+}
+
+func TestExecMemoryLimit(t *testing.T) {
+	// own tables, as a query that fails part way leaves the mockcsv cursor there
+	csv := "id,name\n"
+	for i := 0; i < 200; i++ {
+		csv += fmt.Sprintf("%d,name%03d\n", i, (i*7)%200)
+	}
+	for _, tbl := range []string{"mem_buffer", "mem_sort", "mem_window"} {
+		mockcsv.LoadTable(tbl, csv)
+	}
+
+	// buffered results over the query's limit
+	ctx := td.TestContext("SELECT id, name FROM mem_buffer")
+	ctx.Memory = plan.NewMemoryAccount(2048, nil)
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Tf(t, err == nil, "%v", err)
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	err = job.Setup()
+	assert.T(t, err == nil)
+	err = job.Run()
+	_, isMemErr := err.(*plan.MemoryLimitError)
+	assert.Tf(t, isMemErr, "expected memory limit error but got %v", err)
+	assert.Tf(t, ctx.Memory.Used() == 0, "expected memory released but %d in use", ctx.Memory.Used())
+
+	plan.QueryMemoryLimit = 2048
+	defer func() { plan.QueryMemoryLimit = 0 }()
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	// sort spills to disk instead of failing
+	rows, err := db.Query("SELECT name FROM mem_sort ORDER BY name")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	ct := 0
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		assert.Tf(t, name == fmt.Sprintf("name%03d", ct), "expected sorted row %d but got %v", ct, name)
+		ct++
+	}
+	assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+	assert.Tf(t, ct == 200, "expected 200 rows but got %d", ct)
+
+	// window functions buffer all rows, and can't spill
+	rows, err = db.Query("SELECT name, row_number() OVER (ORDER BY name) AS rn FROM mem_window")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	for rows.Next() {
+	}
+	_, isMemErr = rows.Err().(*plan.MemoryLimitError)
+	assert.Tf(t, isMemErr, "expected memory limit error but got %v", rows.Err())
+	rows.Close()
+}
+
+func TestExecExplain(t *testing.T) {
+	mockcsv.LoadTable("explain_users", "user_id,name\n1,aaron\n2,bob\n3,carla\n4,dan")
+	mockcsv.LoadTable("explain_orders", "order_id,user_id,price\n10,1,5.5\n11,2,20\n12,2,30")
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	type explainRow struct {
+		id, parent        sql.NullInt64
+		op                string
+		src, pred         sql.NullString
+		est, in, out, byt sql.NullInt64
+		elapsed           sql.NullFloat64
+	}
+	explain := func(sqlText string, analyze bool) []*explainRow {
+		rows, err := db.Query(sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		cols, _ := rows.Columns()
+		if analyze {
+			assert.Equal(t, []string{"id", "parent_id", "operator", "source", "predicate", "est_rows",
+				"rows_in", "rows_out", "elapsed_ms", "bytes"}, cols)
+		} else {
+			assert.Equal(t, []string{"id", "parent_id", "operator", "source", "predicate", "est_rows"}, cols)
+		}
+		var ers []*explainRow
+		for rows.Next() {
+			r := &explainRow{}
+			dest := []interface{}{&r.id, &r.parent, &r.op, &r.src, &r.pred, &r.est}
+			if analyze {
+				dest = append(dest, &r.in, &r.out, &r.elapsed, &r.byt)
+			}
+			err = rows.Scan(dest...)
+			assert.Tf(t, err == nil, "no error: %v", err)
+			r.op = strings.TrimSpace(r.op)
+			ers = append(ers, r)
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		return ers
+	}
+	find := func(ers []*explainRow, op, src string) *explainRow {
+		for _, r := range ers {
+			if r.op == op && r.src.String == src {
+				return r
+			}
+		}
+		t.Fatalf("no %s %s in explain %v", op, src, ers)
+		return nil
+	}
+
+	ers := explain("EXPLAIN SELECT name FROM explain_users WHERE user_id > 1 LIMIT 2", false)
+	assert.Tf(t, len(ers) >= 2, "expected tasks but got %v", ers)
+	assert.Tf(t, !ers[0].parent.Valid && ers[0].op == "Projection", "expected projection root %#v", ers[0])
+	assert.Tf(t, ers[0].est.Int64 == 2, "expected limit estimate %#v", ers[0])
+	src := find(ers, "Source", "explain_users")
+	assert.Tf(t, src.est.Valid && src.est.Int64 == 4, "expected 4 row estimate %#v", src)
+	where := find(ers, "Where", "")
+	assert.Tf(t, where.pred.String == "user_id > 1", "expected predicate %#v", where)
+
+	ers = explain(`EXPLAIN ANALYZE SELECT u.name, o.price
+		FROM explain_users AS u INNER JOIN explain_orders AS o ON u.user_id = o.user_id`, true)
+	join := find(ers, "Join", "explain_orders")
+	assert.Tf(t, join.pred.String == "u.user_id = o.user_id", "expected join expr %#v", join)
+	assert.Tf(t, join.in.Int64 == 7 && join.out.Int64 == 3, "expected 7 rows in 3 out %#v", join)
+	src = find(ers, "Source", "explain_users AS u")
+	assert.Tf(t, !src.in.Valid && src.out.Int64 == 4 && src.byt.Int64 > 0, "expected 4 rows out %#v", src)
+	assert.Tf(t, ers[0].out.Int64 == 3 && ers[0].elapsed.Valid, "expected 3 result rows %#v", ers[0])
+}
+
 func TestExecInsert(t *testing.T) {
 
 	//mockSchema, _ = registry.Schema("mockcsv")
@@ -527,3 +1558,327 @@ func testSubselect(t *testing.T) {
 	assert.Tf(t, err == nil, "no error %v", err)
 	assert.Tf(t, len(msgs) == 1, "should have filtered out 2 messages")
 }
+
+func TestExecPushdown(t *testing.T) {
+	// memdb looks up rows by primary key for pushed down filters
+	if datasource.DataSourcesRegistry().Get("pushdown_users") == nil {
+		mdb, err := memdb.NewMemDb("pushdown_users", []string{"user_id", "name", "age"})
+		assert.Tf(t, err == nil, "no error: %v", err)
+		conn, err := mdb.Open("pushdown_users")
+		assert.Tf(t, err == nil, "no error: %v", err)
+		for i, name := range []string{"aaron", "bob", "carla", "dan"} {
+			_, err = conn.(schema.ConnUpsert).Put(context.Background(), nil, []driver.Value{int64(i + 1), name, int64(20 + i*10)})
+			assert.Tf(t, err == nil, "no error: %v", err)
+		}
+		datasource.Register("pushdown_users", mdb)
+	}
+	// string primary keys, some of them numeric
+	if datasource.DataSourcesRegistry().Get("pushdown_codes") == nil {
+		mdb, err := memdb.NewMemDb("pushdown_codes", []string{"code", "name"})
+		assert.Tf(t, err == nil, "no error: %v", err)
+		conn, err := mdb.Open("pushdown_codes")
+		assert.Tf(t, err == nil, "no error: %v", err)
+		for _, row := range [][]driver.Value{{"1", "one"}, {"02", "two"}, {"3", "three"}, {"x", "ex"}} {
+			_, err = conn.(schema.ConnUpsert).Put(context.Background(), nil, row)
+			assert.Tf(t, err == nil, "no error: %v", err)
+		}
+		datasource.Register("pushdown_codes", mdb)
+	}
+
+	db, err := sql.Open("qlbridge", "pushdown_users")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	codesDb, err := sql.Open("qlbridge", "pushdown_codes")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer codesDb.Close()
+
+	query := func(db *sql.DB, sqlText string) []string {
+		rows, err := db.Query(sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		var names []string
+		for rows.Next() {
+			var name string
+			assert.Tf(t, rows.Scan(&name) == nil, "no error")
+			names = append(names, name)
+		}
+		return names
+	}
+	names := func(sqlText string) []string { return query(db, sqlText) }
+	assert.Equal(t, []string{"carla"}, names("SELECT name FROM pushdown_users WHERE user_id = 3"))
+	assert.Equal(t, []string{"dan"}, names("SELECT name FROM pushdown_users WHERE user_id IN (1, 4) AND age > 30"))
+	assert.Equal(t, 0, len(names("SELECT name FROM pushdown_users WHERE user_id IN (1, 2) AND user_id = 3")))
+	assert.Equal(t, []string{"carla", "dan"}, names("SELECT name FROM pushdown_users WHERE age > 30"))
+
+	// a filter the source handles returns the same rows as the vm evaluating
+	// it, the OR keeps the filter from being pushed down
+	sameRows := func(db *sql.DB, table, where, never string) {
+		pushed := query(db, fmt.Sprintf("SELECT name FROM %s WHERE %s", table, where))
+		evaluated := query(db, fmt.Sprintf("SELECT name FROM %s WHERE %s OR %s", table, where, never))
+		sort.Strings(pushed)
+		sort.Strings(evaluated)
+		assert.Tf(t, len(evaluated) > 0, "expected rows for %s", where)
+		assert.Equalf(t, evaluated, pushed, "rows for %s", where)
+	}
+	for _, where := range []string{`user_id = 2`, `user_id = "02"`, `user_id = "2"`, `user_id = 2.0`, `user_id IN (1, "03")`} {
+		sameRows(db, "pushdown_users", where, "user_id = 99")
+	}
+	for _, where := range []string{`code = "02"`, `code = 2`, `code = "x"`, `code IN (1, "3")`, `code IN ("1", "3")`} {
+		sameRows(codesDb, "pushdown_codes", where, `code = "none"`)
+	}
+
+	// the source only reads the rows of the keys, the where filters the rest
+	rows, err := db.Query("EXPLAIN ANALYZE SELECT name FROM pushdown_users WHERE user_id IN (1, 4) AND age > 30")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer rows.Close()
+	wheres := 0
+	for rows.Next() {
+		var id, est, in, out, byt sql.NullInt64
+		var parent sql.NullInt64
+		var op string
+		var src, pred sql.NullString
+		var elapsed sql.NullFloat64
+		err = rows.Scan(&id, &parent, &op, &src, &pred, &est, &in, &out, &elapsed, &byt)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		switch strings.TrimSpace(op) {
+		case "Source":
+			assert.Equal(t, "user_id IN (1,4)", pred.String)
+			assert.Tf(t, out.Int64 == 2, "expected 2 rows from source %v", out)
+		case "Where":
+			assert.Equal(t, "age > 30", pred.String)
+			wheres++
+		}
+	}
+	assert.Tf(t, wheres > 0, "expected where of the residual filter")
+}
+
+// a source of several memdb tables
+type memdbTables map[string]*memdb.MemDb
+
+func (m memdbTables) Tables() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+func (m memdbTables) Open(table string) (schema.Conn, error) { return m[table].Open(table) }
+func (m memdbTables) Table(table string) (*schema.Table, error) {
+	return m[table].Table(table)
+}
+func (m memdbTables) Close() error { return nil }
+
+func TestExecColumnPruning(t *testing.T) {
+	if datasource.DataSourcesRegistry().Get("prune_db") == nil {
+		tables := make(memdbTables)
+		load := func(name string, cols []string, rows [][]driver.Value) {
+			mdb, err := memdb.NewMemDbData(name, rows, cols)
+			assert.Tf(t, err == nil, "no error: %v", err)
+			tables[name] = mdb
+		}
+		load("prune_users", []string{"user_id", "name", "email", "age"}, [][]driver.Value{
+			{int64(1), "aaron", "aaron@email.com", int64(30)},
+			{int64(2), "bob", "bob@email.com", int64(40)},
+			{int64(3), "carla", "carla@email.com", int64(50)},
+		})
+		load("prune_orders", []string{"order_id", "user_id", "price", "note"}, [][]driver.Value{
+			{int64(10), int64(1), 5.5, "a"},
+			{int64(11), int64(2), 20.0, "b"},
+			{int64(12), int64(2), 30.0, "c"},
+		})
+		datasource.Register("prune_db", tables)
+	}
+	s, ok := datasource.DataSourcesRegistry().Schema("prune_db")
+	assert.T(t, ok)
+
+	// columns each source was asked for
+	needed := func(sqlText string) map[string][]string {
+		ctx := plan.NewContext(sqlText)
+		ctx.Schema = s
+		stmt, err := rel.ParseSql(sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		pln, err := plan.WalkStmt(ctx, stmt, plan.NewPlanner(ctx))
+		assert.Tf(t, err == nil, "no error: %v", err)
+		cols := make(map[string][]string)
+		var walk func(task plan.Task)
+		walk = func(task plan.Task) {
+			if src, ok := task.(*plan.Source); ok && src.Conn != nil {
+				cols[src.Stmt.Name] = src.Needed
+			}
+			if jm, ok := task.(*plan.JoinMerge); ok {
+				walk(jm.Left)
+				walk(jm.Right)
+			}
+			for _, c := range task.Children() {
+				walk(c)
+			}
+		}
+		walk(pln)
+		return cols
+	}
+	assert.Equal(t, []string{"name", "age"}, needed("SELECT name FROM prune_users WHERE age > 35")["prune_users"])
+	assert.Equal(t, []string{"name", "age"}, needed("SELECT name, count(*) FROM prune_users GROUP BY name ORDER BY avg(age)")["prune_users"])
+	assert.Equal(t, []string{}, needed("SELECT count(*) FROM prune_users")["prune_users"])
+	assert.Equal(t, 0, len(needed("SELECT * FROM prune_users")["prune_users"]))
+	assert.T(t, needed("SELECT * FROM prune_users")["prune_users"] == nil)
+	joined := needed(`SELECT u.name, o.price FROM prune_users AS u
+		INNER JOIN prune_orders AS o ON u.user_id = o.user_id WHERE o.price > 10`)
+	assert.Equal(t, []string{"user_id", "name"}, joined["prune_users"])
+	assert.Equal(t, []string{"user_id", "price"}, joined["prune_orders"])
+
+	db, err := sql.Open("qlbridge", "prune_db")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	query := func(sqlText string) []string {
+		rows, err := db.Query(sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		cols, _ := rows.Columns()
+		var out []string
+		for rows.Next() {
+			vals := make([]interface{}, len(cols))
+			dest := make([]interface{}, len(cols))
+			for i := range vals {
+				dest[i] = &vals[i]
+			}
+			assert.Tf(t, rows.Scan(dest...) == nil, "no error")
+			parts := make([]string, len(vals))
+			for i, v := range vals {
+				if b, ok := v.([]byte); ok {
+					v = string(b)
+				}
+				parts[i] = fmt.Sprintf("%v", v)
+			}
+			out = append(out, strings.Join(parts, ","))
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		return out
+	}
+	assert.Equal(t, []string{"bob", "carla"}, query("SELECT name FROM prune_users WHERE age > 35"))
+	assert.Equal(t, []string{"3"}, query("SELECT count(*) FROM prune_users"))
+	assert.Equal(t, []string{"carla", "bob"}, query("SELECT name FROM prune_users ORDER BY age DESC LIMIT 2"))
+	joinRows := query(`SELECT u.name, o.price FROM prune_users AS u
+		INNER JOIN prune_orders AS o ON u.user_id = o.user_id WHERE o.price > 10`)
+	sort.Strings(joinRows)
+	assert.Equal(t, []string{"bob,20", "bob,30"}, joinRows)
+}
+
+// a table of memdb partitions, all is the whole table
+type memdbPartitioned struct {
+	name  string
+	all   *memdb.MemDb
+	parts map[string]*memdb.MemDb
+	ids   []string
+}
+
+func (m *memdbPartitioned) Tables() []string                       { return []string{m.name} }
+func (m *memdbPartitioned) Open(table string) (schema.Conn, error) { return m.all.Open(table) }
+func (m *memdbPartitioned) Table(table string) (*schema.Table, error) {
+	return m.all.Table(table)
+}
+func (m *memdbPartitioned) Close() error { return nil }
+func (m *memdbPartitioned) Partitions() []*schema.Partition {
+	parts := make([]*schema.Partition, len(m.ids))
+	for i, id := range m.ids {
+		parts[i] = &schema.Partition{Id: id}
+	}
+	return parts
+}
+func (m *memdbPartitioned) PartitionSource(p *schema.Partition) (schema.Conn, error) {
+	return m.parts[p.Id].Open(m.name)
+}
+
+func TestExecPartitionScan(t *testing.T) {
+	if datasource.DataSourcesRegistry().Get("partition_db") == nil {
+		cols := []string{"order_id", "user_id", "price"}
+		src := &memdbPartitioned{name: "partition_orders", parts: make(map[string]*memdb.MemDb)}
+		var all [][]driver.Value
+		for p, id := range []string{"p0", "p1", "p2"} {
+			var rows [][]driver.Value
+			for i := 0; i < 4; i++ {
+				orderID := int64(p*4 + i + 1)
+				rows = append(rows, []driver.Value{orderID, orderID % 3, float64(orderID)})
+			}
+			mdb, err := memdb.NewMemDbData(src.name, rows, cols)
+			assert.Tf(t, err == nil, "no error: %v", err)
+			src.parts[id] = mdb
+			src.ids = append(src.ids, id)
+			all = append(all, rows...)
+		}
+		mdb, err := memdb.NewMemDbData(src.name, all, cols)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		src.all = mdb
+		datasource.Register("partition_db", src)
+	}
+
+	db, err := sql.Open("qlbridge", "partition_db")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	query := func(sqlText string) []string {
+		rows, err := db.Query(sqlText)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		cols, _ := rows.Columns()
+		var out []string
+		for rows.Next() {
+			vals := make([]interface{}, len(cols))
+			dest := make([]interface{}, len(cols))
+			for i := range vals {
+				dest[i] = &vals[i]
+			}
+			assert.Tf(t, rows.Scan(dest...) == nil, "no error")
+			parts := make([]string, len(vals))
+			for i, v := range vals {
+				parts[i] = fmt.Sprintf("%v", v)
+			}
+			out = append(out, strings.Join(parts, ","))
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		return out
+	}
+
+	// 12 orders, 4 in each partition, user_id = order_id % 3
+	assert.Equal(t, []string{"12,78"}, query("SELECT count(*), sum(price) FROM partition_orders"))
+	assert.Equal(t, []string{"6,57"}, query("SELECT count(*), sum(price) FROM partition_orders WHERE price > 6"))
+	byUser := query("SELECT user_id, count(*), max(price) FROM partition_orders GROUP BY user_id")
+	sort.Strings(byUser)
+	assert.Equal(t, []string{"0,4,12", "1,4,10", "2,4,11"}, byUser)
+	assert.Equal(t, []string{"11", "8", "5", "2"}, query("SELECT order_id FROM partition_orders WHERE user_id = 2 ORDER BY order_id DESC"))
+	assert.Equal(t, []string{"12", "11"}, query("SELECT order_id FROM partition_orders ORDER BY price DESC LIMIT 2"))
+	assert.Equal(t, 12, len(query("SELECT order_id FROM partition_orders")))
+	// aggregate after a semi-join is of the merged rows
+	assert.Equal(t, []string{"2"}, query(`SELECT count(*) FROM partition_orders
+		WHERE order_id IN (SELECT user_id FROM partition_orders WHERE price < 5)`))
+
+	// each partition is scanned, filtered and partially grouped then merged
+	rows, err := db.Query("EXPLAIN ANALYZE SELECT user_id, count(*) FROM partition_orders WHERE price > 2 GROUP BY user_id")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer rows.Close()
+	ops := make(map[string]int)
+	var sources []string
+	for rows.Next() {
+		var id, est, in, out, byt sql.NullInt64
+		var parent sql.NullInt64
+		var op string
+		var src, pred sql.NullString
+		var elapsed sql.NullFloat64
+		err = rows.Scan(&id, &parent, &op, &src, &pred, &est, &in, &out, &elapsed, &byt)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		op = strings.TrimSpace(op)
+		ops[op]++
+		switch op {
+		case "Source":
+			sources = append(sources, src.String)
+			assert.Tf(t, out.Int64 == 4, "expected 4 rows from each partition %v", out)
+		case "PartitionMerge":
+			assert.Tf(t, in.Int64 == 8, "expected partial groups of partitions %v", in)
+		case "GroupByFinal":
+			assert.Tf(t, in.Int64 == 8 && out.Int64 == 3, "expected 3 groups %v %v", in, out)
+		}
+	}
+	assert.Equal(t, 3, ops["GroupByPartial"])
+	assert.Equal(t, 1, ops["GroupByFinal"])
+	sort.Strings(sources)
+	assert.Equal(t, []string{"partition_orders PARTITION (p0)", "partition_orders PARTITION (p1)",
+		"partition_orders PARTITION (p2)"}, sources)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
@@ -40,6 +42,9 @@ func NewExecutor(ctx *plan.Context, planner plan.Planner) *JobExecutor {
 	e.Executor = e
 	e.Planner = planner
 	e.Ctx = ctx
+	if ctx != nil && ctx.Memory == nil {
+		ctx.Memory = plan.NewMemoryAccount(plan.QueryMemoryLimit, plan.GlobalMemory)
+	}
 	return e
 }
 
@@ -58,18 +63,23 @@ func BuildSqlJob(ctx *plan.Context) (*JobExecutor, error) {
 }
 
 // Create Job made up of sub-tasks in DAG that is the
-//  plan for execution of this query/job
+//  plan for execution of this query/job, the ctx.Raw is parsed unless
+//  the ctx.Stmt already is (a prepared statement)
 func BuildSqlJobPlanned(planner plan.Planner, executor Executor, ctx *plan.Context) (Task, error) {
 
-	stmt, err := rel.ParseSql(ctx.Raw)
-	if err != nil {
-		u.Debugf("could not parse sql : %v", err)
-		return nil, err
-	}
+	stmt := ctx.Stmt
 	if stmt == nil {
-		return nil, fmt.Errorf("Not statement for parse? %v", ctx.Raw)
+		var err error
+		stmt, err = rel.ParseSql(ctx.Raw)
+		if err != nil {
+			u.Debugf("could not parse sql : %v", err)
+			return nil, err
+		}
+		if stmt == nil {
+			return nil, fmt.Errorf("Not statement for parse? %v", ctx.Raw)
+		}
+		ctx.Stmt = stmt
 	}
-	ctx.Stmt = stmt
 
 	if ctx.Schema == nil {
 		u.LogTraceDf(u.WARN, 12, "no schema? %s", ctx.Raw)
@@ -128,10 +138,18 @@ func (m *JobExecutor) WalkPlan(p plan.Task) (Task, error) {
 			p.Stmt.SetSystemQry()
 		}
 		return m.Executor.WalkSelect(p)
+	case *plan.SetOperation:
+		return m.Executor.WalkSetOperation(p)
+	case *plan.With:
+		return m.Executor.WalkWith(p)
+	case *plan.Explain:
+		return m.Executor.WalkExplain(p)
 	case *plan.Upsert:
 		return m.Executor.WalkUpsert(p)
 	case *plan.Insert:
 		return m.Executor.WalkInsert(p)
+	case *plan.Into:
+		return m.Executor.WalkInto(p)
 	case *plan.Update:
 		return m.Executor.WalkUpdate(p)
 	case *plan.Delete:
@@ -148,14 +166,106 @@ func (m *JobExecutor) WalkSelect(p *plan.Select) (Task, error) {
 	root := m.NewTask(p)
 	return root, m.WalkChildren(p, root)
 }
+func (m *JobExecutor) WalkSetOperation(p *plan.SetOperation) (Task, error) {
+	// each side is its own job, with its own context
+	inputs := make([]TaskRunner, 0, 2)
+	for _, input := range []plan.Task{p.Left, p.Right} {
+		tr, err := m.walkStatement(input)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, tr)
+	}
+	root := m.NewTask(p)
+	if err := root.Add(NewSetOperation(m.Ctx, p, inputs[0], inputs[1])); err != nil {
+		return nil, err
+	}
+	if err := m.WalkChildren(p, root); err != nil {
+		return nil, err
+	}
+	if p.Stmt.Limit > 0 || p.Stmt.Offset > 0 {
+		lim := NewProjectionLimit(m.Ctx, plan.NewProjectionInProcess(p.Result))
+		if err := root.Add(lim); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+func (m *JobExecutor) WalkWith(p *plan.With) (Task, error) {
+	// each cte, and the statement using them, is its own job
+	anchors := make([]TaskRunner, len(p.Ctes))
+	for i, cte := range p.Ctes {
+		tr, err := m.walkStatement(cte.Anchor)
+		if err != nil {
+			return nil, err
+		}
+		anchors[i] = tr
+	}
+	main, err := m.walkStatement(p.Main)
+	if err != nil {
+		return nil, err
+	}
+	root := m.NewTask(p)
+	return root, root.Add(NewWith(m.Ctx, p, anchors, main, m.walkStatement))
+}
+
+func (m *JobExecutor) WalkExplain(p *plan.Explain) (Task, error) {
+	// the explained statement is its own job, only run for ANALYZE
+	job, err := m.walkStatement(p.Plan)
+	if err != nil {
+		return nil, err
+	}
+	root := m.NewTask(p)
+	return root, root.Add(NewExplain(m.Ctx, p, job))
+}
+
+// walkStatement create the job of a planned statement, which was
+// planned with its own context
+func (m *JobExecutor) walkStatement(p plan.Task) (TaskRunner, error) {
+	var ctx *plan.Context
+	switch pt := p.(type) {
+	case *plan.Select:
+		ctx = pt.Ctx
+	case *plan.SetOperation:
+		ctx = pt.Ctx
+	case *plan.With:
+		ctx = pt.Ctx
+	}
+	t, err := NewExecutor(ctx, m.Planner).WalkPlan(p)
+	if err != nil {
+		return nil, err
+	}
+	tr, ok := t.(TaskRunner)
+	if !ok {
+		return nil, fmt.Errorf("Expected TaskRunner but was %T", t)
+	}
+	return tr, nil
+}
 func (m *JobExecutor) WalkUpsert(p *plan.Upsert) (Task, error) {
 	root := m.NewTask(p)
 	return root, root.Add(NewUpsert(m.Ctx, p))
 }
 func (m *JobExecutor) WalkInsert(p *plan.Insert) (Task, error) {
 	root := m.NewTask(p)
+	if p.Select != nil {
+		// the select is its own job, its rows are written by the insert
+		input, err := m.walkStatement(p.Select)
+		if err != nil {
+			return nil, err
+		}
+		return root, root.Add(NewInto(m.Ctx, p.Source, input, p.Columns, p.ColPos))
+	}
 	return root, root.Add(NewInsert(m.Ctx, p))
 }
+func (m *JobExecutor) WalkInto(p *plan.Into) (Task, error) {
+	// the select is its own job, its rows are written to the into table
+	input, err := m.walkStatement(p.Select)
+	if err != nil {
+		return nil, err
+	}
+	root := m.NewTask(p)
+	return root, root.Add(NewInto(m.Ctx, p.Source, input, p.Columns, p.ColPos))
+}
 func (m *JobExecutor) WalkUpdate(p *plan.Update) (Task, error) {
 	root := m.NewTask(p)
 	return root, root.Add(NewUpdate(m.Ctx, p))
@@ -188,7 +298,7 @@ func (m *JobExecutor) WalkSource(p *plan.Source) (Task, error) {
 			u.Warnf("no datasource")
 			return nil, fmt.Errorf("missing data source")
 		}
-		source, err := p.DataSource.Open(p.Stmt.SourceName())
+		source, err := m.Ctx.OpenConn(p.DataSource, p.Stmt.SourceName())
 		if err != nil {
 			return nil, err
 		}
@@ -209,7 +319,7 @@ func (m *JobExecutor) WalkSourceExec(p *plan.Source) (Task, error) {
 			u.Warnf("no datasource")
 			return nil, fmt.Errorf("missing data source")
 		}
-		source, err := p.DataSource.Open(p.Stmt.SourceName())
+		source, err := m.Ctx.OpenConn(p.DataSource, p.Stmt.SourceName())
 		if err != nil {
 			return nil, err
 		}
@@ -231,8 +341,28 @@ func (m *JobExecutor) WalkHaving(p *plan.Having) (Task, error) {
 	return NewHaving(m.Ctx, p), nil
 }
 func (m *JobExecutor) WalkGroupBy(p *plan.GroupBy) (Task, error) {
+	// columns that can't be aggregated are an error now, not once run
+	if _, err := buildAggs(p); err != nil {
+		return nil, err
+	}
+	if p.Final {
+		return NewGroupByFinal(m.Ctx, p), nil
+	}
 	return NewGroupBy(m.Ctx, p), nil
 }
+func (m *JobExecutor) WalkOrderBy(p *plan.OrderBy) (Task, error) {
+	return NewOrderBy(m.Ctx, p), nil
+}
+func (m *JobExecutor) WalkDistinct(p *plan.Distinct) (Task, error) {
+	return NewDistinct(m.Ctx, p), nil
+}
+func (m *JobExecutor) WalkWindow(p *plan.Window) (Task, error) {
+	w, err := NewWindow(m.Ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
 func (m *JobExecutor) WalkProjection(p *plan.Projection) (Task, error) {
 	return NewProjection(m.Ctx, p), nil
 }
@@ -249,6 +379,18 @@ func (m *JobExecutor) WalkJoin(p *plan.JoinMerge) (Task, error) {
 		u.Errorf("whoops %T  %v", l, err)
 		return nil, err
 	}
+	if p.RightFrom.Seekable {
+		// lookup join, the right source is read by key, not scanned
+		if src, ok := p.Right.(*plan.Source); ok && src.SeekType != value.UnknownType {
+			if seeker, ok := src.Conn.(schema.ConnSeeker); ok {
+				err = execTask.Add(NewJoinLookup(m.Ctx, l.(TaskRunner), seeker, p))
+				if err != nil {
+					return nil, err
+				}
+				return execTask, nil
+			}
+		}
+	}
 	r, err := m.WalkPlanAll(p.Right)
 	if err != nil {
 		return nil, err
@@ -258,7 +400,7 @@ func (m *JobExecutor) WalkJoin(p *plan.JoinMerge) (Task, error) {
 		return nil, err
 	}
 
-	jm := NewJoinNaiveMerge(m.Ctx, l.(TaskRunner), r.(TaskRunner), p)
+	jm := NewJoinMerge(m.Ctx, l.(TaskRunner), r.(TaskRunner), p)
 	err = execTask.Add(jm)
 	if err != nil {
 		return nil, err
@@ -268,6 +410,41 @@ func (m *JobExecutor) WalkJoin(p *plan.JoinMerge) (Task, error) {
 func (m *JobExecutor) WalkJoinKey(p *plan.JoinKey) (Task, error) {
 	return NewJoinKey(m.Ctx, p), nil
 }
+func (m *JobExecutor) WalkSemiJoin(p *plan.SemiJoin) (Task, error) {
+	// the sub-query is its own job, with its own context
+	sub, err := NewExecutor(p.Sub.Ctx, m.Planner).WalkPlan(p.Sub)
+	if err != nil {
+		return nil, err
+	}
+	subRunner, ok := sub.(TaskRunner)
+	if !ok {
+		return nil, fmt.Errorf("Expected TaskRunner but was %T", sub)
+	}
+	return NewSemiJoin(m.Ctx, p, subRunner), nil
+}
+func (m *JobExecutor) WalkPartitionScan(p *plan.PartitionScan) (Task, error) {
+	execTask := NewTaskParallel(m.Ctx)
+	parts := make([]TaskRunner, 0, len(p.Parts))
+	for _, ps := range p.Parts {
+		// each partition is its own sequence of source, where, etc
+		part := NewTaskSequential(m.Ctx)
+		src, err := m.WalkPlanTask(ps)
+		if err != nil {
+			return nil, err
+		}
+		if err = part.Add(src); err != nil {
+			return nil, err
+		}
+		if err = m.WalkChildren(ps, part); err != nil {
+			return nil, err
+		}
+		if err = execTask.Add(part); err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return execTask, execTask.Add(NewPartitionMerge(m.Ctx, p, parts))
+}
 func (m *JobExecutor) WalkPlanAll(p plan.Task) (Task, error) {
 	root, err := m.WalkPlanTask(p)
 	if err != nil {
@@ -300,12 +477,22 @@ func (m *JobExecutor) WalkPlanTask(p plan.Task) (Task, error) {
 		return m.Executor.WalkHaving(p)
 	case *plan.GroupBy:
 		return m.Executor.WalkGroupBy(p)
+	case *plan.OrderBy:
+		return m.Executor.WalkOrderBy(p)
+	case *plan.Distinct:
+		return m.Executor.WalkDistinct(p)
+	case *plan.Window:
+		return m.Executor.WalkWindow(p)
 	case *plan.Projection:
 		return m.Executor.WalkProjection(p)
 	case *plan.JoinMerge:
 		return m.Executor.WalkJoin(p)
 	case *plan.JoinKey:
 		return m.Executor.WalkJoinKey(p)
+	case *plan.SemiJoin:
+		return m.Executor.WalkSemiJoin(p)
+	case *plan.PartitionScan:
+		return m.Executor.WalkPartitionScan(p)
 	}
 	panic(fmt.Sprintf("Task plan-exec Not implemented for %T", p))
 }
@@ -317,6 +504,7 @@ func (m *JobExecutor) WalkChildren(p plan.Task, root Task) error {
 		et, err := m.WalkPlanTask(t)
 		if err != nil {
 			u.Errorf("could not create task %#v err=%v", t, err)
+			return err
 		}
 		if len(t.Children()) == 0 {
 			err = root.Add(et)
@@ -366,9 +554,16 @@ func (m *JobExecutor) Setup() error {
 func (m *JobExecutor) Run() error {
 	if m.Ctx != nil {
 		m.Ctx.DisableRecover = m.Ctx.DisableRecover
+		// once run all operators are done, return their memory to the pool
+		defer m.Ctx.Memory.Close()
 	}
 	//u.Debugf("job run: %#v", m.RootTask)
-	return m.RootTask.Run()
+	err := m.RootTask.Run()
+	if err == nil {
+		// the query was cancelled, or timed out
+		err = m.Ctx.Err()
+	}
+	return err
 }
 
 // Close the normal close of root task
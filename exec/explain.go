@@ -0,0 +1,593 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Explain)(nil)
+)
+
+// Explain:   EXPLAIN [ANALYZE] of a statement, one row per task of the
+//   job of the statement, in tree order where a task is the parent of
+//   the tasks it reads rows from (sub-queries, set operation inputs and
+//   ctes are children of the task that runs them).  Without ANALYZE the
+//   job is only built, not run.
+//
+//   - operator, source, predicate of the task, est_rows from the row
+//     count of the sources if they know it (schema.ConnRowCount)
+//   - ANALYZE runs the job, discarding its rows, and reports rows_in,
+//     rows_out, the wall time each task ran (elapsed_ms, includes
+//     waiting on its input) and the bytes of the rows it output.
+//
+//   explained-job  -->  explain  -->
+//
+type Explain struct {
+	*TaskBase
+	p      *plan.Explain
+	job    TaskRunner
+	closed bool
+}
+
+// NewExplain create an explain task of the job of the explained statement,
+// which is not part of the dag of this task
+func NewExplain(ctx *plan.Context, p *plan.Explain, job TaskRunner) *Explain {
+	return &Explain{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		job:      job,
+	}
+}
+
+func (m *Explain) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	if err := m.job.Close(); err != nil {
+		u.Warnf("could not close explained job %v", err)
+	}
+	return m.TaskBase.Close()
+}
+
+func (m *Explain) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	ex := newExplainer()
+	ex.add(m.job, nil)
+	if m.p.Stmt.Analyze {
+		if err := ex.run(m.Ctx, m.job); err != nil {
+			u.Errorf("could not run EXPLAIN ANALYZE %v", err)
+			close(m.TaskBase.sigCh)
+			return err
+		}
+	}
+
+	cols := m.p.Ctx.Projection.Proj.Columns
+	colIndex := make(map[string]int, len(cols))
+	for i, col := range cols {
+		colIndex[col.As] = i
+	}
+	for i, n := range ex.nodes {
+		vals := n.values(m.p.Stmt.Analyze)
+		msg := datasource.NewSqlDriverMessageMap(uint64(i), vals, colIndex)
+		select {
+		case m.msgOutCh <- msg:
+		case <-m.SigChan():
+			return nil
+		}
+	}
+	return nil
+}
+
+// explainNode is one task of the explained job
+type explainNode struct {
+	id       int
+	parent   *explainNode
+	depth    int
+	sub      bool // root of a job run by parent, not its input
+	task     TaskRunner
+	children []*explainNode
+	stats    *taskStats
+}
+
+// explainer builds the tree of tasks of a job, and with ANALYZE runs it
+type explainer struct {
+	nodes []*explainNode
+	stats map[TaskRunner]*taskStats
+	done  chan struct{}
+}
+
+func newExplainer() *explainer {
+	return &explainer{stats: make(map[TaskRunner]*taskStats)}
+}
+
+func (m *explainer) node(t TaskRunner, parent *explainNode) *explainNode {
+	n := &explainNode{id: len(m.nodes) + 1, parent: parent, task: t}
+	if parent != nil {
+		n.depth = parent.depth + 1
+		parent.children = append(parent.children, n)
+	}
+	m.nodes = append(m.nodes, n)
+	return n
+}
+
+// add the tasks of t under parent, returns the node of the task that
+// reads the input of t, whose children are the tasks before t.
+func (m *explainer) add(t TaskRunner, parent *explainNode) *explainNode {
+	switch tt := t.(type) {
+	case *TaskSequential:
+		for i := len(tt.runners) - 1; i >= 0; i-- {
+			parent = m.add(tt.runners[i], parent)
+		}
+		return parent
+	case *TaskParallel:
+		if mt := parallelMerge(tt); mt != nil {
+			n := m.node(mt, parent)
+			for _, r := range tt.runners {
+				if r != mt {
+					m.add(r, n)
+				}
+			}
+			return n
+		}
+		for _, r := range tt.runners {
+			m.add(r, parent)
+		}
+		return parent
+	}
+	n := m.node(t, parent)
+	for _, sub := range subJobs(t) {
+		k := len(n.children)
+		m.add(sub, n)
+		for _, c := range n.children[k:] {
+			c.sub = true
+		}
+	}
+	return n
+}
+
+// parallelMerge the task merging the others of a parallel task, the join
+// (of left, right) or merge of partitions, nil if there isn't one
+func parallelMerge(t *TaskParallel) TaskRunner {
+	for _, r := range t.runners {
+		switch r.(type) {
+		case *JoinMerge, *PartitionMerge:
+			return r
+		}
+	}
+	return nil
+}
+
+// subJobs the jobs a task runs which are not part of the dag
+func subJobs(t TaskRunner) []TaskRunner {
+	switch tt := t.(type) {
+	case *SemiJoin:
+		return []TaskRunner{tt.sub}
+	case *SetOperation:
+		return []TaskRunner{tt.left, tt.right}
+	case *With:
+		return append(append([]TaskRunner{}, tt.anchors...), tt.main)
+	}
+	return nil
+}
+
+// run the job wrapping each of its tasks to record what it did, rows of
+// the job are discarded
+func (m *explainer) run(ctx *plan.Context, job TaskRunner) error {
+	m.done = make(chan struct{})
+	defer close(m.done)
+
+	collector := NewTaskBase(ctx)
+	collector.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		return true
+	}
+	if err := job.Add(collector); err != nil {
+		return err
+	}
+	m.analyze(job, nil)
+	for _, n := range m.nodes {
+		n.stats = m.stats[n.task]
+	}
+	if err := job.Setup(0); err != nil {
+		return err
+	}
+	return job.Run()
+}
+
+// analyze wraps the tasks of t, in is the stats of the task t reads rows
+// from (nil if none), returns the (wrapped) task and the stats of the task
+// whose rows t outputs
+func (m *explainer) analyze(t TaskRunner, in *taskStats) (TaskRunner, *taskStats) {
+	switch tt := t.(type) {
+	case *TaskSequential:
+		for i, r := range tt.runners {
+			tt.runners[i], in = m.analyze(r, in)
+		}
+		return t, in
+	case *TaskParallel:
+		mt := parallelMerge(tt)
+		var last *taskStats
+		outs := make(map[TaskRunner]*taskStats)
+		for i, r := range tt.runners {
+			if r == mt {
+				continue
+			}
+			tt.runners[i], last = m.analyze(r, in)
+			outs[r] = last
+		}
+		if mt == nil {
+			return t, last
+		}
+		st := m.taskStats(mt)
+		w := &analyzeTask{TaskRunner: mt, stats: st, done: m.done}
+		switch mt := mt.(type) {
+		case *JoinMerge:
+			w.join = []*joinInput{{stats: outs[mt.ltask], left: true}}
+			if mt.rtask != nil {
+				w.join = append(w.join, &joinInput{stats: outs[mt.rtask]})
+			}
+		case *PartitionMerge:
+			for _, part := range mt.parts {
+				w.join = append(w.join, &joinInput{stats: outs[part]})
+			}
+		}
+		for i, r := range tt.runners {
+			if r == mt {
+				tt.runners[i] = w
+			}
+		}
+		return t, st
+	}
+	switch tt := t.(type) {
+	case *SemiJoin:
+		tt.sub = m.analyzeJob(tt.sub)
+	case *SetOperation:
+		tt.left, tt.right = m.analyzeJob(tt.left), m.analyzeJob(tt.right)
+	case *With:
+		for i, a := range tt.anchors {
+			tt.anchors[i] = m.analyzeJob(a)
+		}
+		tt.main = m.analyzeJob(tt.main)
+	}
+	st := m.taskStats(t)
+	return &analyzeTask{TaskRunner: t, stats: st, in: in, done: m.done}, st
+}
+
+func (m *explainer) taskStats(t TaskRunner) *taskStats {
+	st, ok := m.stats[t]
+	if !ok {
+		st = &taskStats{}
+		m.stats[t] = st
+	}
+	return st
+}
+
+// analyzeJob wraps the tasks of a job run by a task, including the
+// collector that task adds when it runs the job
+func (m *explainer) analyzeJob(job TaskRunner) TaskRunner {
+	_, last := m.analyze(job, nil)
+	return &analyzeJob{TaskRunner: job, m: m, last: last}
+}
+
+type analyzeJob struct {
+	TaskRunner
+	m    *explainer
+	last *taskStats
+}
+
+func (j *analyzeJob) Add(t Task) error {
+	if tr, ok := t.(TaskRunner); ok {
+		// the collector, it isn't an explained task
+		return j.TaskRunner.Add(&analyzeTask{TaskRunner: tr, stats: &taskStats{}, in: j.last, done: j.m.done})
+	}
+	return j.TaskRunner.Add(t)
+}
+
+// taskStats what a task did, rows and bytes are counted as they are read
+// by the next task
+type taskStats struct {
+	rowsIn  int64
+	rowsOut int64
+	bytes   int64
+	hasIn   bool // rows in are counted (not a source)
+	hasOut  bool // rows out are counted (read by a counted task)
+	elapsed time.Duration
+}
+
+// pipe the rows of in (output by task of stats from) to the returned
+// channel, counting them
+func (m *taskStats) pipe(in MessageChan, from *taskStats, done chan struct{}) MessageChan {
+	out := make(MessageChan, cap(in))
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					return
+				}
+				if msg != nil {
+					atomic.AddInt64(&m.rowsIn, 1)
+					if from != nil {
+						atomic.AddInt64(&from.rowsOut, 1)
+						atomic.AddInt64(&from.bytes, msgSize(msg))
+					}
+				}
+				select {
+				case out <- msg:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// analyzeTask a task of an EXPLAIN ANALYZE job, times it and counts the
+// rows it reads
+type analyzeTask struct {
+	TaskRunner
+	stats *taskStats
+	in    *taskStats   // task rows are read from
+	join  []*joinInput // inputs of a join (ltask, rtask), or of each partition
+	done  chan struct{}
+}
+
+type joinInput struct {
+	stats *taskStats
+	left  bool
+}
+
+// joinOutput the left or right task of a join, or a partition of a
+// partition merge, as the merging task reads it
+type joinOutput struct {
+	TaskRunner
+	out MessageChan
+}
+
+func (m *joinOutput) MessageOut() MessageChan { return m.out }
+
+func (m *analyzeTask) Setup(depth int) error {
+	if m.in != nil {
+		m.stats.hasIn = true
+		m.in.hasOut = true
+	}
+	for _, in := range m.join {
+		m.stats.hasIn = true
+		if in.stats != nil {
+			in.stats.hasOut = true
+		}
+	}
+	return m.TaskRunner.Setup(depth)
+}
+
+func (m *analyzeTask) Run() error {
+	if m.in != nil {
+		m.TaskRunner.MessageInSet(m.stats.pipe(m.TaskRunner.MessageIn(), m.in, m.done))
+	}
+	switch mt := m.TaskRunner.(type) {
+	case *JoinMerge:
+		for _, in := range m.join {
+			if in.left {
+				mt.ltask = &joinOutput{mt.ltask, m.stats.pipe(mt.ltask.MessageOut(), in.stats, m.done)}
+			} else {
+				mt.rtask = &joinOutput{mt.rtask, m.stats.pipe(mt.rtask.MessageOut(), in.stats, m.done)}
+			}
+		}
+	case *PartitionMerge:
+		for i, in := range m.join {
+			part := mt.parts[i]
+			mt.parts[i] = &joinOutput{part, m.stats.pipe(part.MessageOut(), in.stats, m.done)}
+		}
+	}
+	start := time.Now()
+	err := m.TaskRunner.Run()
+	m.stats.elapsed = time.Since(start)
+	return err
+}
+
+// values of the explain row of the node
+func (n *explainNode) values(analyze bool) []driver.Value {
+	var parent driver.Value
+	if n.parent != nil {
+		parent = int64(n.parent.id)
+	}
+	op, source, predicate := explainTask(n.task)
+	vals := []driver.Value{
+		int64(n.id),
+		parent,
+		strings.Repeat("  ", n.depth) + op,
+		nullString(source),
+		nullString(predicate),
+		nullCount(n.estimate()),
+	}
+	if !analyze {
+		return vals
+	}
+	st := n.stats
+	if st == nil {
+		return append(vals, nil, nil, nil, nil)
+	}
+	var rowsIn, rowsOut, bytes driver.Value
+	if st.hasIn {
+		rowsIn = atomic.LoadInt64(&st.rowsIn)
+	}
+	if st.hasOut {
+		rowsOut = atomic.LoadInt64(&st.rowsOut)
+		bytes = atomic.LoadInt64(&st.bytes)
+	}
+	elapsed := float64(st.elapsed) / float64(time.Millisecond)
+	return append(vals, rowsIn, rowsOut, elapsed, bytes)
+}
+
+func nullString(s string) driver.Value {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullCount(n int64) driver.Value {
+	if n < 0 {
+		return nil
+	}
+	return n
+}
+
+// explainTask the operator, source and predicate of a task
+func explainTask(t TaskRunner) (op, source, predicate string) {
+	switch tt := t.(type) {
+	case *Source:
+		op = "Source"
+		if tt.p != nil && tt.p.Stmt != nil {
+			source = tt.p.Stmt.Name
+			if tt.p.Stmt.Alias != "" && tt.p.Stmt.Alias != tt.p.Stmt.Name {
+				source += " AS " + tt.p.Stmt.Alias
+			}
+			if tt.p.Partition != nil {
+				source += " PARTITION (" + tt.p.Partition.Id + ")"
+			}
+			// conjuncts of the where pushed down to the source
+			pushed := make([]string, len(tt.p.Pushed))
+			for i, n := range tt.p.Pushed {
+				pushed[i] = n.String()
+			}
+			predicate = strings.Join(pushed, " AND ")
+		}
+	case *Where:
+		op = "Where"
+		if tt.having {
+			op = "Having"
+		}
+		if tt.filter != nil {
+			predicate = tt.filter.String()
+		}
+	case *JoinMerge:
+		op = "Join"
+		if tt.seeker != nil {
+			op = "JoinLookup"
+		}
+		source = tt.rightStmt.Name
+		if tt.rightStmt.JoinExpr != nil {
+			predicate = tt.rightStmt.JoinExpr.String()
+		}
+	case *SemiJoin:
+		op = "SemiJoin"
+		predicate = tt.p.Cond.String()
+	case *SetOperation:
+		op = strings.ToUpper(tt.p.Stmt.Op.String())
+		if tt.p.Stmt.All {
+			op += " ALL"
+		}
+	case *With:
+		op = "With"
+		names := make([]string, len(tt.p.Ctes))
+		for i, cte := range tt.p.Ctes {
+			names[i] = cte.Stmt.Name
+		}
+		source = strings.Join(names, ", ")
+	case *GroupBy:
+		op = "GroupBy"
+		if tt.p.Partial {
+			op = "GroupByPartial"
+		}
+	case *GroupByFinal:
+		op = "GroupByFinal"
+	case *PartitionMerge:
+		op = "PartitionMerge"
+		source = tt.p.Source.Stmt.Name
+	default:
+		op = strings.TrimPrefix(fmt.Sprintf("%T", t), "*exec.")
+	}
+	return op, source, predicate
+}
+
+// estimate the rows output by the task of the node, -1 if unknown
+func (n *explainNode) estimate() int64 {
+	in := int64(-1)
+	var subs []int64
+	for _, c := range n.children {
+		if c.sub {
+			subs = append(subs, c.estimate())
+		} else if in < 0 {
+			in = c.estimate()
+		}
+	}
+	switch tt := n.task.(type) {
+	case *Source:
+		if tt.p != nil {
+			if rc, ok := tt.p.Conn.(schema.ConnRowCount); ok {
+				return rc.RowCount()
+			}
+		}
+		return -1
+	case *Projection:
+		if tt.p != nil && tt.p.Stmt != nil {
+			return limitRows(in, tt.p.Stmt.Limit)
+		}
+		return in
+	case *Distinct:
+		return limitRows(-1, tt.p.Stmt.Limit)
+	case *GroupBy:
+		if len(tt.p.Stmt.GroupBy) == 0 {
+			return 1
+		}
+	case *GroupByFinal:
+		if len(tt.p.Stmt.GroupBy) == 0 {
+			return 1
+		}
+	case *OrderBy, *Window, *JoinKey:
+		return in
+	case *PartitionMerge:
+		// rows of all the partitions
+		total := int64(0)
+		for _, c := range n.children {
+			est := c.estimate()
+			if est < 0 {
+				return -1
+			}
+			total += est
+		}
+		return total
+	case *SetOperation:
+		if tt.p.Stmt.All && tt.p.Stmt.Op == lex.TokenUnion && len(subs) == 2 &&
+			subs[0] >= 0 && subs[1] >= 0 {
+			return subs[0] + subs[1]
+		}
+	case *With:
+		if len(subs) > 0 {
+			return subs[len(subs)-1]
+		}
+	}
+	return -1
+}
+
+// limitRows the rows of in (-1 unknown) with a limit (0 none)
+func limitRows(in int64, limit int) int64 {
+	if limit <= 0 {
+		return in
+	}
+	if in < 0 || int64(limit) < in {
+		return int64(limit)
+	}
+	return in
+}
@@ -1,10 +1,11 @@
 package exec
 
 import (
+	"container/heap"
 	"database/sql/driver"
-	"encoding/gob"
 	"fmt"
-	"strings"
+	"io"
+	"sort"
 	"time"
 
 	u "github.com/araddon/gou"
@@ -12,8 +13,6 @@ import (
 	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
-	"github.com/araddon/qlbridge/rel"
-	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -22,16 +21,26 @@ var (
 
 	// Ensure that we implement the Task Runner interface
 	_ TaskRunner = (*GroupBy)(nil)
+
+	// GroupByMemoryLimit is the approximate number of bytes of aggregate
+	// state a GroupBy will hold in memory before spilling to temp files.
+	GroupByMemoryLimit int64 = 64 * 1024 * 1024
 )
 
-func init() {
-	gob.Register(AggPartial{})
-}
+const (
+	// number of hash partitions group state is spilled into
+	groupByPartitions = distinctPartitions
+)
 
 // Group by:   Sql Group By Operator
 //   creates a hashable key commposed of key = {each,value,of,column,in,groupby}
 //
-// A very stupid naive parallel groupby holds values in memory
+//   - aggregates are updated as rows arrive, only per group aggregate
+//     state is held in memory (not rows).
+//   - if state exceeds GroupByMemoryLimit, or the query's memory account
+//     is exhausted, it is spilled to disk and the partial states merged
+//     on output.
+//   - groups are output in the order they were first seen.
 //
 //   task   ->  groupby  -->
 //
@@ -72,20 +81,17 @@ func (m *GroupBy) Run() error {
 	defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
 
-	outCh := m.MessageOut()
 	inCh := m.MessageIn()
 
-	columns := m.p.Stmt.Columns
 	colIndex := m.p.Stmt.ColIndexes()
 
-	aggs, err := buildAggs(m.p)
+	gb, err := newGroupByTable(m.Ctx, m.p)
 	if err != nil {
 		return err
 	}
+	defer gb.Close()
 
-	// are are going to hold entire row in memory while we are calculating
-	//  so obviously not scalable.
-	gb := make(map[string][]*datasource.SqlDriverMessageMap)
+	keyVals := make([]driver.Value, len(m.p.Stmt.GroupBy))
 
 msgReadLoop:
 	for {
@@ -118,90 +124,71 @@ msgReadLoop:
 				}
 
 				// We are going to use VM Engine to create a value for each statement in group by
-				//  then join each value together to create a unique key.
-				keys := make([]string, len(m.p.Stmt.GroupBy))
+				//  then join each value together to create a unique (typed) key.
 				for i, col := range m.p.Stmt.GroupBy {
+					keyVals[i] = nil
 					if col.Expr != nil {
-						if key, ok := vm.Eval(sdm, col.Expr); ok {
+						if key, ok := vm.Eval(sdm, col.Expr); ok && key != nil {
 							//u.Debugf("msgtype:%T  key:%q for-expr:%s", sdm, key, col.Expr)
-							keys[i] = key.ToString()
-						} else {
-							// Is this an error?
-							//u.Warnf("no key?  %s for %+v", col.Expr, sdm)
+							keyVals[i] = key.Value()
 						}
 					} else {
 						u.Warnf("no col.expr? %#v", col)
 					}
 				}
-				key := strings.Join(keys, ",")
-				//u.Infof("found key:%s for %+v", key, sdm)
-				gb[key] = append(gb[key], sdm)
+				key := distinctKey(keyVals)
+
+				// update the running aggregate state of this group
+				g := gb.group(key)
+				for i := range gb.inputs {
+					v, size := gb.inputs[i].eval(sdm)
+					g.aggs[i].Do(v)
+					gb.size += size
+				}
+				if err := gb.checkSpill(); err != nil {
+					u.Errorf("could not spill group by %v", err)
+					close(m.TaskBase.sigCh)
+					return err
+				}
 			}
 		}
 	}
 
 	i := uint64(0)
-	for key, v := range gb {
-		//u.Debugf("got %s:%v msgs", k, len(v))
-
-		for _, mm := range v {
-			for i, col := range columns {
-				//u.Debugf("col: idx:%v sidx: %v pidx:%v key:%v   %s", col.Index, col.SourceIndex, col.ParentIndex, col.Key(), col.Expr)
-
-				if col.Expr == nil {
-					u.Warnf("wat?   nil col expr? %#v", col)
-				} else {
-					v, ok := vm.Eval(mm, col.Expr)
-					//u.Infof("mt: %T  mm %#v", mm, mm)
-					if !ok || v == nil {
-						//u.Debugf("evaled nil? key=%v  val=%v expr:%s", col.Key(), v, col.Expr.String())
-						//u.Infof("mt: %T  mm %#v", mm, mm)
-						aggs[i].Do(value.NewNilValue())
-					} else {
-						//u.Debugf("evaled: key=%v  val=%v", col.Key(), v.Value())
-						aggs[i].Do(v)
-					}
-				}
-			}
-		}
-
-		row := make([]driver.Value, len(columns))
-		for i, agg := range aggs {
-			row[i] = driver.Value(agg.Result())
-			agg.Reset()
-			//u.Debugf("agg result: %#v  %v", row[i], row[i])
-		}
-
+	return gb.emit(m.p.Partial, func(key string, row []driver.Value) bool {
 		if m.p.Partial {
 			// Partial results, append key at end?  shouldn't be able to be fit in message itself?
 			row = append(row, key)
-			//u.Debugf("GroupBy output row? key:%s %#v", key, row)
 		}
 		//u.Debugf("row: %v  cols:%v", row, colIndex)
-		outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex)
 		i++
-	}
-
-	return nil
+		select {
+		case m.msgOutCh <- datasource.NewSqlDriverMessageMap(i-1, row, colIndex):
+			return true
+		case <-m.SigChan():
+			return false
+		}
+	})
 }
 
 func (m *GroupByFinal) Run() error {
 	defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
+	defer func() {
+		m.isComplete = true
+		close(m.complete)
+	}()
 
-	outCh := m.MessageOut()
 	inCh := m.MessageIn()
 
 	columns := m.p.Stmt.Columns
 	colIndex := m.p.Stmt.ColIndexes()
 
-	m.p.Partial = false
-	aggs, err := buildAggs(m.p)
+	gb, err := newGroupByTable(m.Ctx, m.p)
 	if err != nil {
 		return err
 	}
-
-	gb := make(map[string][][]driver.Value)
+	defer gb.Close()
 
 msgReadLoop:
 	for {
@@ -220,14 +207,26 @@ msgReadLoop:
 				case *datasource.SqlDriverMessageMap:
 					if len(mt.Vals) != len(columns)+1 {
 						u.Warnf("Wrong number of values? %#v", mt)
+						continue
 					}
 					key, ok := mt.Vals[len(mt.Vals)-1].(string)
 					if !ok {
 						u.Warnf("expected key?  %#v", mt.Vals)
 					}
-					vals := mt.Vals[0 : len(mt.Vals)-1]
-					//u.Infof("found key:%s for %#v", key, mt.Vals)
-					gb[key] = append(gb[key], vals)
+					// merge the partial aggregate state into this group
+					g := gb.group(key)
+					for i := range columns {
+						if err := mergeAggPartial(g.aggs[i], mt.Vals[i]); err != nil {
+							u.Errorf("could not merge group by %v", err)
+							close(m.TaskBase.sigCh)
+							return err
+						}
+					}
+					if err := gb.checkSpill(); err != nil {
+						u.Errorf("could not spill group by %v", err)
+						close(m.TaskBase.sigCh)
+						return err
+					}
 				default:
 					err := fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
 					u.Errorf("unrecognized msg %T", msg)
@@ -239,50 +238,16 @@ msgReadLoop:
 	}
 
 	i := uint64(0)
-	for _, vals := range gb {
-		//u.Debugf("got %s:%v msgs", key, vals)
-
-		for _, dv := range vals {
-			for i, col := range columns {
-				//u.Debugf("col: idx:%v sidx: %v pidx:%v key:%v   %s", col.Index, col.SourceIndex, col.ParentIndex, col.Key(), col.Expr)
-				if i-1 >= len(dv) {
-					u.Errorf("what??? %v  dv: %d   %#v", i, len(dv), dv)
-				}
-				if col.Expr == nil {
-					u.Warnf("wat?   nil col expr? %#v", col)
-				} else {
-					v := dv[i]
-					switch vt := v.(type) {
-					case *AggPartial:
-						//u.Debugf("evaled: key=%v  val=%v", col.Key(), v.Value())
-						aggs[i].Merge(vt)
-					case AggPartial:
-						aggs[i].Merge(&vt)
-					case int64:
-						aggs[i].Merge(&AggPartial{Ct: vt})
-					case string:
-						aggs[i] = &groupByFunc{vt}
-					default:
-						u.Warnf("unhandled type: %#v", v)
-					}
-				}
-			}
-		}
-
-		row := make([]driver.Value, len(columns))
-		for i, agg := range aggs {
-			row[i] = driver.Value(agg.Result())
-			agg.Reset()
-			//u.Debugf("agg result: %#v  %v", row[i], row[i])
-		}
+	return gb.emit(false, func(key string, row []driver.Value) bool {
 		//u.Debugf("GroupBy output row? %v", row)
-		outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex)
 		i++
-	}
-
-	m.isComplete = true
-	close(m.complete)
-	return nil
+		select {
+		case m.msgOutCh <- datasource.NewSqlDriverMessageMap(i-1, row, colIndex):
+			return true
+		case <-m.SigChan():
+			return false
+		}
+	})
 }
 
 func (m *GroupBy) Close() error {
@@ -312,149 +277,238 @@ func (m *GroupByFinal) Close() error {
 	return m.TaskBase.Close()
 }
 
-type AggPartial struct {
-	Ct int64
-	N  float64
+// groupByTable holds the running (partial) aggregate state of each group
+// in memory, keyed by group key.  Groups are output in order first seen.
+//
+//   - once over GroupByMemoryLimit (or the memory reservation can't grow)
+//     the partial state of all groups is spilled to hash partitions (by
+//     group key) and memory cleared.
+//   - on emit each partition in turn has the partial states of its groups
+//     merged, and the results merged back into first seen order.
+type groupByTable struct {
+	p       *plan.GroupBy
+	groups  map[string]*aggGroup
+	order   []*aggGroup
+	inputs  []aggInput // evaluated for each column's Aggregator.Do
+	seq     uint64
+	size    int64
+	parts   []*spillFile
+	results []*spillFile
+	mem     *memReservation
 }
 
-type AggFunc func(v value.Value)
-type resultFunc func() interface{}
-type Aggregator interface {
-	Do(v value.Value)
-	Result() interface{}
-	Reset()
-	Merge(*AggPartial)
+// aggGroup is the running aggregate state of one group
+type aggGroup struct {
+	seq  uint64 // first seen
+	key  string
+	aggs []Aggregator // one per column
 }
-type agg struct {
-	do     AggFunc
-	result resultFunc
-}
-type groupByFunc struct {
-	last interface{}
+
+type groupsBySeq []*aggGroup
+
+func (m groupsBySeq) Len() int           { return len(m) }
+func (m groupsBySeq) Less(i, j int) bool { return m[i].seq < m[j].seq }
+func (m groupsBySeq) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+func newGroupByTable(ctx *plan.Context, p *plan.GroupBy) (*groupByTable, error) {
+	aggs, err := buildAggs(p)
+	if err != nil {
+		return nil, err
+	}
+	inputs := aggInputs(aggs, p.Stmt.Columns)
+	return &groupByTable{
+		p:      p,
+		inputs: inputs,
+		groups: make(map[string]*aggGroup),
+		mem:    newMemReservation(ctx),
+	}, nil
 }
 
-func (m *groupByFunc) Do(v value.Value)    { m.last = v.Value() }
-func (m *groupByFunc) Result() interface{} { return m.last }
-func (m *groupByFunc) Reset()              { m.last = nil }
-func (m *groupByFunc) Merge(a *AggPartial) {}
-func NewGroupByValue(col *rel.Column) Aggregator {
-	return &groupByFunc{}
+// group finds, or creates, the aggregate state for group key
+func (m *groupByTable) group(key string) *aggGroup {
+	if g, ok := m.groups[key]; ok {
+		return g
+	}
+	return m.newGroup(key, m.seq)
 }
 
-type sum struct {
-	partial bool
-	ct      int64
-	n       float64
+func (m *groupByTable) newGroup(key string, seq uint64) *aggGroup {
+	aggs, _ := buildAggs(m.p)
+	g := &aggGroup{seq: seq, key: key, aggs: aggs}
+	m.groups[key] = g
+	m.order = append(m.order, g)
+	m.seq++
+	m.size += int64(len(key) + 64 + 48*len(aggs))
+	return g
 }
 
-func (m *sum) Do(v value.Value) {
-	m.ct++
-	switch vt := v.(type) {
-	case value.IntValue:
-		m.n += vt.Float()
-	case value.NumberValue:
-		m.n += vt.Val()
+// checkSpill spills the partial state of groups once over memory limit
+func (m *groupByTable) checkSpill() error {
+	if m.size <= GroupByMemoryLimit && m.mem.tryResize(m.size) {
+		return nil
 	}
+	return m.spill()
 }
-func (m *sum) Result() interface{} {
-	if !m.partial {
-		return m.n
+
+func (m *groupByTable) spill() error {
+	if m.parts == nil {
+		m.parts = make([]*spillFile, groupByPartitions)
+		for i := range m.parts {
+			f, err := newSpillFile("groupby")
+			if err != nil {
+				return err
+			}
+			m.parts[i] = f
+		}
 	}
-	return &AggPartial{
-		m.ct,
-		m.n,
+	for _, g := range m.order {
+		vals, err := g.partials()
+		if err != nil {
+			return err
+		}
+		rec := distinctRecord{Seq: g.seq, Key: g.key, Vals: vals}
+		if err := m.parts[partitionOf(g.key)].Write(&rec); err != nil {
+			return err
+		}
 	}
-}
-func (m *sum) Reset() { m.n = 0 }
-func (m *sum) Merge(a *AggPartial) {
-	m.ct += a.Ct
-	m.n += a.N
-}
-func NewSum(col *rel.Column, partial bool) Aggregator {
-	return &sum{partial: partial}
+	m.groups = make(map[string]*aggGroup)
+	m.order = nil
+	m.size = 0
+	m.mem.release()
+	return nil
 }
 
-type avg struct {
-	partial bool
-	ct      int64
-	n       float64
-}
+// emit each group's results in the order groups were first seen, as
+// partial state if partial, else final aggregate values
+func (m *groupByTable) emit(partial bool, fn func(key string, row []driver.Value) bool) error {
+	if m.parts == nil {
+		for _, g := range m.order {
+			row, err := g.row(partial)
+			if err != nil {
+				return err
+			}
+			if !fn(g.key, row) {
+				return nil
+			}
+		}
+		return nil
+	}
+	if err := m.spill(); err != nil {
+		return err
+	}
+
+	mh := &distinctMergeHeap{}
+	for _, part := range m.parts {
+		rdr, err := part.Reader()
+		if err != nil {
+			return err
+		}
+		// merge the partial state of groups in this partition, a group
+		// keeps the earliest seq it was seen at
+		for {
+			rec := distinctRecord{}
+			if err := rdr.Read(&rec); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			g, ok := m.groups[rec.Key]
+			if !ok {
+				g = m.newGroup(rec.Key, rec.Seq)
+			} else if rec.Seq < g.seq {
+				g.seq = rec.Seq
+			}
+			for i, v := range rec.Vals {
+				if err := mergeAggPartial(g.aggs[i], v); err != nil {
+					return err
+				}
+			}
+		}
+		part.Close()
+
+		sort.Sort(groupsBySeq(m.order))
+		out, err := newSpillFile("groupby")
+		if err != nil {
+			return err
+		}
+		m.results = append(m.results, out)
+		for _, g := range m.order {
+			row, err := g.row(partial)
+			if err != nil {
+				return err
+			}
+			rec := distinctRecord{Seq: g.seq, Key: g.key, Vals: row}
+			if err := out.Write(&rec); err != nil {
+				return err
+			}
+		}
+		m.groups = make(map[string]*aggGroup)
+		m.order = nil
 
-func (m *avg) Do(v value.Value) {
-	m.ct++
-	switch vt := v.(type) {
-	case value.IntValue:
-		m.n += vt.Float()
-	case value.NumberValue:
-		m.n += vt.Val()
+		outRdr, err := out.Reader()
+		if err != nil {
+			return err
+		}
+		if err := mh.pushNext(outRdr); err != nil {
+			return err
+		}
 	}
+
+	for mh.Len() > 0 {
+		head := mh.heads[0]
+		if !fn(head.rec.Key, head.rec.Vals) {
+			return nil
+		}
+		heap.Pop(mh)
+		if err := mh.pushNext(head.rdr); err != nil {
+			return err
+		}
+	}
+	return nil
 }
-func (m *avg) Result() interface{} {
-	if !m.partial {
-		return m.n / float64(m.ct)
+
+// Close removes any spill files
+func (m *groupByTable) Close() {
+	m.mem.release()
+	for _, f := range m.parts {
+		f.Close()
 	}
-	return &AggPartial{
-		m.ct,
-		m.n,
+	for _, f := range m.results {
+		f.Close()
 	}
 }
-func (m *avg) Reset() { m.n = 0; m.ct = 0 }
-func (m *avg) Merge(a *AggPartial) {
-	m.ct += a.Ct
-	m.n += a.N
-}
-func NewAvg(col *rel.Column, partial bool) Aggregator {
-	return &avg{partial: partial}
-}
 
-type count struct {
-	n int64
+// row of results for a group, the serialized partial state of each
+// aggregate if partial
+func (m *aggGroup) row(partial bool) ([]driver.Value, error) {
+	if partial {
+		return m.partials()
+	}
+	row := make([]driver.Value, len(m.aggs))
+	for i, agg := range m.aggs {
+		row[i] = driver.Value(agg.Result())
+	}
+	return row, nil
 }
 
-func (m *count) Do(v value.Value) { m.n++ }
-func (m *count) Result() interface{} {
-	return m.n
-}
-func (m *count) Reset() { m.n = 0 }
-func (m *count) Merge(a *AggPartial) {
-	m.n += a.Ct
-}
-func NewCount(col *rel.Column) Aggregator {
-	return &count{}
+// partials the serialized partial state of each aggregator of group
+func (m *aggGroup) partials() ([]driver.Value, error) {
+	vals := make([]driver.Value, len(m.aggs))
+	for i, agg := range m.aggs {
+		st, err := agg.Partial()
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = st
+	}
+	return vals, nil
 }
 
-func buildAggs(p *plan.GroupBy) ([]Aggregator, error) {
-	//u.Debugf("build aggs: partial:%v  sql:%s", p.Partial, p.Stmt)
-	aggs := make([]Aggregator, len(p.Stmt.Columns))
-colLoop:
-	for colIdx, col := range p.Stmt.Columns {
-		for _, gb := range p.Stmt.GroupBy {
-			if gb.As == col.As {
-				// simple Non Aggregate Value
-				aggs[colIdx] = NewGroupByValue(col)
-				continue colLoop
-			}
-		}
-		// Since we made it here, ann aggregate func
-		//  move to a registry of some kind to allow extension
-		switch n := col.Expr.(type) {
-		case *expr.FuncNode:
-
-			// TODO:  extract to a UDF Registry Similar to builtins
-			switch strings.ToLower(n.Name) {
-			case "avg":
-				aggs[colIdx] = NewAvg(col, p.Partial)
-			case "count":
-				aggs[colIdx] = NewCount(col)
-			case "sum":
-				aggs[colIdx] = NewSum(col, p.Partial)
-			default:
-				return nil, fmt.Errorf("Not impelemneted groupby for column: %s", col.Expr)
-			}
-		case *expr.BinaryNode:
-			// binary logic?
-			return nil, fmt.Errorf("Not impelemneted groupby for column: %s", col.Expr)
-		}
+// mergeAggPartial merges serialized partial state into agg
+func mergeAggPartial(agg Aggregator, v driver.Value) error {
+	partial, ok := v.([]byte)
+	if !ok {
+		return fmt.Errorf("expected partial aggregate state but got %T", v)
 	}
-	return aggs, nil
+	return agg.Merge(partial)
 }
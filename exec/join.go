@@ -1,17 +1,23 @@
 package exec
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"fmt"
-	"strings"
-	"sync"
+	"hash/fnv"
+	"io"
+	"math"
+	"strconv"
 
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -20,6 +26,19 @@ var (
 
 	// Ensure that we implement the Task Runner interface
 	_ TaskRunner = (*JoinMerge)(nil)
+
+	// JoinMemoryLimit is the approximate number of bytes of build side rows
+	// a JoinMerge will hold in memory before partitioning to temp files.
+	JoinMemoryLimit int64 = 64 * 1024 * 1024
+
+	// JoinLookupBatchSize is the number of left side rows whose keys are
+	// fetched in a single MultiGet for a lookup join.
+	JoinLookupBatchSize = 100
+)
+
+const (
+	// number of hash partitions each side of a join is spilled into
+	joinPartitions = 16
 )
 
 type KeyEvaluator func(msg schema.Message) driver.Value
@@ -81,23 +100,20 @@ func (m *JoinKey) Run() error {
 			}
 
 			//u.Infof("In joinkey msg %#v", msg)
-		msgTypeSwitch:
 			switch mt := msg.(type) {
 			case *datasource.SqlDriverMessageMap:
-				vals := make([]string, len(joinNodes))
-				for i, node := range joinNodes {
-					joinVal, ok := vm.Eval(mt, node)
-					//u.Debugf("evaluating: ok?%v T:%T result=%v node '%v'", ok, joinVal, joinVal.ToString(), node.String())
-					if !ok {
-						u.Errorf("could not evaluate: %T %#v   %v", joinVal, joinVal, msg)
-						break msgTypeSwitch
-					}
-					vals[i] = joinVal.ToString()
+				// rows with a NULL key part never match but are still sent
+				// as outer joins need them, they just get an empty key
+				keys := evalJoinKeys(mt, joinNodes)
+				//u.Infof("joinkey: %v row:%v", keys, mt)
+				if keys != nil {
+					mt.SetKeyHashed(joinHashKey(keys))
+				}
+				select {
+				case outCh <- mt:
+				case <-m.SigChan():
+					return nil
 				}
-				//u.Infof("joinkey: %v row:%v", vals, mt)
-				key := strings.Join(vals, string(byte(0)))
-				mt.SetKeyHashed(key)
-				outCh <- mt
 			default:
 				return fmt.Errorf("To use JoinKey must use SqlDriverMessageMap but got %T", msg)
 			}
@@ -107,7 +123,19 @@ func (m *JoinKey) Run() error {
 	return nil
 }
 
-// Scans 2 source tasks for rows, evaluate keys, use for join
+// JoinMerge is a build/probe hash join of 2 source tasks
+//
+//   - the right source is the build side, read fully into a hash table
+//     keyed on its join key values.
+//   - the left source is the probe side, streamed through the table.
+//   - INNER, LEFT, RIGHT, FULL [OUTER] semantics, the non-matching rows of
+//     the preserved side(s) are null padded.
+//   - keys of a row are compared typed (see joinValueEqual), any NULL key
+//     part never matches.
+//   - if the build side exceeds JoinMemoryLimit, or the query's memory
+//     account is exhausted, both sides are hash partitioned to temp files
+//     and joined one partition at a time.  A partition that still doesn't
+//     fit in the query's memory fails the join.
 //
 type JoinMerge struct {
 	*TaskBase
@@ -117,10 +145,17 @@ type JoinMerge struct {
 	ltask     TaskRunner
 	rtask     TaskRunner
 	colIndex  map[string]int
+	keepLeft  bool // left or full outer join
+	keepRight bool // right or full outer join
+	rowCt     uint64
+
+	// lookup join, right side rows are fetched by key instead of scanned
+	seeker     schema.ConnSeeker
+	seekType   value.ValueType // type of the column sought on, keys are converted to
+	lookupCols map[string]int
 }
 
-// A very stupid naive parallel join merge, uses Key() as value to merge
-//   two different input channels
+// A hash join merge of 2 different input channels
 //
 //   source1   ->
 //                \
@@ -138,7 +173,7 @@ type JoinMerge struct {
 //   source2b  -> key-hash-route |-> --  join  -->
 //   source2n  ->                |-> --  join  -->
 //
-func NewJoinNaiveMerge(ctx *plan.Context, l, r TaskRunner, p *plan.JoinMerge) *JoinMerge {
+func NewJoinMerge(ctx *plan.Context, l, r TaskRunner, p *plan.JoinMerge) *JoinMerge {
 
 	m := &JoinMerge{
 		TaskBase: NewTaskBase(ctx),
@@ -150,6 +185,41 @@ func NewJoinNaiveMerge(ctx *plan.Context, l, r TaskRunner, p *plan.JoinMerge) *J
 	m.leftStmt = p.LeftFrom
 	m.rightStmt = p.RightFrom
 
+	// the join type is on the right hand source  ie  "LEFT OUTER JOIN orders AS o ON ..."
+	switch m.rightStmt.LeftOrRight {
+	case lex.TokenLeft:
+		m.keepLeft = true
+	case lex.TokenRight:
+		m.keepRight = true
+	case lex.TokenFull:
+		m.keepLeft, m.keepRight = true, true
+	default:
+		if m.rightStmt.JoinType == lex.TokenOuter {
+			m.keepLeft, m.keepRight = true, true
+		}
+	}
+
+	return m
+}
+
+// A lookup (index nested loop) join, the left source is read in batches
+// and the matching right side rows fetched by key using MultiGet on the
+// right source, so the right source is never scanned.
+//
+//   source1   ->  batch keys  -> MultiGet(source2) --  join  -->
+//
+func NewJoinLookup(ctx *plan.Context, l TaskRunner, seeker schema.ConnSeeker, p *plan.JoinMerge) *JoinMerge {
+	m := NewJoinMerge(ctx, l, nil, p)
+	m.seeker = seeker
+	if src, ok := p.Right.(*plan.Source); ok {
+		m.seekType = src.SeekType
+	}
+	m.lookupCols = make(map[string]int)
+	if cols, ok := seeker.(schema.ConnColumns); ok {
+		for i, col := range cols.Columns() {
+			m.lookupCols[col] = i
+		}
+	}
 	return m
 }
 
@@ -165,129 +235,312 @@ func (m *JoinMerge) Run() error {
 	defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
 
-	outCh := m.MessageOut()
+	if m.seeker != nil {
+		return m.runLookup()
+	}
 
-	leftIn := m.ltask.MessageOut()
-	rightIn := m.rtask.MessageOut()
-
-	lh := make(map[string][]*datasource.SqlDriverMessageMap)
-	rh := make(map[string][]*datasource.SqlDriverMessageMap)
-
-	wg := new(sync.WaitGroup)
-	wg.Add(1)
-	var fatalErr error
-	go func() {
-		for {
-			//u.Infof("In source Scanner msg %#v", msg)
-			select {
-			case <-m.SigChan():
-				u.Debugf("got signal quit")
-				wg.Done()
-				wg.Done()
-				return
-			case msg, ok := <-leftIn:
-				if !ok {
-					//u.Debugf("NICE, got left shutdown")
-					wg.Done()
-					return
-				} else {
-					switch mt := msg.(type) {
-					case *datasource.SqlDriverMessageMap:
-						key := mt.Key()
-						if key == "" {
-							fatalErr = fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
-							u.Errorf("no key? %#v  %v", mt, fatalErr)
-							close(m.TaskBase.sigCh)
-							return
-						}
-						lh[key] = append(lh[key], mt)
-					default:
-						fatalErr = fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
-						u.Errorf("unrecognized msg %T", msg)
-						close(m.TaskBase.sigCh)
-						return
-					}
-				}
+	ht := newJoinHashTable(m.Ctx)
+	defer ht.Close()
+
+	// Build
+	rightNodes := m.rightStmt.JoinNodes()
+	err := m.readSource(m.rtask.MessageOut(), rightNodes, func(row *joinRow) (bool, error) {
+		if row.keys == nil {
+			// a null key never matches
+			if m.keepRight {
+				return m.emit(nil, row), nil
 			}
+			return true, nil
+		}
+		return true, ht.add(row)
+	})
+	if err != nil || m.quitting() {
+		return err
+	}
 
+	// Probe
+	leftNodes := m.leftStmt.JoinNodes()
+	err = m.readSource(m.ltask.MessageOut(), leftNodes, func(row *joinRow) (bool, error) {
+		if row.keys == nil {
+			if m.keepLeft {
+				return m.emit(row, nil), nil
+			}
+			return true, nil
 		}
-	}()
-	wg.Add(1)
-	go func() {
-		for {
-
-			//u.Infof("In source Scanner iter %#v", item)
-			select {
-			case <-m.SigChan():
-				u.Debugf("got quit signal join source 1")
-				wg.Done()
-				wg.Done()
-				return
-			case msg, ok := <-rightIn:
-				if !ok {
-					//u.Debugf("NICE, got right shutdown")
-					wg.Done()
-					return
-				} else {
-					switch mt := msg.(type) {
-					case *datasource.SqlDriverMessageMap:
-						key := mt.Key()
-						if key == "" {
-							fatalErr = fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
-							u.Errorf("no key? %#v  %v", mt, fatalErr)
-							close(m.TaskBase.sigCh)
-							return
-						}
-						rh[key] = append(rh[key], mt)
-					default:
-						fatalErr = fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
-						u.Errorf("unrecognized msg %T", msg)
-						close(m.TaskBase.sigCh)
-						return
-					}
-				}
+		if ht.parts != nil {
+			return true, ht.spillProbe(row)
+		}
+		return m.probe(ht, row), nil
+	})
+	if err != nil || m.quitting() {
+		return err
+	}
+
+	if ht.parts == nil {
+		m.emitUnmatched(ht)
+		return nil
+	}
+	return m.joinPartitions(ht)
+}
+
+func (m *JoinMerge) quitting() bool {
+	select {
+	case <-m.SigChan():
+		return true
+	default:
+	}
+	return false
+}
+
+// runLookup reads the left source in batches, joining each batch to the
+// right side rows fetched for its keys
+func (m *JoinMerge) runLookup() error {
+	batch := make([]*joinRow, 0, JoinLookupBatchSize)
+	err := m.readSource(m.ltask.MessageOut(), m.leftStmt.JoinNodes(), func(row *joinRow) (bool, error) {
+		batch = append(batch, row)
+		if len(batch) < JoinLookupBatchSize {
+			return true, nil
+		}
+		cont, err := m.lookup(batch)
+		batch = batch[:0]
+		return cont, err
+	})
+	if err != nil || m.quitting() || len(batch) == 0 {
+		return err
+	}
+	if _, err = m.lookup(batch); err != nil {
+		u.Errorf("could not join %v", err)
+		close(m.TaskBase.sigCh)
+	}
+	return err
+}
+
+// lookup the right side rows for a batch of left rows, and emit the joined
+// rows in left row order, false if we have been told to quit
+func (m *JoinMerge) lookup(batch []*joinRow) (bool, error) {
+	keys := make([]driver.Value, 0, len(batch))
+	seen := make(map[string]struct{}, len(batch))
+	for _, row := range batch {
+		if row.keys == nil {
+			continue
+		}
+		// the key as the type of the column sought on, ie 1 for "1", one
+		// that doesn't convert to an equal value matches no rows
+		ck, err := value.Cast(m.seekType, row.keys[0])
+		if err != nil || !joinValueEqual(row.keys[0], ck) {
+			continue
+		}
+		key := ck.Value()
+		dk := distinctKey([]driver.Value{key})
+		if _, exists := seen[dk]; exists {
+			continue
+		}
+		seen[dk] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	ht := newJoinHashTable(m.Ctx)
+	if len(keys) > 0 {
+		msgs, err := m.seeker.MultiGet(keys)
+		if err != nil && err != schema.ErrNotFound {
+			return false, err
+		}
+		fetched := make(map[uint64]struct{}, len(msgs))
+		for _, msg := range msgs {
+			if _, exists := fetched[msg.Id()]; exists {
+				continue
+			}
+			fetched[msg.Id()] = struct{}{}
+			row, err := m.lookupRow(msg)
+			if err != nil {
+				return false, err
+			}
+			if row != nil && row.keys != nil {
+				ht.insert(row)
+			}
+		}
+	}
+
+	for _, lrow := range batch {
+		if lrow.keys == nil {
+			if m.keepLeft && !m.emit(lrow, nil) {
+				return false, nil
 			}
+			continue
+		}
+		if !m.probe(ht, lrow) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// lookupRow does for a fetched right side row what the scan of right
+// source would have, apply its where filter and project its columns,
+// nil if filtered out
+func (m *JoinMerge) lookupRow(msg schema.Message) (*joinRow, error) {
+	var raw *datasource.SqlDriverMessageMap
+	switch mt := msg.(type) {
+	case *datasource.SqlDriverMessageMap:
+		raw = mt
+	case *datasource.SqlDriverMessage:
+		raw = datasource.NewSqlDriverMessageMap(mt.IdVal, mt.Vals, m.lookupCols)
+	default:
+		return nil, fmt.Errorf("To use Join lookup must use SqlDriverMessageMap but got %T", msg)
+	}
+
+	src := m.rightStmt.Source
+	if src.Where != nil && src.Where.Expr != nil {
+		wv, ok := vm.Eval(raw, src.Where.Expr)
+		if !ok {
+			return nil, nil
+		}
+		if bv, isBool := wv.(value.BoolValue); !isBool || !bv.Val() {
+			return nil, nil
+		}
+	}
 
+	vals := make([]driver.Value, len(src.Columns))
+	for i, col := range src.Columns {
+		if col.Expr == nil {
+			continue
+		}
+		if v, ok := vm.Eval(raw, col.Expr); ok && v != nil {
+			vals[i] = v.Value()
 		}
-	}()
-	wg.Wait()
-	//u.Info("leaving source scanner")
-	i := uint64(0)
-	for keyLeft, valLeft := range lh {
-		//u.Debugf("compare:  key:%v  left:%#v  right:%#v  rh: %#v", keyLeft, valLeft, rh[keyLeft], rh)
-		if valRight, ok := rh[keyLeft]; ok {
-			//u.Debugf("found match?\n\t%d left=%#v\n\t%d right=%#v", len(valLeft), valLeft, len(valRight), valRight)
-			msgs := m.mergeValueMessages(valLeft, valRight)
-			//u.Debugf("msgsct: %v   msgs:%#v", len(msgs), msgs)
-			for _, msg := range msgs {
-				//outCh <- datasource.NewUrlValuesMsg(i, msg)
-				//u.Debugf("i:%d   msg:%#v", i, msg)
-				msg.IdVal = i
-				i++
-				outCh <- msg
+	}
+	proj := datasource.NewSqlDriverMessageMap(raw.IdVal, vals, src.ColIndexes())
+	return &joinRow{keys: evalJoinKeys(proj, m.rightStmt.JoinNodes()), vals: vals}, nil
+}
+
+// read all rows from a source, evaluating the join key of each
+func (m *JoinMerge) readSource(in MessageChan, nodes []expr.Node, fn func(row *joinRow) (bool, error)) error {
+	for {
+		select {
+		case <-m.SigChan():
+			u.Debugf("got signal quit")
+			return nil
+		case msg, ok := <-in:
+			if !ok {
+				return nil
+			}
+			mt, isSdm := msg.(*datasource.SqlDriverMessageMap)
+			if !isSdm {
+				err := fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
+				u.Errorf("unrecognized msg %T", msg)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+			row := &joinRow{keys: evalJoinKeys(mt, nodes), vals: mt.Vals}
+			cont, err := fn(row)
+			if err != nil {
+				u.Errorf("could not join %v", err)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+			if !cont {
+				return nil
 			}
 		}
 	}
-	return nil
 }
 
-func (m *JoinMerge) mergeValueMessages(lmsgs, rmsgs []*datasource.SqlDriverMessageMap) []*datasource.SqlDriverMessageMap {
-	// m.leftStmt.Columns, m.rightStmt.Columns, nil
-	//func mergeValuesMsgs(lmsgs, rmsgs []datasource.Message, lcols, rcols []*rel.Column, cols map[string]*rel.Column) []*datasource.SqlDriverMessageMap {
-	out := make([]*datasource.SqlDriverMessageMap, 0)
-	//u.Infof("merge values: %v:%v", len(lcols), len(rcols))
-	for _, lm := range lmsgs {
-		//u.Warnf("nice SqlDriverMessageMap: %#v", lmt)
-		for _, rm := range rmsgs {
-			vals := make([]driver.Value, len(m.colIndex))
-			vals = m.valIndexing(vals, lm.Values(), m.leftStmt.Source.Columns)
-			vals = m.valIndexing(vals, rm.Values(), m.rightStmt.Source.Columns)
-			newMsg := datasource.NewSqlDriverMessageMap(0, vals, m.colIndex)
-			//u.Infof("out: %+v", newMsg)
-			out = append(out, newMsg)
+// probe the hash table with a left row, emitting matches, false if
+// we have been told to quit
+func (m *JoinMerge) probe(ht *joinHashTable, lrow *joinRow) bool {
+	matched := false
+	for _, rrow := range ht.rows[joinHashKey(lrow.keys)] {
+		if !joinKeysEqual(lrow.keys, rrow.keys) {
+			continue
+		}
+		matched = true
+		rrow.matched = true
+		if !m.emit(lrow, rrow) {
+			return false
+		}
+	}
+	if !matched && m.keepLeft {
+		return m.emit(lrow, nil)
+	}
+	return true
+}
+
+// emit the build side rows that never matched, for right/full outer joins
+func (m *JoinMerge) emitUnmatched(ht *joinHashTable) bool {
+	if !m.keepRight {
+		return true
+	}
+	for _, rrow := range ht.order {
+		if !rrow.matched {
+			if !m.emit(nil, rrow) {
+				return false
+			}
 		}
 	}
-	return out
+	return true
+}
+
+// join each of the spilled partitions, building a hash table from the
+// right side partition then probing with the left side partition
+func (m *JoinMerge) joinPartitions(ht *joinHashTable) error {
+	for i := range ht.parts {
+		pht := newJoinHashTable(m.Ctx)
+		rdr, err := ht.parts[i].Reader()
+		if err != nil {
+			return err
+		}
+		if err := readJoinRecords(rdr, func(row *joinRow) bool {
+			pht.insert(row)
+			return true
+		}); err != nil {
+			return err
+		}
+		if pht.size > JoinMemoryLimit {
+			u.Warnf("join partition %d is over memory limit %d > %d", i, pht.size, JoinMemoryLimit)
+		}
+		if err := pht.mem.resize(pht.size); err != nil {
+			u.Errorf("join partition %d does not fit in memory %v", i, err)
+			return err
+		}
+		rdr, err = ht.probeParts[i].Reader()
+		if err != nil {
+			return err
+		}
+		quit := false
+		if err := readJoinRecords(rdr, func(row *joinRow) bool {
+			quit = !m.probe(pht, row)
+			return !quit
+		}); err != nil {
+			return err
+		}
+		if quit || !m.emitUnmatched(pht) {
+			return nil
+		}
+		ht.parts[i].Close()
+		ht.probeParts[i].Close()
+		pht.Close()
+	}
+	return nil
+}
+
+// emit a joined row, either side may be nil for outer join null padding,
+// false if we have been told to quit
+func (m *JoinMerge) emit(lrow, rrow *joinRow) bool {
+	vals := make([]driver.Value, len(m.colIndex))
+	if lrow != nil {
+		vals = m.valIndexing(vals, lrow.vals, m.leftStmt.Source.Columns)
+	}
+	if rrow != nil {
+		vals = m.valIndexing(vals, rrow.vals, m.rightStmt.Source.Columns)
+	}
+	msg := datasource.NewSqlDriverMessageMap(m.rowCt, vals, m.colIndex)
+	m.rowCt++
+	select {
+	case m.msgOutCh <- msg:
+		return true
+	case <-m.SigChan():
+		return false
+	}
 }
 
 func (m *JoinMerge) valIndexing(valOut, valSource []driver.Value, cols []*rel.Column) []driver.Value {
@@ -299,17 +552,215 @@ func (m *JoinMerge) valIndexing(valOut, valSource []driver.Value, cols []*rel.Co
 			u.Warnf("not enough values to read col? i=%v len(vals)=%v  %#v", col.ParentIndex, len(valOut), valOut)
 			continue
 		}
-		if col.ParentIndex < 0 {
-			// Negative parent index means the parent query doesn't use this field, ie used
-			// as where, or join key, but not projected
-			u.Errorf("negative parentindex? %s", col)
-			continue
-		}
 		if col.Index < 0 || col.Index >= len(valSource) {
 			u.Errorf("source index out of range? idx:%v of %d  source: %#v  \n\tcol=%#v", col.Index, len(valSource), valSource, col)
+			continue
 		}
 		//u.Infof("found: si=%v pi:%v idx:%d as=%v vals:%v len(out):%v", col.SourceIndex, col.ParentIndex, col.Index, col.As, valSource, len(valOut))
 		valOut[col.ParentIndex] = valSource[col.Index]
 	}
 	return valOut
 }
+
+// a row of one side of join, along with its evaluated join keys
+type joinRow struct {
+	keys    []value.Value // nil if any key part is NULL
+	vals    []driver.Value
+	matched bool
+}
+
+// serialized form of joinRow for spill files
+type joinRecord struct {
+	Keys []driver.Value
+	Vals []driver.Value
+}
+
+// joinHashTable is the in-memory build side of hash join, once it gets
+// over JoinMemoryLimit it moves rows to hash partitioned spill files
+type joinHashTable struct {
+	rows       map[string][]*joinRow
+	order      []*joinRow // insertion order, for emitting unmatched rows
+	size       int64
+	parts      []*spillFile // build side partitions
+	probeParts []*spillFile // probe side partitions
+	mem        *memReservation
+}
+
+func newJoinHashTable(ctx *plan.Context) *joinHashTable {
+	return &joinHashTable{rows: make(map[string][]*joinRow), mem: newMemReservation(ctx)}
+}
+
+func (m *joinHashTable) insert(row *joinRow) {
+	key := joinHashKey(row.keys)
+	m.rows[key] = append(m.rows[key], row)
+	m.order = append(m.order, row)
+	m.size += rowSize(row.vals) + int64(len(key)+16*len(row.keys))
+}
+
+// add a build side row, spilling if over memory limit
+func (m *joinHashTable) add(row *joinRow) error {
+	if m.parts != nil {
+		return writeJoinRecord(m.parts, row)
+	}
+	m.insert(row)
+	if m.size > JoinMemoryLimit || !m.mem.tryResize(m.size) {
+		return m.spill()
+	}
+	return nil
+}
+
+// spill moves the in-memory rows out to partition files
+func (m *joinHashTable) spill() error {
+	var err error
+	if m.parts, err = newJoinPartitions(); err != nil {
+		return err
+	}
+	if m.probeParts, err = newJoinPartitions(); err != nil {
+		return err
+	}
+	for _, row := range m.order {
+		if err := writeJoinRecord(m.parts, row); err != nil {
+			return err
+		}
+	}
+	m.rows = make(map[string][]*joinRow)
+	m.order = nil
+	m.size = 0
+	m.mem.release()
+	return nil
+}
+
+// write a probe side row to its partition
+func (m *joinHashTable) spillProbe(row *joinRow) error {
+	return writeJoinRecord(m.probeParts, row)
+}
+
+// Close removes any spill files
+func (m *joinHashTable) Close() {
+	m.mem.release()
+	for _, f := range m.parts {
+		f.Close()
+	}
+	for _, f := range m.probeParts {
+		f.Close()
+	}
+}
+
+func newJoinPartitions() ([]*spillFile, error) {
+	parts := make([]*spillFile, joinPartitions)
+	for i := range parts {
+		f, err := newSpillFile("join")
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = f
+	}
+	return parts, nil
+}
+
+func writeJoinRecord(parts []*spillFile, row *joinRow) error {
+	key := joinHashKey(row.keys)
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	rec := joinRecord{Keys: make([]driver.Value, len(row.keys)), Vals: row.vals}
+	for i, k := range row.keys {
+		rec.Keys[i] = k.Value()
+	}
+	return parts[h.Sum32()%joinPartitions].Write(&rec)
+}
+
+func readJoinRecords(rdr *spillReader, fn func(row *joinRow) bool) error {
+	for {
+		rec := joinRecord{}
+		if err := rdr.Read(&rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		row := &joinRow{keys: make([]value.Value, len(rec.Keys)), vals: rec.Vals}
+		for i, k := range rec.Keys {
+			row.keys[i] = value.NewValue(k)
+		}
+		if !fn(row) {
+			return nil
+		}
+	}
+}
+
+// evalJoinKeys evaluates the join key expressions against a row, returns
+// nil if any part is NULL (or could not be evaluated) as it can never match
+func evalJoinKeys(msg *datasource.SqlDriverMessageMap, nodes []expr.Node) []value.Value {
+	keys := make([]value.Value, len(nodes))
+	for i, node := range nodes {
+		v, ok := vm.Eval(msg, node)
+		//u.Debugf("evaluating: ok?%v T:%T result=%v node '%v'", ok, v, v, node.String())
+		if !ok || value.IsNull(v) {
+			return nil
+		}
+		keys[i] = v
+	}
+	return keys
+}
+
+// joinHashKey creates the hash table key for join key values.  All values
+// that may be equal under joinValueEqual hash the same:  numbers (and strings
+// that coerce to numbers) use their float value, so 1, 1.0, "1" all share
+// a key; the typed equality check then decides if they actually match.
+func joinHashKey(keys []value.Value) string {
+	var buf bytes.Buffer
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(0)
+		}
+		switch kt := k.(type) {
+		case value.IntValue:
+			buf.WriteString("n" + strconv.FormatFloat(kt.Float(), 'g', -1, 64))
+		case value.NumberValue:
+			buf.WriteString("n" + strconv.FormatFloat(kt.Float(), 'g', -1, 64))
+		case value.TimeValue:
+			buf.WriteString("n" + strconv.FormatFloat(kt.Float(), 'g', -1, 64))
+		case value.BoolValue:
+			if kt.Val() {
+				buf.WriteString("n1")
+			} else {
+				buf.WriteString("n0")
+			}
+		default:
+			if f, ok := value.ToFloat64(k.Rv()); ok && !math.IsNaN(f) {
+				buf.WriteString("n" + strconv.FormatFloat(f, 'g', -1, 64))
+			} else {
+				buf.WriteString("s" + k.ToString())
+			}
+		}
+	}
+	return buf.String()
+}
+
+func joinKeysEqual(a, b []value.Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !joinValueEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// joinValueEqual is the typed equality used for join keys, the coercion
+// rules being those of value.Compare:
+//
+//   - numbers (int, float, bool) are compared as numbers, 1 = 1.0
+//   - a number and a string compare numerically if the string coerces
+//     to a number, 1 = "1", otherwise they are not equal
+//   - two strings compare as strings, "1" != "1.0"
+//   - times compare as instants
+//   - NULL never equals anything, including NULL
+func joinValueEqual(a, b value.Value) bool {
+	if a == nil || b == nil || a.Type() == value.NilType || b.Type() == value.NilType {
+		return false
+	}
+	c, err := value.Compare(a, b)
+	return err == nil && c == 0
+}
@@ -7,9 +7,11 @@ import (
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -17,8 +19,13 @@ var (
 	_ = u.EMPTY
 
 	_ TaskRunner = (*Upsert)(nil)
+	_ TaskRunner = (*Into)(nil)
 	_ TaskRunner = (*DeletionTask)(nil)
 	_ TaskRunner = (*DeletionScanner)(nil)
+
+	// IntoBatchSize is the number of rows of an INSERT ... SELECT, or
+	// SELECT ... INTO, written to the target source in a single PutMulti.
+	IntoBatchSize = 500
 )
 
 type (
@@ -32,6 +39,20 @@ type (
 		db      schema.ConnUpsert
 		dbpatch schema.ConnPatchWhere
 	}
+	// Into task for INSERT ... SELECT and SELECT ... INTO, writes the rows
+	// of the select job, which is not part of the dag of this task, to
+	// the target source in batches
+	Into struct {
+		*TaskBase
+		closed  bool
+		input   TaskRunner
+		db      schema.ConnUpsert
+		width   int   // number of columns of the target
+		colPos  []int // position in the target of each column of input
+		batch   [][]driver.Value
+		written int64
+		err     error
+	}
 	// Delete task for sources that natively support delete
 	DeletionTask struct {
 		*TaskBase
@@ -72,6 +93,19 @@ func NewUpsert(ctx *plan.Context, p *plan.Upsert) *Upsert {
 	return m
 }
 
+// NewInto create a task writing the rows of input to db, each column of
+// the rows of input to the target column at colPos
+func NewInto(ctx *plan.Context, db schema.ConnUpsert, input TaskRunner, cols []string, colPos []int) *Into {
+	m := &Into{
+		TaskBase: NewTaskBase(ctx),
+		db:       db,
+		input:    input,
+		width:    len(cols),
+		colPos:   colPos,
+	}
+	return m
+}
+
 // An inserter to write to data source
 func NewDelete(ctx *plan.Context, p *plan.Delete) *DeletionTask {
 	m := &DeletionTask{
@@ -133,41 +167,58 @@ func (m *Upsert) updateValues() (int64, error) {
 		// fall through
 	}
 
+	var where expr.Node
+	if m.update.Where != nil {
+		where = m.update.Where.Expr
+	}
+
+	// values, and expressions that don't refer to the row (ie params) are
+	// evaluated once, the others for each row (SET x = x + 1)
 	valmap := make(map[string]driver.Value, len(m.update.Values))
+	exprs := make(map[string]expr.Node)
 	for key, valcol := range m.update.Values {
 		//u.Debugf("key:%v  val:%v", key, valcol)
-
-		// TODO: #13  Need a way of expressing which layer (here, db) this expr should run in?
-		//  - ie, run in backend datasource?   or here?  translate the expr to native language
-		if valcol.Expr != nil {
+		switch {
+		case valcol.Expr == nil:
+			valmap[key] = valcol.Value.Value()
+		case len(expr.FindAllIdentityField(valcol.Expr)) > 0:
+			exprs[key] = valcol.Expr
+		default:
 			exprVal, ok := vm.Eval(nil, valcol.Expr)
 			if !ok {
 				u.Errorf("Could not evaluate: %s", valcol.Expr)
 				return 0, fmt.Errorf("Could not evaluate expression: %v", valcol.Expr)
 			}
 			valmap[key] = exprVal.Value()
-		} else {
-			u.Debugf("%T  %v", valcol.Value.Value(), valcol.Value.Value())
-			valmap[key] = valcol.Value.Value()
 		}
 		//u.Debugf("key:%v col: %v   vals:%v", key, valcol, valmap[key])
 	}
+	var patch interface{} = valmap
+	if len(exprs) > 0 {
+		for key, val := range valmap {
+			exprs[key] = expr.NewValueNode(value.NewValue(val))
+		}
+		patch = exprs
+	}
 
 	// if our backend source supports Where-Patches, ie update multiple
-	dbpatch, ok := m.db.(schema.ConnPatchWhere)
-	if ok {
-		updated, err := dbpatch.PatchWhere(m.Ctx, m.update.Where.Expr, valmap)
-		u.Infof("patch: %v %v", updated, err)
+	if dbpatch, ok := m.db.(schema.ConnPatchWhere); ok {
+		updated, err := dbpatch.PatchWhere(m.Ctx, where, patch)
+		u.Debugf("patch: %v %v", updated, err)
 		if err != nil {
 			return updated, err
 		}
 		return updated, nil
 	}
 
-	// TODO:   If it does not implement Where Patch then we need to do a poly fill
-	//      Do we have to recognize if the Where is on a primary key?
-	// - for sources/queries that can't do partial updates we need to do a read first
-	//u.Infof("does not implement PatchWhere")
+	// else poly fill, read the rows to update and write them back
+	if scanner, ok := m.db.(schema.ConnScanner); ok {
+		return m.patchScan(scanner, where, patch)
+	}
+
+	if len(exprs) > 0 || where == nil {
+		return 0, fmt.Errorf("%T can only update a row by key: %s", m.db, m.update)
+	}
 
 	// Create a key from Where
 	key := datasource.KeyFromWhere(m.update.Where)
@@ -180,6 +231,34 @@ func (m *Upsert) updateValues() (int64, error) {
 	return 1, nil
 }
 
+// patchScan update the rows of scanner matching where with patch, they are
+// all read before being written back
+func (m *Upsert) patchScan(scanner schema.ConnScanner, where expr.Node, patch interface{}) (int64, error) {
+	var rows [][]driver.Value
+	for msg := scanner.Next(); msg != nil; msg = scanner.Next() {
+		row, ok := msg.(*datasource.SqlDriverMessageMap)
+		if !ok {
+			return 0, fmt.Errorf("To update must use SqlDriverMessageMap but got %T", msg)
+		}
+		if !vm.Matches(row, where) {
+			continue
+		}
+		vals, err := vm.PatchRow(row.Vals, row.ColIndex, row, patch)
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, vals)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if _, err := m.db.PutMulti(m.Ctx, nil, rows); err != nil {
+		u.Errorf("Could not put values: %v", err)
+		return 0, err
+	}
+	return int64(len(rows)), nil
+}
+
 func (m *Upsert) insertRows(rows [][]*rel.ValueColumn) (int64, error) {
 	for i, row := range rows {
 		//u.Infof("In Insert Scanner iter %#v", row)
@@ -219,6 +298,100 @@ func (m *Upsert) insertRows(rows [][]*rel.ValueColumn) (int64, error) {
 	return int64(len(rows)), nil
 }
 
+func (m *Into) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	if err := m.input.Close(); err != nil {
+		u.Warnf("could not close into input %v", err)
+	}
+	if closer, ok := m.db.(schema.Source); ok {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return m.TaskBase.Close()
+}
+
+func (m *Into) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	collector := NewTaskBase(m.Ctx)
+	collector.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if m.err != nil {
+			return false
+		}
+		select {
+		case <-m.SigChan():
+			m.err = ErrShuttingDown
+			return false
+		default:
+		}
+		var vals []driver.Value
+		switch mt := msg.(type) {
+		case *datasource.SqlDriverMessageMap:
+			vals = mt.Vals
+		case *datasource.SqlDriverMessage:
+			vals = mt.Vals
+		default:
+			m.err = fmt.Errorf("To use INTO must use SqlDriverMessageMap but got %T", msg)
+			return false
+		}
+		if len(vals) != len(m.colPos) {
+			m.err = fmt.Errorf("expected %d columns to write but got %d", len(m.colPos), len(vals))
+			return false
+		}
+		row := make([]driver.Value, m.width)
+		for i, v := range vals {
+			row[m.colPos[i]] = v
+		}
+		m.batch = append(m.batch, row)
+		if len(m.batch) >= IntoBatchSize {
+			m.err = m.flush()
+		}
+		return m.err == nil
+	}
+	err := m.input.Add(collector)
+	if err == nil {
+		err = m.input.Setup(0)
+	}
+	if err == nil {
+		err = m.input.Run()
+	}
+	if err == nil {
+		err = m.err
+	}
+	if err == nil {
+		err = m.flush()
+	}
+	if err != nil {
+		if err != ErrShuttingDown {
+			u.Errorf("could not write rows %v", err)
+		}
+		return err
+	}
+	vals := make([]driver.Value, 2)
+	vals[0] = int64(0)
+	vals[1] = m.written
+	m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+	return nil
+}
+
+// flush write the batched rows to the target
+func (m *Into) flush() error {
+	if len(m.batch) == 0 {
+		return nil
+	}
+	if _, err := m.db.PutMulti(m.Ctx, nil, m.batch); err != nil {
+		return err
+	}
+	m.written += int64(len(m.batch))
+	m.batch = nil
+	return nil
+}
+
 func (m *DeletionTask) Close() error {
 	if m.closed {
 		return nil
@@ -0,0 +1,554 @@
+package exec
+
+import (
+	"container/heap"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*OrderBy)(nil)
+
+	// SortMemoryLimit is the approximate number of bytes of rows an OrderBy
+	// task will hold in memory before spilling a sorted run to a temp file.
+	SortMemoryLimit int64 = 64 * 1024 * 1024
+)
+
+// OrderBy:   Sql Order By Operator
+//   sorts rows on each of the order by columns (asc, desc)
+//
+//   - if there is a Limit (and not distinct), only keeps top (limit + offset)
+//     rows in a heap
+//   - otherwise buffers rows in memory up to SortMemoryLimit (or until the
+//     query's memory account is exhausted), after which sorted runs are
+//     spilled to disk and merged on output.
+//
+//   task   ->  orderby  -->
+//
+type OrderBy struct {
+	*TaskBase
+	closed bool
+	p      *plan.OrderBy
+}
+
+func NewOrderBy(ctx *plan.Context, p *plan.OrderBy) *OrderBy {
+	m := &OrderBy{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+	}
+	return m
+}
+
+func (m *OrderBy) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	return m.TaskBase.Close()
+}
+
+func (m *OrderBy) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	inCh := m.MessageIn()
+	colIndex := m.p.Stmt.ColIndexes()
+
+	sorter := newRowSorter(m.Ctx, m.p.Stmt)
+	defer sorter.Close()
+
+	topN := 0
+	// with distinct, rows are de-duped after sort so we can't cut at limit
+	if m.p.Stmt.Limit > 0 && !m.p.Stmt.Distinct {
+		topN = m.p.Stmt.Limit + m.p.Stmt.Offset
+	}
+	var top *topNHeap
+	if topN > 0 {
+		top = &topNHeap{s: sorter, rows: make([]*sortRow, 0, topN)}
+	}
+
+	seq := uint64(0)
+
+msgReadLoop:
+	for {
+
+		select {
+		case <-m.SigChan():
+			u.Warnf("got signal quit")
+			return nil
+		case msg, ok := <-inCh:
+			if !ok || msg == nil {
+				break msgReadLoop
+			}
+
+			var sdm *datasource.SqlDriverMessageMap
+
+			switch mt := msg.(type) {
+			case *datasource.SqlDriverMessageMap:
+				sdm = mt
+			default:
+				msgReader, isContextReader := msg.(expr.ContextReader)
+				if !isContextReader {
+					err := fmt.Errorf("To use OrderBy must use SqlDriverMessageMap but got %T", msg)
+					u.Errorf("unrecognized msg %T", msg)
+					close(m.TaskBase.sigCh)
+					return err
+				}
+				sdm = datasource.NewSqlDriverMessageMapCtx(msg.Id(), msgReader, colIndex)
+			}
+
+			row := &sortRow{seq: seq, keys: sorter.keys(sdm), msg: sdm}
+			seq++
+
+			if top != nil {
+				top.add(row)
+				continue
+			}
+			if err := sorter.add(row); err != nil {
+				u.Errorf("could not sort %v", err)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+		}
+	}
+
+	if top != nil {
+		sorter.buf = top.rows
+	}
+	return sorter.emit(m)
+}
+
+// send a sorted msg downstream, false if we have been told to quit
+func (m *OrderBy) send(msg *datasource.SqlDriverMessageMap) bool {
+	select {
+	case m.msgOutCh <- msg:
+		return true
+	case <-m.SigChan():
+		return false
+	}
+}
+
+type orderCol struct {
+	expr expr.Node
+	idx  int // position in row for post-aggregate rows, -1 to evaluate expr
+	desc bool
+}
+
+type sortRow struct {
+	seq  uint64
+	keys []value.Value
+	msg  *datasource.SqlDriverMessageMap
+}
+
+// serialized form of sortRow for spill files
+type sortRecord struct {
+	Seq  uint64
+	Id   uint64
+	Keys []driver.Value
+	Vals []driver.Value
+}
+
+// rowSorter evaluates sort keys, compares rows, and holds the buffer
+// and spilled runs of an external merge sort
+type rowSorter struct {
+	cols     []*orderCol
+	buf      []*sortRow
+	bufSize  int64
+	runs     []*spillFile
+	colIndex map[string]int
+	mem      *memReservation
+}
+
+func newRowSorter(ctx *plan.Context, stmt *rel.SqlSelect) *rowSorter {
+	isAgg := stmt.IsAggQuery()
+	cols := make([]*orderCol, len(stmt.OrderBy))
+	for i, col := range stmt.OrderBy {
+		oc := &orderCol{expr: col.Expr, idx: -1, desc: strings.ToUpper(col.Order) == "DESC"}
+		if selIdx, selCol := findOrderBySelectCol(stmt, col); selCol != nil {
+			if isAgg {
+				// post group-by rows are projected in select column order
+				oc.idx = selIdx
+			} else if selCol.Over != nil {
+				// window func values were added to row by the window task
+				oc.expr = &expr.IdentityNode{Text: selCol.As}
+			} else if selCol.Expr != nil {
+				// order by an alias, use the select columns expression
+				oc.expr = selCol.Expr
+			}
+		}
+		cols[i] = oc
+	}
+	return &rowSorter{cols: cols, mem: newMemReservation(ctx)}
+}
+
+// find the select column an order by column refers to, either by alias or
+// by being same expression
+func findOrderBySelectCol(stmt *rel.SqlSelect, col *rel.Column) (int, *rel.Column) {
+	if col.Expr == nil {
+		return -1, nil
+	}
+	if in, ok := col.Expr.(*expr.IdentityNode); ok {
+		for i, sc := range stmt.Columns {
+			if sc.As == in.Text {
+				return i, sc
+			}
+		}
+	}
+	exprStr := col.Expr.String()
+	for i, sc := range stmt.Columns {
+		if sc.Expr != nil && sc.Expr.String() == exprStr {
+			return i, sc
+		}
+	}
+	return -1, nil
+}
+
+func (m *rowSorter) keys(msg *datasource.SqlDriverMessageMap) []value.Value {
+	if m.colIndex == nil {
+		m.colIndex = msg.ColIndex
+	}
+	keys := make([]value.Value, len(m.cols))
+	for i, oc := range m.cols {
+		if oc.idx >= 0 {
+			if oc.idx < len(msg.Vals) {
+				keys[i] = value.NewValue(msg.Vals[oc.idx])
+			} else {
+				keys[i] = value.NewNilValue()
+			}
+			continue
+		}
+		if oc.expr == nil {
+			keys[i] = value.NewNilValue()
+			continue
+		}
+		v, ok := vm.Eval(msg, oc.expr)
+		if !ok || v == nil {
+			keys[i] = value.NewNilValue()
+		} else {
+			keys[i] = v
+		}
+	}
+	return keys
+}
+
+// compare two rows returns -1, 0, 1, equal keys keep input order
+func (m *rowSorter) compare(a, b *sortRow) int {
+	for i, oc := range m.cols {
+		if c := compareSortKey(a.keys[i], b.keys[i]); c != 0 {
+			if oc.desc {
+				return -c
+			}
+			return c
+		}
+	}
+	switch {
+	case a.seq < b.seq:
+		return -1
+	case a.seq > b.seq:
+		return 1
+	}
+	return 0
+}
+
+// compareSortKey compare two sort key values returns -1, 0, 1.  Values
+// are ordered by type first (nulls first), then by value within a type, so
+// the order is the same whatever order the rows are compared in.
+func compareSortKey(a, b value.Value) int {
+	ra, rb := sortKeyRank(a), sortKeyRank(b)
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	}
+	switch ra {
+	case sortRankNil:
+		return 0
+	case sortRankNumber:
+		return compareSortNumber(a, b)
+	}
+	c, err := value.Compare(a, b)
+	if err != nil {
+		c = strings.Compare(a.ToString(), b.ToString())
+	}
+	return c
+}
+
+// compareSortNumber compare two int or number sort keys, ints exactly not
+// as floats, NaN before all numbers
+func compareSortNumber(a, b value.Value) int {
+	if ai, ok := a.(value.IntValue); ok {
+		if bi, ok := b.(value.IntValue); ok {
+			switch {
+			case ai.Val() < bi.Val():
+				return -1
+			case ai.Val() > bi.Val():
+				return 1
+			}
+			return 0
+		}
+	}
+	af, bf := sortNumber(a), sortNumber(b)
+	switch {
+	case math.IsNaN(af) && math.IsNaN(bf):
+		return 0
+	case math.IsNaN(af):
+		return -1
+	case math.IsNaN(bf):
+		return 1
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	}
+	return 0
+}
+
+func sortNumber(v value.Value) float64 {
+	switch vt := v.(type) {
+	case value.IntValue:
+		return float64(vt.Val())
+	case value.NumberValue:
+		return vt.Val()
+	}
+	return math.NaN()
+}
+
+// ranks of the types of sort keys, in sort order
+const (
+	sortRankNil = iota
+	sortRankBool
+	sortRankNumber
+	sortRankTime
+	sortRankString
+	sortRankOther // other types by their ValueType
+)
+
+// sortKeyRank the rank of the type of a sort key, ints and numbers are
+// one rank compared by value
+func sortKeyRank(v value.Value) int {
+	if value.IsNull(v) {
+		return sortRankNil
+	}
+	switch vt := v.Type(); vt {
+	case value.BoolType:
+		return sortRankBool
+	case value.IntType, value.NumberType:
+		return sortRankNumber
+	case value.TimeType:
+		return sortRankTime
+	case value.StringType:
+		return sortRankString
+	default:
+		return sortRankOther + int(vt)
+	}
+}
+
+func (m *rowSorter) add(row *sortRow) error {
+	m.buf = append(m.buf, row)
+	m.bufSize += rowSize(row.msg.Vals) + int64(16*len(row.keys))
+	if m.bufSize > SortMemoryLimit || !m.mem.tryResize(m.bufSize) {
+		return m.spill()
+	}
+	return nil
+}
+
+// spill sort the current buffer and write it out as a run to temp file
+func (m *rowSorter) spill() error {
+	sort.Sort(&sortRows{m.buf, m})
+	f, err := newSpillFile("sort")
+	if err != nil {
+		return err
+	}
+	m.runs = append(m.runs, f)
+	for _, row := range m.buf {
+		rec := sortRecord{Seq: row.seq, Id: row.msg.IdVal, Vals: row.msg.Vals}
+		rec.Keys = make([]driver.Value, len(row.keys))
+		for i, k := range row.keys {
+			rec.Keys[i] = k.Value()
+		}
+		if err := f.Write(&rec); err != nil {
+			u.Errorf("could not write sort run %v", err)
+			return err
+		}
+	}
+	m.buf = m.buf[:0]
+	m.bufSize = 0
+	m.mem.release()
+	return nil
+}
+
+// emit all rows in sorted order to task
+func (m *rowSorter) emit(task *OrderBy) error {
+	sort.Sort(&sortRows{m.buf, m})
+	if len(m.runs) == 0 {
+		for _, row := range m.buf {
+			if !task.send(row.msg) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	// k-way merge of the spilled runs plus whatever is left in memory
+	mh := &mergeHeap{s: m}
+	for _, f := range m.runs {
+		rdr, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		it := &spillRunIter{rdr: rdr, colIndex: m.colIndex}
+		if err := mh.pushNext(it); err != nil {
+			return err
+		}
+	}
+	if err := mh.pushNext(&memRunIter{rows: m.buf}); err != nil {
+		return err
+	}
+	for mh.Len() > 0 {
+		head := mh.heads[0]
+		if !task.send(head.row.msg) {
+			return nil
+		}
+		next, err := head.it.next()
+		if err == io.EOF {
+			heap.Pop(mh)
+			continue
+		} else if err != nil {
+			u.Errorf("could not read sort run %v", err)
+			return err
+		}
+		head.row = next
+		heap.Fix(mh, 0)
+	}
+	return nil
+}
+
+// Close removes any spilled runs
+func (m *rowSorter) Close() {
+	m.mem.release()
+	for _, f := range m.runs {
+		f.Close()
+	}
+	m.runs = nil
+}
+
+type sortRows struct {
+	rows []*sortRow
+	s    *rowSorter
+}
+
+func (m *sortRows) Len() int           { return len(m.rows) }
+func (m *sortRows) Less(i, j int) bool { return m.s.compare(m.rows[i], m.rows[j]) < 0 }
+func (m *sortRows) Swap(i, j int)      { m.rows[i], m.rows[j] = m.rows[j], m.rows[i] }
+
+// topNHeap is a max-heap holding the n lowest sorting rows seen so far
+type topNHeap struct {
+	rows []*sortRow
+	s    *rowSorter
+}
+
+func (m *topNHeap) Len() int           { return len(m.rows) }
+func (m *topNHeap) Less(i, j int) bool { return m.s.compare(m.rows[i], m.rows[j]) > 0 }
+func (m *topNHeap) Swap(i, j int)      { m.rows[i], m.rows[j] = m.rows[j], m.rows[i] }
+func (m *topNHeap) Push(x interface{}) { m.rows = append(m.rows, x.(*sortRow)) }
+func (m *topNHeap) Pop() (x interface{}) {
+	n := len(m.rows)
+	x = m.rows[n-1]
+	m.rows = m.rows[:n-1]
+	return x
+}
+func (m *topNHeap) add(row *sortRow) {
+	if len(m.rows) < cap(m.rows) {
+		heap.Push(m, row)
+		return
+	}
+	if m.s.compare(row, m.rows[0]) < 0 {
+		m.rows[0] = row
+		heap.Fix(m, 0)
+	}
+}
+
+type runIter interface {
+	next() (*sortRow, error)
+}
+
+type memRunIter struct {
+	rows []*sortRow
+	pos  int
+}
+
+func (m *memRunIter) next() (*sortRow, error) {
+	if m.pos >= len(m.rows) {
+		return nil, io.EOF
+	}
+	row := m.rows[m.pos]
+	m.pos++
+	return row, nil
+}
+
+type spillRunIter struct {
+	rdr      *spillReader
+	colIndex map[string]int
+}
+
+func (m *spillRunIter) next() (*sortRow, error) {
+	rec := sortRecord{}
+	if err := m.rdr.Read(&rec); err != nil {
+		return nil, err
+	}
+	row := &sortRow{seq: rec.Seq, keys: make([]value.Value, len(rec.Keys))}
+	for i, k := range rec.Keys {
+		row.keys[i] = value.NewValue(k)
+	}
+	row.msg = datasource.NewSqlDriverMessageMap(rec.Id, rec.Vals, m.colIndex)
+	return row, nil
+}
+
+type mergeHead struct {
+	row *sortRow
+	it  runIter
+}
+
+// mergeHeap is a min-heap of the current head row of each sorted run
+type mergeHeap struct {
+	heads []*mergeHead
+	s     *rowSorter
+}
+
+func (m *mergeHeap) Len() int           { return len(m.heads) }
+func (m *mergeHeap) Less(i, j int) bool { return m.s.compare(m.heads[i].row, m.heads[j].row) < 0 }
+func (m *mergeHeap) Swap(i, j int)      { m.heads[i], m.heads[j] = m.heads[j], m.heads[i] }
+func (m *mergeHeap) Push(x interface{}) { m.heads = append(m.heads, x.(*mergeHead)) }
+func (m *mergeHeap) Pop() (x interface{}) {
+	n := len(m.heads)
+	x = m.heads[n-1]
+	m.heads = m.heads[:n-1]
+	return x
+}
+func (m *mergeHeap) pushNext(it runIter) error {
+	row, err := it.next()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	heap.Push(m, &mergeHead{row: row, it: it})
+	return nil
+}
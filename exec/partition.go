@@ -0,0 +1,82 @@
+package exec
+
+import (
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/plan"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*PartitionMerge)(nil)
+)
+
+// PartitionMerge:   merge of the rows of each partition of a source, the
+//   partitions are scanned (filtered, partially grouped) in parallel
+//   and their rows sent on as they arrive, in no particular order.
+//
+//   partition 1  ->
+//                   \
+//   partition 2  ->  --  merge  -->
+//                   /
+//   partition n  ->
+//
+type PartitionMerge struct {
+	*TaskBase
+	p      *plan.PartitionScan
+	parts  []TaskRunner
+	closed bool
+}
+
+// NewPartitionMerge create a merge of the output of the tasks of each
+// partition, which are run in parallel along side it
+func NewPartitionMerge(ctx *plan.Context, p *plan.PartitionScan, parts []TaskRunner) *PartitionMerge {
+	return &PartitionMerge{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		parts:    parts,
+	}
+}
+
+func (m *PartitionMerge) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	return m.TaskBase.Close()
+}
+
+func (m *PartitionMerge) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	sigChan := m.SigChan()
+	var wg sync.WaitGroup
+	for _, part := range m.parts {
+		wg.Add(1)
+		go func(in MessageChan) {
+			defer wg.Done()
+			for {
+				select {
+				case <-sigChan:
+					return
+				case msg, ok := <-in:
+					if !ok {
+						return
+					}
+					select {
+					case m.msgOutCh <- msg:
+					case <-sigChan:
+						return
+					}
+				}
+			}
+		}(part.MessageOut())
+	}
+	wg.Wait()
+	return nil
+}
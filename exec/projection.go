@@ -113,6 +113,14 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 	columns := m.p.Stmt.Columns
 	colIndex := m.p.Stmt.ColIndexes()
 	limit := m.p.Stmt.Limit
+	offset := 0
+	if isFinal {
+		offset = m.p.Stmt.Offset
+		if m.p.Stmt.Distinct {
+			// the distinct task downstream does offset/limit after de-dupe
+			limit, offset = 0, 0
+		}
+	}
 	if limit == 0 {
 		limit = math.MaxInt32
 	}
@@ -139,6 +147,11 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 		default:
 		}
 
+		if rowCt < offset {
+			rowCt++
+			return true
+		}
+
 		//u.Infof("got projection message: %T %#v", msg, msg.Body())
 		var outMsg schema.Message
 		switch mt := msg.(type) {
@@ -200,6 +213,11 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 
 				} else if col.Expr == nil {
 					u.Warnf("wat?   nil col expr? %#v", col)
+				} else if col.Over != nil {
+					// window func values were added to row by the window task
+					if v, ok := mt.Get(col.As); ok && v != nil {
+						row[i+colCt] = v.Value()
+					}
 				} else {
 					v, ok := vm.Eval(rdr, col.Expr)
 					if !ok {
@@ -291,7 +309,7 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 			u.Errorf("could not project msg:  %T", msg)
 		}
 
-		if rowCt >= limit {
+		if rowCt >= limit+offset {
 			//u.Debugf("%p Projection reaching Limit!!! rowct:%v  limit:%v", m, rowCt, limit)
 			out <- nil // Sending nil message is a message to downstream to shutdown
 			m.Quit()   // should close rest of dag as well
@@ -317,6 +335,8 @@ func (m *Projection) limitEvaluator() MessageHandler {
 	if limit == 0 {
 		limit = math.MaxInt32
 	}
+	offset := m.p.Stmt.Offset
+	limit += offset
 
 	rowCt := 0
 	return func(ctx *plan.Context, msg schema.Message) bool {
@@ -328,6 +348,11 @@ func (m *Projection) limitEvaluator() MessageHandler {
 		default:
 		}
 
+		if rowCt < offset {
+			rowCt++
+			return true // skip it
+		}
+
 		if rowCt >= limit {
 			if rowCt == limit {
 				//u.Debugf("%p Projection reaching Limit!!! rowct:%v  limit:%v", m, rowCt, limit)
@@ -3,6 +3,8 @@ package exec
 import (
 	"database/sql/driver"
 	"io"
+	"reflect"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 const (
@@ -20,7 +23,11 @@ var (
 	_ = u.EMPTY
 
 	// ensure our resultwriter implements database/sql/driver `driver.Rows`
-	_ driver.Rows = (*ResultWriter)(nil)
+	_ driver.Rows                           = (*ResultWriter)(nil)
+	_ driver.RowsColumnTypeDatabaseTypeName = (*ResultWriter)(nil)
+	_ driver.RowsColumnTypeScanType         = (*ResultWriter)(nil)
+	_ driver.RowsColumnTypeNullable         = (*ResultWriter)(nil)
+	_ driver.RowsColumnTypeLength           = (*ResultWriter)(nil)
 
 	// Ensure that we implement the Task Runner interface
 	// to ensure this can run in exec engine
@@ -38,13 +45,19 @@ type ResultExecWriter struct {
 }
 type ResultWriter struct {
 	*TaskBase
-	closed bool
-	cols   []string
+	closed   bool
+	cols     []string
+	fields   []*schema.Field // describe the cols, nil if not known
+	ended    bool            // all rows have been read
+	drained  chan struct{}   // closed once all rows have been read
+	finished chan struct{}   // closed once the job of the rows has run, see finish
+	err      error           // error the job of the rows ran with
 }
 type ResultBuffer struct {
 	*TaskBase
 	closed bool
 	cols   []string
+	err    error
 }
 
 func NewResultExecWriter(ctx *plan.Context) *ResultExecWriter {
@@ -86,15 +99,29 @@ func NewResultRows(ctx *plan.Context, cols []string) *ResultWriter {
 	m := &ResultWriter{
 		TaskBase: stepper.TaskBase,
 		cols:     cols,
+		drained:  make(chan struct{}),
+		finished: make(chan struct{}),
 	}
 	return m
 }
 
+// NewResultBuffer a result task that appends each message to writeTo, the
+// buffered messages are reserved from the query's memory account and
+// once over its limit the rest are dropped and Run errors
 func NewResultBuffer(ctx *plan.Context, writeTo *[]schema.Message) *ResultBuffer {
 	m := &ResultBuffer{
 		TaskBase: NewTaskBase(ctx),
 	}
+	mem := newMemReservation(ctx)
 	m.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if m.err != nil {
+			// keep reading so upstream tasks can finish
+			return true
+		}
+		if m.err = mem.grow(msgSize(msg)); m.err != nil {
+			u.Errorf("could not buffer results %v", m.err)
+			return true
+		}
 		*writeTo = append(*writeTo, msg)
 		//u.Infof("write to msgs: %v", len(*writeTo))
 		return true
@@ -124,6 +151,12 @@ func (m *ResultWriter) Close() error {
 	return m.TaskBase.Close()
 }
 func (m *ResultBuffer) Copy() *ResultBuffer { return NewResultBuffer(m.Ctx, nil) }
+func (m *ResultBuffer) Run() error {
+	if err := m.TaskBase.Run(); err != nil {
+		return err
+	}
+	return m.err
+}
 func (m *ResultBuffer) Close() error {
 	u.Debugf("%p ResultBuffer.Close()???? already closed?%v", m, m.closed)
 	if m.closed {
@@ -138,23 +171,51 @@ func (m *ResultWriter) Next(dest []driver.Value) error {
 	//u.Debugf("resultwriter.Next()")
 	select {
 	case <-m.SigChan():
+		if err := m.Ctx.Err(); err != nil {
+			return err
+		}
 		return ErrShuttingDown
+	case <-m.Ctx.Done():
+		return m.Ctx.Err()
 	case err := <-m.ErrChan():
 		return err
 	case msg, ok := <-m.MessageIn():
-		if !ok {
-			return io.EOF
-		}
-		if msg == nil {
-			//u.Warnf("nil message?")
-			return io.EOF
-			//return fmt.Errorf("Nil message error?")
+		if !ok || msg == nil {
+			return m.end()
 		}
 		//u.Infof("got msg: T:%T   v:%#v", msg, msg)
 		return msgToRow(msg, m.cols, dest)
 	}
 }
 
+// end of the rows, once the job of the rows has run io.EOF, or the
+// error a task of it failed with
+func (m *ResultWriter) end() error {
+	if m.finished == nil {
+		return io.EOF
+	}
+	if !m.ended {
+		m.ended = true
+		close(m.drained)
+	}
+	select {
+	case <-m.finished:
+		if m.err != nil {
+			return m.err
+		}
+		return io.EOF
+	case <-m.Ctx.Done():
+		return m.Ctx.Err()
+	}
+}
+
+// finish the rows with the error the job of them ran with, which Next
+// returns instead of io.EOF
+func (m *ResultWriter) finish(err error) {
+	m.err = err
+	close(m.finished)
+}
+
 // For ResultWriter, since we are are not paging through messages
 //  using this mesage channel, instead using Next() as defined by sql/driver
 //  we don't read the input channel, just watch stop channels
@@ -172,6 +233,9 @@ func (m *ResultWriter) Run() error {
 	case <-m.sigCh:
 		u.Infof("%p got resultwriter.Run() sigquit?", m)
 		return nil
+	case <-m.drained:
+		// the rows have all been read, let the rest of the job end
+		return nil
 	}
 	return nil
 }
@@ -180,6 +244,85 @@ func (m *ResultWriter) Columns() []string {
 	return m.cols
 }
 
+// field describing column i, nil if not known
+func (m *ResultWriter) field(i int) *schema.Field {
+	if i < len(m.fields) {
+		return m.fields[i]
+	}
+	return nil
+}
+func (m *ResultWriter) fieldType(i int) value.ValueType {
+	if f := m.field(i); f != nil {
+		return f.Type
+	}
+	return value.UnknownType
+}
+
+// ColumnTypeDatabaseTypeName the type name of column i, ie BIGINT, VARCHAR,
+// empty if its type is not known
+func (m *ResultWriter) ColumnTypeDatabaseTypeName(i int) string {
+	switch m.fieldType(i) {
+	case value.BoolType:
+		return "BOOL"
+	case value.IntType:
+		return "BIGINT"
+	case value.NumberType:
+		return "DOUBLE"
+	case value.TimeType:
+		return "DATETIME"
+	case value.StringType:
+		return "VARCHAR"
+	case value.ByteSliceType:
+		return "BLOB"
+	case value.JsonType:
+		return "JSON"
+	}
+	return ""
+}
+
+// ColumnTypeScanType the go type of the values of column i, interface{}
+// if its type is not known
+func (m *ResultWriter) ColumnTypeScanType(i int) reflect.Type {
+	switch m.fieldType(i) {
+	case value.BoolType:
+		return reflect.TypeOf(false)
+	case value.IntType:
+		return reflect.TypeOf(int64(0))
+	case value.NumberType:
+		return reflect.TypeOf(float64(0))
+	case value.TimeType:
+		return reflect.TypeOf(time.Time{})
+	case value.StringType:
+		return reflect.TypeOf("")
+	case value.ByteSliceType:
+		return reflect.TypeOf([]byte(nil))
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
+// ColumnTypeNullable if column i may be null, ok is false if not known
+func (m *ResultWriter) ColumnTypeNullable(i int) (nullable, ok bool) {
+	f := m.field(i)
+	if f == nil || f.Type == value.UnknownType {
+		return false, false
+	}
+	return !f.NoNulls, true
+}
+
+// ColumnTypeLength the length of column i if it is of variable length (ie
+// string) and its source declared one, ok is false otherwise
+func (m *ResultWriter) ColumnTypeLength(i int) (length int64, ok bool) {
+	f := m.field(i)
+	if f == nil || f.Length == 0 {
+		return 0, false
+	}
+	switch f.Type {
+	case value.StringType, value.ByteSliceType, value.JsonType:
+		return int64(f.Length), true
+	}
+	return 0, false
+}
+
 func resultWrite(m *ResultWriter) MessageHandler {
 	out := m.MessageOut()
 	return func(ctx *plan.Context, msg schema.Message) bool {
@@ -199,6 +342,29 @@ func resultWrite(m *ResultWriter) MessageHandler {
 	}
 }
 
+// driverValue the go value of val for database/sql, the elements of a
+// slice or map (ie array_agg, json_objectagg) are their go values too not
+// the value.Value
+func driverValue(val value.Value) driver.Value {
+	switch vt := val.(type) {
+	case nil:
+		return nil
+	case value.SliceValue:
+		vals := make([]interface{}, vt.Len())
+		for i, v := range vt.Val() {
+			vals[i] = driverValue(v)
+		}
+		return vals
+	case value.MapValue:
+		vals := make(map[string]interface{}, len(vt.Val()))
+		for k, v := range vt.Val() {
+			vals[k] = driverValue(v)
+		}
+		return vals
+	}
+	return val.Value()
+}
+
 func msgToRow(msg schema.Message, cols []string, dest []driver.Value) error {
 
 	//u.Debugf("msg? %v  %T \n%p %v", msg, msg, dest, dest)
@@ -233,11 +399,14 @@ func msgToRow(msg schema.Message, cols []string, dest []driver.Value) error {
 		for i, key := range cols {
 			//u.Debugf("key=%v mt = nil? %v", key, mt)
 			if val, ok := mt.Get(key); ok && val != nil && !val.Nil() {
-				dest[i] = val.Value()
+				dest[i] = driverValue(val)
 				//u.Infof("key=%v   val=%v", key, val)
 			} else if val == nil {
+				// dest is re-used across rows, don't leave the previous value
+				dest[i] = nil
 				u.Errorf("could not evaluate? %v  %#v", key, mt)
 			} else {
+				dest[i] = nil
 				u.Warnf("missing value? %v %T %v", key, val.Value(), val.Value())
 			}
 		}
@@ -0,0 +1,271 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*SemiJoin)(nil)
+)
+
+// SemiJoin:   filter of rows by an IN, EXISTS sub-query
+//   the sub-query is run first to build a hash set of its keys, then each
+//   row is kept if its key (the Left expressions) is found in the set (IN, EXISTS)
+//   or not found (NOT IN, NOT EXISTS).
+//
+//   - IN is null-aware: a NULL key, or no match when the sub-query returned a
+//     NULL, is unknown so the row is filtered out for both IN and NOT IN.
+//   - EXISTS keys come from correlated equality predicates, a NULL key
+//     never matches so the row is kept for NOT EXISTS.
+//
+//   where  ->  semijoin  ->  projection
+//                 ^
+//             sub-query
+//
+type SemiJoin struct {
+	*TaskBase
+	p      *plan.SemiJoin
+	sub    TaskRunner
+	cols   map[string]*rel.Column
+	closed bool
+
+	rows  int           // number of sub-query rows
+	keys  semiJoinSet   // keys of sub-query rows
+	corr  semiJoinSet   // correlation keys of all sub-query rows (IN only)
+	nulls semiJoinSet   // correlation keys of sub-query rows with NULL value (IN only)
+	left  []expr.Node   // outer row key expressions
+	lvals []value.Value // outer row key values, re-used per row
+	mem   *memReservation
+}
+
+// NewSemiJoin create a semi-join filter task, reading keys from the sub-query
+// task sub, which is not part of the dag of this task
+func NewSemiJoin(ctx *plan.Context, p *plan.SemiJoin, sub TaskRunner) *SemiJoin {
+	m := &SemiJoin{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		sub:      sub,
+		cols:     p.Stmt.UnAliasedColumns(),
+		keys:     make(semiJoinSet),
+		corr:     make(semiJoinSet),
+		nulls:    make(semiJoinSet),
+		left:     p.Left,
+		lvals:    make([]value.Value, len(p.Left)),
+		mem:      newMemReservation(ctx),
+	}
+	return m
+}
+
+func (m *SemiJoin) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	if err := m.sub.Close(); err != nil {
+		u.Warnf("could not close sub-query %v", err)
+	}
+	return m.TaskBase.Close()
+}
+
+func (m *SemiJoin) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+	defer m.mem.release()
+
+	if err := m.runSubQuery(); err != nil {
+		u.Errorf("could not run sub-query %v", err)
+		close(m.TaskBase.sigCh)
+		return err
+	}
+
+	outCh := m.MessageOut()
+	inCh := m.MessageIn()
+
+msgReadLoop:
+	for {
+
+		select {
+		case <-m.SigChan():
+			u.Warnf("got signal quit")
+			return nil
+		case msg, ok := <-inCh:
+			if !ok || msg == nil {
+				break msgReadLoop
+			}
+
+			var reader expr.ContextReader
+			switch mt := msg.(type) {
+			case *datasource.SqlDriverMessage:
+				reader = datasource.NewValueContextWrapper(mt, m.cols)
+			case expr.ContextReader:
+				reader = mt
+			default:
+				err := fmt.Errorf("To use SemiJoin must use ContextReader but got %T", msg)
+				u.Errorf("unrecognized msg %T", msg)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+
+			if !m.keep(reader) {
+				continue
+			}
+			select {
+			case outCh <- msg:
+			case <-m.SigChan():
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// runSubQuery runs the sub-query to completion, collecting its keys, the
+// keys are reserved from the query's memory account
+func (m *SemiJoin) runSubQuery() error {
+	var memErr error
+	collector := NewTaskBase(m.Ctx)
+	collector.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if memErr != nil {
+			return false
+		}
+		var vals []driver.Value
+		switch mt := msg.(type) {
+		case *datasource.SqlDriverMessageMap:
+			vals = mt.Vals
+		case *datasource.SqlDriverMessage:
+			vals = mt.Vals
+		default:
+			u.Warnf("unrecognized sub-query msg %T", msg)
+			return true
+		}
+		if memErr = m.mem.grow(rowSize(vals)); memErr != nil {
+			return false
+		}
+		m.addRow(vals)
+		return true
+	}
+	if err := m.sub.Add(collector); err != nil {
+		return err
+	}
+	if err := m.sub.Setup(0); err != nil {
+		return err
+	}
+	if err := m.sub.Run(); err != nil {
+		return err
+	}
+	return memErr
+}
+
+// addRow adds the keys of a sub-query row
+func (m *SemiJoin) addRow(vals []driver.Value) {
+	m.rows++
+	if len(m.left) == 0 {
+		return
+	}
+	if len(vals) < len(m.left) {
+		u.Warnf("expected %d sub-query columns got %v", len(m.left), vals)
+		return
+	}
+	keys := make([]value.Value, len(m.left))
+	for i := range keys {
+		keys[i] = value.NewValue(vals[i])
+	}
+	first := 0
+	if m.p.NullAware() {
+		first = 1
+	}
+	for _, k := range keys[first:] {
+		if isNull(k) {
+			// correlation key is NULL, can never match an outer row
+			return
+		}
+	}
+	if m.p.NullAware() {
+		m.corr.add(keys[1:])
+		if isNull(keys[0]) {
+			m.nulls.add(keys[1:])
+			return
+		}
+	}
+	m.keys.add(keys)
+}
+
+// keep should this row be kept, is the condition true
+func (m *SemiJoin) keep(reader expr.ContextReader) bool {
+	if len(m.left) == 0 {
+		// un-correlated EXISTS
+		return (m.rows > 0) != m.p.Anti()
+	}
+	hasNull := false
+	for i, n := range m.left {
+		v, ok := vm.Eval(reader, n)
+		if !ok || v == nil {
+			v = value.NewNilValue()
+		}
+		m.lvals[i] = v
+		hasNull = hasNull || isNull(v)
+	}
+	if !m.p.NullAware() {
+		if hasNull {
+			return m.p.Anti()
+		}
+		return m.keys.has(m.lvals) != m.p.Anti()
+	}
+
+	// IN, NOT IN
+	corr := m.lvals[1:]
+	for _, v := range corr {
+		if isNull(v) {
+			// correlation predicate is unknown for every sub-query row
+			// so the sub-query is empty
+			return m.p.Anti()
+		}
+	}
+	switch {
+	case isNull(m.lvals[0]):
+		if m.corr.has(corr) {
+			// unknown
+			return false
+		}
+		return m.p.Anti()
+	case m.keys.has(m.lvals):
+		return !m.p.Anti()
+	case m.nulls.has(corr):
+		// unknown
+		return false
+	}
+	return m.p.Anti()
+}
+
+// semiJoinSet hash set of keys, using the typed join key equality
+type semiJoinSet map[string][][]value.Value
+
+func (m semiJoinSet) add(keys []value.Value) {
+	if m.has(keys) {
+		return
+	}
+	hk := joinHashKey(keys)
+	m[hk] = append(m[hk], keys)
+}
+func (m semiJoinSet) has(keys []value.Value) bool {
+	for _, k := range m[joinHashKey(keys)] {
+		if joinKeysEqual(k, keys) {
+			return true
+		}
+	}
+	return false
+}
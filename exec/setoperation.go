@@ -0,0 +1,257 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*SetOperation)(nil)
+)
+
+// SetOperation:   UNION, INTERSECT, EXCEPT of the rows of two inputs
+//   each input is its own job (select, or nested set operation) which is
+//   run to completion in turn, rows are compared on all of their values
+//   (typed, and NULLs are equal to each other).
+//
+//   - UNION ALL concatenates left then right rows
+//   - UNION de-duplicates the concatenated rows, spilling to disk once
+//     over DistinctMemoryLimit same as Distinct
+//   - INTERSECT, EXCEPT hold a hash of the right rows in memory, then
+//     stream the left rows that are (or are not) in it.  With ALL each
+//     right row matches at most one left row.  The hash is reserved from
+//     the query's memory account, it fails the query if over the limit.
+//
+//   Both inputs must have same number of columns, and values of a column
+//   must be of compatible types.
+//
+//   left, right  ->  set-operation  -->  [orderby]  -->  [limit]
+//
+type SetOperation struct {
+	*TaskBase
+	p        *plan.SetOperation
+	left     TaskRunner
+	right    TaskRunner
+	colIndex map[string]int
+	types    []value.ValueType // type of first non-null value of each column
+	closed   bool
+	quit     bool
+	err      error
+}
+
+// NewSetOperation create a set operation task, reading rows from the left
+// and right tasks which are not part of the dag of this task
+func NewSetOperation(ctx *plan.Context, p *plan.SetOperation, left, right TaskRunner) *SetOperation {
+	m := &SetOperation{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		left:     left,
+		right:    right,
+		colIndex: p.Result.ColIndexes(),
+		types:    make([]value.ValueType, len(p.Result.Columns)),
+	}
+	return m
+}
+
+func (m *SetOperation) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for _, t := range []TaskRunner{m.left, m.right} {
+		if err := t.Close(); err != nil {
+			u.Warnf("could not close set operation input %v", err)
+		}
+	}
+	return m.TaskBase.Close()
+}
+
+func (m *SetOperation) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	var err error
+	switch m.p.Stmt.Op {
+	case lex.TokenUnion:
+		err = m.union()
+	case lex.TokenIntersect, lex.TokenExcept:
+		err = m.compare()
+	default:
+		err = fmt.Errorf("unsupported set operation %s", m.p.Stmt.Op)
+	}
+	if err != nil {
+		u.Errorf("could not run %s %v", m.p.Stmt.Op, err)
+		close(m.TaskBase.sigCh)
+		return err
+	}
+	return nil
+}
+
+// union concatenate left and right, de-duplicating unless ALL
+func (m *SetOperation) union() error {
+	if m.p.Stmt.All {
+		for _, input := range []TaskRunner{m.left, m.right} {
+			if err := m.runInput(input, m.send); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	d := newRowDeduper(m.Ctx)
+	defer d.Close()
+	lim := newOffsetLimit(0, 0)
+	seq := uint64(0)
+	for _, input := range []TaskRunner{m.left, m.right} {
+		err := m.runInput(input, func(msg *datasource.SqlDriverMessageMap) bool {
+			isNew, err := d.add(seq, msg)
+			seq++
+			if err != nil {
+				m.err = err
+				return false
+			}
+			return !isNew || lim.next(m, msg)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return d.emit(m, lim, m.colIndex)
+}
+
+// compare INTERSECT, EXCEPT the left rows with hash of right rows
+func (m *SetOperation) compare() error {
+	// count of right rows by key, for distinct -1 marks keys already sent
+	counts := make(map[string]int)
+	mem := newMemReservation(m.Ctx)
+	defer mem.release()
+	err := m.runInput(m.right, func(msg *datasource.SqlDriverMessageMap) bool {
+		key := distinctKey(msg.Vals)
+		if _, exists := counts[key]; !exists {
+			if err := mem.grow(int64(len(key) + 48)); err != nil {
+				m.err = err
+				return false
+			}
+		}
+		counts[key]++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	intersect, all := m.p.Stmt.Op == lex.TokenIntersect, m.p.Stmt.All
+	return m.runInput(m.left, func(msg *datasource.SqlDriverMessageMap) bool {
+		key := distinctKey(msg.Vals)
+		ct := counts[key]
+		switch {
+		case all && intersect:
+			if ct <= 0 {
+				return true
+			}
+			counts[key] = ct - 1
+		case all:
+			if ct > 0 {
+				counts[key] = ct - 1
+				return true
+			}
+		case intersect:
+			if ct <= 0 {
+				return true
+			}
+			counts[key] = -1
+		default:
+			if ct != 0 {
+				return true
+			}
+			counts[key] = -1
+		}
+		return m.send(msg)
+	})
+}
+
+// runInput runs an input to completion, passing each row to fn as a
+// message keyed by the result columns.  Once fn returns false the
+// remaining rows are dropped.
+func (m *SetOperation) runInput(input TaskRunner, fn func(msg *datasource.SqlDriverMessageMap) bool) error {
+	collector := NewTaskBase(m.Ctx)
+	collector.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if m.quit || m.err != nil {
+			return false
+		}
+		var vals []driver.Value
+		switch mt := msg.(type) {
+		case *datasource.SqlDriverMessageMap:
+			vals = mt.Vals
+		case *datasource.SqlDriverMessage:
+			vals = mt.Vals
+		default:
+			m.err = fmt.Errorf("To use %s must use SqlDriverMessageMap but got %T", m.p.Stmt.Op, msg)
+			return false
+		}
+		if err := m.checkRow(vals); err != nil {
+			m.err = err
+			return false
+		}
+		if !fn(datasource.NewSqlDriverMessageMap(msg.Id(), vals, m.colIndex)) {
+			m.quit = m.err == nil
+			return false
+		}
+		return true
+	}
+	if err := input.Add(collector); err != nil {
+		return err
+	}
+	if err := input.Setup(0); err != nil {
+		return err
+	}
+	if err := input.Run(); err != nil {
+		return err
+	}
+	return m.err
+}
+
+// checkRow ensure row has the result columns, and each value is of a
+// type compatible with the earlier values of that column
+func (m *SetOperation) checkRow(vals []driver.Value) error {
+	if len(vals) != len(m.types) {
+		return fmt.Errorf("each SELECT of %s must have same number of columns: %d != %d",
+			strings.ToUpper(m.p.Stmt.Op.String()), len(m.types), len(vals))
+	}
+	for i, v := range vals {
+		if v == nil {
+			continue
+		}
+		vt := value.NewValue(v).Type()
+		switch prev := m.types[i]; {
+		case prev == value.NilType:
+			m.types[i] = vt
+		case !plan.SetOperationTypesMatch(prev, vt):
+			return fmt.Errorf("%s column %q types do not match: %s != %s",
+				strings.ToUpper(m.p.Stmt.Op.String()), m.p.Result.Columns[i].As, prev, vt)
+		}
+	}
+	return nil
+}
+
+// send a msg downstream, false if we have been told to quit
+func (m *SetOperation) send(msg *datasource.SqlDriverMessageMap) bool {
+	select {
+	case m.msgOutCh <- msg:
+		return true
+	case <-m.SigChan():
+		return false
+	}
+}
@@ -17,7 +17,8 @@ var (
 	_ TaskRunner = (*Source)(nil)
 )
 
-// Source data sources requires context
+// Source data sources requires context, the plan.Context is also the
+// context.Context of the query so sources can stop when it is cancelled
 type RequiresContext interface {
 	SetContext(ctx *plan.Context)
 }
@@ -119,6 +120,7 @@ func (m *Source) Run() error {
 
 	//u.Debugf("scanner: %T %#v", m.Scanner, m.Scanner)
 	sigChan := m.SigChan()
+	done := m.Ctx.Done()
 
 	for item := m.Scanner.Next(); item != nil; item = m.Scanner.Next() {
 
@@ -127,6 +129,9 @@ func (m *Source) Run() error {
 		case <-sigChan:
 			//u.Debugf("exec/source SigChan shutdown")
 			return nil
+		case <-done:
+			// query cancelled, or timed out
+			return m.Ctx.Err()
 		case m.msgOutCh <- item:
 			// continue
 		}
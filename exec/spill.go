@@ -0,0 +1,183 @@
+package exec
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	_ = u.EMPTY
+
+	// SpillDir is the directory used by tasks (sort, etc) that spill
+	// rows to temp files when they exceed their memory budget.  Empty
+	// uses the os default temp dir.
+	SpillDir = ""
+)
+
+func init() {
+	// driver.Values we may need to write out to spill files
+	gob.Register(time.Time{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// spillFile is an append only temp file of gob encoded records, once
+// all records have been written it can be read back in same order
+type spillFile struct {
+	f   *os.File
+	w   *bufio.Writer
+	enc *gob.Encoder
+	ct  int
+}
+
+func newSpillFile(prefix string) (*spillFile, error) {
+	f, err := ioutil.TempFile(SpillDir, "qlbridge_"+prefix)
+	if err != nil {
+		u.Errorf("could not create spill file %v", err)
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &spillFile{f: f, w: w, enc: gob.NewEncoder(w)}, nil
+}
+
+// Write a single record
+func (m *spillFile) Write(rec interface{}) error {
+	m.ct++
+	return m.enc.Encode(rec)
+}
+
+// Reader flushes the file and returns a reader from start of file
+func (m *spillFile) Reader() (*spillReader, error) {
+	if err := m.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := m.f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return &spillReader{dec: gob.NewDecoder(bufio.NewReader(m.f))}, nil
+}
+
+// Close and remove the underlying temp file
+func (m *spillFile) Close() error {
+	if m.f == nil {
+		return nil
+	}
+	name := m.f.Name()
+	err := m.f.Close()
+	m.f = nil
+	os.Remove(name)
+	return err
+}
+
+type spillReader struct {
+	dec *gob.Decoder
+}
+
+// Read next record, returns io.EOF when done
+func (m *spillReader) Read(rec interface{}) error {
+	err := m.dec.Decode(rec)
+	if err == io.ErrUnexpectedEOF {
+		u.Warnf("truncated spill file? %v", err)
+	}
+	return err
+}
+
+// approximate in-memory size of a row of values, used against
+// memory budgets of tasks that buffer rows
+func rowSize(vals []driver.Value) int64 {
+	n := int64(24 + 16*len(vals))
+	for _, v := range vals {
+		switch vt := v.(type) {
+		case string:
+			n += int64(len(vt))
+		case []byte:
+			n += int64(len(vt))
+		case time.Time:
+			n += 24
+		case []string:
+			for _, s := range vt {
+				n += int64(16 + len(s))
+			}
+		case map[string]interface{}:
+			n += int64(64 * len(vt))
+		}
+	}
+	return n
+}
+
+// approximate in-memory size of a message
+func msgSize(msg schema.Message) int64 {
+	switch mt := msg.(type) {
+	case *datasource.SqlDriverMessageMap:
+		return rowSize(mt.Vals)
+	case *datasource.SqlDriverMessage:
+		return rowSize(mt.Vals)
+	}
+	return 64
+}
+
+// memReservation is the bytes a task holds reserved from the memory
+// account of its query, resized as its buffers grow or are spilled
+type memReservation struct {
+	acct *plan.MemoryAccount
+	size int64
+}
+
+func newMemReservation(ctx *plan.Context) *memReservation {
+	m := &memReservation{}
+	if ctx != nil {
+		m.acct = ctx.Memory
+	}
+	return m
+}
+
+// resize the reservation to n bytes, a *plan.MemoryLimitError if the
+// query can't have that much and the reservation is unchanged
+func (m *memReservation) resize(n int64) error {
+	switch {
+	case n > m.size:
+		if err := m.acct.Reserve(n - m.size); err != nil {
+			return err
+		}
+	case n < m.size:
+		m.acct.Release(m.size - n)
+	}
+	m.size = n
+	return nil
+}
+
+// tryResize the reservation to n bytes, false if the query can't have that
+// much and the reservation is unchanged.  For tasks that spill instead.
+func (m *memReservation) tryResize(n int64) bool {
+	switch {
+	case n > m.size:
+		if !m.acct.TryReserve(n - m.size) {
+			return false
+		}
+	case n < m.size:
+		m.acct.Release(m.size - n)
+	}
+	m.size = n
+	return true
+}
+
+// grow the reservation by n bytes
+func (m *memReservation) grow(n int64) error {
+	return m.resize(m.size + n)
+}
+
+// release all of the reservation
+func (m *memReservation) release() {
+	m.resize(0)
+}
@@ -1,16 +1,11 @@
 package exec
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
-	"errors"
 	"fmt"
-	"io"
-	"strconv"
-	"strings"
 	"sync"
-	"time"
 
 	u "github.com/araddon/gou"
 
@@ -19,6 +14,7 @@ import (
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
@@ -27,10 +23,13 @@ var (
 	_ driver.Driver  = (*qlbdriver)(nil)
 	_ driver.Execer  = (*qlbConn)(nil)
 	_ driver.Queryer = (*qlbConn)(nil)
-	_ driver.Result  = (*qlbResult)(nil)
-	_ driver.Rows    = (*qlbRows)(nil)
-	_ driver.Stmt    = (*qlbStmt)(nil)
-	//_ driver.Tx      = (*driverConn)(nil)
+
+	_ driver.ExecerContext  = (*qlbConn)(nil)
+	_ driver.QueryerContext = (*qlbConn)(nil)
+	_ driver.Result         = (*qlbResult)(nil)
+	_ driver.Rows           = (*qlbRows)(nil)
+	_ driver.Stmt           = (*qlbStmt)(nil)
+	_ driver.Tx             = (*qlbTx)(nil)
 
 	// Create an instance of our driver
 	qlbd          = &qlbdriver{}
@@ -109,6 +108,7 @@ type qlbConn struct {
 	parallel bool   // Do we Run In Background Mode?  Default = true
 	connInfo string //
 	schema   *schema.Schema
+	tx       *qlbTx // open transaction, nil if none
 }
 
 // Exec may return ErrSkip.
@@ -116,7 +116,10 @@ type qlbConn struct {
 // Execer implementation. To be used for queries that do not return any rows
 // such as Create Index, Insert, Upset, Delete etc
 func (m *qlbConn) Exec(query string, args []driver.Value) (driver.Result, error) {
-	stmt := &qlbStmt{conn: m, query: query}
+	stmt, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
 	return stmt.Exec(args)
 }
 
@@ -124,13 +127,47 @@ func (m *qlbConn) Exec(query string, args []driver.Value) (driver.Result, error)
 // Query may return ErrSkip
 //
 func (m *qlbConn) Query(query string, args []driver.Value) (driver.Rows, error) {
-	stmt := &qlbStmt{conn: m, query: query}
+	stmt, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
 	return stmt.Query(args)
 }
 
+// ExecContext is Exec which is cancelled when ctx is cancelled or its
+// deadline passes
+func (m *qlbConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	stmt, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.execContext(ctx, args)
+}
+
+// QueryContext is Query which is cancelled when ctx is cancelled or its
+// deadline passes, Rows.Next() then returns the ctx error
+func (m *qlbConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	stmt, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.queryContext(ctx, args)
+}
+
 // Prepare returns a prepared statement, bound to this connection.
+//
+// The query is parsed once, its placeholders (? $1 :name) are bound to
+// the values of the args of each execution.
 func (m *qlbConn) Prepare(query string) (driver.Stmt, error) {
-	return nil, expr.ErrNotImplemented
+	return m.prepare(query)
+}
+
+func (m *qlbConn) prepare(query string) (*qlbStmt, error) {
+	stmt, err := rel.ParseSql(query)
+	if err != nil {
+		return nil, err
+	}
+	return &qlbStmt{conn: m, query: query, stmt: stmt, params: rel.Params(stmt)}, nil
 }
 
 // Close invalidates and potentially stops any current
@@ -143,21 +180,40 @@ func (m *qlbConn) Prepare(query string) (driver.Stmt, error) {
 // do their own connection caching.
 func (m *qlbConn) Close() error {
 	//u.Debugf("sqlbConn.Close() do we need to do anything here?")
+	if m.tx != nil {
+		// don't leave the tables of an abandoned transaction locked
+		return m.tx.Rollback()
+	}
 	return nil
 }
 
 // Begin starts and returns a new transaction.
 func (m *qlbConn) Begin() (driver.Tx, error) {
-	return nil, expr.ErrNotImplemented
+	if m.tx != nil {
+		return nil, fmt.Errorf("a transaction is already open on this connection")
+	}
+	m.tx = &qlbTx{conn: m, tx: plan.NewTransaction()}
+	return m.tx, nil
 }
 
 // sql.Tx Transaction Interface implementation.
-type qlbTx struct{}
+//
+// The statements run on the conn until Commit/Rollback are part of
+// the transaction, the tables of transactional sources
+// (schema.ConnTransaction) they write are only changed on Commit.
+type qlbTx struct {
+	conn *qlbConn
+	tx   *plan.Transaction
+}
 
-func (conn *qlbTx) Commit() error {
-	return expr.ErrNotImplemented
+func (m *qlbTx) Commit() error {
+	m.conn.tx = nil
+	return m.tx.Commit()
+}
+func (m *qlbTx) Rollback() error {
+	m.conn.tx = nil
+	return m.tx.Rollback()
 }
-func (conn *qlbTx) Rollback() error { return expr.ErrNotImplemented }
 
 // driver.Stmt Interface implementation.
 //
@@ -165,9 +221,15 @@ func (conn *qlbTx) Rollback() error { return expr.ErrNotImplemented }
 // used by multiple goroutines concurrently.
 //
 type qlbStmt struct {
-	job   *JobExecutor
-	query string
-	conn  *qlbConn
+	job     *JobExecutor
+	running chan struct{} // closed when the background job of the last Query ends
+	query   string
+	stmt    rel.SqlStatement  // parsed once
+	params  []*expr.ParamNode // placeholders of stmt the args are bound to
+	conn    *qlbConn
+	ctx     *plan.Context     // context stmt was planned in, re-run for each execution
+	pln     plan.Task         // plan of stmt, nil until first executed
+	planTx  *plan.Transaction // transaction stmt was planned in
 }
 
 // Close closes the statement.
@@ -190,58 +252,155 @@ func (m *qlbStmt) Close() error {
 // NumInput may also return -1, if the driver doesn't know
 // its number of placeholders. In that case, the sql package
 // will not sanity check Exec or Query argument counts.
-func (m *qlbStmt) NumInput() int { return 0 }
+//
+// Positional placeholders ? $1 count by the highest position, each
+// distinct :name once.
+func (m *qlbStmt) NumInput() int {
+	positional := 0
+	names := make(map[string]bool)
+	for _, p := range m.params {
+		if p.Name != "" {
+			names[p.Name] = true
+		} else if p.Index+1 > positional {
+			positional = p.Index + 1
+		}
+	}
+	return positional + len(names)
+}
+
+// bind the values of the args to the placeholders, positional ones by
+// the order of the args that are not named, named ones by name
+func (m *qlbStmt) bind(args []driver.NamedValue) error {
+	m.wait()
+	if n := m.NumInput(); len(args) != n {
+		return fmt.Errorf("expected %d args for the placeholders but got %d", n, len(args))
+	}
+	var positional []driver.Value
+	named := make(map[string]driver.Value)
+	for _, nv := range args {
+		if nv.Name != "" {
+			named[nv.Name] = nv.Value
+			continue
+		}
+		positional = append(positional, nv.Value)
+	}
+	for _, p := range m.params {
+		var v driver.Value
+		if p.Name != "" {
+			nv, ok := named[p.Name]
+			if !ok {
+				return fmt.Errorf("no value for parameter %s", p)
+			}
+			v = nv
+		} else if p.Index < len(positional) {
+			v = positional[p.Index]
+		} else {
+			return fmt.Errorf("no value for parameter %s", p)
+		}
+		p.Bind(paramValue(v))
+	}
+	return nil
+}
+
+// wait for the job of the last Query to end, its rows have been closed but
+// its tasks may still be reading the values bound to the params
+func (m *qlbStmt) wait() {
+	if m.running == nil {
+		return
+	}
+	m.job.Close()
+	<-m.running
+	m.running = nil
+}
+
+// build the job to run stmt with the args bound, it is planned once and
+// the plan re-run, only the tasks are made again.  Its plan is made again
+// if it runs in another transaction, or its sources can't be re-opened.
+func (m *qlbStmt) build(c context.Context) (*JobExecutor, error) {
+	var tx *plan.Transaction
+	if m.conn.tx != nil {
+		tx = m.conn.tx.tx
+	}
+	if m.pln != nil && m.planTx == tx {
+		err := m.ctx.Rerun(c)
+		if err == nil {
+			return m.walk(NewExecutor(m.ctx, plan.NewPlanner(m.ctx)))
+		}
+		u.Debugf("re-planning %q: %v", m.query, err)
+	}
+	m.pln = nil
+
+	ctx := plan.NewContextWithContext(c, m.query)
+	ctx.Schema = m.conn.schema
+	ctx.Stmt = m.stmt
+	ctx.Tx = tx
+	job := NewExecutor(ctx, plan.NewPlanner(ctx))
+	pln, err := plan.WalkStmt(ctx, m.stmt, job.Planner)
+	if err != nil {
+		return nil, err
+	}
+	m.ctx, m.pln, m.planTx = ctx, pln, tx
+	return m.walk(job)
+}
+
+// walk the plan into the tasks of job
+func (m *qlbStmt) walk(job *JobExecutor) (*JobExecutor, error) {
+	task, err := job.WalkPlan(m.pln)
+	if err != nil {
+		return nil, err
+	}
+	taskRunner, ok := task.(TaskRunner)
+	if !ok {
+		return nil, fmt.Errorf("Expected TaskRunner but was %T", task)
+	}
+	job.RootTask = taskRunner
+	return job, nil
+}
 
 // Exec executes a query that doesn't return rows, such
 // as an INSERT, UPDATE, DELETE
 func (m *qlbStmt) Exec(args []driver.Value) (driver.Result, error) {
-	var err error
-	if len(args) > 0 {
-		m.query, err = queryArgsConvert(m.query, args)
-		if err != nil {
-			return nil, err
-		}
+	return m.execContext(context.Background(), namedValues(args))
+}
+
+func (m *qlbStmt) execContext(c context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := m.bind(args); err != nil {
+		return nil, err
 	}
 
 	// Create a Job, which is Dag of Tasks that Run()
-	ctx := plan.NewContext(m.query)
-	ctx.Schema = m.conn.schema
-	job, err := BuildSqlJob(ctx)
+	job, err := m.build(c)
 	if err != nil {
 		return nil, err
 	}
 	m.job = job
 
-	resultWriter := NewResultExecWriter(ctx)
+	resultWriter := NewResultExecWriter(job.Ctx)
 	job.RootTask.Add(resultWriter)
 
 	job.Setup()
 	//u.Infof("in qlbdriver.Exec about to run")
-	err = job.Run()
 	//u.Debugf("After qlb driver.Run() in Exec()")
-	if err != nil {
+	if err = job.Run(); err != nil {
 		u.Errorf("error on Query.Run(): %v", err)
-		//resultWriter.ErrChan() <- err
-		//job.Close()
+		return nil, err
 	}
 	return resultWriter.Result(), nil
 }
 
 // Query executes a query that may return rows, such as a SELECT
 func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
-	var err error
-	if len(args) > 0 {
-		m.query, err = queryArgsConvert(m.query, args)
-		if err != nil {
-			return nil, err
-		}
+	return m.queryContext(context.Background(), namedValues(args))
+}
+
+func (m *qlbStmt) queryContext(c context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := m.bind(args); err != nil {
+		return nil, err
 	}
 	//u.Debugf("query: %v", m.query)
 
 	// Create a Job, which is Dag of Tasks that Run()
-	ctx := plan.NewContext(m.query)
-	ctx.Schema = m.conn.schema
-	job, err := BuildSqlJob(ctx)
+	job, err := m.build(c)
 	if err != nil {
 		u.Warnf("return error? %v", err)
 		return nil, err
@@ -250,15 +409,45 @@ func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
 
 	// The only type of stmt that makes sense for Query is SELECT
 	//  and we need list of columns that requires casing
-	sqlSelect, ok := job.Ctx.Stmt.(*rel.SqlSelect)
-	if !ok {
+	var cols []string
+	typed := job.Ctx.Stmt // statement the types of the cols are inferred from
+	switch stmt := job.Ctx.Stmt.(type) {
+	case *rel.SqlSelect:
+		if stmt.Into != nil {
+			job.Close()
+			return nil, fmt.Errorf("SELECT ... INTO does not return rows, it must be Exec'd")
+		}
+		cols = stmt.Columns.AliasedFieldNames()
+	case *rel.SqlSetOperation:
+		// columns are named by the first select, as planned
+		for _, col := range job.Ctx.Projection.Proj.Columns {
+			cols = append(cols, col.As)
+		}
+	case *rel.SqlWith:
+		if sel, ok := stmt.Stmt.(*rel.SqlSelect); ok {
+			cols = sel.Columns.AliasedFieldNames()
+			typed = sel
+			break
+		}
+		for _, col := range job.Ctx.Projection.Proj.Columns {
+			cols = append(cols, col.As)
+		}
+	case *rel.SqlDescribe:
+		// EXPLAIN, columns as planned
+		for _, col := range job.Ctx.Projection.Proj.Columns {
+			cols = append(cols, col.As)
+		}
+	default:
 		u.Warnf("ctx? %v", job.Ctx)
 		return nil, fmt.Errorf("We could not recognize that as a select query: %T", job.Ctx.Stmt)
 	}
 
 	// Prepare a result writer, we manually append this task to end
 	// of job?
-	resultWriter := NewResultRows(ctx, sqlSelect.Columns.AliasedFieldNames())
+	resultWriter := NewResultRows(job.Ctx, cols)
+	if fields := plan.ResultFields(job.Ctx, typed); len(fields) == len(cols) {
+		resultWriter.fields = fields
+	}
 
 	job.RootTask.Add(resultWriter)
 
@@ -266,15 +455,18 @@ func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
 
 	// TODO:   this can't run in parallel-buffered mode?
 	// how to open in go-routine and still be able to send error to rows?
+	running := make(chan struct{})
+	m.running = running
 	go func() {
+		defer close(running)
 		//u.Debugf("Start Job.Run")
-		err = job.Run()
+		err := job.Run()
 		//u.Debugf("After job.Run()")
 		if err != nil {
 			u.Errorf("error on Query.Run(): %v", err)
-			//resultWriter.ErrChan() <- err
-			//job.Close()
 		}
+		// the rows end with the error, once they have been read
+		resultWriter.finish(err)
 		job.Close()
 		//u.Debugf("exiting Background Query")
 	}()
@@ -292,7 +484,9 @@ func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
 // column index.  If the type of a specific column isn't known
 // or shouldn't be handled specially, DefaultValueConverter
 // can be returned.
-func (conn *qlbStmt) ColumnConverter(idx int) driver.ValueConverter { return nil }
+func (conn *qlbStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return driver.DefaultParameterConverter
+}
 
 // driver.Rows Interface implementation.
 //
@@ -339,109 +533,21 @@ func (r *qlbResult) LastInsertId() (int64, error) { return r.lastId, r.err }
 // query.
 func (r *qlbResult) RowsAffected() (int64, error) { return r.affected, r.err }
 
-func join(a []string) string {
-	n := 0
-	for _, s := range a {
-		n += len(s)
-	}
-	b := make([]byte, n)
-	n = 0
-	for _, s := range a {
-		n += copy(b[n:], s)
-	}
-	return string(b)
-}
-
-func queryArgsConvert(query string, args []driver.Value) (string, error) {
-	if len(args) == 0 {
-		return query, nil
-	}
-	// a tiny, tiny, tiny bit of string sanitization
-	if strings.ContainsAny(query, `'"`) {
-		return "", nil
-	}
-	q := make([]string, 2*len(args)+1)
-	n := 0
-	for _, a := range args {
-		i := strings.IndexRune(query, '?')
-		if i == -1 {
-			return "", errors.New("number of parameters doesn't match number of placeholders")
-		}
-		var s string
-		switch v := a.(type) {
-		case nil:
-			s = "NULL"
-		case string:
-			s = "'" + escapeString(v) + "'"
-		case []byte:
-			s = "'" + escapeString(string(v)) + "'"
-		case int64:
-			s = strconv.FormatInt(v, 10)
-		case time.Time:
-			s = "'" + v.Format(MysqlTimeFormat) + "'"
-		case bool:
-			if v {
-				s = "1"
-			} else {
-				s = "0"
-			}
-		case float64:
-			s = strconv.FormatFloat(v, 'e', 12, 64)
-		default:
-			panic(fmt.Sprintf("%v (%T) can't be handled by godrv"))
-		}
-		q[n] = query[:i]
-		q[n+1] = s
-		query = query[i+1:]
-		n += 2
-	}
-	q[n] = query
-	return join(q), nil
-}
-
-func escapeString(txt string) string {
-	var (
-		esc string
-		buf bytes.Buffer
-	)
-	last := 0
-	for ii, bb := range txt {
-		switch bb {
-		case 0:
-			esc = `\0`
-		case '\n':
-			esc = `\n`
-		case '\r':
-			esc = `\r`
-		case '\\':
-			esc = `\\`
-		case '\'':
-			esc = `\'`
-		case '"':
-			esc = `\"`
-		case '\032':
-			esc = `\Z`
-		default:
-			continue
-		}
-		io.WriteString(&buf, txt[last:ii])
-		io.WriteString(&buf, esc)
-		last = ii + 1
+// namedValues the args of Exec, Query as the ordinal args of an
+// ExecContext, QueryContext
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
 	}
-	io.WriteString(&buf, txt[last:])
-	return buf.String()
+	return named
 }
 
-func escapeQuotes(txt string) string {
-	var buf bytes.Buffer
-	last := 0
-	for ii, bb := range txt {
-		if bb == '\'' {
-			io.WriteString(&buf, txt[last:ii])
-			io.WriteString(&buf, `''`)
-			last = ii + 1
-		}
+// paramValue the value a placeholder is bound to, of an arg which is
+// one of the driver.Value types
+func paramValue(v driver.Value) value.Value {
+	if bv, ok := v.([]byte); ok {
+		return value.NewStringValue(string(bv))
 	}
-	io.WriteString(&buf, txt[last:])
-	return buf.String()
+	return value.NewValue(v)
 }
@@ -1,7 +1,12 @@
 package exec_test
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,7 +14,10 @@ import (
 	"github.com/bmizerany/assert"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/memdb"
+	"github.com/araddon/qlbridge/datasource/mockcsv"
 	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/plan"
 )
 
 var _ = u.EMPTY
@@ -374,3 +382,552 @@ func TestSqlDbConnFailure(t *testing.T) {
 	assert.Tf(t, uo1.Price == 22.5, "? %#v", uo1)
 	rows2.Close()
 }
+
+func TestSqlDriverContext(t *testing.T) {
+	// more rows than fit in the task channels, so the query is still
+	// running when cancelled
+	csv := "id,name\n"
+	for i := 0; i < 1000; i++ {
+		csv += fmt.Sprintf("%d,name%d\n", i, i)
+	}
+	mockcsv.LoadTable("names_ctx", csv)
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rows, err := db.QueryContext(ctx, "SELECT id, name FROM names_ctx WHERE id > ?", -1)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	assert.T(t, rows.Next())
+	var id, name string
+	err = rows.Scan(&id, &name)
+	assert.Tf(t, err == nil, "no error: %v", err)
+
+	// cancel the rest of the query
+	cancel()
+	ct := 1
+	for rows.Next() {
+		ct++
+	}
+	assert.Tf(t, rows.Err() == context.Canceled, "expected cancelled but got %v", rows.Err())
+	assert.Tf(t, ct < 1000, "expected cancelled query to stop early but read %d", ct)
+
+	result, err := db.ExecContext(context.Background(), "DELETE FROM users WHERE user_id = ?", "not_a_user")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	affected, err := result.RowsAffected()
+	assert.Tf(t, err == nil && affected == 0, "affected %d err %v", affected, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = db.ExecContext(ctx, "DELETE FROM users WHERE user_id = ?", "not_a_user")
+	assert.Tf(t, err == context.Canceled, "expected cancelled but got %v", err)
+}
+
+func TestSqlDriverPrepare(t *testing.T) {
+	if datasource.DataSourcesRegistry().Get("prepare_users") == nil {
+		mdb, err := memdb.NewMemDb("prepare_users", []string{"user_id", "name", "age"})
+		assert.Tf(t, err == nil, "no error: %v", err)
+		datasource.Register("prepare_users", mdb)
+	}
+
+	db, err := sql.Open("qlbridge", "prepare_users")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	// the values are bound as is, no quoting or escaping
+	ins, err := db.Prepare("INSERT INTO prepare_users (user_id, name, age) VALUES (?, ?, ?)")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	for i, name := range []string{"aaron", "O'Brien", "carla"} {
+		_, err = ins.Exec(int64(i+1), name, int64(20+i*10))
+		assert.Tf(t, err == nil, "no error: %v", err)
+	}
+	_, err = ins.Exec(int64(4), nil, int64(50))
+	assert.Tf(t, err == nil, "no error: %v", err)
+
+	// one parse, many executions with different args
+	sel, err := db.Prepare("SELECT name, age FROM prepare_users WHERE user_id = $1")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer sel.Close()
+	for id, want := range map[int64]string{1: "aaron", 2: "O'Brien", 3: "carla"} {
+		var name string
+		var age int64
+		err = sel.QueryRow(id).Scan(&name, &age)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		assert.Equal(t, want, name)
+		assert.Equal(t, 10+id*10, age)
+	}
+	var name sql.NullString
+	err = sel.QueryRow(int64(4)).Scan(&name, new(int64))
+	assert.Tf(t, err == nil, "no error: %v", err)
+	assert.Tf(t, !name.Valid, "expected null name but got %v", name)
+
+	// the arg count must match the placeholders
+	_, err = sel.Query(int64(1), int64(2))
+	assert.Tf(t, err != nil, "expected error for too many args")
+
+	rows, err := db.Query("SELECT user_id FROM prepare_users WHERE name = :name OR age > :age",
+		sql.Named("name", "O'Brien"), sql.Named("age", 30))
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer rows.Close()
+	ids := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		assert.Tf(t, rows.Scan(&id) == nil, "no error")
+		ids[id] = true
+	}
+	assert.Tf(t, len(ids) == 3 && ids[2] && ids[3] && ids[4], "wrong ids %v", ids)
+}
+
+func TestSqlDriverPrepareRerun(t *testing.T) {
+	mockcsv.LoadTable("rerun_users", "user_id,name\n1,aaron\n2,bob\n3,carla\n4,dan")
+	mockcsv.LoadTable("rerun_orders", "order_id,user_id,price\n10,1,5\n11,2,20\n12,2,30\n13,3,40")
+	mockcsv.LoadTable("rerun_cats", "id,parent_id\n1,0\n2,1\n3,1\n4,2")
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	// one conn, so each statement is prepared once and its plan re-run
+	db.SetMaxOpenConns(1)
+
+	rowsOf := func(stmt *sql.Stmt, args ...interface{}) []string {
+		rows, err := stmt.Query(args...)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		cols, err := rows.Columns()
+		assert.Tf(t, err == nil, "no error: %v", err)
+		var vals []string
+		for rows.Next() {
+			row := make([]string, len(cols))
+			dest := make([]interface{}, len(cols))
+			for i := range row {
+				dest[i] = &row[i]
+			}
+			assert.Tf(t, rows.Scan(dest...) == nil, "no error")
+			vals = append(vals, strings.Join(row, ","))
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		sort.Strings(vals)
+		return vals
+	}
+
+	type run struct {
+		args []interface{}
+		rows []string
+	}
+	for _, tc := range []struct {
+		sql  string
+		runs []run
+	}{
+		{"SELECT ? AS x, ?", []run{
+			{[]interface{}{"a", int64(1)}, []string{"a,1"}},
+			{[]interface{}{"b", int64(2)}, []string{"b,2"}},
+		}},
+		{"SELECT name FROM rerun_users WHERE user_id = ?", []run{
+			{[]interface{}{int64(1)}, []string{"aaron"}},
+			{[]interface{}{int64(3)}, []string{"carla"}},
+			{[]interface{}{int64(1)}, []string{"aaron"}},
+		}},
+		{`SELECT u.name, o.order_id FROM rerun_users AS u
+			INNER JOIN rerun_orders AS o ON u.user_id = o.user_id WHERE o.price > ?`, []run{
+			{[]interface{}{int64(25)}, []string{"bob,12", "carla,13"}},
+			{[]interface{}{int64(10)}, []string{"bob,11", "bob,12", "carla,13"}},
+		}},
+		{"SELECT name FROM rerun_users WHERE user_id IN (SELECT user_id FROM rerun_orders WHERE price > ?)", []run{
+			{[]interface{}{int64(35)}, []string{"carla"}},
+			{[]interface{}{int64(1)}, []string{"aaron", "bob", "carla"}},
+		}},
+		{"SELECT name FROM rerun_users WHERE user_id = ? UNION SELECT name FROM rerun_users WHERE user_id = ?", []run{
+			{[]interface{}{int64(1), int64(2)}, []string{"aaron", "bob"}},
+			{[]interface{}{int64(4), int64(4)}, []string{"dan"}},
+		}},
+		{"WITH big AS (SELECT user_id FROM rerun_orders WHERE price >= ?) SELECT name FROM rerun_users WHERE user_id IN (SELECT user_id FROM big)", []run{
+			{[]interface{}{int64(30)}, []string{"bob", "carla"}},
+			{[]interface{}{int64(40)}, []string{"carla"}},
+		}},
+		{`WITH RECURSIVE tree AS (
+				SELECT id FROM rerun_cats WHERE id = ?
+				UNION ALL
+				SELECT c.id FROM rerun_cats AS c INNER JOIN tree AS t ON c.parent_id = t.id
+			) SELECT id FROM tree`, []run{
+			{[]interface{}{int64(2)}, []string{"2", "4"}},
+			{[]interface{}{int64(1)}, []string{"1", "2", "3", "4"}},
+		}},
+	} {
+		stmt, err := db.Prepare(tc.sql)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		for _, r := range tc.runs {
+			assert.Equalf(t, r.rows, rowsOf(stmt, r.args...), "%s %v", tc.sql, r.args)
+		}
+		stmt.Close()
+	}
+
+	// the rows a prepared update writes are read again by the next run
+	upd, err := db.Prepare("UPDATE rerun_orders SET price = price + ? WHERE user_id = ?")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer upd.Close()
+	for _, args := range [][]interface{}{{int64(1), int64(1)}, {int64(10), int64(2)}, {int64(1), int64(1)}} {
+		result, err := upd.Exec(args...)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		affected, err := result.RowsAffected()
+		assert.Tf(t, err == nil, "no error: %v", err)
+		assert.Tf(t, affected > 0, "expected rows updated for %v", args)
+	}
+	sel, err := db.Prepare("SELECT order_id, price FROM rerun_orders")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer sel.Close()
+	assert.Equal(t, []string{"10,7", "11,30", "12,40", "13,40"}, rowsOf(sel))
+}
+
+func TestSqlDriverTransaction(t *testing.T) {
+	if datasource.DataSourcesRegistry().Get("tx_users") == nil {
+		mdb, err := memdb.NewMemDb("tx_users", []string{"user_id", "name"})
+		assert.Tf(t, err == nil, "no error: %v", err)
+		datasource.Register("tx_users", mdb)
+	}
+
+	db, err := sql.Open("qlbridge", "tx_users")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	names := func(q interface {
+		Query(string, ...interface{}) (*sql.Rows, error)
+	}) []string {
+		rows, err := q.Query("SELECT name FROM tx_users")
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		var names []string
+		for rows.Next() {
+			var name string
+			assert.Tf(t, rows.Scan(&name) == nil, "no error")
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	_, err = db.Exec("DELETE FROM tx_users WHERE user_id < 100")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	_, err = db.Exec(`INSERT INTO tx_users (user_id, name) VALUES (1, "aaron"), (2, "bob")`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+
+	// the writes of a transaction are seen in it, but not outside until commit
+	tx, err := db.Begin()
+	assert.Tf(t, err == nil, "no error: %v", err)
+	_, err = tx.Exec("INSERT INTO tx_users (user_id, name) VALUES (?, ?)", 3, "carla")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	result, err := tx.Exec("DELETE FROM tx_users WHERE name = ?", "aaron")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	affected, err := result.RowsAffected()
+	assert.Tf(t, err == nil && affected == 1, "affected %d err %v", affected, err)
+	assert.Equal(t, []string{"bob", "carla"}, names(tx))
+	assert.Equal(t, []string{"aaron", "bob"}, names(db))
+
+	assert.Tf(t, tx.Rollback() == nil, "no error on rollback")
+	assert.Equal(t, []string{"aaron", "bob"}, names(db))
+
+	tx, err = db.Begin()
+	assert.Tf(t, err == nil, "no error: %v", err)
+	_, err = tx.Exec("INSERT INTO tx_users (user_id, name) VALUES (?, ?)", 3, "carla")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	_, err = tx.Exec("DELETE FROM tx_users WHERE name = ?", "aaron")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	assert.Tf(t, tx.Commit() == nil, "no error on commit")
+	assert.Equal(t, []string{"bob", "carla"}, names(db))
+	assert.Tf(t, tx.Commit() != nil, "transaction is done")
+}
+
+func TestSqlDriverColumnTypes(t *testing.T) {
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	rows, err := db.Query("SELECT user_id, count(*) AS ct FROM users GROUP BY user_id")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	cols, err := rows.ColumnTypes()
+	assert.Tf(t, err == nil, "no error: %v", err)
+	assert.Equal(t, 2, len(cols))
+
+	// the mockcsv tables have no typed fields, so nothing is known
+	assert.Equal(t, "user_id", cols[0].Name())
+	assert.Equal(t, "", cols[0].DatabaseTypeName())
+	_, ok := cols[0].Nullable()
+	assert.Tf(t, !ok, "nullable is unknown")
+	_, ok = cols[0].Length()
+	assert.Tf(t, !ok, "length is unknown")
+
+	assert.Equal(t, "BIGINT", cols[1].DatabaseTypeName())
+	assert.Equal(t, reflect.TypeOf(int64(0)), cols[1].ScanType())
+	nullable, ok := cols[1].Nullable()
+	assert.Tf(t, ok && !nullable, "count is never null")
+	_, ok = cols[1].Length()
+	assert.Tf(t, !ok, "no length for ints")
+
+	ct := 0
+	for rows.Next() {
+		var userId string
+		var cnt int64
+		err = rows.Scan(&userId, &cnt)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		assert.Tf(t, cnt == 1, "one row per user: %d", cnt)
+		ct++
+	}
+	assert.Equal(t, 3, ct)
+	rows.Close()
+
+	rows, err = db.Query(`SELECT toint(referral_count) AS rc, user_id == "9Ip1aKbeZe2njCDM" AS is_aaron FROM users`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer rows.Close()
+	cols, err = rows.ColumnTypes()
+	assert.Tf(t, err == nil, "no error: %v", err)
+	assert.Equal(t, 2, len(cols))
+	assert.Equal(t, "BIGINT", cols[0].DatabaseTypeName())
+	assert.Equal(t, "BOOL", cols[1].DatabaseTypeName())
+	assert.Equal(t, reflect.TypeOf(true), cols[1].ScanType())
+
+	aarons := 0
+	for rows.Next() {
+		var rc int64
+		var isAaron bool
+		err = rows.Scan(&rc, &isAaron)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		if isAaron {
+			aarons++
+		}
+	}
+	assert.Equal(t, 1, aarons)
+
+	typeNames := func(q string) []string {
+		rows, err := db.Query(q)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		cols, err := rows.ColumnTypes()
+		assert.Tf(t, err == nil, "no error: %v", err)
+		names := make([]string, len(cols))
+		for i, col := range cols {
+			names[i] = col.DatabaseTypeName()
+			_, ok := col.Length()
+			assert.Tf(t, !ok, "no length declared for %s of %s", col.Name(), q)
+		}
+		for rows.Next() {
+		}
+		assert.Tf(t, rows.Err() == nil, "no error: %v", rows.Err())
+		return names
+	}
+	assert.Equal(t, []string{"BIGINT", "DOUBLE"},
+		typeNames(`SELECT toint(referral_count) + 1 AS x, toint(referral_count) + 1.5 AS y FROM users`))
+	// mockcsv columns are untyped, so is arithmetic on them
+	assert.Equal(t, []string{"", ""},
+		typeNames(`SELECT referral_count + 1 AS x, 2 * referral_count AS y FROM users`))
+	// a CASE is typed only if all of its results agree
+	assert.Equal(t, []string{"BIGINT", "", "VARCHAR", "", "BIGINT"},
+		typeNames(`SELECT CASE WHEN user_id = "x" THEN 1 ELSE 2 END AS a,
+			CASE WHEN user_id = "x" THEN 1 ELSE 2.5 END AS b,
+			CASE WHEN user_id = "x" THEN "y" END AS c,
+			CASE WHEN user_id = "x" THEN "y" ELSE user_id END AS d,
+			CASE WHEN user_id = "x" THEN NULL ELSE 3 END AS e
+			FROM users`))
+	// a set operation is typed by both its operands
+	assert.Equal(t, []string{"BIGINT", "DOUBLE", "", ""},
+		typeNames(`SELECT toint(referral_count) AS a, toint(referral_count) AS b, user_id, email FROM users
+			UNION SELECT toint(order_id), tonumber(price), order_id, "x" FROM orders`))
+	assert.Equal(t, []string{"", ""},
+		typeNames(`SELECT coalesce(user_id, email) AS c, row_number() OVER (ORDER BY user_id) AS rn FROM users`))
+}
+
+func TestSqlDriverInsertSelect(t *testing.T) {
+	mockcsv.LoadTable("users_archive", "user_id,email\n")
+	mockcsv.LoadTable("users_emails", "email,user_id\n")
+	mockcsv.LoadTable("order_log", "id,who\n")
+
+	// write in more than one batch
+	origBatch := exec.IntoBatchSize
+	exec.IntoBatchSize = 2
+	defer func() { exec.IntoBatchSize = origBatch }()
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	rowsOf := func(q string) []string {
+		rows, err := db.Query(q)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		var vals []string
+		for rows.Next() {
+			var a, b string
+			assert.Tf(t, rows.Scan(&a, &b) == nil, "no error")
+			vals = append(vals, a+"="+b)
+		}
+		sort.Strings(vals)
+		return vals
+	}
+
+	// columns of the select written to the insert's columns in order
+	result, err := db.Exec(`INSERT INTO users_archive (user_id, email) SELECT user_id, email FROM users WHERE user_id != "hT2impsabc345c"`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	affected, err := result.RowsAffected()
+	assert.Tf(t, err == nil && affected == 2, "affected %d err %v", affected, err)
+	assert.Equal(t, []string{"9Ip1aKbeZe2njCDM=aaron@email.com", "hT2impsOPUREcVPc=bob@email.com"},
+		rowsOf("SELECT user_id, email FROM users_archive"))
+
+	// into the columns of the same name, whatever their order
+	result, err = db.Exec(`SELECT user_id, email INTO users_emails FROM users`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	affected, err = result.RowsAffected()
+	assert.Tf(t, err == nil && affected == 3, "affected %d err %v", affected, err)
+	assert.Equal(t, []string{"9Ip1aKbeZe2njCDM=aaron@email.com", "hT2impsOPUREcVPc=bob@email.com", "hT2impsabc345c=not_an_email"},
+		rowsOf("SELECT user_id, email FROM users_emails"))
+
+	// names that aren't columns of the table are written in order
+	result, err = db.Exec(`INSERT INTO order_log SELECT order_id, user_id FROM orders`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	affected, err = result.RowsAffected()
+	assert.Tf(t, err == nil && affected == 3, "affected %d err %v", affected, err)
+	assert.Equal(t, []string{"1=9Ip1aKbeZe2njCDM", "2=9Ip1aKbeZe2njCDM", "3=abcabcabc"},
+		rowsOf("SELECT id, who FROM order_log"))
+
+	_, err = db.Exec(`INSERT INTO users_archive (user_id) SELECT user_id, email FROM users`)
+	assert.Tf(t, err != nil, "expected error for column count mismatch")
+	_, err = db.Exec(`INSERT INTO users_archive (user_id, nope) SELECT user_id, email FROM users`)
+	assert.Tf(t, err != nil, "expected error for unknown column")
+	_, err = db.Exec(`INSERT INTO users_archive SELECT user_id, email, interests FROM users`)
+	assert.Tf(t, err != nil, "expected error for column count mismatch")
+	_, err = db.Query(`SELECT user_id, email INTO users_emails FROM users`)
+	assert.Tf(t, err != nil, "expected error for query of select into")
+}
+
+func TestSqlDriverUpdate(t *testing.T) {
+	if datasource.DataSourcesRegistry().Get("update_users") == nil {
+		mdb, err := memdb.NewMemDb("update_users", []string{"user_id", "name", "ct"})
+		assert.Tf(t, err == nil, "no error: %v", err)
+		datasource.Register("update_users", mdb)
+	}
+	mockcsv.LoadTable("update_events", "id,event\n1,Signup\n2,Logon\n3,Logon")
+
+	rowsOf := func(db *sql.DB, q string) []string {
+		rows, err := db.Query(q)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		defer rows.Close()
+		var vals []string
+		for rows.Next() {
+			var a, b, c string
+			assert.Tf(t, rows.Scan(&a, &b, &c) == nil, "no error")
+			vals = append(vals, a+","+b+","+c)
+		}
+		sort.Strings(vals)
+		return vals
+	}
+	exec := func(db *sql.DB, q string, args ...interface{}) int64 {
+		result, err := db.Exec(q, args...)
+		assert.Tf(t, err == nil, "no error: %v", err)
+		affected, err := result.RowsAffected()
+		assert.Tf(t, err == nil, "no error: %v", err)
+		return affected
+	}
+
+	// memdb patches the rows itself
+	db, err := sql.Open("qlbridge", "update_users")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	exec(db, "DELETE FROM update_users WHERE user_id < 100")
+	exec(db, `INSERT INTO update_users (user_id, name, ct) VALUES (1, "Aaron", 1), (2, "Bob", 5), (3, "Carla", 10)`)
+
+	affected := exec(db, "UPDATE update_users SET ct = ct + 1, name = tolower(name) WHERE ct > 2")
+	assert.Equal(t, int64(2), affected)
+	assert.Equal(t, []string{"1,Aaron,1", "2,bob,6", "3,carla,11"},
+		rowsOf(db, "SELECT user_id, name, ct FROM update_users"))
+
+	affected = exec(db, "UPDATE update_users SET name = ?, ct = ct * ? WHERE user_id = ?", "dan", 3, 1)
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, []string{"1,dan,3", "2,bob,6", "3,carla,11"},
+		rowsOf(db, "SELECT user_id, name, ct FROM update_users"))
+
+	// a NULL value along with an expression
+	affected = exec(db, "UPDATE update_users SET name = NULL, ct = ct + 1 WHERE user_id = 1")
+	assert.Equal(t, int64(1), affected)
+	var name sql.NullString
+	var ct int64
+	err = db.QueryRow("SELECT name, ct FROM update_users WHERE user_id = 1").Scan(&name, &ct)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	assert.Tf(t, !name.Valid, "expected NULL name but got %v", name.String)
+	assert.Equal(t, int64(4), ct)
+
+	// mockcsv rows are scanned, and the matching ones written back
+	csvDb, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer csvDb.Close()
+
+	affected = exec(csvDb, `UPDATE update_events SET event = tolower(event) WHERE event == "Logon"`)
+	assert.Equal(t, int64(2), affected)
+	assert.Equal(t, []string{"1,Signup,1", "2,logon,2", "3,logon,3"},
+		rowsOf(csvDb, "SELECT id, event, id AS id2 FROM update_events"))
+
+	affected = exec(csvDb, `UPDATE update_events SET event = "gone"`)
+	assert.Equal(t, int64(3), affected)
+	assert.Equal(t, []string{"1,gone,1", "2,gone,2", "3,gone,3"},
+		rowsOf(csvDb, "SELECT id, event, id AS id2 FROM update_events"))
+}
+
+func TestSqlDriverRunErrors(t *testing.T) {
+	if datasource.DataSourcesRegistry().Get("run_err_users") == nil {
+		mdb, err := memdb.NewMemDb("run_err_users", []string{"user_id", "name"})
+		assert.Tf(t, err == nil, "no error: %v", err)
+		datasource.Register("run_err_users", mdb)
+	}
+	db, err := sql.Open("qlbridge", "run_err_users")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+	_, err = db.Exec(`INSERT INTO run_err_users (user_id, name) VALUES (1, "aaron")`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+
+	// a task that fails as the statement runs fails the Exec
+	_, err = db.Exec("UPDATE run_err_users SET nosuch = name WHERE user_id = 1")
+	assert.Tf(t, err != nil, "expected error for unknown column")
+
+	// and the rows of a query, once read
+	csv := "id,name\n"
+	for i := 0; i < 200; i++ {
+		csv += fmt.Sprintf("%d,name%03d\n", i, i)
+	}
+	mockcsv.LoadTable("run_err_ids", csv)
+	csvDb, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer csvDb.Close()
+
+	plan.QueryMemoryLimit = 1024
+	defer func() { plan.QueryMemoryLimit = 0 }()
+	rows, err := csvDb.Query("SELECT name FROM run_err_ids WHERE id IN (SELECT id FROM run_err_ids)")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	for rows.Next() {
+	}
+	_, isMemErr := rows.Err().(*plan.MemoryLimitError)
+	assert.Tf(t, isMemErr, "expected memory limit error but got %v", rows.Err())
+	rows.Close()
+}
+
+func TestSqlDriverRecursionLimit(t *testing.T) {
+	// 1 and 2 are each other's parent, walking down the hierarchy never ends
+	mockcsv.LoadTable("cycle_cats", "id,parent_id,name\n1,2,a\n2,1,b\n3,1,c")
+
+	origMax := exec.WithMaxRecursion
+	exec.WithMaxRecursion = 10
+	defer func() { exec.WithMaxRecursion = origMax }()
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Tf(t, err == nil, "no error: %v", err)
+	defer db.Close()
+
+	rows, err := db.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM cycle_cats WHERE id = 1
+			UNION ALL
+			SELECT c.id FROM cycle_cats AS c INNER JOIN tree AS t ON c.parent_id = t.id
+		) SELECT id FROM tree`)
+	assert.Tf(t, err == nil, "no error: %v", err)
+	for rows.Next() {
+	}
+	assert.Tf(t, rows.Err() != nil, "expected recursion limit error")
+	assert.Tf(t, strings.Contains(rows.Err().Error(), "exceeded 10 iterations"), "expected recursion limit error but got %v", rows.Err())
+	rows.Close()
+}
@@ -93,6 +93,26 @@ func (m *TaskBase) Close() error {
 }
 func (m *TaskBase) CloseFinal() error { return nil }
 
+// quitOnDone quits the tasks when the context of the query is cancelled,
+// or its deadline passes.  Call the returned func once the tasks have exited.
+func quitOnDone(ctx *plan.Context, tasks []TaskRunner) func() {
+	done := ctx.Done()
+	if done == nil {
+		return func() {}
+	}
+	exit := make(chan bool)
+	go func() {
+		select {
+		case <-done:
+			for _, task := range tasks {
+				task.Quit()
+			}
+		case <-exit:
+		}
+	}()
+	return func() { close(exit) }
+}
+
 func MakeHandler(task TaskRunner) MessageHandler {
 	out := task.MessageOut()
 	return func(ctx *plan.Context, msg schema.Message) bool {
@@ -120,6 +140,7 @@ func (m *TaskBase) Run() error {
 	ok := true
 	var err error
 	var msg schema.Message
+	done := m.Ctx.Done()
 msgLoop:
 	for ok {
 
@@ -132,6 +153,9 @@ msgLoop:
 		case <-m.sigCh: // Signal, ie quit etc
 			//u.Debugf("got taskbase signal")
 			break msgLoop
+		case <-done: // query cancelled, or timed out
+			err = m.Ctx.Err()
+			break msgLoop
 		default:
 		}
 
@@ -147,6 +171,9 @@ msgLoop:
 			}
 		case <-m.sigCh:
 			break msgLoop
+		case <-done:
+			err = m.Ctx.Err()
+			break msgLoop
 		}
 	}
 
@@ -115,7 +115,11 @@ func (m *TaskSequential) Run() (err error) {
 		//u.Debugf("close TaskSequential: %v", m.Type())
 	}()
 
+	// quit all tasks if the query is cancelled or times out
+	defer quitOnDone(m.Ctx, m.runners)()
+
 	var wg sync.WaitGroup
+	var errMu sync.Mutex
 
 	// Either of the SigQuit, or error channel will
 	//  cause breaking out of task execution below
@@ -144,8 +148,10 @@ func (m *TaskSequential) Run() (err error) {
 			if taskErr := task.Run(); taskErr != nil {
 				u.Errorf("%T.Run() errored %v", task, taskErr)
 				// TODO:  what do we do with this error?   send to error channel?
+				errMu.Lock()
 				err = taskErr
 				m.errors = append(m.errors, taskErr)
+				errMu.Unlock()
 			}
 			//u.Debugf("%p %q exiting taskId: %p %v %T", m, m.Name, task, taskId, task)
 			wg.Done()
@@ -17,6 +17,7 @@ type Where struct {
 	*TaskBase
 	filter expr.Node
 	sel    *rel.SqlSelect
+	having bool
 }
 
 // Where-Filter
@@ -25,6 +26,15 @@ func NewWhere(ctx *plan.Context, p *plan.Where) *Where {
 	if p.Final {
 		return NewWhereFinal(ctx, p)
 	}
+	if p.Filter != nil {
+		// residual of the where, the rest was pushed down to the source
+		s := &Where{
+			TaskBase: NewTaskBase(ctx),
+			filter:   p.Filter,
+		}
+		s.Handler = whereFilter(s.filter, s, p.Stmt.UnAliasedColumns())
+		return s
+	}
 	return NewWhereFilter(ctx, p.Stmt)
 }
 
@@ -34,6 +44,9 @@ func NewWhereFinal(ctx *plan.Context, p *plan.Where) *Where {
 		sel:      p.Stmt,
 		filter:   p.Stmt.Where.Expr,
 	}
+	if p.Filter != nil {
+		s.filter = p.Filter
+	}
 	cols := make(map[string]*rel.Column)
 
 	if len(p.Stmt.From) == 1 {
@@ -86,6 +99,7 @@ func NewHaving(ctx *plan.Context, p *plan.Having) *Where {
 	s := &Where{
 		TaskBase: NewTaskBase(ctx),
 		filter:   p.Stmt.Having,
+		having:   true,
 	}
 	s.Handler = whereFilter(p.Stmt.Having, s, p.Stmt.UnAliasedColumns())
 	return s
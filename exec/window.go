@@ -0,0 +1,435 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Window)(nil)
+
+	// the window function registry
+	windowMu    sync.RWMutex
+	windowFuncs = make(map[string]WindowFuncFactory)
+)
+
+func init() {
+	WindowFuncAdd("row_number", NewRowNumber)
+	WindowFuncAdd("rank", NewRank)
+	WindowFuncAdd("dense_rank", NewDenseRank)
+	WindowFuncAdd("lag", NewLag)
+	WindowFuncAdd("lead", NewLead)
+	WindowFuncAdd("first_value", NewFirstValue)
+	WindowFuncAdd("last_value", NewLastValue)
+}
+
+// WindowFunc is a window function, evaluated for each row of a partition
+// in window order.  Any registered Aggregator may also be used as a window
+// function, it is evaluated over the frame of each row.
+type WindowFunc interface {
+	Eval(w *WindowRows) driver.Value
+}
+
+// WindowFuncFactory creates a new WindowFunc for a column whose expression
+// is a call to the registered window function, ie  rank() OVER (..)
+type WindowFuncFactory func(col *rel.Column) (WindowFunc, error)
+
+// WindowRows is the partition a window function is evaluated over, in
+// window order, and the position of the current row and its frame.
+type WindowRows struct {
+	Rows      []*datasource.SqlDriverMessageMap // rows of the partition
+	Partition int                               // ordinal of the partition
+	Row       int                               // position of the current row in Rows
+	PeerStart int                               // first row with same ORDER BY values as current row
+	PeerGroup int                               // ordinal of the current rows peer group
+	Start     int                               // frame of current row is Rows[Start:End]
+	End       int
+}
+
+// WindowFuncAdd registers a window function by name.  An expr function of
+// this name is also registered so the parser recognizes it, evaluated
+// outside of a window it is NULL.
+func WindowFuncAdd(name string, factory WindowFuncFactory) {
+	name = strings.ToLower(name)
+	windowMu.Lock()
+	windowFuncs[name] = factory
+	windowMu.Unlock()
+	if _, ok := expr.FuncsGet()[name]; !ok {
+		expr.FuncAdd(name, windowOnlyFunc)
+	}
+}
+
+// WindowFuncGet finds the registered window function factory by name
+func WindowFuncGet(name string) (WindowFuncFactory, bool) {
+	windowMu.RLock()
+	defer windowMu.RUnlock()
+	factory, ok := windowFuncs[strings.ToLower(name)]
+	return factory, ok
+}
+
+// NewWindowFunc creates the WindowFunc for a window function column,
+// either a registered window function or aggregate.
+func NewWindowFunc(col *rel.Column) (WindowFunc, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok {
+		return nil, fmt.Errorf("Not a window function: %s", col.Expr)
+	}
+	if factory, ok := WindowFuncGet(fn.Name); ok {
+		return factory(col)
+	}
+	if _, ok := AggregatorGet(fn.Name); ok {
+		agg, err := NewAggregator(col)
+		if err != nil {
+			return nil, err
+		}
+		return &windowAgg{agg: agg, input: newAggInput(agg, col), partition: -1}, nil
+	}
+	return nil, fmt.Errorf("%s is not a window function", fn.Name)
+}
+
+// windowOnlyFunc is the expr function registered for window functions
+func windowOnlyFunc(ctx expr.EvalContext, vals ...value.Value) (value.Value, bool) {
+	return value.NewNilValue(), false
+}
+
+// Window:   evaluate the window function columns  ie rank() OVER (..)
+//   the rows are buffered in memory (reserved from the query's memory
+//   account, over its limit fails the query), then for each distinct window
+//   (PARTITION BY, ORDER BY) they are partitioned and sorted, and the
+//   functions evaluated for each row.  The values are appended to the rows
+//   keyed by the column name for the projection (and order by) to use, rows
+//   are sent on in the order they were received.
+//
+//   task   ->  where  -->  window  -->  [orderby]  -->  projection
+//
+type Window struct {
+	*TaskBase
+	p      *plan.Window
+	cols   []*rel.Column
+	funcs  []WindowFunc
+	specs  []*windowSpec
+	closed bool
+}
+
+// windowSpec the window shared by one or more columns
+type windowSpec struct {
+	over *rel.SqlWindow
+	cols []int // position in Window.cols
+	desc []bool
+}
+
+// NewWindow create a window task for the window function columns of select
+func NewWindow(ctx *plan.Context, p *plan.Window) (*Window, error) {
+	m := &Window{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+	}
+	specs := make(map[string]*windowSpec)
+	for _, col := range p.Stmt.Columns {
+		if col.Over == nil {
+			continue
+		}
+		if f := col.Over.Frame; f != nil && f.Range && (!windowRangeBound(f.Start) || !windowRangeBound(f.End)) {
+			return nil, fmt.Errorf("RANGE window frame only supports UNBOUNDED and CURRENT ROW: %s", col)
+		}
+		fn, err := NewWindowFunc(col)
+		if err != nil {
+			return nil, err
+		}
+		// columns with same partition and order share a sort
+		key := (&rel.SqlWindow{PartitionBy: col.Over.PartitionBy, OrderBy: col.Over.OrderBy}).String()
+		spec, ok := specs[key]
+		if !ok {
+			spec = &windowSpec{over: col.Over}
+			for _, ob := range col.Over.OrderBy {
+				spec.desc = append(spec.desc, strings.ToUpper(ob.Order) == "DESC")
+			}
+			specs[key] = spec
+			m.specs = append(m.specs, spec)
+		}
+		spec.cols = append(spec.cols, len(m.cols))
+		m.cols = append(m.cols, col)
+		m.funcs = append(m.funcs, fn)
+	}
+	return m, nil
+}
+
+func windowRangeBound(b rel.SqlWindowBound) bool {
+	return b.Unbounded || b.Type == lex.TokenCurrentRow
+}
+
+func (m *Window) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	return m.TaskBase.Close()
+}
+
+func (m *Window) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	inCh := m.MessageIn()
+	colIndex := m.p.Stmt.ColIndexes()
+	rows := make([]*datasource.SqlDriverMessageMap, 0)
+	mem := newMemReservation(m.Ctx)
+	defer mem.release()
+
+msgReadLoop:
+	for {
+		select {
+		case <-m.SigChan():
+			return nil
+		case msg, ok := <-inCh:
+			if !ok || msg == nil {
+				break msgReadLoop
+			}
+			var sdm *datasource.SqlDriverMessageMap
+			switch mt := msg.(type) {
+			case *datasource.SqlDriverMessageMap:
+				sdm = mt
+			default:
+				msgReader, isContextReader := msg.(expr.ContextReader)
+				if !isContextReader {
+					err := fmt.Errorf("To use Window must use SqlDriverMessageMap but got %T", msg)
+					u.Errorf("unrecognized msg %T", msg)
+					close(m.TaskBase.sigCh)
+					return err
+				}
+				sdm = datasource.NewSqlDriverMessageMapCtx(msg.Id(), msgReader, colIndex)
+			}
+			if err := mem.grow(rowSize(sdm.Vals)); err != nil {
+				u.Errorf("could not buffer window rows %v", err)
+				close(m.TaskBase.sigCh)
+				return err
+			}
+			rows = append(rows, sdm)
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// values of each window column for each row
+	results := make([][]driver.Value, len(m.cols))
+	for i := range results {
+		results[i] = make([]driver.Value, len(rows))
+	}
+	for _, spec := range m.specs {
+		m.evalSpec(spec, rows, results)
+	}
+
+	outIndex := make(map[string]int, len(rows[0].ColIndex)+len(m.cols))
+	for k, idx := range rows[0].ColIndex {
+		outIndex[k] = idx
+	}
+	width := len(rows[0].Vals)
+	for i, col := range m.cols {
+		outIndex[col.As] = width + i
+	}
+	for ri, row := range rows {
+		vals := make([]driver.Value, width, width+len(m.cols))
+		copy(vals, row.Vals)
+		for i := range m.cols {
+			vals = append(vals, results[i][ri])
+		}
+		select {
+		case m.msgOutCh <- datasource.NewSqlDriverMessageMap(row.IdVal, vals, outIndex):
+		case <-m.SigChan():
+			return nil
+		}
+	}
+	return nil
+}
+
+// evalSpec partition and sort the rows by the window, then evaluate each of
+// the columns of this window for each row
+func (m *Window) evalSpec(spec *windowSpec, rows []*datasource.SqlDriverMessageMap, results [][]driver.Value) {
+	ws := &windowSorter{
+		idx:  make([]int, len(rows)),
+		part: make([]string, len(rows)),
+		keys: make([][]value.Value, len(rows)),
+		desc: spec.desc,
+	}
+	partVals := make([]driver.Value, len(spec.over.PartitionBy))
+	for i, row := range rows {
+		ws.idx[i] = i
+		for pi, n := range spec.over.PartitionBy {
+			partVals[pi] = evalAggArg(row, n).Value()
+		}
+		ws.part[i] = distinctKey(partVals)
+		ws.keys[i] = make([]value.Value, len(spec.over.OrderBy))
+		for oi, ob := range spec.over.OrderBy {
+			ws.keys[i][oi] = evalAggArg(row, ob.Expr)
+		}
+	}
+	sort.Sort(ws)
+
+	partition := 0
+	for ps := 0; ps < len(rows); partition++ {
+		pe := ps + 1
+		for pe < len(rows) && ws.part[ws.idx[pe]] == ws.part[ws.idx[ps]] {
+			pe++
+		}
+		idx := ws.idx[ps:pe]
+		partRows := make([]*datasource.SqlDriverMessageMap, len(idx))
+		for i, ri := range idx {
+			partRows[i] = rows[ri]
+		}
+
+		// peer groups, rows with equal ORDER BY values
+		peerStart := make([]int, len(idx))
+		peerEnd := make([]int, len(idx))
+		peerGroup := make([]int, len(idx))
+		for i := range idx {
+			if i > 0 && ws.peers(idx[i-1], idx[i]) {
+				peerStart[i] = peerStart[i-1]
+				peerGroup[i] = peerGroup[i-1]
+			} else {
+				peerStart[i] = i
+				if i > 0 {
+					peerGroup[i] = peerGroup[i-1] + 1
+				}
+			}
+		}
+		for i := len(idx) - 1; i >= 0; i-- {
+			if i < len(idx)-1 && peerStart[i+1] == peerStart[i] {
+				peerEnd[i] = peerEnd[i+1]
+			} else {
+				peerEnd[i] = i + 1
+			}
+		}
+
+		for _, ci := range spec.cols {
+			col, fn := m.cols[ci], m.funcs[ci]
+			w := &WindowRows{Rows: partRows, Partition: partition}
+			for i, ri := range idx {
+				w.Row, w.PeerStart, w.PeerGroup = i, peerStart[i], peerGroup[i]
+				w.Start, w.End = windowFrame(col.Over, i, peerStart[i], peerEnd[i], len(idx))
+				results[ci][ri] = fn.Eval(w)
+			}
+		}
+		ps = pe
+	}
+}
+
+// windowFrame the frame [start, end) of row i of a partition of n rows
+//   - no frame, with ORDER BY rows up to and including peers of current row
+//   - no frame, no ORDER BY whole partition
+func windowFrame(over *rel.SqlWindow, i, peerStart, peerEnd, n int) (int, int) {
+	f := over.Frame
+	if f == nil {
+		if len(over.OrderBy) == 0 {
+			return 0, n
+		}
+		return 0, peerEnd
+	}
+	bound := func(b rel.SqlWindowBound, isEnd bool) int {
+		switch {
+		case b.Unbounded && b.Type == lex.TokenPreceding:
+			return 0
+		case b.Unbounded:
+			return n
+		case b.Type == lex.TokenCurrentRow && f.Range:
+			if isEnd {
+				return peerEnd
+			}
+			return peerStart
+		}
+		pos := i
+		switch b.Type {
+		case lex.TokenPreceding:
+			pos -= b.Offset
+		case lex.TokenFollowing:
+			pos += b.Offset
+		}
+		if isEnd {
+			pos++
+		}
+		return pos
+	}
+	start, end := bound(f.Start, false), bound(f.End, true)
+	if start < 0 {
+		start = 0
+	}
+	if end > n {
+		end = n
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// windowSorter sorts row positions by partition, then window ORDER BY,
+// then input order
+type windowSorter struct {
+	idx  []int
+	part []string
+	keys [][]value.Value
+	desc []bool
+}
+
+func (m *windowSorter) Len() int      { return len(m.idx) }
+func (m *windowSorter) Swap(i, j int) { m.idx[i], m.idx[j] = m.idx[j], m.idx[i] }
+func (m *windowSorter) Less(i, j int) bool {
+	a, b := m.idx[i], m.idx[j]
+	if m.part[a] != m.part[b] {
+		return m.part[a] < m.part[b]
+	}
+	if c := m.compareKeys(a, b); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+func (m *windowSorter) compareKeys(a, b int) int {
+	for i, desc := range m.desc {
+		if c := compareSortKey(m.keys[a][i], m.keys[b][i]); c != 0 {
+			if desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+// peers are rows a, b equal on the window ORDER BY
+func (m *windowSorter) peers(a, b int) bool { return m.compareKeys(a, b) == 0 }
+
+// windowAgg an aggregate evaluated over the frame of each row, while the
+// frame start doesn't move rows are added to the running aggregate
+type windowAgg struct {
+	agg        Aggregator
+	input      aggInput
+	partition  int
+	start, end int // frame that has been aggregated
+}
+
+func (m *windowAgg) Eval(w *WindowRows) driver.Value {
+	if w.Partition != m.partition || w.Start != m.start || w.End < m.end {
+		m.agg.Reset()
+		m.partition, m.start, m.end = w.Partition, w.Start, w.Start
+	}
+	for ; m.end < w.End; m.end++ {
+		v, _ := m.input.eval(w.Rows[m.end])
+		m.agg.Do(v)
+	}
+	return m.agg.Result()
+}
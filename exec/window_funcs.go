@@ -0,0 +1,151 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+// windowFuncArgs the args of a window function column, between min and max
+// of them
+func windowFuncArgs(col *rel.Column, min, max int) ([]expr.Node, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok {
+		return nil, fmt.Errorf("Not a window function: %s", col.Expr)
+	}
+	if len(fn.Args) < min || len(fn.Args) > max {
+		if min == max {
+			return nil, fmt.Errorf("%s takes %d args but got %d", fn.Name, min, len(fn.Args))
+		}
+		return nil, fmt.Errorf("%s takes %d to %d args but got %d", fn.Name, min, max, len(fn.Args))
+	}
+	return fn.Args, nil
+}
+
+// rowNumber the position of the row in its partition, starting at 1
+type rowNumber struct{}
+
+func (m *rowNumber) Eval(w *WindowRows) driver.Value { return int64(w.Row + 1) }
+
+func NewRowNumber(col *rel.Column) (WindowFunc, error) {
+	if _, err := windowFuncArgs(col, 0, 0); err != nil {
+		return nil, err
+	}
+	return &rowNumber{}, nil
+}
+
+// rank the position of the first peer of row in its partition, so there
+// are gaps after peers  1, 1, 3.  dense_rank is the position of the peer
+// group, without gaps  1, 1, 2
+type rank struct {
+	dense bool
+}
+
+func (m *rank) Eval(w *WindowRows) driver.Value {
+	if m.dense {
+		return int64(w.PeerGroup + 1)
+	}
+	return int64(w.PeerStart + 1)
+}
+
+func NewRank(col *rel.Column) (WindowFunc, error) {
+	if _, err := windowFuncArgs(col, 0, 0); err != nil {
+		return nil, err
+	}
+	return &rank{}, nil
+}
+func NewDenseRank(col *rel.Column) (WindowFunc, error) {
+	if _, err := windowFuncArgs(col, 0, 0); err != nil {
+		return nil, err
+	}
+	return &rank{dense: true}, nil
+}
+
+// lagLead the value of arg for the row offset (default 1) rows before
+// (lag) or after (lead) the current row in the partition, or the default
+// (NULL) if there is no such row
+//
+//     lag(price, 1, 0) OVER (PARTITION BY user_id ORDER BY ts)
+type lagLead struct {
+	arg    expr.Node
+	offset expr.Node
+	def    expr.Node
+	sign   int
+}
+
+func (m *lagLead) Eval(w *WindowRows) driver.Value {
+	cur := w.Rows[w.Row]
+	offset := 1
+	if m.offset != nil {
+		v := evalAggArg(cur, m.offset)
+		if v.Nil() {
+			return nil
+		}
+		n, ok := value.ToInt64(v.Rv())
+		if !ok {
+			return nil
+		}
+		offset = int(n)
+	}
+	pos := w.Row + m.sign*offset
+	if pos < 0 || pos >= len(w.Rows) {
+		if m.def == nil {
+			return nil
+		}
+		return evalAggArg(cur, m.def).Value()
+	}
+	return evalAggArg(w.Rows[pos], m.arg).Value()
+}
+
+func newLagLead(col *rel.Column, sign int) (WindowFunc, error) {
+	args, err := windowFuncArgs(col, 1, 3)
+	if err != nil {
+		return nil, err
+	}
+	m := &lagLead{arg: args[0], sign: sign}
+	if len(args) > 1 {
+		m.offset = args[1]
+	}
+	if len(args) > 2 {
+		m.def = args[2]
+	}
+	return m, nil
+}
+func NewLag(col *rel.Column) (WindowFunc, error)  { return newLagLead(col, -1) }
+func NewLead(col *rel.Column) (WindowFunc, error) { return newLagLead(col, 1) }
+
+// firstLastValue the value of arg for the first (or last) row of the frame
+// of the current row, NULL if the frame is empty
+type firstLastValue struct {
+	arg  expr.Node
+	last bool
+}
+
+func (m *firstLastValue) Eval(w *WindowRows) driver.Value {
+	if w.Start >= w.End {
+		return nil
+	}
+	row := w.Rows[w.Start]
+	if m.last {
+		row = w.Rows[w.End-1]
+	}
+	return evalAggArg(row, m.arg).Value()
+}
+
+func NewFirstValue(col *rel.Column) (WindowFunc, error) {
+	args, err := windowFuncArgs(col, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &firstLastValue{arg: args[0]}, nil
+}
+func NewLastValue(col *rel.Column) (WindowFunc, error) {
+	args, err := windowFuncArgs(col, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &firstLastValue{arg: args[0], last: true}, nil
+}
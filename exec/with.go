@@ -0,0 +1,197 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*With)(nil)
+
+	// WithMaxRecursion is the most iterations of the recursive step of a
+	// WITH RECURSIVE before it is an error, ie a cycle in a hierarchy
+	// walked with UNION ALL.
+	WithMaxRecursion = 1000
+)
+
+// With:   common table expressions of a WITH statement.  The rows of
+//   each cte are materialized in memory in order, by running its job to
+//   completion, then the job of the statement using them is run and its
+//   rows sent downstream.  For WITH RECURSIVE the recursive step is run
+//   against the rows of the previous step until it returns no new rows,
+//   de-duplicating all rows of the cte unless UNION ALL.  The rows are
+//   reserved from the query's memory account, over its limit fails the query.
+//
+//   cte anchor, [recursive step ...]  -->  ...  -->  main  -->
+//
+type With struct {
+	*TaskBase
+	p       *plan.With
+	anchors []TaskRunner
+	main    TaskRunner
+	walk    func(p plan.Task) (TaskRunner, error)
+	closed  bool
+	err     error
+	mem     *memReservation
+}
+
+// NewWith create a with task, the anchor of each cte, and main are jobs
+// which are not part of the dag of this task, walk creates the job of
+// each recursive step
+func NewWith(ctx *plan.Context, p *plan.With, anchors []TaskRunner, main TaskRunner,
+	walk func(p plan.Task) (TaskRunner, error)) *With {
+	return &With{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		anchors:  anchors,
+		main:     main,
+		walk:     walk,
+		mem:      newMemReservation(ctx),
+	}
+}
+
+func (m *With) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for _, t := range append(m.anchors, m.main) {
+		if err := t.Close(); err != nil {
+			u.Warnf("could not close with input %v", err)
+		}
+	}
+	return m.TaskBase.Close()
+}
+
+func (m *With) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+	defer m.mem.release()
+
+	for i, cte := range m.p.Ctes {
+		if err := m.materialize(cte, m.anchors[i]); err != nil {
+			u.Errorf("could not run WITH %s %v", cte.Stmt.Name, err)
+			close(m.TaskBase.sigCh)
+			return err
+		}
+	}
+	err := m.runInput(m.main, func(msg schema.Message) bool {
+		select {
+		case m.msgOutCh <- msg:
+			return true
+		case <-m.SigChan():
+			return false
+		}
+	})
+	if err != nil {
+		u.Errorf("could not run WITH %v", err)
+		close(m.TaskBase.sigCh)
+		return err
+	}
+	return nil
+}
+
+// materialize the rows of a cte
+func (m *With) materialize(cte *plan.Cte, anchor TaskRunner) error {
+
+	colIndex := make(map[string]int, len(cte.Tbl.Columns()))
+	for i, col := range cte.Tbl.Columns() {
+		colIndex[col] = i
+	}
+	dedupe := cte.Recursive() && !cte.All
+	seen := make(map[string]struct{})
+
+	rows := make([]schema.Message, 0)
+	var added []schema.Message
+	collect := func(msg schema.Message) bool {
+		var vals []driver.Value
+		switch mt := msg.(type) {
+		case *datasource.SqlDriverMessageMap:
+			vals = mt.Vals
+		case *datasource.SqlDriverMessage:
+			vals = mt.Vals
+		default:
+			m.err = fmt.Errorf("To use WITH must use SqlDriverMessageMap but got %T", msg)
+			return false
+		}
+		if len(vals) != len(colIndex) {
+			m.err = fmt.Errorf("WITH %s has %d columns but row has %d", cte.Stmt.Name, len(colIndex), len(vals))
+			return false
+		}
+		if dedupe {
+			key := distinctKey(vals)
+			if _, ok := seen[key]; ok {
+				return true
+			}
+			seen[key] = struct{}{}
+		}
+		if err := m.mem.grow(rowSize(vals)); err != nil {
+			m.err = err
+			return false
+		}
+		row := datasource.NewSqlDriverMessageMap(uint64(len(rows)), vals, colIndex)
+		rows = append(rows, row)
+		added = append(added, row)
+		return true
+	}
+
+	if err := m.runInput(anchor, collect); err != nil {
+		return err
+	}
+	for i := 0; cte.Recursive() && len(added) > 0; i++ {
+		if i >= WithMaxRecursion {
+			return fmt.Errorf("WITH RECURSIVE %s exceeded %d iterations", cte.Stmt.Name, WithMaxRecursion)
+		}
+		cte.SetWorkRows(added)
+		added = nil
+		step, err := cte.PlanStep()
+		if err != nil {
+			return err
+		}
+		tr, err := m.walk(step)
+		if err != nil {
+			return err
+		}
+		err = m.runInput(tr, collect)
+		tr.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if cte.Recursive() {
+		cte.SetWorkRows(nil)
+	}
+	cte.Rows = rows
+	return nil
+}
+
+// runInput runs a job to completion, passing each row to fn.  Once fn
+// returns false the remaining rows are dropped.
+func (m *With) runInput(input TaskRunner, fn func(msg schema.Message) bool) error {
+	collector := NewTaskBase(m.Ctx)
+	collector.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if m.err != nil {
+			return false
+		}
+		return fn(msg)
+	}
+	if err := input.Add(collector); err != nil {
+		return err
+	}
+	if err := input.Setup(0); err != nil {
+		return err
+	}
+	if err := input.Run(); err != nil {
+		return err
+	}
+	return m.err
+}
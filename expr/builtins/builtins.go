@@ -56,6 +56,11 @@ func LoadAllBuiltins() {
 		expr.FuncAdd("exists", Exists)
 		expr.FuncAdd("map", MapFunc)
 
+		// null handling
+		expr.FuncAdd("coalesce", Coalesce)
+		expr.FuncAdd("ifnull", IfNull)
+		expr.FuncAdd("nullif", NullIf)
+
 		// Date/Time functions
 		expr.FuncAdd("now", Now)
 		expr.FuncAdd("yy", Yy)
@@ -408,6 +413,53 @@ func Exists(ctx expr.EvalContext, item interface{}) (value.BoolValue, bool) {
 	return value.BoolValueFalse, true
 }
 
+// Coalesce:  returns the first arg which is not NULL, NULL if they all are.
+//   The vm only evaluates args until it finds one.  Empty values are NULL.
+//
+//     coalesce(not_a_field, "", "default") => "default"
+//     coalesce(not_a_field, 5) => 5
+//     coalesce(not_a_field) => NULL
+//
+func Coalesce(ctx expr.EvalContext, vals ...value.Value) (value.Value, bool) {
+	for _, v := range vals {
+		if !value.IsNull(v) {
+			return v, true
+		}
+	}
+	return value.NilValueVal, true
+}
+
+// IfNull:  returns the first arg unless it is NULL, then the second
+//
+//     ifnull(not_a_field, "default") => "default"
+//     ifnull("hello", "default") => "hello"
+//
+func IfNull(ctx expr.EvalContext, item, alt value.Value) (value.Value, bool) {
+	if value.IsNull(item) {
+		return alt, true
+	}
+	return item, true
+}
+
+// NullIf:  returns NULL if the args are equal, else the first arg
+//
+//     nullif("", "") => NULL
+//     nullif(5, 4) => 5
+//     nullif(not_a_field, 4) => NULL
+//
+func NullIf(ctx expr.EvalContext, itemA, itemB value.Value) (value.Value, bool) {
+	if value.IsNull(itemA) {
+		return value.NilValueVal, true
+	}
+	if value.IsNull(itemB) {
+		return itemA, true
+	}
+	if eq, err := value.Equal(itemA, itemB); err == nil && eq {
+		return value.NilValueVal, true
+	}
+	return itemA, true
+}
+
 // Map()    Create a map from two values.   If the right side value is nil
 //    then does not evaluate
 //
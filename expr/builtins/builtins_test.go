@@ -119,7 +119,7 @@ var builtinTests = []testBuiltins{
 	{`len("abc")`, value.NewIntValue(3)},
 	{`len(split(reg_date,"/"))`, value.NewIntValue(3)},
 	{`len(not_a_field)`, nil},
-	{`len(not_a_field) >= 10`, value.BoolValueFalse},
+	{`len(not_a_field) >= 10`, value.NilValueVal}, // NULL >= 10 is NULL
 	{`len("abc") >= 2`, value.BoolValueTrue},
 	{`CHAR_LENGTH("abc") `, value.NewIntValue(3)},
 	{`CHAR_LENGTH(CAST("abc" AS CHAR))`, value.NewIntValue(3)},
@@ -167,6 +167,10 @@ var builtinTests = []testBuiltins{
 	{`email("Bob <bob>")`, value.ErrValue},
 	{`email("Bob <bob@bob.com>")`, value.NewStringValue("bob@bob.com")},
 
+	{`coalesce(not_a_field, email("Bob <bob@bob.com>"))`, value.NewStringValue("bob@bob.com")},
+	{`ifnull(not_a_field, "default")`, value.NewStringValue("default")},
+	{`nullif(email, "email@email.com")`, value.NilValueVal},
+	{`nullif(email, "bob")`, value.NewStringValue("email@email.com")},
 	{`oneof(not_a_field, email("Bob <bob@bob.com>"))`, value.NewStringValue("bob@bob.com")},
 	{`oneof(email, email(not_a_field))`, value.NewStringValue("email@email.com")},
 	{`oneof(email, email(not_a_field)) NOT IN ("a","b",10, 4.5) `, value.NewBoolValue(true)},
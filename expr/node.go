@@ -3,6 +3,7 @@
 package expr
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -138,10 +139,21 @@ type (
 	//
 	// interfaces:   Node
 	FuncNode struct {
-		Name    string // Name of func
-		F       Func   // The actual function that this AST maps to
-		Missing bool
-		Args    []Node // Arguments are them-selves nodes
+		Name     string // Name of func
+		F        Func   // The actual function that this AST maps to
+		Missing  bool
+		Distinct bool           // aggregate over distinct values  ie count(DISTINCT x)
+		Args     []Node         // Arguments are them-selves nodes
+		OrderBy  []*FuncOrderBy // ORDER BY inside aggregate  ie group_concat(x ORDER BY y)
+	}
+
+	// FuncOrderBy is one sort expression of the ORDER BY inside
+	// of an aggregate func
+	//
+	//     group_concat(name, ", " ORDER BY age DESC)
+	FuncOrderBy struct {
+		Node Node
+		Desc bool
 	}
 
 	// IdentityNode will look up a value out of a env bag
@@ -218,6 +230,30 @@ type (
 		wraptype string //  (   or [
 		Args     []Node
 	}
+
+	// CaseNode conditional expression, which has Then of the first When
+	// that is true, or for the simple form (with Arg) the first When that
+	// is equal to Arg.  If none match it has value of Else, or nil.
+	//
+	//    CASE WHEN x > 1 THEN "a" WHEN y THEN "b" ELSE "c" END
+	//    CASE x WHEN 1 THEN "a" WHEN 2 THEN "b" END
+	CaseNode struct {
+		Arg   Node // optional, the value of simple form
+		Whens []Node
+		Thens []Node
+		Else  Node // optional
+	}
+
+	// ParamNode is a placeholder for a value bound at execution of
+	// a prepared statement, by position (?, $1) or by name (:name),
+	// positional ? are numbered by the lexer so are the same as $1, $2...
+	//
+	//    WHERE user_id = ? AND name = :name
+	ParamNode struct {
+		Index int         // 0 based position of the arg it is bound to, -1 if named
+		Name  string      // name of the arg it is bound to, if named
+		Value value.Value // the bound value, nil if not yet bound
+	}
 )
 
 // Determine if this expression node uses datemath (ie, "now-4h")
@@ -276,10 +312,109 @@ func findallidents(node Node, current []string) []string {
 		for _, arg := range n.Args {
 			current = findallidents(arg, current)
 		}
+	case *UnaryNode:
+		current = findallidents(n.Arg, current)
+	case *TriNode:
+		for _, arg := range n.Args {
+			current = findallidents(arg, current)
+		}
+	case *ArrayNode:
+		for _, arg := range n.Args {
+			current = findallidents(arg, current)
+		}
 	case *FuncNode:
 		for _, arg := range n.Args {
 			current = findallidents(arg, current)
 		}
+	case *CaseNode:
+		for _, arg := range n.args() {
+			current = findallidents(arg, current)
+		}
+	}
+	return current
+}
+
+// Recursively descend down a node looking for an aggregate function
+//
+//     CASE WHEN count(*) > 2 THEN "many" END  == true
+func HasAggFunc(node Node) bool {
+	switch n := node.(type) {
+	case *FuncNode:
+		if IsAgg(strings.ToLower(n.Name)) {
+			return true
+		}
+		for _, arg := range n.Args {
+			if HasAggFunc(arg) {
+				return true
+			}
+		}
+	case *BinaryNode:
+		for _, arg := range n.Args {
+			if HasAggFunc(arg) {
+				return true
+			}
+		}
+	case *TriNode:
+		for _, arg := range n.Args {
+			if HasAggFunc(arg) {
+				return true
+			}
+		}
+	case *ArrayNode:
+		for _, arg := range n.Args {
+			if HasAggFunc(arg) {
+				return true
+			}
+		}
+	case *UnaryNode:
+		return HasAggFunc(n.Arg)
+	case *CaseNode:
+		for _, arg := range n.args() {
+			if HasAggFunc(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Recursively descend down a node finding all of the parameter
+// placeholders of a prepared statement
+//
+//     x = $1 AND y IN ($2, :name)  == {$1, $2, :name}
+func FindAllParams(node Node) []*ParamNode {
+	return findallparams(node, nil)
+}
+
+func findallparams(node Node, current []*ParamNode) []*ParamNode {
+	switch n := node.(type) {
+	case *ParamNode:
+		current = append(current, n)
+	case *BinaryNode:
+		for _, arg := range n.Args {
+			current = findallparams(arg, current)
+		}
+	case *TriNode:
+		for _, arg := range n.Args {
+			current = findallparams(arg, current)
+		}
+	case *ArrayNode:
+		for _, arg := range n.Args {
+			current = findallparams(arg, current)
+		}
+	case *UnaryNode:
+		current = findallparams(n.Arg, current)
+	case *FuncNode:
+		for _, arg := range n.Args {
+			current = findallparams(arg, current)
+		}
+		for _, ob := range n.OrderBy {
+			current = findallparams(ob.Node, current)
+		}
+	case *CaseNode:
+		for _, arg := range n.args() {
+			current = findallparams(arg, current)
+		}
 	}
 	return current
 }
@@ -324,6 +459,8 @@ func ValueTypeFromNode(n Node) value.ValueType {
 		return value.StringType
 	case *NumberNode:
 		return value.NumberType
+	case *CaseNode:
+		return ValueTypeFromNode(nt.Thens[0])
 	case *BinaryNode:
 		switch nt.Operator.T {
 		case lex.TokenLogicAnd, lex.TokenLogicOr, lex.TokenEqual, lex.TokenEqualEqual:
@@ -355,6 +492,9 @@ func (c *FuncNode) append(arg Node) {
 }
 func (c *FuncNode) FingerPrint(r rune) string {
 	s := c.Name + "("
+	if c.Distinct {
+		s += "DISTINCT "
+	}
 	for i, arg := range c.Args {
 		//u.Debugf("arg: %v   %T %v", arg, arg, arg.String())
 		if i > 0 {
@@ -362,11 +502,25 @@ func (c *FuncNode) FingerPrint(r rune) string {
 		}
 		s += arg.FingerPrint(r)
 	}
+	for i, o := range c.OrderBy {
+		if i == 0 {
+			s += " ORDER BY "
+		} else {
+			s += ", "
+		}
+		s += o.Node.FingerPrint(r)
+		if o.Desc {
+			s += " DESC"
+		}
+	}
 	s += ")"
 	return s
 }
 func (c *FuncNode) String() string {
 	s := c.Name + "("
+	if c.Distinct {
+		s += "DISTINCT "
+	}
 	for i, arg := range c.Args {
 		//u.Debugf("arg: %v   %T %v", arg, arg, arg.String())
 		if i > 0 {
@@ -374,6 +528,17 @@ func (c *FuncNode) String() string {
 		}
 		s += arg.String()
 	}
+	for i, o := range c.OrderBy {
+		if i == 0 {
+			s += " ORDER BY "
+		} else {
+			s += ", "
+		}
+		s += o.Node.String()
+		if o.Desc {
+			s += " DESC"
+		}
+	}
 	s += ")"
 	return s
 }
@@ -415,11 +580,16 @@ func (f *FuncNode) Type() reflect.Value { return f.F.Return }
 func (m *FuncNode) ToPB() *NodePb {
 	n := &FuncNodePb{}
 	n.Name = m.Name
+	n.Distinct = m.Distinct
 	n.Args = make([]NodePb, len(m.Args))
 	for i, a := range m.Args {
 		//u.Debugf("Func ToPB: arg %T", a)
 		n.Args[i] = *a.ToPB()
 	}
+	for _, o := range m.OrderBy {
+		n.OrderBy = append(n.OrderBy, o.Node.ToPB())
+		n.OrderDesc = append(n.OrderDesc, o.Desc)
+	}
 	return &NodePb{Fn: n}
 }
 func (m *FuncNode) FromPB(n *NodePb) Node {
@@ -428,11 +598,19 @@ func (m *FuncNode) FromPB(n *NodePb) Node {
 		u.Errorf("Not Found Func %q", n.Fn.Name)
 		// Panic?
 	}
-	return &FuncNode{
-		Name: n.Fn.Name,
-		Args: NodesFromNodesPb(n.Fn.Args),
-		F:    fn,
+	f := &FuncNode{
+		Name:     n.Fn.Name,
+		Distinct: n.Fn.Distinct,
+		Args:     NodesFromNodesPb(n.Fn.Args),
+		F:        fn,
+	}
+	for i, o := range n.Fn.OrderBy {
+		f.OrderBy = append(f.OrderBy, &FuncOrderBy{
+			Node: NodeFromNodePb(o),
+			Desc: i < len(n.Fn.OrderDesc) && n.Fn.OrderDesc[i],
+		})
 	}
+	return f
 }
 func (m *FuncNode) Equal(n Node) bool {
 	if m == nil && n == nil {
@@ -445,7 +623,7 @@ func (m *FuncNode) Equal(n Node) bool {
 		return false
 	}
 	if nt, ok := n.(*FuncNode); ok {
-		if m.Name != nt.Name {
+		if m.Name != nt.Name || m.Distinct != nt.Distinct {
 			return false
 		}
 		for i, arg := range nt.Args {
@@ -453,6 +631,14 @@ func (m *FuncNode) Equal(n Node) bool {
 				return false
 			}
 		}
+		if len(m.OrderBy) != len(nt.OrderBy) {
+			return false
+		}
+		for i, o := range nt.OrderBy {
+			if o.Desc != m.OrderBy[i].Desc || !o.Node.Equal(m.OrderBy[i].Node) {
+				return false
+			}
+		}
 		return true
 	}
 	return false
@@ -1061,6 +1247,246 @@ func (m *ArrayNode) Equal(n Node) bool {
 	return false
 }
 
+// Create a conditional expression, arg is nil for the searched form
+//
+//    CASE WHEN @when THEN @then [ELSE @else] END
+//    CASE @arg WHEN @when THEN @then [ELSE @else] END
+//
+func NewCaseNode(arg Node) *CaseNode {
+	return &CaseNode{Arg: arg}
+}
+func (m *CaseNode) FingerPrint(r rune) string {
+	return m.toString(func(n Node) string { return n.FingerPrint(r) })
+}
+func (m *CaseNode) String() string {
+	return m.toString(func(n Node) string { return n.String() })
+}
+func (m *CaseNode) toString(str func(n Node) string) string {
+	var buf bytes.Buffer
+	buf.WriteString("CASE")
+	if m.Arg != nil {
+		buf.WriteString(" ")
+		buf.WriteString(str(m.Arg))
+	}
+	for i, when := range m.Whens {
+		fmt.Fprintf(&buf, " WHEN %s THEN %s", str(when), str(m.Thens[i]))
+	}
+	if m.Else != nil {
+		buf.WriteString(" ELSE ")
+		buf.WriteString(str(m.Else))
+	}
+	buf.WriteString(" END")
+	return buf.String()
+}
+
+// Append a WHEN @when THEN @then
+func (m *CaseNode) Append(when, then Node) {
+	m.Whens = append(m.Whens, when)
+	m.Thens = append(m.Thens, then)
+}
+func (m *CaseNode) Check() error {
+	if len(m.Whens) == 0 {
+		return fmt.Errorf("CASE must have at least one WHEN")
+	}
+	for _, arg := range m.args() {
+		if err := arg.Check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// all of the child nodes, in order
+func (m *CaseNode) args() []Node {
+	args := make([]Node, 0, 2*len(m.Whens)+2)
+	if m.Arg != nil {
+		args = append(args, m.Arg)
+	}
+	for i, when := range m.Whens {
+		args = append(args, when, m.Thens[i])
+	}
+	if m.Else != nil {
+		args = append(args, m.Else)
+	}
+	return args
+}
+func (m *CaseNode) ToPB() *NodePb {
+	n := &CaseNodePb{}
+	if m.Arg != nil {
+		n.Arg = casePb(m.Arg)
+	}
+	n.Whens = make([]*NodePb, len(m.Whens))
+	n.Thens = make([]*NodePb, len(m.Thens))
+	for i, when := range m.Whens {
+		n.Whens[i] = casePb(when)
+		n.Thens[i] = casePb(m.Thens[i])
+	}
+	if m.Else != nil {
+		n.Else = casePb(m.Else)
+	}
+	return &NodePb{Cn: n}
+}
+func (m *CaseNode) FromPB(n *NodePb) Node {
+	cn := &CaseNode{
+		Whens: make([]Node, len(n.Cn.Whens)),
+		Thens: make([]Node, len(n.Cn.Thens)),
+	}
+	if n.Cn.Arg != nil {
+		cn.Arg = caseNodeFromPb(n.Cn.Arg)
+	}
+	for i, when := range n.Cn.Whens {
+		cn.Whens[i] = caseNodeFromPb(when)
+		cn.Thens[i] = caseNodeFromPb(n.Cn.Thens[i])
+	}
+	if n.Cn.Else != nil {
+		cn.Else = caseNodeFromPb(n.Cn.Else)
+	}
+	return cn
+}
+
+// NULL has no pb, ie  THEN NULL, so is an empty NodePb
+func casePb(n Node) *NodePb {
+	if pb := n.ToPB(); pb != nil {
+		return pb
+	}
+	return &NodePb{}
+}
+func caseNodeFromPb(pb *NodePb) Node {
+	if n := NodeFromNodePb(pb); n != nil {
+		return n
+	}
+	return NewNull(lex.Token{T: lex.TokenNull, V: "NULL"})
+}
+func (m *CaseNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	if nt, ok := n.(*CaseNode); ok {
+		if (m.Arg == nil) != (nt.Arg == nil) || (m.Else == nil) != (nt.Else == nil) {
+			return false
+		}
+		if len(m.Whens) != len(nt.Whens) {
+			return false
+		}
+		margs, nargs := m.args(), nt.args()
+		for i, arg := range margs {
+			if !arg.Equal(nargs[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Create a parameter placeholder from its token   $1   :name
+func NewParamNode(tok lex.Token) (*ParamNode, error) {
+	if strings.HasPrefix(tok.V, ":") {
+		return &ParamNode{Index: -1, Name: tok.V[1:]}, nil
+	}
+	i, err := strconv.Atoi(strings.TrimPrefix(tok.V, "$"))
+	if err != nil || i < 1 {
+		return nil, fmt.Errorf("invalid parameter %q", tok.V)
+	}
+	return &ParamNode{Index: i - 1}, nil
+}
+
+// Bind the value the parameter evaluates to
+func (m *ParamNode) Bind(v value.Value) { m.Value = v }
+
+func (m *ParamNode) FingerPrint(r rune) string { return string(r) }
+func (m *ParamNode) String() string {
+	if m.Name != "" {
+		return ":" + m.Name
+	}
+	return "$" + strconv.Itoa(m.Index+1)
+}
+func (m *ParamNode) Check() error { return nil }
+func (m *ParamNode) ToPB() *NodePb {
+	return &NodePb{Pn: &ParamNodePb{Index: int32(m.Index), Name: m.Name, Value: paramValueToPb(m.Value)}}
+}
+func (m *ParamNode) FromPB(n *NodePb) Node {
+	return &ParamNode{Index: int(n.Pn.Index), Name: n.Pn.Name, Value: paramValueFromPb(n.Pn.Value)}
+}
+
+// paramValueToPb the bound value of a param as its type and text, the
+// scalar values args are bound as.  nil if not bound.
+func paramValueToPb(v value.Value) *ValueNodePb {
+	if v == nil {
+		return nil
+	}
+	var text string
+	switch vt := v.(type) {
+	case value.NilValue:
+	case value.StringValue, value.IntValue, value.BoolValue:
+		text = vt.ToString()
+	case value.NumberValue:
+		text = strconv.FormatFloat(vt.Val(), 'g', -1, 64)
+	case value.TimeValue:
+		text = vt.Val().Format(time.RFC3339Nano)
+	case value.ByteSliceValue:
+		return &ValueNodePb{Valuetype: int32(v.Type()), Value: vt.Val()}
+	default:
+		u.Warnf("param value of type %T not serializable", v)
+		return nil
+	}
+	return &ValueNodePb{Valuetype: int32(v.Type()), Value: []byte(text)}
+}
+
+func paramValueFromPb(pb *ValueNodePb) value.Value {
+	if pb == nil {
+		return nil
+	}
+	text := string(pb.Value)
+	switch value.ValueType(pb.Valuetype) {
+	case value.NilType:
+		return value.NilValueVal
+	case value.StringType:
+		return value.NewStringValue(text)
+	case value.IntType:
+		if iv, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return value.NewIntValue(iv)
+		}
+	case value.NumberType:
+		if fv, err := strconv.ParseFloat(text, 64); err == nil {
+			return value.NewNumberValue(fv)
+		}
+	case value.BoolType:
+		if bv, err := strconv.ParseBool(text); err == nil {
+			return value.NewBoolValue(bv)
+		}
+	case value.TimeType:
+		if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+			return value.NewTimeValue(t)
+		}
+	case value.ByteSliceType:
+		return value.NewByteSliceValue(pb.Value)
+	}
+	u.Warnf("could not read param value of type %d %q", pb.Valuetype, text)
+	return nil
+}
+func (m *ParamNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	if nt, ok := n.(*ParamNode); ok {
+		return nt.Index == m.Index && nt.Name == m.Name
+	}
+	return false
+}
+
 // Node serialization helpers
 func tokenFromInt(iv int32) lex.Token {
 	t, ok := lex.TokenNameMap[lex.TokenType(iv)]
@@ -1098,6 +1524,12 @@ func NodeFromNodePb(n *NodePb) Node {
 	case n.An != nil:
 		var an *ArrayNode
 		return an.FromPB(n)
+	case n.Cn != nil:
+		var cn *CaseNode
+		return cn.FromPB(n)
+	case n.Pn != nil:
+		var pn *ParamNode
+		return pn.FromPB(n)
 	case n.Nn != nil:
 		var nn *NumberNode
 		return nn.FromPB(n)
@@ -3,22 +3,26 @@
 // DO NOT EDIT!
 
 /*
-	Package expr is a generated protocol buffer package.
-
-	It is generated from these files:
-		node.proto
-
-	It has these top-level messages:
-		NodePb
-		BinaryNodePb
-		UnaryNodePb
-		FuncNodePb
-		TriNodePb
-		ArrayNodePb
-		StringNodePb
-		IdentityNodePb
-		NumberNodePb
-		ValueNodePb
+Package expr is a generated protocol buffer package.
+
+It is generated from these files:
+
+	node.proto
+
+It has these top-level messages:
+
+	NodePb
+	BinaryNodePb
+	UnaryNodePb
+	FuncNodePb
+	TriNodePb
+	ArrayNodePb
+	CaseNodePb
+	ParamNodePb
+	StringNodePb
+	IdentityNodePb
+	NumberNodePb
+	ValueNodePb
 */
 package expr
 
@@ -43,6 +47,8 @@ type NodePb struct {
 	Fn               *FuncNodePb     `protobuf:"bytes,3,opt,name=fn" json:"fn,omitempty"`
 	Tn               *TriNodePb      `protobuf:"bytes,4,opt,name=tn" json:"tn,omitempty"`
 	An               *ArrayNodePb    `protobuf:"bytes,5,opt,name=an" json:"an,omitempty"`
+	Cn               *CaseNodePb     `protobuf:"bytes,6,opt,name=cn" json:"cn,omitempty"`
+	Pn               *ParamNodePb    `protobuf:"bytes,7,opt,name=pn" json:"pn,omitempty"`
 	Nn               *NumberNodePb   `protobuf:"bytes,10,opt,name=nn" json:"nn,omitempty"`
 	Vn               *ValueNodePb    `protobuf:"bytes,11,opt,name=vn" json:"vn,omitempty"`
 	In               *IdentityNodePb `protobuf:"bytes,12,opt,name=in" json:"in,omitempty"`
@@ -80,9 +86,12 @@ func (*UnaryNodePb) ProtoMessage()    {}
 
 // Func Node, args are children
 type FuncNodePb struct {
-	Name             string   `protobuf:"bytes,1,req,name=name" json:"name"`
-	Args             []NodePb `protobuf:"bytes,2,rep,name=args" json:"args"`
-	XXX_unrecognized []byte   `json:"-"`
+	Name             string    `protobuf:"bytes,1,req,name=name" json:"name"`
+	Args             []NodePb  `protobuf:"bytes,2,rep,name=args" json:"args"`
+	Distinct         bool      `protobuf:"varint,3,opt,name=distinct" json:"distinct"`
+	OrderBy          []*NodePb `protobuf:"bytes,4,rep,name=order_by" json:"order_by,omitempty"`
+	OrderDesc        []bool    `protobuf:"varint,5,rep,name=order_desc" json:"order_desc,omitempty"`
+	XXX_unrecognized []byte    `json:"-"`
 }
 
 func (m *FuncNodePb) Reset()         { *m = FuncNodePb{} }
@@ -111,6 +120,31 @@ func (m *ArrayNodePb) Reset()         { *m = ArrayNodePb{} }
 func (m *ArrayNodePb) String() string { return proto.CompactTextString(m) }
 func (*ArrayNodePb) ProtoMessage()    {}
 
+// Case Node, optional arg compared to each when, when/then pairs, optional else
+type CaseNodePb struct {
+	Arg              *NodePb   `protobuf:"bytes,1,opt,name=arg" json:"arg,omitempty"`
+	Whens            []*NodePb `protobuf:"bytes,2,rep,name=whens" json:"whens,omitempty"`
+	Thens            []*NodePb `protobuf:"bytes,3,rep,name=thens" json:"thens,omitempty"`
+	Else             *NodePb   `protobuf:"bytes,4,opt,name=else" json:"else,omitempty"`
+	XXX_unrecognized []byte    `json:"-"`
+}
+
+func (m *CaseNodePb) Reset()         { *m = CaseNodePb{} }
+func (m *CaseNodePb) String() string { return proto.CompactTextString(m) }
+func (*CaseNodePb) ProtoMessage()    {}
+
+// Param Node, placeholder of a prepared statement, no children
+type ParamNodePb struct {
+	Index            int32        `protobuf:"varint,1,opt,name=index" json:"index"`
+	Name             string       `protobuf:"bytes,2,opt,name=name" json:"name"`
+	Value            *ValueNodePb `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
+	XXX_unrecognized []byte       `json:"-"`
+}
+
+func (m *ParamNodePb) Reset()         { *m = ParamNodePb{} }
+func (m *ParamNodePb) String() string { return proto.CompactTextString(m) }
+func (*ParamNodePb) ProtoMessage()    {}
+
 // String literal, no children
 type StringNodePb struct {
 	Noquote          *bool  `protobuf:"varint,1,opt,name=noquote" json:"noquote,omitempty"`
@@ -165,6 +199,8 @@ func init() {
 	proto.RegisterType((*FuncNodePb)(nil), "expr.FuncNodePb")
 	proto.RegisterType((*TriNodePb)(nil), "expr.TriNodePb")
 	proto.RegisterType((*ArrayNodePb)(nil), "expr.ArrayNodePb")
+	proto.RegisterType((*CaseNodePb)(nil), "expr.CaseNodePb")
+	proto.RegisterType((*ParamNodePb)(nil), "expr.ParamNodePb")
 	proto.RegisterType((*StringNodePb)(nil), "expr.StringNodePb")
 	proto.RegisterType((*IdentityNodePb)(nil), "expr.IdentityNodePb")
 	proto.RegisterType((*NumberNodePb)(nil), "expr.NumberNodePb")
@@ -235,6 +271,26 @@ func (m *NodePb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n5
 	}
+	if m.Cn != nil {
+		data[i] = 0x32
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Cn.Size()))
+		n11, err := m.Cn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.Pn != nil {
+		data[i] = 0x3a
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Pn.Size()))
+		n14, err := m.Pn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
 	if m.Nn != nil {
 		data[i] = 0x52
 		i++
@@ -396,6 +452,38 @@ func (m *FuncNodePb) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	data[i] = 0x18
+	i++
+	if m.Distinct {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	if len(m.OrderBy) > 0 {
+		for _, msg := range m.OrderBy {
+			data[i] = 0x22
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.OrderDesc) > 0 {
+		for _, b := range m.OrderDesc {
+			data[i] = 0x28
+			i++
+			if b {
+				data[i] = 1
+			} else {
+				data[i] = 0
+			}
+			i++
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -478,6 +566,109 @@ func (m *ArrayNodePb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *CaseNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *CaseNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Arg != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Arg.Size()))
+		n12, err := m.Arg.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	if len(m.Whens) > 0 {
+		for _, msg := range m.Whens {
+			data[i] = 0x12
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Thens) > 0 {
+		for _, msg := range m.Thens {
+			data[i] = 0x1a
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Else != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Else.Size()))
+		n13, err := m.Else.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ParamNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *ParamNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0x8
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Index))
+	data[i] = 0x12
+	i++
+	i = encodeVarintNode(data, i, uint64(len(m.Name)))
+	i += copy(data[i:], m.Name)
+	if m.Value != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Value.Size()))
+		n, err := m.Value.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func (m *StringNodePb) Marshal() (data []byte, err error) {
 	size := m.Size()
 	data = make([]byte, size)
@@ -672,6 +863,14 @@ func (m *NodePb) Size() (n int) {
 		l = m.An.Size()
 		n += 1 + l + sovNode(uint64(l))
 	}
+	if m.Cn != nil {
+		l = m.Cn.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.Pn != nil {
+		l = m.Pn.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
 	if m.Nn != nil {
 		l = m.Nn.Size()
 		n += 1 + l + sovNode(uint64(l))
@@ -735,6 +934,16 @@ func (m *FuncNodePb) Size() (n int) {
 			n += 1 + l + sovNode(uint64(l))
 		}
 	}
+	n += 2
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if len(m.OrderDesc) > 0 {
+		n += 2 * len(m.OrderDesc)
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -775,6 +984,51 @@ func (m *ArrayNodePb) Size() (n int) {
 	return n
 }
 
+func (m *CaseNodePb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Arg != nil {
+		l = m.Arg.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Whens) > 0 {
+		for _, e := range m.Whens {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if len(m.Thens) > 0 {
+		for _, e := range m.Thens {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Else != nil {
+		l = m.Else.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ParamNodePb) Size() (n int) {
+	var l int
+	_ = l
+	n += 1 + sovNode(uint64(m.Index))
+	l = len(m.Name)
+	n += 1 + l + sovNode(uint64(l))
+	if m.Value != nil {
+		l = m.Value.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *StringNodePb) Size() (n int) {
 	var l int
 	_ = l
@@ -1039,6 +1293,72 @@ func (m *NodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cn == nil {
+				m.Cn = &CaseNodePb{}
+			}
+			if err := m.Cn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Pn == nil {
+				m.Pn = &ParamNodePb{}
+			}
+			if err := m.Pn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 10:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Nn", wireType)
@@ -1539,26 +1859,97 @@ func (m *FuncNodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipNode(data[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthNode
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			m.Distinct = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-	if hasFields[0]&uint64(0x00000001) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = append(m.OrderBy, &NodePb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderDesc", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.OrderDesc = append(m.OrderDesc, bool(v != 0))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
@@ -1777,6 +2168,317 @@ func (m *ArrayNodePb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *CaseNodePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CaseNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CaseNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Arg == nil {
+				m.Arg = &NodePb{}
+			}
+			if err := m.Arg.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Whens", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Whens = append(m.Whens, &NodePb{})
+			if err := m.Whens[len(m.Whens)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Thens", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Thens = append(m.Thens, &NodePb{})
+			if err := m.Thens[len(m.Thens)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Else", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Else == nil {
+				m.Else = &NodePb{}
+			}
+			if err := m.Else.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParamNodePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParamNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParamNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Index |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Value == nil {
+				m.Value = &ValueNodePb{}
+			}
+			if err := m.Value.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *StringNodePb) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
@@ -2,18 +2,25 @@ package expr_test
 
 import (
 	"testing"
+	"time"
 
 	u "github.com/araddon/gou"
 	"github.com/bmizerany/assert"
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/value"
 )
 
 var pbTests = []string{
 	`eq(event,"stuff") OR ge(party, 1)`,
 	`"Portland" IN ("ohio")`,
 	`"xyz" BETWEEN todate("1/1/2015") AND 50`,
+	`count(DISTINCT user_id)`,
+	`count(user_id ORDER BY toint(age) DESC, user_id)`,
+	`CASE WHEN x > 1 THEN "a" WHEN y THEN NULL ELSE toint(z) END`,
+	`CASE x WHEN 1 THEN "a" END`,
+	`x = $1 AND y IN ($2,:name)`,
 }
 
 func TestNodePb(t *testing.T) {
@@ -32,4 +39,52 @@ func TestNodePb(t *testing.T) {
 	}
 }
 
+func TestParamNodePb(t *testing.T) {
+	t.Parallel()
+	et, err := expr.ParseExpression(`x IN ($1, $2, $3, $4, $5, $6, :name) OR y = $7`)
+	assert.T(t, err == nil, "Should not error parse expr but got ", err)
+	vals := []value.Value{
+		value.NewStringValue("O'Brien"),
+		value.NewIntValue(1<<60 + 1),
+		value.NewNumberValue(0.1),
+		value.NewBoolValue(true),
+		value.NewTimeValue(time.Date(2017, 3, 4, 5, 6, 7, 8, time.UTC)),
+		value.NewByteSliceValue([]byte("abc")),
+		value.NilValueVal,
+		nil, // not bound
+	}
+	params := expr.FindAllParams(et.Root)
+	assert.Equal(t, len(vals), len(params))
+	for i, p := range params {
+		p.Bind(vals[i])
+	}
+	pbBytes, err := proto.Marshal(et.Root.ToPB())
+	assert.Tf(t, err == nil, "Should not error on proto.Marshal but got %v", err)
+	n2, err := expr.NodeFromPb(pbBytes)
+	assert.Tf(t, err == nil, "Should not error from pb but got %v", err)
+	for i, p := range expr.FindAllParams(n2) {
+		if vals[i] == nil {
+			assert.Tf(t, p.Value == nil, "param %s should not be bound but was %v", p, p.Value)
+			continue
+		}
+		assert.Tf(t, p.Value != nil, "param %s should be bound", p)
+		assert.Equalf(t, vals[i].Type(), p.Value.Type(), "param %s", p)
+		assert.Equalf(t, vals[i].Value(), p.Value.Value(), "param %s", p)
+	}
+}
+
+func TestCaseFingerPrint(t *testing.T) {
+	t.Parallel()
+	et, err := expr.ParseExpression(`CASE x WHEN 1 THEN "a" WHEN 2 THEN toint(y) ELSE "b" END`)
+	assert.T(t, err == nil, "Should not error parse expr but got ", err)
+	fp := `CASE x WHEN ? THEN ? WHEN ? THEN toint(y) ELSE ? END`
+	assert.Tf(t, et.Root.FingerPrint('?') == fp, "fingerprint %s", et.Root.FingerPrint('?'))
+	et2, err := expr.ParseExpression(`CASE x WHEN 3 THEN "c" WHEN 4 THEN toint(y) ELSE "d" END`)
+	assert.T(t, err == nil, "Should not error parse expr but got ", err)
+	assert.T(t, et.Root.FingerPrint('?') == et2.Root.FingerPrint('?'))
+	et3, err := expr.ParseExpression(et.Root.String())
+	assert.T(t, err == nil, "Should not error parse expr but got ", err)
+	assert.T(t, et.Root.Equal(et3.Root), "Equal?")
+}
+
 var _ = u.EMPTY
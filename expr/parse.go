@@ -304,7 +304,8 @@ func (t *Tree) O(depth int) Node {
 			t.Next()
 			t.Next()
 		case lex.TokenEOF, lex.TokenEOS, lex.TokenFrom, lex.TokenComma, lex.TokenIf,
-			lex.TokenAs, lex.TokenSelect, lex.TokenLimit:
+			lex.TokenAs, lex.TokenSelect, lex.TokenLimit,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			// these are indicators of End of Current Clause, so we can return?
 			//u.Debugf("done, return: %v", tok)
 			return n
@@ -345,9 +346,19 @@ func (t *Tree) C(depth int) Node {
 			t.Next()
 			if t.Cur().T == lex.TokenNegate {
 				cur = t.Next()
+				if t.Cur().T == lex.TokenNull {
+					// x IS NOT NULL is its own operator, x != NULL is NULL
+					isNot := lex.Token{T: lex.TokenIsNot, V: "IS NOT"}
+					return NewBinaryNode(isNot, n, t.P(depth+1))
+				}
 				ne := lex.Token{T: lex.TokenNE, V: "!="}
 				return NewBinaryNode(ne, n, t.P(depth+1))
 			}
+			if t.Cur().T == lex.TokenNull {
+				// x IS NULL is its own operator, x = NULL is NULL
+				is := lex.Token{T: lex.TokenIs, V: "IS"}
+				return NewBinaryNode(is, n, t.P(depth+1))
+			}
 			return NewUnary(cur, t.cInner(n, depth+1))
 		default:
 			return t.cInner(n, depth)
@@ -457,6 +468,34 @@ func (t *Tree) ArrayNode(depth int) Node {
 	}
 }
 
+// Case parses a conditional expression, searched (no arg) or simple form
+//
+//   CASE WHEN x > 1 THEN "a" ELSE "b" END
+//   CASE x WHEN 1 THEN "a" WHEN 2 THEN "b" END
+func (t *Tree) Case(depth int) Node {
+	//u.Debugf("%s t.Case: %v", strings.Repeat("→ ", depth), t.Cur())
+	t.Next() // Consume CASE
+	cn := NewCaseNode(nil)
+	if t.Cur().T != lex.TokenWhen {
+		cn.Arg = t.O(depth + 1)
+	}
+	t.expect(lex.TokenWhen, "input")
+	for t.Cur().T == lex.TokenWhen {
+		t.Next()
+		when := t.O(depth + 1)
+		t.expect(lex.TokenThen, "input")
+		t.Next()
+		cn.Append(when, t.O(depth+1))
+	}
+	if t.Cur().T == lex.TokenElse {
+		t.Next()
+		cn.Else = t.O(depth + 1)
+	}
+	t.expect(lex.TokenEnd, "input")
+	t.Next() // Consume END
+	return cn
+}
+
 func (t *Tree) F(depth int) Node {
 	//u.Debugf("%s t.F: %v", strings.Repeat("→ ", depth), t.Cur())
 	switch cur := t.Cur(); cur.T {
@@ -470,12 +509,16 @@ func (t *Tree) F(depth int) Node {
 		return t.v(depth)
 	case lex.TokenNull:
 		return t.v(depth)
+	case lex.TokenParam:
+		return t.v(depth)
 	case lex.TokenLeftBracket:
 		// [
 		return t.v(depth)
 	case lex.TokenStar:
 		// in special situations:   count(*) ??
 		return t.v(depth)
+	case lex.TokenCase:
+		return t.Case(depth)
 	case lex.TokenNegate, lex.TokenMinus, lex.TokenExists:
 		t.Next()
 		n := NewUnary(cur, t.F(depth+1))
@@ -526,6 +569,13 @@ func (t *Tree) v(depth int) Node {
 	case lex.TokenNull:
 		t.Next()
 		return NewNull(cur)
+	case lex.TokenParam:
+		n, err := NewParamNode(cur)
+		if err != nil {
+			t.error(err)
+		}
+		t.Next()
+		return n
 	case lex.TokenStar:
 		n := NewStringNoQuoteNode(cur.V)
 		t.Next()
@@ -636,6 +686,19 @@ func (t *Tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 					fn.append(node)
 				}
 				return
+			case lex.TokenIdentity:
+				if len(fn.Args) == 0 && !fn.Distinct && strings.ToLower(firstToken.V) == "distinct" {
+					switch t.Peek().T {
+					case lex.TokenRightParenthesis, lex.TokenComma:
+						// a column named distinct
+					default:
+						//  count(DISTINCT x)
+						fn.Distinct = true
+						t.Next()
+						continue
+					}
+				}
+				node = t.O(depth + 1)
 			case lex.TokenComma:
 				if len(fn.Args) == 0 || t.Peek().T == lex.TokenComma || lastComma {
 					//u.Errorf("No node but comma? %v", tok)
@@ -673,6 +736,13 @@ func (t *Tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 				t.Next()
 				//u.Debugf("return: %v", t.Cur())
 				return
+			case lex.TokenOrderBy:
+				//  group_concat(name, ", " ORDER BY age DESC)
+				if node != nil {
+					fn.append(node)
+				}
+				t.funcOrderBy(depth, fn)
+				return
 			case lex.TokenEqual, lex.TokenEqualEqual, lex.TokenNE, lex.TokenGT, lex.TokenGE,
 				lex.TokenLE, lex.TokenLT, lex.TokenStar, lex.TokenMultiply, lex.TokenDivide:
 				// this func arg is an expression
@@ -693,6 +763,38 @@ func (t *Tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 	}
 }
 
+// funcOrderBy parses the sort expressions of the ORDER BY inside of an
+// aggregate func, up to and including the closing paren
+//
+//     group_concat(name ORDER BY age DESC, name)
+func (t *Tree) funcOrderBy(depth int, fn *FuncNode) {
+	for {
+		t.Next() // consume ORDER BY or comma
+		node := t.O(depth + 1)
+		if node == nil {
+			t.unexpected(t.Cur(), "func ORDER BY")
+		}
+		ob := &FuncOrderBy{Node: node}
+		switch t.Cur().T {
+		case lex.TokenDesc:
+			ob.Desc = true
+			t.Next()
+		case lex.TokenAsc:
+			t.Next()
+		}
+		fn.OrderBy = append(fn.OrderBy, ob)
+		switch t.Cur().T {
+		case lex.TokenComma:
+			// another sort expression
+		case lex.TokenRightParenthesis:
+			t.Next()
+			return
+		default:
+			t.unexpected(t.Cur(), "func ORDER BY")
+		}
+	}
+}
+
 // get Function from Global
 func (t *Tree) getFunction(name string) (v Func, ok bool) {
 	if t.fr != nil {
@@ -119,6 +119,20 @@ var parseTests = []parseTest{
 	{"in ident", `1 IN ident`, noError, `1 IN ident`},
 	{"general parse test", "`tablename` LIKE '%'", noError, "`tablename` LIKE '%'"},
 	{"general parse test", `"value" IN hosts(@@content_whitelist_domains)`, noError, "\"value\" IN hosts(`@@content_whitelist_domains`)"},
+	{"distinct agg", `count(distinct user_id)`, noError, `count(DISTINCT user_id)`},
+	{"ident named distinct", `len(distinct)`, noError, `len(distinct)`},
+	{"agg order by", `count(user_id order by created desc, user_id)`, noError, `count(user_id ORDER BY created DESC, user_id)`},
+	{"agg order by expr", `count(DISTINCT user_id ORDER BY toint(age) ASC)`, noError, `count(DISTINCT user_id ORDER BY toint(age))`},
+	{"case", `case when x > 1 then "a" when y then 2 else "b" end`, noError, `CASE WHEN x > 1 THEN "a" WHEN y THEN 2 ELSE "b" END`},
+	{"case simple", `CASE toint(x) WHEN 1 THEN "a" WHEN 2 THEN "b" END`, noError, `CASE toint(x) WHEN 1 THEN "a" WHEN 2 THEN "b" END`},
+	{"case nested", `tolower(CASE WHEN x THEN CASE y WHEN 1 THEN "a" END ELSE z END) == "a"`, noError, `tolower(CASE WHEN x THEN CASE y WHEN 1 THEN "a" END ELSE z END) == "a"`},
+	{"case no when", `CASE x ELSE "a" END`, hasError, ``},
+	{"case no end", `CASE WHEN x THEN "a"`, hasError, ``},
+	{"params", `x = ? AND y IN (?, :name) AND z > $3`, noError, `x = $1 AND y IN ($2,:name) AND z > $3`},
+	{"is null", `x IS NULL`, noError, `x IS NULL`},
+	{"is not null", `x IS NOT NULL AND y > 1`, noError, `x IS NOT NULL AND y > 1`},
+	{"equal null", `x = NULL`, noError, `x = NULL`},
+	{"coalesce", `coalesce(x, nullif(y, ""), ifnull(z, 1))`, noError, `coalesce(x, nullif(y, ""), ifnull(z, 1))`},
 }
 
 func TestParseExpressions(t *testing.T) {
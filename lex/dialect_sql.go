@@ -20,13 +20,16 @@ var SqlSelect = []*Clause{
 	{Token: TokenOffset, Lexer: LexNumber, Optional: true},
 	{Token: TokenWith, Lexer: LexJsonOrKeyValue, Optional: true},
 	{Token: TokenAlias, Lexer: LexIdentifier, Optional: true},
+	{Token: TokenUnion, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.union"},
+	{Token: TokenIntersect, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.intersect"},
+	{Token: TokenExcept, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.except"},
 	{Token: TokenEOF, Lexer: LexEndOfStatement, Optional: false},
 }
 
 // find any keyword that starts a source
 //    FROM <name>
 //    FROM (select ...)
-//         [(INNER | LEFT)] JOIN
+//         [(INNER | LEFT | RIGHT | FULL)] JOIN
 func sourceMatch(c *Clause, peekWord string, l *Lexer) bool {
 	//u.Debugf("%p sourceMatch?   peekWord: %s", c, peekWord)
 	switch peekWord {
@@ -34,7 +37,7 @@ func sourceMatch(c *Clause, peekWord string, l *Lexer) bool {
 		return true
 	case "select":
 		return true
-	case "left", "right", "inner", "outer", "join":
+	case "left", "right", "full", "inner", "outer", "join":
 		return true
 	}
 	return false
@@ -158,6 +161,12 @@ var SqlPrepare = []*Clause{
 	{Token: TokenFrom, Lexer: LexTableReferences},
 }
 
+// SqlWith common table expressions, which are followed by the statement
+// that uses them
+var SqlWith = []*Clause{
+	{Token: TokenWith, Lexer: LexWith},
+}
+
 var SqlSet = []*Clause{
 	{Token: TokenSet, Lexer: LexColumns},
 }
@@ -168,6 +177,7 @@ var SqlUse = []*Clause{
 // SqlDialect is a SQL like dialect
 //
 //    SELECT
+//    WITH ... SELECT
 //    UPDATE
 //    INSERT
 //    UPSERT
@@ -187,6 +197,7 @@ var SqlDialect *Dialect = &Dialect{
 	Statements: []*Clause{
 		&Clause{Token: TokenPrepare, Clauses: SqlPrepare},
 		&Clause{Token: TokenSelect, Clauses: SqlSelect},
+		&Clause{Token: TokenWith, Clauses: SqlWith},
 		&Clause{Token: TokenUpdate, Clauses: SqlUpdate},
 		&Clause{Token: TokenUpsert, Clauses: SqlUpsert},
 		&Clause{Token: TokenInsert, Clauses: SqlInsert},
@@ -4,6 +4,7 @@ package lex
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -108,6 +109,10 @@ type Lexer struct {
 	peekedWordPos int
 	peekedWord    string
 	lastQuoteMark byte
+	parens        []bool // open parens, true if they are func args
+	subQueries    []int  // depth of open parens at start of each sub-query
+	windows       []int  // depth of open parens at start of each OVER (...)
+	params        int    // count of ? placeholders, which are numbered in order
 
 	// Due to nested Expressions and evaluation this allows us to descend/ascend
 	// during lex, using push/pop to add and remove states needing evaluation
@@ -346,6 +351,20 @@ func (l *Lexer) IsComment() bool {
 // emit passes an token back to the client.
 func (l *Lexer) Emit(t TokenType) {
 	//u.Debugf("emit: %s  '%s'  stack=%v start=%d pos=%d", t, l.input[l.start:l.pos], len(l.stack), l.start, l.pos)
+	switch t {
+	case TokenLeftParenthesis:
+		l.parens = append(l.parens, l.lastToken.T == TokenUdfExpr || l.lastToken.T == TokenOver)
+		if l.lastToken.T == TokenOver {
+			l.windows = append(l.windows, len(l.parens))
+		}
+	case TokenRightParenthesis:
+		if l.inWindow() {
+			l.windows = l.windows[:len(l.windows)-1]
+		}
+		if len(l.parens) > 0 {
+			l.parens = l.parens[:len(l.parens)-1]
+		}
+	}
 	if l.lastQuoteMark != 0 {
 		l.lastToken = Token{T: t, V: l.input[l.start:l.pos], Quote: l.lastQuoteMark}
 		l.lastQuoteMark = 0
@@ -356,6 +375,16 @@ func (l *Lexer) Emit(t TokenType) {
 	l.start = l.pos
 }
 
+// are we lexing the args of a func   ie   count(x)
+func (l *Lexer) inFuncArgs() bool {
+	return len(l.parens) > 0 && l.parens[len(l.parens)-1]
+}
+
+// are we lexing the window of a func   ie   OVER (PARTITION BY x)
+func (l *Lexer) inWindow() bool {
+	return len(l.windows) > 0 && l.windows[len(l.windows)-1] == len(l.parens)
+}
+
 // ignore skips over the pending input before this point.
 func (l *Lexer) ignore() {
 	l.start = l.pos
@@ -948,6 +977,7 @@ func LexEmpty(l *Lexer) StateFn { return nil }
 //  1.23  -> [float] = 1.23
 //  100   -> [integer] = 100
 //  ["hello","world"]  -> [array] {"hello","world"}
+//  ?     -> [param] = $1
 //
 func LexValue(l *Lexer) StateFn {
 
@@ -975,6 +1005,11 @@ func LexValue(l *Lexer) StateFn {
 		l.Emit(TokenLeftBracket)
 		return LexJsonArray
 		//return LexValue
+	case '?', '$', ':':
+		if lexParam(l, rune) {
+			return nil
+		}
+		return l.errorToken("expected value but got " + string(rune))
 	case '\'', '"':
 		// quoted string, allows escaping
 		firstRune := rune
@@ -1040,6 +1075,41 @@ func LexValue(l *Lexer) StateFn {
 	return nil
 }
 
+// lexParam a parameter placeholder of a prepared statement, whose first
+// rune r has been consumed, the positional ? are numbered in the order of
+// the statement so that they are the same as $1, $2 ...
+//
+//  ?        -> [param] = $1
+//  $2       -> [param] = $2
+//  :name    -> [param] = :name
+//
+func lexParam(l *Lexer, r rune) bool {
+	switch r {
+	case '?':
+		l.params++
+		l.lastToken = Token{T: TokenParam, V: "$" + strconv.Itoa(l.params)}
+		l.tokens <- l.lastToken
+		l.start = l.pos
+		return true
+	case '$':
+		if !isDigit(l.Peek()) {
+			return false
+		}
+		for isDigit(l.Peek()) {
+			l.Next()
+		}
+	case ':':
+		if !isIdentifierFirstRune(l.Peek()) {
+			return false
+		}
+		for isIdentCh(l.Peek()) {
+			l.Next()
+		}
+	}
+	l.Emit(TokenParam)
+	return true
+}
+
 // lex a regex:   first character must be a /
 //
 //  /^stats\./i
@@ -1259,7 +1329,11 @@ func LexListOfArgs(l *Lexer) StateFn {
 			l.backup()
 			return nil
 		}
-	case '!', '=', '>', '<', '-', '+', '%', '&', '/', '|':
+	case '=', '>', '<':
+		// comparison in an arg   sum(CASE WHEN x > 1 THEN 1 END)
+		l.lexComparison(r)
+		return LexListOfArgs
+	case '!', '-', '+', '%', '&', '/', '|':
 		l.backup()
 		return nil
 	case ';':
@@ -1279,6 +1353,9 @@ func LexListOfArgs(l *Lexer) StateFn {
 			l.Emit(TokenAs)
 			return LexListOfArgs
 		}
+		if l.lexWindowSpec(peekWord) || l.lexFuncOrderBy(peekWord) || l.lexCase(peekWord) {
+			return LexListOfArgs
+		}
 		if l.isNextKeyword(peekWord) {
 			//u.Warnf("found keyword while looking for arg? %v", string(r))
 			return nil
@@ -1293,6 +1370,133 @@ func LexListOfArgs(l *Lexer) StateFn {
 	return nil
 }
 
+// lexComparison emits the comparison operator starting with r
+func (l *Lexer) lexComparison(r rune) {
+	r2 := l.Peek()
+	switch {
+	case r == '=' && r2 == '=':
+		l.Next()
+		l.Emit(TokenEqualEqual)
+	case r == '=':
+		l.Emit(TokenEqual)
+	case r == '>' && r2 == '=':
+		l.Next()
+		l.Emit(TokenGE)
+	case r == '>':
+		l.Emit(TokenGT)
+	case r == '<' && r2 == '=':
+		l.Next()
+		l.Emit(TokenLE)
+	case r == '<' && r2 == '>':
+		l.Next()
+		l.Emit(TokenNE)
+	case r == '<':
+		l.Emit(TokenLT)
+	}
+}
+
+// lexCase lexes the keywords of a conditional expression, returns true
+// if it consumed a keyword
+//
+//       CASE WHEN x > 1 THEN "a" ELSE "b" END
+//       CASE x WHEN 1 THEN "a" END
+//
+func (l *Lexer) lexCase(word string) bool {
+	switch word {
+	case "case":
+		l.ConsumeWord(word)
+		l.Emit(TokenCase)
+	case "when":
+		l.ConsumeWord(word)
+		l.Emit(TokenWhen)
+	case "then":
+		l.ConsumeWord(word)
+		l.Emit(TokenThen)
+	case "else":
+		l.ConsumeWord(word)
+		l.Emit(TokenElse)
+	case "end":
+		l.ConsumeWord(word)
+		l.Emit(TokenEnd)
+	default:
+		return false
+	}
+	return true
+}
+
+// lexFuncOrderBy lexes the ORDER BY, ASC, DESC keywords inside the args
+// of an aggregate func, returns true if it consumed a keyword
+//
+//       group_concat(name, ", " ORDER BY age DESC)
+//
+func (l *Lexer) lexFuncOrderBy(word string) bool {
+	if !l.inFuncArgs() {
+		return false
+	}
+	switch word {
+	case "order":
+		if strings.ToLower(l.PeekX(len("order by"))) == "order by" {
+			l.ConsumeWord("order by")
+			l.Emit(TokenOrderBy)
+			return true
+		}
+	case "asc":
+		l.ConsumeWord(word)
+		l.Emit(TokenAsc)
+		return true
+	case "desc":
+		l.ConsumeWord(word)
+		l.Emit(TokenDesc)
+		return true
+	}
+	return false
+}
+
+// lexWindowSpec lexes the keywords of the window of a func, returns true
+// if it consumed a keyword
+//
+//       sum(x) OVER (PARTITION BY a ORDER BY b ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)
+//
+func (l *Lexer) lexWindowSpec(word string) bool {
+	if !l.inWindow() {
+		return false
+	}
+	switch word {
+	case "partition":
+		if strings.ToLower(l.PeekX(len("partition by"))) == "partition by" {
+			l.ConsumeWord("partition by")
+			l.Emit(TokenPartitionBy)
+			return true
+		}
+	case "current":
+		if strings.ToLower(l.PeekX(len("current row"))) == "current row" {
+			l.ConsumeWord("current row")
+			l.Emit(TokenCurrentRow)
+			return true
+		}
+	case "rows", "range", "unbounded", "preceding", "following", "between", "and":
+		l.ConsumeWord(word)
+		switch word {
+		case "rows":
+			l.Emit(TokenRows)
+		case "range":
+			l.Emit(TokenRange)
+		case "unbounded":
+			l.Emit(TokenUnbounded)
+		case "preceding":
+			l.Emit(TokenPreceding)
+		case "following":
+			l.Emit(TokenFollowing)
+		case "between":
+			l.Emit(TokenBetween)
+		case "and":
+			l.Emit(TokenLogicAnd)
+		}
+		return true
+	}
+	return false
+}
+
 // LexIdentifier scans and finds named things (tables, columns)
 //  and specifies them as TokenIdentity, uses LexIdentifierType
 //
@@ -1484,6 +1688,146 @@ func LexEndOfStatement(l *Lexer) StateFn {
 	return l.errorToken("Unexpected token:" + l.current())
 }
 
+// LexSetOperation lexes the optional ALL | DISTINCT of a set operation
+// (UNION, INTERSECT, EXCEPT) and then starts over with the clauses of the
+// statement, to lex the select that follows
+//
+//     SELECT ... UNION [ALL | DISTINCT] SELECT ...
+//
+func LexSetOperation(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	switch word := strings.ToLower(l.PeekWord()); word {
+	case "all":
+		l.ConsumeWord(word)
+		l.Emit(TokenAll)
+	case "distinct":
+		l.ConsumeWord(word)
+		l.Emit(TokenDistinct)
+	}
+	l.curClause = l.statement.Clauses[0]
+	return nil // pop up to LexStatement
+}
+
+// LexWith common table expressions, the statement of each is lexed by its
+// own lexer, then the statement that follows them by the dialect
+//
+//     WITH [RECURSIVE] <name> [(<col>, ...)] AS (<statement>) [, ...] <statement>
+//
+func LexWith(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	if l.IsEnd() {
+		return nil
+	}
+	if l.lastToken.T == TokenWith {
+		if word := strings.ToLower(l.PeekWord()); word == "recursive" {
+			l.ConsumeWord(word)
+			l.Emit(TokenRecursive)
+			return LexWith
+		}
+	}
+	l.Push("lexWithAs", lexWithAs)
+	return LexIdentifier
+}
+
+// lexWithAs the column names, and AS of a common table expression
+func lexWithAs(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	if l.Peek() == '(' {
+		l.Push("lexWithAs", lexWithAs)
+		return LexColumnNames
+	}
+	word := strings.ToLower(l.PeekWord())
+	if word != "as" {
+		return l.errorf("expected AS for WITH but got %q", word)
+	}
+	l.ConsumeWord(word)
+	l.Emit(TokenAs)
+	return lexWithStatement
+}
+
+// lexWithStatement the (<statement>) of a common table expression
+func lexWithStatement(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	if l.Peek() != '(' {
+		return l.errorf("expected ( after AS for WITH but got %q", l.PeekX(1))
+	}
+	l.Next()
+	l.Emit(TokenLeftParenthesis)
+	end := l.matchingParen()
+	if end < 0 {
+		return l.errorf("expected ) to end WITH statement")
+	}
+	sub := NewLexer(l.input[l.pos:end], l.dialect)
+	sub.params = l.params
+	l.pos = end
+	l.ignore()
+	return lexNested(sub, lexWithEnd)
+}
+
+// lexWithEnd the closing paren of the statement of a common table
+// expression, then either the next one or the statement using them
+func lexWithEnd(l *Lexer) StateFn {
+	l.Next()
+	l.Emit(TokenRightParenthesis)
+	return func(l *Lexer) StateFn {
+		l.SkipWhiteSpaces()
+		if l.Peek() == ',' {
+			l.Next()
+			l.Emit(TokenComma)
+			return LexWith
+		}
+		// the statement using them is lexed as if it were on its own
+		l.stack = l.stack[:0]
+		return LexDialectForStatement
+	}
+}
+
+// lexNested emits the tokens of a lexer of part of our input, up to its
+// end, then moves on to next
+func lexNested(sub *Lexer, next StateFn) StateFn {
+	return func(l *Lexer) StateFn {
+		tok := sub.NextToken()
+		switch tok.T {
+		case TokenEOF, TokenEOS:
+			l.params = sub.params
+			return next
+		case TokenError:
+			l.tokens <- tok
+			return nil
+		}
+		l.lastToken = tok
+		l.tokens <- tok
+		return lexNested(sub, next)
+	}
+}
+
+// matchingParen position of the paren closing the one just consumed,
+// ignoring those in quoted strings, identities, or -1 if there isn't one
+func (l *Lexer) matchingParen() int {
+	depth := 1
+	var quote rune
+	for i, r := range l.input[l.pos:] {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'', r == '"', r == '`':
+			quote = r
+		case r == '[':
+			quote = ']'
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return l.pos + i
+			}
+		}
+	}
+	return -1
+}
+
 // Handle start of select statements, specifically looking for
 //    @@variables, *, or else we drop into <select_list>
 //
@@ -1592,6 +1936,9 @@ func LexUpsertClause(l *Lexer) StateFn {
 
 // Handle recursive subqueries
 //
+//     WHERE user_id IN (SELECT user_id FROM orders ORDER BY x LIMIT 10)
+//     WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id)
+//
 func LexSubQuery(l *Lexer) StateFn {
 
 	//u.Debugf("LexSubQuery  '%v'", l.PeekX(10))
@@ -1604,12 +1951,29 @@ func LexSubQuery(l *Lexer) StateFn {
 		TODO:   this is a hack because the LexDialect from above should be recursive,
 		 	ie support sub-queries, but doesn't currently
 	*/
+	inSubQuery := len(l.subQueries) > 0
+	if inSubQuery {
+		depth := l.subQueries[len(l.subQueries)-1]
+		switch {
+		case len(l.parens) < depth:
+			// the closing paren of sub-query was lexed by its where clause
+			l.subQueries = l.subQueries[:len(l.subQueries)-1]
+			return LexConditionalClause
+		case len(l.parens) == depth && l.Peek() == ')':
+			l.Next()
+			l.Emit(TokenRightParenthesis)
+			l.subQueries = l.subQueries[:len(l.subQueries)-1]
+			return LexConditionalClause
+		}
+	}
+
 	word := strings.ToLower(l.PeekWord())
 	switch word {
 	case "select":
 		l.ConsumeWord(word)
 		l.Emit(TokenSelect)
-		return LexSubQuery
+		l.Push("LexSubQuery", LexSubQuery)
+		return LexSelectClause
 	case "where":
 		l.ConsumeWord(word)
 		l.Emit(TokenWhere)
@@ -1620,11 +1984,43 @@ func LexSubQuery(l *Lexer) StateFn {
 		l.Push("LexSubQuery", LexSubQuery)
 		l.Push("LexConditionalClause", LexConditionalClause)
 		return LexTableReferences
-	default:
+	}
+	if !inSubQuery {
+		return nil
 	}
 
-	l.Push("LexSubQuery", LexSubQuery)
-	return LexSelectClause
+	// the remaining clauses of a sub-query, which are also keywords
+	// of the outer statement
+	switch word {
+	case "group", "order":
+		kw := strings.ToLower(l.PeekX(len(word) + 3))
+		if kw != word+" by" {
+			return nil
+		}
+		l.ConsumeWord(kw)
+		l.Push("LexSubQuery", LexSubQuery)
+		if word == "group" {
+			l.Emit(TokenGroupBy)
+			return LexColumns
+		}
+		l.Emit(TokenOrderBy)
+		return LexOrderByColumn
+	case "having":
+		l.ConsumeWord(word)
+		l.Emit(TokenHaving)
+		l.Push("LexSubQuery", LexSubQuery)
+		return LexConditionalClause
+	case "limit", "offset":
+		l.ConsumeWord(word)
+		if word == "limit" {
+			l.Emit(TokenLimit)
+		} else {
+			l.Emit(TokenOffset)
+		}
+		l.Push("LexSubQuery", LexSubQuery)
+		return LexNumber
+	}
+	return nil
 }
 
 // Handle prepared statements
@@ -1702,7 +2098,7 @@ func LexSelectList(l *Lexer) StateFn {
 //    <sources>      := <source> [, <join_clause> <source>]*
 //    <source>       := ( <table_source> | <subselect> ) [AS <identifier>]
 //    <table_source> := <identifier>
-//    <join_clause>  := (INNER | LEFT | OUTER)? JOIN [ON <conditional_clause>]
+//    <join_clause>  := (INNER | LEFT | RIGHT | FULL | OUTER)? JOIN [ON <conditional_clause>]
 //    <subselect>    := '(' <select_stmt> ')'
 //
 func LexTableReferenceFirst(l *Lexer) StateFn {
@@ -1755,6 +2151,9 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 		l.Push("LexTableReferenceFirst", LexTableReferenceFirst)
 		l.Push("LexListOfArgs", LexListOfArgs)
 		return nil
+	case "left", "right", "full", "join":
+		// start of a join, let the source matcher take over
+		return nil
 
 	default:
 		r = l.Peek()
@@ -1788,7 +2187,7 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 //    <sources>      := <source> [, <join_clause> <source>]*
 //    <source>       := ( <table_source> | <subselect> ) [AS <identifier>]
 //    <table_source> := <identifier>
-//    <join_clause>  := (INNER | LEFT | OUTER)? JOIN [ON <conditional_clause>]
+//    <join_clause>  := (INNER | LEFT | RIGHT | FULL | OUTER)? JOIN [ON <conditional_clause>]
 //    <subselect>    := '(' <select_stmt> ')'
 //
 func LexTableReferences(l *Lexer) StateFn {
@@ -1859,6 +2258,10 @@ func LexTableReferences(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenRight)
 		return LexTableReferences
+	case "full":
+		l.ConsumeWord(word)
+		l.Emit(TokenFull)
+		return LexTableReferences
 	case "join":
 		l.ConsumeWord(word)
 		l.Emit(TokenJoin)
@@ -1912,7 +2315,7 @@ func LexTableReferences(l *Lexer) StateFn {
 //    <sources>      := <source> [, <join_clause> <source>]*
 //    <source>       := ( <table_source> | <subselect> ) [AS <identifier>]
 //    <table_source> := <identifier>
-//    <join_clause>  := (INNER | LEFT | OUTER)? JOIN [ON <conditional_clause>]
+//    <join_clause>  := (INNER | LEFT | RIGHT | FULL | OUTER)? JOIN [ON <conditional_clause>]
 //    <subselect>    := '(' <select_stmt> ')'
 //
 func LexJoinEntry(l *Lexer) StateFn {
@@ -1967,6 +2370,10 @@ func LexJoinEntry(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenRight)
 		return LexJoinEntry
+	case "full":
+		l.ConsumeWord(word)
+		l.Emit(TokenFull)
+		return LexJoinEntry
 	case "join":
 		l.ConsumeWord(word)
 		l.Emit(TokenJoin)
@@ -2124,7 +2531,13 @@ func LexConditionalClause(l *Lexer) StateFn {
 	word := strings.ToLower(l.PeekWord())
 	//u.Debugf("lexConditional word: %v", word)
 	switch word {
-	case "select", "where", "from":
+	case "select":
+		if l.lastToken.T == TokenLeftParenthesis {
+			// start of sub-query, which ends with the close of this paren
+			l.subQueries = append(l.subQueries, len(l.parens))
+		}
+		return LexSubQuery
+	case "where", "from":
 		//u.LogThrottle(u.WARN, 5, "sure we want subQuery here? %v", word)
 		return LexSubQuery
 	}
@@ -2353,6 +2766,15 @@ func LexExpression(l *Lexer) StateFn {
 			l.Push("LexExpressionOrIdentity", LexExpressionOrIdentity)
 			return nil
 		}
+	case "over":
+		// window func   row_number() OVER (PARTITION BY a ORDER BY b)
+		if l.lastToken.T == TokenRightParenthesis {
+			l.ConsumeWord(word)
+			l.Emit(TokenOver)
+			l.SkipWhiteSpaces()
+			l.Push("LexExpression", l.clauseState())
+			return LexExpressionParens
+		}
 	case "exists":
 		l.ConsumeWord(word)
 		r = l.Peek()
@@ -2366,6 +2788,12 @@ func LexExpression(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenIs)
 		return LexExpression
+	case "case", "when", "then", "else", "end":
+		l.lexCase(word)
+		if word == "end" {
+			return l.clauseState()
+		}
+		return LexExpression
 	case "null":
 		l.ConsumeWord(word)
 		l.Emit(TokenNull)
@@ -2403,6 +2831,9 @@ func LexExpression(l *Lexer) StateFn {
 			l.Push("LexExpression", l.clauseState())
 			return LexExpressionOrIdentity
 		}
+		if l.lexWindowSpec(word) || l.lexFuncOrderBy(word) {
+			return nil
+		}
 		if l.isNextKeyword(word) {
 			//u.Debugf("found keyword? %v ", word)
 			return nil
@@ -2614,7 +3045,8 @@ func LexOrderByColumn(l *Lexer) StateFn {
 	case '`':
 		l.Push("LexOrderByColumn", LexOrderByColumn)
 		return LexIdentifier
-	case ';':
+	case ';', ')':
+		// end of statement, or sub-query
 		return nil
 	case ',':
 		l.Next()
@@ -2639,7 +3071,7 @@ func LexOrderByColumn(l *Lexer) StateFn {
 		l.Emit(TokenDesc)
 		return LexOrderByColumn
 	default:
-		if len(l.stack) < 2 {
+		if len(l.stack) < 2 || (len(l.subQueries) > 0 && len(l.stack) < 100) {
 			l.Push("LexOrderByColumn", LexOrderByColumn)
 			return LexExpressionOrIdentity
 		} else {
@@ -936,6 +936,23 @@ func TestLexSelectNestedExpressions(t *testing.T) {
 		})
 }
 
+func TestLexSelectCase(t *testing.T) {
+
+	verifyTokenTypes(t, `SELECT
+			CASE WHEN a > 1 THEN "x" ELSE b END AS c,
+			sum(CASE b WHEN 1 THEN 1 END)
+		FROM x WHERE CASE WHEN a THEN true END`,
+		[]TokenType{TokenSelect,
+			TokenCase, TokenWhen, TokenIdentity, TokenGT, TokenInteger, TokenThen, TokenValue,
+			TokenElse, TokenIdentity, TokenEnd, TokenAs, TokenIdentity, TokenComma,
+			TokenUdfExpr, TokenLeftParenthesis,
+			TokenCase, TokenIdentity, TokenWhen, TokenInteger, TokenThen, TokenInteger, TokenEnd,
+			TokenRightParenthesis,
+			TokenFrom, TokenIdentity, TokenWhere,
+			TokenCase, TokenWhen, TokenIdentity, TokenThen, TokenIdentity, TokenEnd,
+		})
+}
+
 func TestLexAlter(t *testing.T) {
 
 	verifyTokens(t, `-- lets alter the table
@@ -1198,6 +1215,76 @@ func TestLexInsert(t *testing.T) {
 		})
 }
 
+func TestLexParams(t *testing.T) {
+	// positional ? are numbered in order, same as $1, $2
+	verifyTokens(t, `SELECT name FROM users WHERE id = ? AND age > $2 AND x IN (?, :name)`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "name"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "users"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "id"),
+			tv(TokenEqual, "="),
+			tv(TokenParam, "$1"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "age"),
+			tv(TokenGT, ">"),
+			tv(TokenParam, "$2"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "x"),
+			tv(TokenIN, "IN"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenParam, "$2"),
+			tv(TokenComma, ","),
+			tv(TokenParam, ":name"),
+			tv(TokenRightParenthesis, ")"),
+		})
+
+	verifyTokens(t, `UPDATE users SET name = ? WHERE id = ?`,
+		[]Token{
+			tv(TokenUpdate, "UPDATE"),
+			tv(TokenTable, "users"),
+			tv(TokenSet, "SET"),
+			tv(TokenIdentity, "name"),
+			tv(TokenEqual, "="),
+			tv(TokenParam, "$1"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "id"),
+			tv(TokenEqual, "="),
+			tv(TokenParam, "$2"),
+		})
+
+	verifyTokens(t, `INSERT INTO users (id, name) VALUES (?, ?)`,
+		[]Token{
+			tv(TokenInsert, "INSERT"),
+			tv(TokenInto, "INTO"),
+			tv(TokenTable, "users"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "id"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "name"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenValues, "VALUES"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenParam, "$1"),
+			tv(TokenComma, ","),
+			tv(TokenParam, "$2"),
+			tv(TokenRightParenthesis, ")"),
+		})
+
+	// the statement of a WITH continues the numbering
+	verifyTokenTypes(t, `WITH a AS (SELECT x FROM t WHERE y = ?) SELECT x FROM a WHERE x > ?`,
+		[]TokenType{TokenWith, TokenIdentity, TokenAs, TokenLeftParenthesis,
+			TokenSelect, TokenIdentity, TokenFrom, TokenIdentity, TokenWhere,
+			TokenIdentity, TokenEqual, TokenParam, TokenRightParenthesis,
+			TokenSelect, TokenIdentity, TokenFrom, TokenIdentity, TokenWhere,
+			TokenIdentity, TokenGT, TokenParam,
+		})
+	toks := lexTokens(`WITH a AS (SELECT x FROM t WHERE y = ?) SELECT x FROM a WHERE x > ?`)
+	assert.Equal(t, "$2", toks[len(toks)-1].V)
+}
+
 func TestLexDelete(t *testing.T) {
 	/*
 		DELETE [LOW_PRIORITY] [QUICK] [IGNORE] FROM tbl_name
@@ -35,26 +35,24 @@ func (t Token) String() string {
 }
 
 /*
-
-	// List of datatypes from MySql, implement them as tokens?   or leave as Identity during
-	// DDL create/alter statements?
-	BOOL	TINYINT
-	BOOLEAN	TINYINT
-	CHARACTER VARYING(M)	VARCHAR(M)
-	FIXED	DECIMAL
-	FLOAT4	FLOAT
-	FLOAT8	DOUBLE
-	INT1	TINYINT
-	INT2	SMALLINT
-	INT3	MEDIUMINT
-	INT4	INT
-	INT8	BIGINT
-	LONG VARBINARY	MEDIUMBLOB
-	LONG VARCHAR	MEDIUMTEXT
-	LONG	MEDIUMTEXT
-	MIDDLEINT	MEDIUMINT
-	NUMERIC	DECIMAL
-
+// List of datatypes from MySql, implement them as tokens?   or leave as Identity during
+// DDL create/alter statements?
+BOOL	TINYINT
+BOOLEAN	TINYINT
+CHARACTER VARYING(M)	VARCHAR(M)
+FIXED	DECIMAL
+FLOAT4	FLOAT
+FLOAT8	DOUBLE
+INT1	TINYINT
+INT2	SMALLINT
+INT3	MEDIUMINT
+INT4	INT
+INT8	BIGINT
+LONG VARBINARY	MEDIUMBLOB
+LONG VARCHAR	MEDIUMTEXT
+LONG	MEDIUMTEXT
+MIDDLEINT	MEDIUMINT
+NUMERIC	DECIMAL
 */
 const (
 	// List of all TokenTypes Note we do NOT use IOTA because it is evil
@@ -120,6 +118,7 @@ const (
 	TokenNull             TokenType = 88 // NULL
 	TokenContains         TokenType = 89 // CONTAINS
 	TokenIntersects       TokenType = 90 // INTERSECTS
+	TokenIsNot            TokenType = 91 // IS NOT
 
 	// ql top-level keywords, these first keywords determine parser
 	TokenPrepare   TokenType = 200
@@ -167,6 +166,31 @@ const (
 	TokenSession  TokenType = 327 // SESSION
 	TokenTables   TokenType = 328 // TABLES
 
+	// WITH common table expressions
+	TokenRecursive TokenType = 329 // RECURSIVE
+
+	// set operations, combine the results of select statements
+	TokenUnion     TokenType = 340 // UNION
+	TokenIntersect TokenType = 341 // INTERSECT
+	TokenExcept    TokenType = 342 // EXCEPT
+
+	// window functions, func() OVER (PARTITION BY .. ORDER BY .. ROWS BETWEEN ..)
+	TokenOver        TokenType = 350 // OVER
+	TokenPartitionBy TokenType = 351 // PARTITION BY
+	TokenRows        TokenType = 352 // ROWS
+	TokenRange       TokenType = 353 // RANGE
+	TokenUnbounded   TokenType = 354 // UNBOUNDED
+	TokenPreceding   TokenType = 355 // PRECEDING
+	TokenFollowing   TokenType = 356 // FOLLOWING
+	TokenCurrentRow  TokenType = 357 // CURRENT ROW
+
+	// conditional expressions, CASE [x] WHEN a THEN b ELSE c END
+	TokenCase TokenType = 360 // CASE
+	TokenWhen TokenType = 361 // WHEN
+	TokenThen TokenType = 362 // THEN
+	TokenElse TokenType = 363 // ELSE
+	TokenEnd  TokenType = 364 // END
+
 	// ddl
 	TokenChange       TokenType = 400 // change
 	TokenAdd          TokenType = 401 // add
@@ -190,6 +214,7 @@ const (
 	TokenValueWithSingleQuote TokenType = 602 // '' becomes ' inside the string, parser will need to replace the string
 	TokenRegex                TokenType = 603 // regex
 	TokenDuration             TokenType = 604 // 14d , 22w, 3y, 45ms, 45us, 24hr, 2h, 45m, 30s
+	TokenParam                TokenType = 605 // parameter placeholder  ? $1 :name   (? is numbered, ie $1)
 
 	// Scalar literal data-types
 	TokenDataType TokenType = 1000 // A generic Identifier of DataTypes
@@ -273,6 +298,7 @@ var (
 		TokenNull:       {Kw: "null", Description: "NULL"},
 		TokenContains:   {Kw: "contains", Description: "contains"},
 		TokenIntersects: {Kw: "intersects", Description: "intersects"},
+		TokenIsNot:      {Kw: "is not", Description: "IS NOT"},
 
 		// Identity ish bools
 		TokenTrue:  {Kw: "true", Description: "True"},
@@ -332,6 +358,31 @@ var (
 		TokenSession:  {Description: "session"},
 		TokenTables:   {Description: "tables"},
 
+		// WITH common table expressions
+		TokenRecursive: {Description: "recursive"},
+
+		// set operations
+		TokenUnion:     {Description: "union"},
+		TokenIntersect: {Description: "intersect"},
+		TokenExcept:    {Description: "except"},
+
+		// window functions
+		TokenOver:        {Description: "over"},
+		TokenPartitionBy: {Description: "partition by"},
+		TokenRows:        {Description: "rows"},
+		TokenRange:       {Description: "range"},
+		TokenUnbounded:   {Description: "unbounded"},
+		TokenPreceding:   {Description: "preceding"},
+		TokenFollowing:   {Description: "following"},
+		TokenCurrentRow:  {Description: "current row"},
+
+		// conditional expressions
+		TokenCase: {Description: "case"},
+		TokenWhen: {Description: "when"},
+		TokenThen: {Description: "then"},
+		TokenElse: {Description: "else"},
+		TokenEnd:  {Description: "end"},
+
 		// ddl keywords
 		TokenChange:       {Description: "change"},
 		TokenCharacterSet: {Description: "character set"},
@@ -352,6 +403,7 @@ var (
 		TokenValueWithSingleQuote: {Description: "valueWithSingleQuote"},
 		TokenRegex:                {Description: "regex"},
 		TokenDuration:             {Description: "duration"},
+		TokenParam:                {Description: "param"},
 
 		// scalar literals.
 		TokenBool:    {Description: "Bool"},
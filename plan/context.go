@@ -48,6 +48,9 @@ type Context struct {
 	Session expr.ContextReadWriter // Session for this connection
 	Schema  *schema.Schema         // this schema for this connection
 	Funcs   expr.FuncResolver      // Local/Dialect specific functions
+	Ctes    map[string]*Cte        // WITH common table expressions by lower-case name
+	Memory  *MemoryAccount         // memory reserved by operators of this query
+	Tx      *Transaction           // transaction this statement runs in, nil if none
 
 	// From configuration
 	DisableRecover bool
@@ -55,6 +58,8 @@ type Context struct {
 	// Local State
 	Errors     []error
 	errRecover interface{}
+	subs       []*Context     // contexts of statements planned as part of this one
+	reopen     []func() error // re-open the conns of the plan made with this context
 }
 
 // NewContext plan context
@@ -65,6 +70,45 @@ func NewContextFromPb(pb *ContextPb) *Context {
 	return &Context{id: pb.Id, fingerprint: pb.Fingerprint, SchemaName: pb.Schema}
 }
 
+// NewContextWithContext plan context for query which is cancelled, or
+// times out, with the given context.Context
+func NewContextWithContext(ctx context.Context, query string) *Context {
+	return &Context{Context: ctx, Raw: query}
+}
+
+// Deadline of the embedded context.Context, none if there isn't one
+func (m *Context) Deadline() (deadline time.Time, ok bool) {
+	if m == nil || m.Context == nil {
+		return
+	}
+	return m.Context.Deadline()
+}
+
+// Done is closed when the query is cancelled or its deadline passes,
+// nil (never closes) if there is no embedded context.Context
+func (m *Context) Done() <-chan struct{} {
+	if m == nil || m.Context == nil {
+		return nil
+	}
+	return m.Context.Done()
+}
+
+// Err why the query was cancelled, nil if it was not
+func (m *Context) Err() error {
+	if m == nil || m.Context == nil {
+		return nil
+	}
+	return m.Context.Err()
+}
+
+// Value of key on the embedded context.Context
+func (m *Context) Value(key interface{}) interface{} {
+	if m == nil || m.Context == nil {
+		return nil
+	}
+	return m.Context.Value(key)
+}
+
 // called by go routines/tasks to ensure any recovery panics are captured
 func (m *Context) Recover() {
 	if m == nil {
@@ -82,6 +126,74 @@ func (m *Context) Recover() {
 		m.errRecover = r
 	}
 }
+
+// Rerun readies the plan made with this context to run again, as ctx with
+// a new memory account.  The tasks of a plan (and the conns they read) can
+// only be run once, so the conns of its sources are re-opened, the plan is
+// not re-made.
+func (m *Context) Rerun(ctx context.Context) error {
+	return m.rerun(ctx, NewMemoryAccount(QueryMemoryLimit, GlobalMemory))
+}
+
+func (m *Context) rerun(ctx context.Context, mem *MemoryAccount) error {
+	m.Context = ctx
+	m.Memory = mem
+	m.Errors = nil
+	m.errRecover = nil
+	for _, reopen := range m.reopen {
+		if err := reopen(); err != nil {
+			return err
+		}
+	}
+	for _, sub := range m.subs {
+		if err := sub.rerun(ctx, mem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cte the common table expression (of a WITH) of given name, nil if none
+func (m *Context) Cte(name string) *Cte {
+	if len(m.Ctes) == 0 {
+		return nil
+	}
+	return m.Ctes[strings.ToLower(name)]
+}
+
+// Table the schema of given table, which may be a common table expression
+// of a WITH which hides a table of the same name in the schema
+func (m *Context) Table(name string) (*schema.Table, error) {
+	if cte := m.Cte(name); cte != nil {
+		return cte.Tbl, nil
+	}
+	return m.Schema.Table(name)
+}
+
+// OpenConn a conn of table from ds for this statement, in a transaction it
+// is the conn of the transaction
+func (m *Context) OpenConn(ds schema.Source, table string) (schema.Conn, error) {
+	if m != nil && m.Tx != nil {
+		return m.Tx.Open(ds, table)
+	}
+	return ds.Open(table)
+}
+
+// Open a conn of table in the schema for this statement, in a transaction
+// it is the conn of the transaction
+func (m *Context) Open(table string) (schema.Conn, error) {
+	if m.Tx == nil {
+		return m.Schema.Open(table)
+	}
+	ss, err := m.Schema.Source(table)
+	if err != nil {
+		return nil, err
+	}
+	if ss.DS == nil {
+		return nil, fmt.Errorf("Could not find a DataSource for that table %q", table)
+	}
+	return m.Tx.Open(ss.DS, table)
+}
 func (m *Context) init() {
 	if m.id == 0 {
 		if m.Schema != nil {
@@ -0,0 +1,101 @@
+package plan
+
+import (
+	"strings"
+
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	// Ensure a Cte can be read as a FROM source
+	_ schema.Source            = (*Cte)(nil)
+	_ schema.SourceTableSchema = (*Cte)(nil)
+	_ schema.ConnScanner       = (*cteConn)(nil)
+	_ schema.ConnColumns       = (*cteConn)(nil)
+)
+
+// Cte a common table expression, ie named result set of a WITH statement.
+//
+//   WITH a AS (SELECT ...)
+//
+// The rows of Anchor are materialized into Rows by the executor, which
+// statements that select FROM the name read.  For WITH RECURSIVE where
+// the statement is a UNION [ALL] whose second SELECT reads from the name
+// itself, the Anchor is the first SELECT, the second is the recursive
+// step which is run against the rows of the previous step (starting with
+// the anchor rows) until it returns no new rows.
+//
+//   WITH RECURSIVE tree AS (
+//        SELECT id, parent_id FROM categories WHERE parent_id IS NULL
+//        UNION ALL
+//        SELECT c.id, c.parent_id FROM categories AS c INNER JOIN tree AS t ON c.parent_id = t.id
+//   ) SELECT id FROM tree
+//
+type Cte struct {
+	Stmt   *rel.SqlCte
+	Anchor Task             // *Select or *SetOperation
+	All    bool             // recursive UNION ALL, step rows aren't de-duplicated
+	Tbl    *schema.Table    // name, columns of the result set
+	Rows   []schema.Message // materialized rows, set by executor
+
+	work     *Cte                 // rows of the previous step, read by step
+	planStep func() (Task, error) // plan of recursive step
+}
+
+// NewCte create a common table expression with the given result columns
+func NewCte(stmt *rel.SqlCte, tbl *schema.Table) *Cte {
+	return &Cte{Stmt: stmt, Tbl: tbl}
+}
+
+// Recursive does this cte have a recursive step
+func (m *Cte) Recursive() bool { return m.planStep != nil }
+
+// PlanStep plan the recursive step, the tasks of a plan can only be
+// run once so this is re-planned for each iteration.  It reads the rows
+// set with SetWorkRows.
+func (m *Cte) PlanStep() (Task, error) { return m.planStep() }
+
+// SetWorkRows set the rows of the previous step, the recursive step
+// reads these (instead of Rows) as the name of this cte.
+func (m *Cte) SetWorkRows(rows []schema.Message) { m.work.Rows = rows }
+
+// Tables the single table of this cte
+func (m *Cte) Tables() []string { return []string{m.Tbl.Name} }
+
+// Open a scanner of the rows of this cte, each has its own cursor
+func (m *Cte) Open(name string) (schema.Conn, error) { return &cteConn{cte: m}, nil }
+
+// Close no-op
+func (m *Cte) Close() error { return nil }
+
+// Table schema of this cte
+func (m *Cte) Table(name string) (*schema.Table, error) { return m.Tbl, nil }
+
+// cteConn scanner of rows of a cte, which are read once the executor
+// has materialized them not when opened at plan time.
+type cteConn struct {
+	cte *Cte
+	pos int
+}
+
+func (m *cteConn) Columns() []string { return m.cte.Tbl.Columns() }
+func (m *cteConn) Close() error      { return nil }
+func (m *cteConn) Next() schema.Message {
+	if m.pos >= len(m.cte.Rows) {
+		return nil
+	}
+	msg := m.cte.Rows[m.pos]
+	m.pos++
+	return msg
+}
+
+// withCte copy of ctes, with the addition of cte
+func withCte(ctes map[string]*Cte, cte *Cte) map[string]*Cte {
+	all := make(map[string]*Cte, len(ctes)+1)
+	for name, c := range ctes {
+		all[name] = c
+	}
+	all[strings.ToLower(cte.Stmt.Name)] = cte
+	return all
+}
@@ -0,0 +1,202 @@
+package plan
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	// QueryMemoryLimit is the default number of bytes the operators (join,
+	// group by, sort, buffered results, etc) of a single query may reserve,
+	// 0 is no limit.  Set Context.Memory for a per query limit.
+	QueryMemoryLimit int64 = 0
+
+	// GlobalMemory is the pool all queries reserve their memory from, its
+	// limit (0 is none) bounds the memory of all running queries together.
+	GlobalMemory = NewMemoryPool(0)
+)
+
+// MemoryLimitError is returned when a reservation would take a query, or
+// all queries (Global), over its memory limit
+type MemoryLimitError struct {
+	Global    bool  // the global pool, not the query, is over limit
+	Limit     int64 // limit in bytes
+	Used      int64 // bytes in use before this reservation
+	Requested int64 // bytes requested
+}
+
+func (e *MemoryLimitError) Error() string {
+	scope := "query"
+	if e.Global {
+		scope = "global"
+	}
+	return fmt.Sprintf("QLBridge: %s memory limit of %d bytes exceeded, %d in use requested %d",
+		scope, e.Limit, e.Used, e.Requested)
+}
+
+// MemoryPool is memory shared by many queries
+type MemoryPool struct {
+	mu    sync.Mutex
+	limit int64
+	used  int64
+}
+
+// NewMemoryPool of limit bytes, 0 is no limit
+func NewMemoryPool(limit int64) *MemoryPool {
+	return &MemoryPool{limit: limit}
+}
+
+// SetLimit of the pool in bytes, 0 is no limit
+func (m *MemoryPool) SetLimit(limit int64) {
+	m.mu.Lock()
+	m.limit = limit
+	m.mu.Unlock()
+}
+
+// Used bytes reserved by all queries
+func (m *MemoryPool) Used() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.used
+}
+
+func (m *MemoryPool) reserve(n int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.limit > 0 && m.used+n > m.limit {
+		return &MemoryLimitError{Global: true, Limit: m.limit, Used: m.used, Requested: n}
+	}
+	m.used += n
+	return nil
+}
+
+func (m *MemoryPool) release(n int64) {
+	m.mu.Lock()
+	m.used -= n
+	m.mu.Unlock()
+}
+
+// MemoryAccount tracks the memory reserved by the operators of one query
+// against its limit and the pool it shares with other queries.
+//
+//   - operators Reserve bytes before buffering rows/state and Release them
+//     when done, a *MemoryLimitError means the row can't be buffered and
+//     the query fails with it (see Err).
+//   - operators that can spill to disk TryReserve, and spill instead of
+//     failing.
+//   - a nil account does no accounting, and has no limit.
+type MemoryAccount struct {
+	mu    sync.Mutex
+	limit int64
+	used  int64
+	peak  int64
+	pool  *MemoryPool
+	err   error
+}
+
+// NewMemoryAccount for a query with limit bytes (0 is no limit), reserving
+// from pool (may be nil)
+func NewMemoryAccount(limit int64, pool *MemoryPool) *MemoryAccount {
+	return &MemoryAccount{limit: limit, pool: pool}
+}
+
+// Reserve n bytes, a *MemoryLimitError if that is over the limit of the
+// query or its pool, which is then the error of the query
+func (m *MemoryAccount) Reserve(n int64) error {
+	if m == nil || n <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := m.reserve(n)
+	if err != nil && m.err == nil {
+		m.err = err
+	}
+	return err
+}
+
+// TryReserve n bytes, false if that is over the limit of the query or
+// its pool
+func (m *MemoryAccount) TryReserve(n int64) bool {
+	if m == nil || n <= 0 {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reserve(n) == nil
+}
+
+func (m *MemoryAccount) reserve(n int64) error {
+	if m.limit > 0 && m.used+n > m.limit {
+		return &MemoryLimitError{Limit: m.limit, Used: m.used, Requested: n}
+	}
+	if m.pool != nil {
+		if err := m.pool.reserve(n); err != nil {
+			return err
+		}
+	}
+	m.used += n
+	if m.used > m.peak {
+		m.peak = m.used
+	}
+	return nil
+}
+
+// Release n previously reserved bytes
+func (m *MemoryAccount) Release(n int64) {
+	if m == nil || n <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n > m.used {
+		n = m.used
+	}
+	m.used -= n
+	if m.pool != nil {
+		m.pool.release(n)
+	}
+}
+
+// Err the *MemoryLimitError of the first Reserve that failed, nil if none
+func (m *MemoryAccount) Err() error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// Used bytes currently reserved
+func (m *MemoryAccount) Used() int64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.used
+}
+
+// Peak the most bytes reserved at once
+func (m *MemoryAccount) Peak() int64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peak
+}
+
+// Close releases everything still reserved back to the pool
+func (m *MemoryAccount) Close() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pool != nil {
+		m.pool.release(m.used)
+	}
+	m.used = 0
+}
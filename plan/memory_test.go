@@ -0,0 +1,51 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+
+	"github.com/araddon/qlbridge/plan"
+)
+
+func TestMemoryAccount(t *testing.T) {
+	pool := plan.NewMemoryPool(150)
+	q1 := plan.NewMemoryAccount(100, pool)
+	q2 := plan.NewMemoryAccount(0, pool)
+
+	assert.T(t, q1.Reserve(60) == nil)
+	assert.T(t, q1.TryReserve(40))
+	assert.Equal(t, int64(100), q1.Used())
+	assert.T(t, q1.Err() == nil)
+
+	// over the query limit, a failed TryReserve doesn't fail the query
+	assert.T(t, !q1.TryReserve(1))
+	assert.T(t, q1.Err() == nil)
+	err := q1.Reserve(1)
+	me, ok := err.(*plan.MemoryLimitError)
+	assert.Tf(t, ok, "expected memory limit error but got %v", err)
+	assert.T(t, !me.Global && me.Limit == 100 && me.Used == 100 && me.Requested == 1)
+	assert.Equal(t, err, q1.Err())
+
+	// over the limit of the pool shared by both queries
+	err = q2.Reserve(60)
+	me, ok = err.(*plan.MemoryLimitError)
+	assert.Tf(t, ok && me.Global, "expected global memory limit error but got %v", err)
+	assert.T(t, q2.Reserve(50) == nil)
+	assert.Equal(t, int64(150), pool.Used())
+
+	q1.Release(30)
+	assert.Equal(t, int64(70), q1.Used())
+	assert.Equal(t, int64(100), q1.Peak())
+	assert.Equal(t, int64(120), pool.Used())
+
+	q1.Close()
+	assert.Equal(t, int64(0), q1.Used())
+	assert.Equal(t, int64(50), pool.Used())
+
+	// nil account has no limit
+	var none *plan.MemoryAccount
+	assert.T(t, none.Reserve(1<<40) == nil)
+	none.Release(1)
+	assert.Equal(t, int64(0), none.Used())
+}
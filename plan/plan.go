@@ -12,8 +12,11 @@ import (
 	u "github.com/araddon/gou"
 	"github.com/golang/protobuf/proto"
 
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
@@ -25,6 +28,9 @@ var (
 	// Force Plans to implement Task
 	_ Task = (*PreparedStatement)(nil)
 	_ Task = (*Select)(nil)
+	_ Task = (*SetOperation)(nil)
+	_ Task = (*With)(nil)
+	_ Task = (*Explain)(nil)
 	_ Task = (*Insert)(nil)
 	_ Task = (*Upsert)(nil)
 	_ Task = (*Update)(nil)
@@ -36,8 +42,13 @@ var (
 	_ Task = (*Where)(nil)
 	_ Task = (*Having)(nil)
 	_ Task = (*GroupBy)(nil)
+	_ Task = (*OrderBy)(nil)
+	_ Task = (*Distinct)(nil)
+	_ Task = (*Window)(nil)
 	_ Task = (*JoinMerge)(nil)
 	_ Task = (*JoinKey)(nil)
+	_ Task = (*SemiJoin)(nil)
+	_ Task = (*PartitionScan)(nil)
 
 	// Force any plan that participates in a Select to implement Proto
 	//  which allows us to serialize and distribute to multiple nodes.
@@ -85,6 +96,9 @@ type (
 	Planner interface {
 		WalkPreparedStatement(p *PreparedStatement) error
 		WalkSelect(p *Select) error
+		WalkSetOperation(p *SetOperation) error
+		WalkWith(p *With) error
+		WalkExplain(p *Explain) error
 		WalkInsert(p *Insert) error
 		WalkUpsert(p *Upsert) error
 		WalkUpdate(p *Update) error
@@ -124,10 +138,48 @@ type (
 		ChildDag bool
 		pbplan   *PlanPb
 	}
+	// SetOperation, UNION, INTERSECT, EXCEPT of the rows of two inputs which
+	// are each planned as their own Select (or nested SetOperation) with their
+	// own Context.  Result is a select of the combined rows, for the
+	// ORDER BY, LIMIT of the set operation.
+	SetOperation struct {
+		*PlanBase
+		Ctx    *Context
+		Stmt   *rel.SqlSetOperation
+		Left   Task // *Select or *SetOperation
+		Right  Task // *Select or *SetOperation
+		Result *rel.SqlSelect
+	}
+	// With, the common table expressions of a WITH statement, each planned
+	// as its own statement in order.  Their rows are materialized by the
+	// executor before the Main statement is run, which (as can each later
+	// Cte) reads them by name as a FROM source.
+	With struct {
+		*PlanBase
+		Ctx  *Context
+		Stmt *rel.SqlWith
+		Ctes []*Cte
+		Main Task // *Select or *SetOperation
+	}
+	// Explain, EXPLAIN [ANALYZE] of a statement which is planned with its
+	// own Context.  Its rows describe the tasks the executor builds for
+	// Plan, with ANALYZE the statement is run and the rows also report
+	// what each task did.
+	Explain struct {
+		*PlanBase
+		Ctx  *Context
+		Stmt *rel.SqlDescribe
+		Plan Task // *Select, *SetOperation or *With
+	}
+	// Insert, of VALUES or of the rows of a SELECT which is planned with
+	// its own Context.
 	Insert struct {
 		*PlanBase
-		Stmt   *rel.SqlInsert
-		Source schema.ConnUpsert
+		Stmt    *rel.SqlInsert
+		Source  schema.ConnUpsert
+		Select  Task     // planned Stmt.Select, nil for VALUES
+		Columns []string // columns of the table, rows are written in this order
+		ColPos  []int    // position in Columns of each column of Select
 	}
 	Upsert struct {
 		*PlanBase
@@ -180,22 +232,51 @@ type (
 		Tbl          *schema.Table        // Table schema for this From
 		Static       []driver.Value       // this is static data source
 		Cols         []string
+		Pushed       []expr.Node       // conjuncts of the where the Conn filters (schema.ConnFilter)
+		Needed       []string          // columns the Conn reads (schema.ConnProjection), nil if all
+		Partition    *schema.Partition // partition the Conn scans, nil if all
+		SeekType     value.ValueType   // type of the column a lookup join seeks on
 	}
-	// Select INTO table
+	// Select INTO table, the select is planned with its own Context and
+	// its rows written to the table same as INSERT ... SELECT
 	Into struct {
 		*PlanBase
-		Stmt *rel.SqlInto
+		Stmt    *rel.SqlInto
+		Query   *rel.SqlSelect // the select with the INTO
+		Source  schema.ConnUpsert
+		Select  Task     // planned Query, without its INTO
+		Columns []string // columns of the table, rows are written in this order
+		ColPos  []int    // position in Columns of each column of Select
 	}
 	GroupBy struct {
 		*PlanBase
 		Stmt    *rel.SqlSelect
-		Partial bool
+		Partial bool // output the partial aggregate state of each group
+		Final   bool // merge partial states, of each partition's GroupBy
+	}
+	// OrderBy, sort of result rows, post aggregation
+	OrderBy struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
+	}
+	// Distinct, de-duplication of projected result rows, also
+	// applies the Offset, Limit of the statement
+	Distinct struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
+	}
+	// Window, evaluate the window function columns of the rows,
+	// pre order by and projection
+	Window struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
 	}
 	// Where, pre-aggregation filter
 	Where struct {
 		*PlanBase
-		Final bool
-		Stmt  *rel.SqlSelect
+		Final  bool
+		Stmt   *rel.SqlSelect
+		Filter expr.Node // if not nil, filter instead of Stmt.Where, the residual of pushdown
 	}
 	// Having, post-aggregation filter
 	Having struct {
@@ -215,6 +296,25 @@ type (
 		*PlanBase
 		Source *Source
 	}
+	// SemiJoin, filter of rows by an IN, EXISTS sub-query condition.  The
+	// sub-query is planned as its own Select whose rows are the keys that
+	// the Left expressions of each row are matched against.
+	SemiJoin struct {
+		*PlanBase
+		Stmt *rel.SqlSelect // outer statement
+		Cond *rel.SqlWhere  // the IN, EXISTS sub-query condition
+		Left []expr.Node    // expressions of outer row matched to sub-query columns
+		Sub  *Select        // plan of (rewritten) sub-query
+	}
+	// PartitionScan, parallel scan of the partitions of a source that is
+	// partitionable (schema.SourcePartitionable).  Each partition is its
+	// own Source, with its where and partial group by, their rows are
+	// merged into one stream.
+	PartitionScan struct {
+		*PlanBase
+		Source *Source   // source of the statement, it isn't scanned itself
+		Parts  []*Source // source of each partition
+	}
 )
 
 // Walk given statement for given Planner to produce a query plan
@@ -224,7 +324,15 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 	base := NewPlanBase(false)
 	switch st := stmt.(type) {
 	case *rel.SqlSelect:
+		if st.Into != nil {
+			p = &Into{Stmt: st.Into, Query: st, PlanBase: base}
+			break
+		}
 		p = &Select{Stmt: st, PlanBase: base, Ctx: ctx}
+	case *rel.SqlSetOperation:
+		p = &SetOperation{Stmt: st, PlanBase: base, Ctx: ctx}
+	case *rel.SqlWith:
+		p = &With{Stmt: st, PlanBase: base, Ctx: ctx}
 	case *rel.PreparedStatement:
 		p = &PreparedStatement{Stmt: st, PlanBase: base}
 	case *rel.SqlInsert:
@@ -243,6 +351,10 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 		ctx.Stmt = sel
 		p = &Select{Stmt: sel, PlanBase: base, Ctx: ctx}
 	case *rel.SqlDescribe:
+		if st.Stmt != nil {
+			p = &Explain{Stmt: st, PlanBase: base, Ctx: ctx}
+			break
+		}
 		sel, err := RewriteDescribeAsSelect(st, ctx)
 		if err != nil {
 			return nil, err
@@ -280,6 +392,12 @@ func SelectTaskFromTaskPb(pb *PlanPb, ctx *Context, sel *rel.SqlSelect) (Task, e
 		return HavingFromPB(pb), nil
 	case pb.GroupBy != nil:
 		return GroupByFromPB(pb), nil
+	case pb.OrderBy != nil:
+		return OrderByFromPB(pb), nil
+	case pb.Distinct != nil:
+		return DistinctFromPB(pb), nil
+	case pb.Window != nil:
+		return WindowFromPB(pb), nil
 	case pb.Projection != nil:
 		return ProjectionFromPB(pb, sel), nil
 	case pb.JoinMerge != nil:
@@ -343,8 +461,12 @@ func (m *PlanBase) EqualBase(p *PlanBase) bool {
 
 func (m *PlanBase) Walk(p Planner) error          { return ErrNotImplemented }
 func (m *Select) Walk(p Planner) error            { return p.WalkSelect(m) }
+func (m *SetOperation) Walk(p Planner) error      { return p.WalkSetOperation(m) }
+func (m *With) Walk(p Planner) error              { return p.WalkWith(m) }
+func (m *Explain) Walk(p Planner) error           { return p.WalkExplain(m) }
 func (m *PreparedStatement) Walk(p Planner) error { return p.WalkPreparedStatement(m) }
 func (m *Insert) Walk(p Planner) error            { return p.WalkInsert(m) }
+func (m *Into) Walk(p Planner) error              { return p.WalkInto(m) }
 func (m *Upsert) Walk(p Planner) error            { return p.WalkUpsert(m) }
 func (m *Update) Walk(p Planner) error            { return p.WalkUpdate(m) }
 func (m *Delete) Walk(p Planner) error            { return p.WalkDelete(m) }
@@ -553,6 +675,7 @@ func NewSource(ctx *Context, stmt *rel.SqlSource, isFinal bool) (*Source, error)
 	if err != nil {
 		return nil, err
 	}
+	ctx.reopen = append(ctx.reopen, s.reopenConn)
 	return s, nil
 }
 func NewSourceStaticPlan(ctx *Context) *Source {
@@ -586,13 +709,51 @@ func (m *Source) LoadConn() error {
 			return nil
 		}
 	}
-	source, err := m.DataSource.Open(m.Stmt.SourceName())
+	source, err := m.ctx.OpenConn(m.DataSource, m.Stmt.SourceName())
 	if err != nil {
 		return err
 	}
 	m.Conn = source
 	return nil
 }
+
+// reopenConn opens a new conn for this source to run its plan again, the
+// last run's is closed by the task that read it.  The filters, columns
+// pushed down to the last are pushed to the new one.
+func (m *Source) reopenConn() error {
+	if m.Conn == nil || m.DataSource == nil {
+		return nil
+	}
+	if _, ok := m.Conn.(SourcePlanner); ok {
+		return fmt.Errorf("%T plans its own source select, can't be re-opened", m.Conn)
+	}
+	var conn schema.Conn
+	var err error
+	if m.Partition != nil {
+		ds, ok := m.DataSource.(schema.SourcePartitionable)
+		if !ok {
+			return fmt.Errorf("%T is not partitionable", m.DataSource)
+		}
+		conn, err = ds.PartitionSource(m.Partition)
+	} else {
+		conn, err = m.ctx.OpenConn(m.DataSource, m.Stmt.SourceName())
+	}
+	if err != nil {
+		return err
+	}
+	if len(m.Pushed) > 0 {
+		filter, ok := conn.(schema.ConnFilter)
+		if !ok {
+			return fmt.Errorf("%T can't filter, the filters pushed to %q", conn, m.Stmt.SourceName())
+		}
+		filter.PushFilters(m.Pushed)
+	}
+	if proj, ok := conn.(schema.ConnProjection); ok && m.Needed != nil {
+		proj.ProjectColumns(m.Needed)
+	}
+	m.Conn = conn
+	return nil
+}
 func (m *Source) IsSchemaQuery() bool {
 	if m.Stmt != nil && len(m.Stmt.Schema) > 0 {
 		//u.Debugf("schema:%q name:%q", m.Stmt.Schema, m.Stmt.Name)
@@ -685,6 +846,12 @@ func (m *Source) load() error {
 		u.Errorf("missing schema in *plan.Source load() from:%q", fromName)
 		return fmt.Errorf("Missing schema")
 	}
+	if cte := m.ctx.Cte(fromName); cte != nil {
+		// WITH fromName AS (...), rows are materialized by the executor
+		m.DataSource = cte
+		m.Tbl = cte.Tbl
+		return projectionForSourcePlan(m)
+	}
 	ss, err := m.ctx.Schema.Source(fromName)
 	if err != nil {
 		u.Debugf("no schema found for %T  %q.%q ? err=%v", m.ctx.Schema, m.Stmt.Schema, fromName, err)
@@ -797,12 +964,47 @@ func NewJoinMerge(l, r Task, lf, rf *rel.SqlSource) *JoinMerge {
 
 	return m
 }
+// A semi-join (IN, EXISTS) or anti-join (NOT IN, NOT EXISTS) filter of
+// rows by the keys produced by a sub-query
+//
+//   source  ->  where  ->  semijoin  ->  projection
+//                             ^
+//                   sub-query select keys
+//
+func NewSemiJoin(stmt *rel.SqlSelect, cond *rel.SqlWhere, left []expr.Node, sub *Select) *SemiJoin {
+	return &SemiJoin{Stmt: stmt, Cond: cond, Left: left, Sub: sub, PlanBase: NewPlanBase(false)}
+}
+
+// Anti is this a NOT IN, NOT EXISTS, ie rows that do not match are kept
+func (m *SemiJoin) Anti() bool { return m.Cond.Not }
+
+// NullAware IN semantics, where NULL on either side makes the match unknown,
+// instead of EXISTS where NULL keys just never match
+func (m *SemiJoin) NullAware() bool { return m.Cond.Op == lex.TokenIN }
+
+// A parallel scan of the partitions of a source
+//
+//   partition 1 -> where -> groupby partial ->
+//                                             \
+//                                               --  merge  -->  groupby final
+//                                             /
+//   partition 2 -> where -> groupby partial ->
+//
+func NewPartitionScan(s *Source, parts []*Source) *PartitionScan {
+	m := &PartitionScan{Source: s, Parts: parts, PlanBase: NewPlanBase(false)}
+	m.SetParallel()
+	return m
+}
+
 func NewJoinKey(s *Source) *JoinKey {
 	return &JoinKey{Source: s, PlanBase: NewPlanBase(false)}
 }
 func NewWhere(stmt *rel.SqlSelect) *Where {
 	return &Where{Stmt: stmt, PlanBase: NewPlanBase(false)}
 }
+func NewWhereFilter(stmt *rel.SqlSelect, filter expr.Node) *Where {
+	return &Where{Stmt: stmt, Filter: filter, PlanBase: NewPlanBase(false)}
+}
 func NewWhereFinal(stmt *rel.SqlSelect) *Where {
 	return &Where{Stmt: stmt, Final: true, PlanBase: NewPlanBase(false)}
 }
@@ -812,6 +1014,21 @@ func NewHaving(stmt *rel.SqlSelect) *Having {
 func NewGroupBy(stmt *rel.SqlSelect) *GroupBy {
 	return &GroupBy{Stmt: stmt, PlanBase: NewPlanBase(false)}
 }
+func NewGroupByPartial(stmt *rel.SqlSelect) *GroupBy {
+	return &GroupBy{Stmt: stmt, Partial: true, PlanBase: NewPlanBase(false)}
+}
+func NewGroupByFinal(stmt *rel.SqlSelect) *GroupBy {
+	return &GroupBy{Stmt: stmt, Final: true, PlanBase: NewPlanBase(false)}
+}
+func NewOrderBy(stmt *rel.SqlSelect) *OrderBy {
+	return &OrderBy{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
+func NewDistinct(stmt *rel.SqlSelect) *Distinct {
+	return &Distinct{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
+func NewWindow(stmt *rel.SqlSelect) *Window {
+	return &Window{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
 
 func (m *Into) Equal(t Task) bool {
 	if m == nil && t == nil {
@@ -928,6 +1145,9 @@ func (m *GroupBy) Equal(t Task) bool {
 	if !ok {
 		return false
 	}
+	if m.Partial != s.Partial || m.Final != s.Final {
+		return false
+	}
 
 	if !m.PlanBase.EqualBase(s.PlanBase) {
 		return false
@@ -942,6 +1162,114 @@ func GroupByFromPB(pb *PlanPb) *GroupBy {
 	return &m
 }
 
+func (m *OrderBy) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.OrderBy = &OrderByPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *OrderBy) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*OrderBy)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func OrderByFromPB(pb *PlanPb) *OrderBy {
+	m := OrderBy{
+		Stmt: rel.SqlSelectFromPb(pb.OrderBy.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
+func (m *Distinct) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.Distinct = &DistinctPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *Distinct) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Distinct)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func DistinctFromPB(pb *PlanPb) *Distinct {
+	m := Distinct{
+		Stmt: rel.SqlSelectFromPb(pb.Distinct.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
+func (m *Window) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.Window = &WindowPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *Window) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Window)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func WindowFromPB(pb *PlanPb) *Window {
+	m := Window{
+		Stmt: rel.SqlSelectFromPb(pb.Window.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
 func (m *JoinMerge) Equal(t Task) bool {
 	if m == nil && t == nil {
 		return true
@@ -982,3 +1310,126 @@ func (m *JoinKey) Equal(t Task) bool {
 	}
 	return true
 }
+func (m *PartitionScan) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*PartitionScan)
+	if !ok {
+		return false
+	}
+	if len(m.Parts) != len(s.Parts) {
+		return false
+	}
+	for i, part := range m.Parts {
+		if !part.Equal(s.Parts[i]) {
+			return false
+		}
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func (m *SemiJoin) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*SemiJoin)
+	if !ok {
+		return false
+	}
+	if !m.Cond.Equal(s.Cond) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func (m *SetOperation) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*SetOperation)
+	if !ok {
+		return false
+	}
+	if !m.Stmt.Equal(s.Stmt) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func (m *With) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*With)
+	if !ok {
+		return false
+	}
+	if !m.Stmt.Equal(s.Stmt) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func (m *Explain) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Explain)
+	if !ok {
+		return false
+	}
+	if m.Stmt.Analyze != s.Stmt.Analyze {
+		return false
+	}
+	if m.Plan == nil || s.Plan == nil {
+		if m.Plan != s.Plan {
+			return false
+		}
+	} else if !m.Plan.Equal(s.Plan) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
@@ -18,6 +18,9 @@
 		HavingPb
 		JoinMergePb
 		JoinKeyPb
+		OrderByPb
+		DistinctPb
+		WindowPb
 */
 package plan
 
@@ -53,6 +56,9 @@ type PlanPb struct {
 	JoinKey          *JoinKeyPb        `protobuf:"bytes,9,opt,name=joinKey" json:"joinKey,omitempty"`
 	Projection       *rel.ProjectionPb `protobuf:"bytes,10,opt,name=projection" json:"projection,omitempty"`
 	Children         []*PlanPb         `protobuf:"bytes,11,rep,name=children" json:"children,omitempty"`
+	OrderBy          *OrderByPb        `protobuf:"bytes,12,opt,name=orderBy" json:"orderBy,omitempty"`
+	Distinct         *DistinctPb       `protobuf:"bytes,13,opt,name=distinct" json:"distinct,omitempty"`
+	Window           *WindowPb         `protobuf:"bytes,14,opt,name=window" json:"window,omitempty"`
 	XXX_unrecognized []byte            `json:"-"`
 }
 
@@ -161,6 +167,38 @@ func (m *JoinKeyPb) String() string            { return proto.CompactTextString(
 func (*JoinKeyPb) ProtoMessage()               {}
 func (*JoinKeyPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{8} }
 
+// Order By Plan
+type OrderByPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *OrderByPb) Reset()                    { *m = OrderByPb{} }
+func (m *OrderByPb) String() string            { return proto.CompactTextString(m) }
+func (*OrderByPb) ProtoMessage()               {}
+func (*OrderByPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{9} }
+
+// Distinct Plan
+type DistinctPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *DistinctPb) Reset()                    { *m = DistinctPb{} }
+func (m *DistinctPb) String() string            { return proto.CompactTextString(m) }
+func (*DistinctPb) ProtoMessage()               {}
+func (*DistinctPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{10} }
+
+type WindowPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *WindowPb) Reset()                    { *m = WindowPb{} }
+func (m *WindowPb) String() string            { return proto.CompactTextString(m) }
+func (*WindowPb) ProtoMessage()               {}
+func (*WindowPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{11} }
+
 func init() {
 	proto.RegisterType((*PlanPb)(nil), "plan.PlanPb")
 	proto.RegisterType((*SelectPb)(nil), "plan.SelectPb")
@@ -171,6 +209,9 @@ func init() {
 	proto.RegisterType((*HavingPb)(nil), "plan.HavingPb")
 	proto.RegisterType((*JoinMergePb)(nil), "plan.JoinMergePb")
 	proto.RegisterType((*JoinKeyPb)(nil), "plan.JoinKeyPb")
+	proto.RegisterType((*OrderByPb)(nil), "plan.OrderByPb")
+	proto.RegisterType((*DistinctPb)(nil), "plan.DistinctPb")
+	proto.RegisterType((*WindowPb)(nil), "plan.WindowPb")
 }
 func (m *PlanPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -287,6 +328,36 @@ func (m *PlanPb) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.OrderBy != nil {
+		data[i] = 0x62
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.OrderBy.Size()))
+		n19, err := m.OrderBy.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	if m.Distinct != nil {
+		data[i] = 0x6a
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Distinct.Size()))
+		n21, err := m.Distinct.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	if m.Window != nil {
+		data[i] = 0x72
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Window.Size()))
+		n22, err := m.Window.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -617,6 +688,99 @@ func (m *JoinKeyPb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *OrderByPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *OrderByPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n18, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n18
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *DistinctPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *DistinctPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n20, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *WindowPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *WindowPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n23, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func encodeFixed64Plan(data []byte, offset int, v uint64) int {
 	data[offset] = uint8(v)
 	data[offset+1] = uint8(v >> 8)
@@ -686,6 +850,18 @@ func (m *PlanPb) Size() (n int) {
 			n += 1 + l + sovPlan(uint64(l))
 		}
 	}
+	if m.OrderBy != nil {
+		l = m.OrderBy.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.Distinct != nil {
+		l = m.Distinct.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.Window != nil {
+		l = m.Window.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -814,6 +990,45 @@ func (m *JoinKeyPb) Size() (n int) {
 	return n
 }
 
+func (m *OrderByPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DistinctPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovPlan(x uint64) (n int) {
 	for {
 		n++
@@ -1173,6 +1388,105 @@ func (m *PlanPb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OrderBy == nil {
+				m.OrderBy = &OrderByPb{}
+			}
+			if err := m.OrderBy.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Distinct == nil {
+				m.Distinct = &DistinctPb{}
+			}
+			if err := m.Distinct.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Window == nil {
+				m.Window = &WindowPb{}
+			}
+			if err := m.Window.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPlan(data[iNdEx:])
@@ -2165,6 +2479,258 @@ func (m *JoinKeyPb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *OrderByPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OrderByPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OrderByPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DistinctPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DistinctPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DistinctPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPlan(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -2271,46 +2837,51 @@ var (
 )
 
 var fileDescriptorPlan = []byte{
-	// 644 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x94, 0xdd, 0x4e, 0xd4, 0x40,
-	0x14, 0xc7, 0xd9, 0xb2, 0xc0, 0xf6, 0x2c, 0x2a, 0x4e, 0xb8, 0x98, 0x10, 0x83, 0x9b, 0xc6, 0x18,
-	0xf0, 0xa3, 0x55, 0x12, 0x43, 0xd4, 0x3b, 0x8c, 0x91, 0x60, 0x34, 0x1b, 0x88, 0xf1, 0xba, 0x1f,
-	0x43, 0x5b, 0x1c, 0x3a, 0x65, 0xb6, 0xab, 0xf0, 0x26, 0xbe, 0x8b, 0x2f, 0xc0, 0xa5, 0x4f, 0x60,
-	0x44, 0x5f, 0xc4, 0xf9, 0xea, 0x74, 0x90, 0x60, 0xdc, 0x8b, 0x4d, 0x3a, 0xff, 0xf3, 0x3b, 0x73,
-	0x76, 0xe6, 0xfc, 0xcf, 0x00, 0xd4, 0x34, 0xae, 0xc2, 0x9a, 0xb3, 0x86, 0xa1, 0xbe, 0xfc, 0x5e,
-	0x7b, 0x9c, 0x97, 0x4d, 0x31, 0x4d, 0xc2, 0x94, 0x1d, 0x47, 0x39, 0xcb, 0x59, 0xa4, 0x82, 0xc9,
-	0xf4, 0x50, 0xad, 0xd4, 0x42, 0x7d, 0xe9, 0xa4, 0xb5, 0x4d, 0x07, 0x8f, 0x79, 0x9c, 0x65, 0xac,
-	0x8a, 0x4e, 0x68, 0xc2, 0xcb, 0x2c, 0x27, 0x11, 0x27, 0x34, 0x9a, 0x9c, 0x50, 0x83, 0x3e, 0xfc,
-	0x17, 0x4a, 0x4e, 0x6b, 0x1e, 0x55, 0x2c, 0x23, 0x1a, 0x0e, 0x2e, 0xe6, 0x61, 0x71, 0x2c, 0xfe,
-	0xcf, 0x38, 0x41, 0x23, 0x18, 0xd4, 0x82, 0xa7, 0x94, 0x50, 0xdc, 0x1b, 0x79, 0x1b, 0x83, 0x9d,
-	0xfe, 0xf9, 0x8f, 0xbb, 0x73, 0xfb, 0x56, 0x45, 0x8f, 0x60, 0x71, 0x42, 0x28, 0x49, 0x1b, 0x3c,
-	0x3f, 0xea, 0x6d, 0x0c, 0xb7, 0x6e, 0x86, 0xea, 0x58, 0x07, 0x4a, 0x1b, 0x27, 0x8a, 0xef, 0xed,
-	0x1b, 0x46, 0xd1, 0x6c, 0xca, 0x53, 0x82, 0xfb, 0x97, 0x68, 0xa5, 0x39, 0xb4, 0x5a, 0xa3, 0x4d,
-	0x58, 0xf8, 0x52, 0x10, 0x4e, 0xf0, 0x82, 0x82, 0x6f, 0x68, 0xf8, 0xa3, 0x94, 0x2c, 0xab, 0x09,
-	0xb9, 0x71, 0x11, 0x7f, 0x2e, 0xab, 0x1c, 0x2f, 0xba, 0x1b, 0xef, 0x2a, 0xad, 0xdb, 0x58, 0x33,
-	0x28, 0x82, 0xa5, 0x9c, 0xb3, 0x69, 0xbd, 0x73, 0x86, 0x97, 0x14, 0x7e, 0x4b, 0xe3, 0x6f, 0xb4,
-	0x68, 0xf9, 0x96, 0x42, 0xcf, 0xc0, 0x3f, 0x62, 0x65, 0xf5, 0x8e, 0xf0, 0x9c, 0xe0, 0x81, 0x4a,
-	0xb9, 0xad, 0x53, 0xf6, 0x5a, 0xd9, 0x26, 0x75, 0xa4, 0xac, 0x23, 0x17, 0x6f, 0xc9, 0x19, 0xf6,
-	0xdd, 0x3a, 0x7b, 0x5a, 0xec, 0xea, 0x18, 0x0a, 0x6d, 0x0b, 0x57, 0x70, 0x76, 0x24, 0xae, 0xaa,
-	0x64, 0x15, 0x06, 0x53, 0x48, 0xf4, 0x32, 0x1c, 0x5b, 0xd9, 0x66, 0x39, 0x28, 0x0a, 0x61, 0x90,
-	0x16, 0x25, 0xcd, 0x38, 0xa9, 0xf0, 0x70, 0x34, 0x2f, 0xd2, 0x96, 0x75, 0x29, 0xdd, 0x48, 0x93,
-	0x61, 0x99, 0xe0, 0x13, 0x0c, 0xda, 0x16, 0x89, 0xdc, 0xb6, 0x85, 0xb2, 0xc5, 0xc3, 0xad, 0x15,
-	0x55, 0xf0, 0xe0, 0x84, 0x5e, 0xd3, 0x44, 0x71, 0xaa, 0x94, 0x55, 0x0d, 0x39, 0x6d, 0xb0, 0xe7,
-	0x9e, 0xea, 0x95, 0x16, 0xbb, 0x53, 0x19, 0x2a, 0xc8, 0xc1, 0xb7, 0x31, 0x74, 0x47, 0x54, 0x4b,
-	0x0b, 0x72, 0x1c, 0xab, 0x6a, 0xbe, 0x31, 0x94, 0xd1, 0xd0, 0x2a, 0x78, 0x65, 0x26, 0xb6, 0xf5,
-	0x36, 0xfa, 0x26, 0x22, 0xd6, 0xe8, 0x3e, 0x0c, 0x0f, 0x45, 0xdf, 0x08, 0xaf, 0x79, 0x59, 0x49,
-	0xa7, 0x75, 0x61, 0x37, 0x10, 0x7c, 0xf3, 0xc4, 0xb1, 0x8c, 0x97, 0xd0, 0x13, 0x58, 0xa9, 0x08,
-	0xc9, 0x26, 0xbb, 0xf1, 0xa4, 0x88, 0x13, 0x4a, 0x64, 0x17, 0x3c, 0xc7, 0xc3, 0x57, 0xa2, 0x68,
-	0x0d, 0x16, 0xc4, 0x6e, 0x31, 0x55, 0x05, 0x5a, 0x4c, 0x4b, 0x72, 0x12, 0xc4, 0xf0, 0xd4, 0x94,
-	0x34, 0xd2, 0xbb, 0xce, 0x24, 0xb4, 0x2a, 0xc2, 0xd0, 0x97, 0x6d, 0x14, 0x66, 0xed, 0xa2, 0x4a,
-	0x41, 0xf7, 0x00, 0xb4, 0xa3, 0x5f, 0x9f, 0x92, 0x54, 0x18, 0xb4, 0x8b, 0x3b, 0xba, 0xbc, 0x98,
-	0x74, 0x3a, 0x69, 0xd8, 0xb1, 0xf2, 0xe4, 0x72, 0x7b, 0xe9, 0x5a, 0x13, 0x4d, 0xf2, 0xc5, 0x38,
-	0xeb, 0xc3, 0x19, 0x07, 0x76, 0x7d, 0x32, 0x47, 0xde, 0xef, 0x10, 0xf4, 0xf4, 0x92, 0x93, 0xfc,
-	0x6b, 0x9c, 0xe4, 0x7a, 0x28, 0xf8, 0x00, 0x4b, 0x66, 0xb6, 0x2e, 0x59, 0xa2, 0xf7, 0x1f, 0x96,
-	0xb0, 0x37, 0xe7, 0x5d, 0xb9, 0xb9, 0xe0, 0x25, 0xf8, 0x76, 0xae, 0x66, 0xdd, 0x38, 0x78, 0x01,
-	0x83, 0x76, 0x86, 0x67, 0xce, 0x7d, 0x0e, 0x43, 0x67, 0x3a, 0xd1, 0x03, 0xfb, 0x44, 0xe8, 0xf4,
-	0xe5, 0x50, 0x3e, 0x7c, 0xe1, 0x7b, 0xf1, 0xf0, 0xfd, 0xfd, 0x40, 0x04, 0xdb, 0xe0, 0xdb, 0x19,
-	0x9d, 0x25, 0x71, 0x67, 0xf5, 0xfc, 0x62, 0x7d, 0xee, 0xfc, 0xd7, 0x7a, 0xef, 0xbb, 0xf8, 0xfd,
-	0x14, 0xbf, 0xaf, 0xbf, 0xd7, 0xe7, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0x9f, 0x12, 0x33, 0xef,
-	0xeb, 0x05, 0x00, 0x00,
+	// 723 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xdd, 0x6e, 0xd3, 0x48,
+	0x14, 0xc7, 0x1b, 0x37, 0x1f, 0xf6, 0x49, 0xda, 0xed, 0x8e, 0x7a, 0x31, 0xaa, 0x56, 0xd9, 0xc8,
+	0x5a, 0xad, 0xd2, 0x5d, 0xb0, 0xa1, 0x12, 0xaa, 0xa0, 0x5c, 0x05, 0x10, 0x55, 0x11, 0x10, 0xb5,
+	0x42, 0xbd, 0x76, 0xec, 0xa9, 0xe3, 0x32, 0xf1, 0x38, 0x63, 0x87, 0xb6, 0x6f, 0xc2, 0xbb, 0xf0,
+	0x02, 0xbd, 0xe4, 0x09, 0x10, 0x94, 0x57, 0xe0, 0x01, 0xd0, 0xcc, 0xd8, 0xe3, 0x29, 0x55, 0x11,
+	0xe1, 0xce, 0xfe, 0x9f, 0xdf, 0x99, 0x33, 0x1f, 0xff, 0x73, 0x00, 0x32, 0x1a, 0xa4, 0x5e, 0xc6,
+	0x59, 0xc1, 0x50, 0x53, 0x7c, 0x6f, 0xdd, 0x8d, 0x93, 0x62, 0xba, 0x98, 0x78, 0x21, 0x9b, 0xf9,
+	0x31, 0x8b, 0x99, 0x2f, 0x83, 0x93, 0xc5, 0x89, 0xfc, 0x93, 0x3f, 0xf2, 0x4b, 0x25, 0x6d, 0x6d,
+	0x1b, 0x78, 0xc0, 0x83, 0x28, 0x62, 0xa9, 0x3f, 0xa7, 0x13, 0x9e, 0x44, 0x31, 0xf1, 0x39, 0xa1,
+	0x7e, 0x3e, 0xa7, 0x25, 0xfa, 0xff, 0xcf, 0x50, 0x72, 0x9e, 0x71, 0x3f, 0x65, 0x11, 0x51, 0xb0,
+	0xfb, 0xad, 0x09, 0xed, 0x31, 0x0d, 0xd2, 0xf1, 0x04, 0x0d, 0xc0, 0xce, 0x02, 0x1e, 0x50, 0x4a,
+	0x28, 0x6e, 0x0c, 0xac, 0xa1, 0x3d, 0x6a, 0x5e, 0x7e, 0xfa, 0x7b, 0xe5, 0x50, 0xab, 0xe8, 0x0e,
+	0xb4, 0x73, 0x42, 0x49, 0x58, 0xe0, 0xd5, 0x41, 0x63, 0xd8, 0xdd, 0x59, 0xf7, 0xe4, 0xb1, 0x8e,
+	0xa4, 0x36, 0x9e, 0x48, 0xbe, 0x71, 0x58, 0x32, 0x92, 0x66, 0x0b, 0x1e, 0x12, 0xdc, 0xbc, 0x46,
+	0x4b, 0xcd, 0xa0, 0xe5, 0x3f, 0xda, 0x86, 0xd6, 0xd9, 0x94, 0x70, 0x82, 0x5b, 0x12, 0x5e, 0x53,
+	0xf0, 0xb1, 0x90, 0x34, 0xab, 0x08, 0xb1, 0xf0, 0x34, 0x78, 0x97, 0xa4, 0x31, 0x6e, 0x9b, 0x0b,
+	0xef, 0x4b, 0xad, 0x5e, 0x58, 0x31, 0xc8, 0x87, 0x4e, 0xcc, 0xd9, 0x22, 0x1b, 0x5d, 0xe0, 0x8e,
+	0xc4, 0xff, 0x50, 0xf8, 0x73, 0x25, 0x6a, 0xbe, 0xa2, 0xd0, 0x03, 0x70, 0x4e, 0x59, 0x92, 0xbe,
+	0x24, 0x3c, 0x26, 0xd8, 0x96, 0x29, 0x7f, 0xaa, 0x94, 0x83, 0x4a, 0xd6, 0x49, 0x35, 0x29, 0xea,
+	0x88, 0x9f, 0x17, 0xe4, 0x02, 0x3b, 0x66, 0x9d, 0x03, 0x25, 0xd6, 0x75, 0x4a, 0x0a, 0xed, 0x02,
+	0x64, 0x9c, 0x9d, 0x92, 0xb0, 0x48, 0x58, 0x8a, 0xa1, 0x2c, 0xc4, 0x09, 0xf5, 0xc6, 0x5a, 0xd6,
+	0x59, 0x06, 0x8a, 0x3c, 0xb0, 0xc3, 0x69, 0x42, 0x23, 0x4e, 0x52, 0xdc, 0x1d, 0xac, 0x0e, 0xbb,
+	0x3b, 0x3d, 0x55, 0x4a, 0x3d, 0x64, 0x99, 0xa1, 0x19, 0xb1, 0x33, 0xc6, 0x23, 0xc2, 0x47, 0x17,
+	0xb8, 0x67, 0xee, 0xec, 0xb5, 0x12, 0xeb, 0x9d, 0x95, 0x14, 0xda, 0x01, 0x3b, 0x4a, 0xf2, 0x22,
+	0x49, 0xc3, 0x02, 0xaf, 0xc9, 0x8c, 0x0d, 0x95, 0xf1, 0xb4, 0x54, 0xeb, 0x22, 0x15, 0x27, 0x1e,
+	0xe5, 0x2c, 0x49, 0x23, 0x76, 0x86, 0xd7, 0xcd, 0x47, 0x39, 0x96, 0x5a, 0xfd, 0x28, 0x8a, 0x71,
+	0xdf, 0x82, 0x5d, 0xb9, 0x06, 0x79, 0xda, 0x55, 0xc2, 0x75, 0xa2, 0x96, 0xb8, 0x83, 0xa3, 0x39,
+	0xbd, 0xc5, 0x57, 0x3e, 0x74, 0x42, 0x96, 0x16, 0xe4, 0xbc, 0xc0, 0x96, 0x79, 0x9c, 0x27, 0x4a,
+	0xac, 0x8f, 0x53, 0x52, 0x6e, 0x0c, 0x8e, 0x8e, 0xa1, 0xbf, 0xa0, 0x9d, 0x87, 0x53, 0x32, 0x0b,
+	0x64, 0x35, 0xa7, 0xf4, 0x78, 0xa9, 0xa1, 0x4d, 0xb0, 0x92, 0x08, 0x5b, 0x03, 0x6b, 0xd8, 0x2c,
+	0x23, 0x56, 0x12, 0xa1, 0x7f, 0xa1, 0x7b, 0x92, 0xa4, 0x31, 0xe1, 0x19, 0x4f, 0x52, 0x61, 0xfe,
+	0x3a, 0x6c, 0x06, 0xdc, 0x0f, 0x16, 0xd8, 0x95, 0xbd, 0xd1, 0x3d, 0xd8, 0x48, 0x09, 0x89, 0xf2,
+	0xfd, 0x20, 0x9f, 0x06, 0x13, 0x4a, 0x84, 0x31, 0x2c, 0xa3, 0xad, 0x6e, 0x44, 0xd1, 0x16, 0xb4,
+	0x4e, 0x92, 0x34, 0xa0, 0x78, 0xd5, 0xc0, 0x94, 0x24, 0x9a, 0x33, 0x64, 0xb3, 0x8c, 0x92, 0x42,
+	0xb4, 0x53, 0x1d, 0xd6, 0x2a, 0xc2, 0xd0, 0x14, 0xce, 0xc2, 0x2d, 0x23, 0x2a, 0x15, 0xf4, 0x0f,
+	0x80, 0x6a, 0xb2, 0x67, 0xe7, 0x24, 0xc4, 0x6d, 0x23, 0x6e, 0xe8, 0xe2, 0x62, 0xc2, 0x45, 0x5e,
+	0xb0, 0x99, 0x6c, 0x93, 0x5e, 0x75, 0xe9, 0x4a, 0x43, 0x1e, 0x38, 0xf9, 0x9c, 0xaa, 0xc3, 0x95,
+	0x4d, 0x51, 0xbf, 0x53, 0x79, 0xe4, 0xc3, 0x1a, 0x41, 0xf7, 0xaf, 0x99, 0xdb, 0xb9, 0xc5, 0xdc,
+	0xa6, 0xad, 0xdd, 0x37, 0xd0, 0x29, 0xdb, 0xfd, 0x9a, 0x25, 0x1a, 0xbf, 0x60, 0x09, 0x7d, 0x73,
+	0xd6, 0x8d, 0x9b, 0x73, 0xf7, 0xc0, 0xd1, 0xad, 0xbe, 0xec, 0xc2, 0xee, 0x23, 0xb0, 0xab, 0xb1,
+	0xb2, 0x74, 0xee, 0x43, 0xe8, 0x1a, 0x03, 0x03, 0xfd, 0xa7, 0xa7, 0x96, 0x4a, 0xef, 0x79, 0x62,
+	0x16, 0x7b, 0xaf, 0x58, 0x44, 0x7e, 0x9c, 0x59, 0xee, 0x2e, 0x38, 0x7a, 0x6c, 0x2c, 0x95, 0xb8,
+	0x07, 0x8e, 0xee, 0xea, 0xa5, 0x37, 0xfc, 0x18, 0xa0, 0x6e, 0xf0, 0xdf, 0xb9, 0xaa, 0xaa, 0xd9,
+	0x97, 0xcd, 0x1d, 0x6d, 0x5e, 0x7e, 0xe9, 0xaf, 0x5c, 0x5e, 0xf5, 0x1b, 0x1f, 0xaf, 0xfa, 0x8d,
+	0xcf, 0x57, 0xfd, 0xc6, 0xfb, 0xaf, 0xfd, 0x95, 0xef, 0x03, 0x00, 0x10, 0x6b, 0x4f, 0x76, 0x35,
+	0x07, 0x00, 0x00,
 }
@@ -23,6 +23,8 @@ var sqlStatements = []string{
 	"SELECT AVG(CHAR_LENGTH(CAST(`title` AS CHAR))) as title_avg from orders WITH distributed=true, node_ct=2",
 	// this one tests a session_time that doesn't exist in table schema
 	"SELECT session_time FROM orders",
+	"SELECT user_id, price FROM orders ORDER BY price DESC, user_id LIMIT 10",
+	"SELECT DISTINCT user_id FROM orders ORDER BY user_id LIMIT 10 OFFSET 5",
 }
 
 // var sqlStatements = []string{
@@ -73,6 +75,37 @@ func TestSelectSerialization(t *testing.T) {
 	}
 }
 
+func TestOrderBySerialization(t *testing.T) {
+	for _, sqlStatement := range []string{
+		"SELECT user_id, price FROM orders ORDER BY price DESC, user_id LIMIT 10",
+		"SELECT DISTINCT user_id FROM orders ORDER BY user_id LIMIT 10 OFFSET 5",
+	} {
+		ctx := td.TestContext(sqlStatement)
+		p := selectPlan(t, ctx)
+		var ob *plan.OrderBy
+		for _, task := range p.Children() {
+			if o, ok := task.(*plan.OrderBy); ok {
+				ob = o
+			}
+		}
+		assert.Tf(t, ob != nil, "must have order by task for %s", sqlStatement)
+		pb, err := ob.ToPb()
+		assert.Tf(t, err == nil, "expected no error but got %v", err)
+		data, err := pb.Marshal()
+		assert.Tf(t, err == nil, "expected no error but got %v", err)
+		pb2 := &plan.PlanPb{}
+		err = pb2.Unmarshal(data)
+		assert.Tf(t, err == nil, "expected no error but got %v", err)
+		task, err := plan.SelectTaskFromTaskPb(pb2, ctx, p.Stmt)
+		assert.Tf(t, err == nil, "expected no error but got %v", err)
+		ob2, ok := task.(*plan.OrderBy)
+		assert.Tf(t, ok, "must be *plan.OrderBy but was %T", task)
+		assert.T(t, ob.Equal(ob2), "Should be equal plans")
+		assert.Equal(t, ob.Stmt.String(), ob2.Stmt.String())
+		assert.Equal(t, len(ob.Stmt.OrderBy), len(ob2.Stmt.OrderBy))
+	}
+}
+
 var (
 	_ = u.EMPTY
 
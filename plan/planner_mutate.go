@@ -2,9 +2,11 @@ package plan
 
 import (
 	"fmt"
+	"strings"
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
 
@@ -12,14 +14,110 @@ var (
 	_ = u.EMPTY
 )
 
+// WalkInto plans SELECT ... INTO table, the select without its INTO is
+// planned as its own statement, and its rows written to the table same
+// as for INSERT INTO table SELECT ...
 func (m *PlannerDefault) WalkInto(p *Into) error {
 	u.Debugf("VisitInto %+v", p.Stmt)
-	return ErrNotImplemented
+	sel := *p.Query
+	sel.Into = nil
+	var err error
+	p.Select, p.Columns, p.ColPos, err = m.walkSelectInto(p.Stmt.Table, nil, &sel)
+	if err != nil {
+		return err
+	}
+	return m.openUpsert(p.Stmt.Table, func(src schema.ConnUpsert) { p.Source = src })
+}
+
+// walkSelectInto plan sel whose rows are written to table, returns the
+// columns of table and the position in them of each column of sel.  With
+// names (insert into table (a, b) select ...) the columns of sel are
+// written to those in order, else to the columns of the same name or if
+// sel's columns aren't all columns of table, to all columns of table in
+// order.
+func (m *PlannerDefault) walkSelectInto(table string, names []string, sel *rel.SqlSelect) (Task, []string, []int, error) {
+	// the source of a table added since the schema was loaded refreshes it
+	if _, err := m.Ctx.Schema.Source(table); err != nil {
+		u.Warnf("%p no schema for %q err=%v", m.Ctx.Schema, table, err)
+		return nil, nil, nil, err
+	}
+	tbl, err := m.Ctx.Schema.Table(table)
+	if err != nil {
+		u.Warnf("%p no schema for %q err=%v", m.Ctx.Schema, table, err)
+		return nil, nil, nil, err
+	}
+	cols := tbl.Columns()
+	t, rcols, err := m.walkResult(m.subContext(sel), sel)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	colPos := func(name string) int {
+		for i, col := range cols {
+			if strings.EqualFold(col, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	pos := make([]int, len(rcols))
+	if len(names) > 0 {
+		if len(names) != len(rcols) {
+			return nil, nil, nil, fmt.Errorf("INSERT has %d columns but SELECT has %d", len(names), len(rcols))
+		}
+		for i, name := range names {
+			if pos[i] = colPos(name); pos[i] < 0 {
+				return nil, nil, nil, fmt.Errorf("no column %q in table %q", name, table)
+			}
+		}
+	} else {
+		for i, rc := range rcols {
+			if pos[i] = colPos(rc.As); pos[i] < 0 {
+				pos = nil
+				break
+			}
+		}
+		if pos == nil {
+			if len(rcols) != len(cols) {
+				return nil, nil, nil, fmt.Errorf("SELECT has %d columns but table %q has %d", len(rcols), table, len(cols))
+			}
+			pos = make([]int, len(rcols))
+			for i := range pos {
+				pos[i] = i
+			}
+		}
+	}
+	written := make(map[int]bool, len(pos))
+	for _, p := range pos {
+		if written[p] {
+			return nil, nil, nil, fmt.Errorf("column %q of table %q written more than once", cols[p], table)
+		}
+		written[p] = true
+	}
+	return t, cols, pos, nil
+}
+
+// openUpsert opens the upsert conn for table and hands it to set, again
+// each time the plan is re-run as the task writing to it closes it.
+func (m *PlannerDefault) openUpsert(table string, set func(schema.ConnUpsert)) error {
+	open := func() error {
+		src, err := upsertSource(m.Ctx, table)
+		if err != nil {
+			return err
+		}
+		set(src)
+		return nil
+	}
+	if err := open(); err != nil {
+		return err
+	}
+	m.Ctx.reopen = append(m.Ctx.reopen, open)
+	return nil
 }
 
 func upsertSource(ctx *Context, table string) (schema.ConnUpsert, error) {
 
-	conn, err := ctx.Schema.Open(table)
+	conn, err := ctx.Open(table)
 	if err != nil {
 		u.Warnf("%p no schema for %q err=%v", ctx.Schema, table, err)
 		return nil, err
@@ -45,58 +143,73 @@ func upsertSource(ctx *Context, table string) (schema.ConnUpsert, error) {
 
 func (m *PlannerDefault) WalkInsert(p *Insert) error {
 	u.Debugf("VisitInsert %s", p.Stmt)
-	src, err := upsertSource(m.Ctx, p.Stmt.Table)
-	if err != nil {
-		return err
+	if p.Stmt.Select != nil {
+		var err error
+		p.Select, p.Columns, p.ColPos, err = m.walkSelectInto(p.Stmt.Table, p.Stmt.ColumnNames(), p.Stmt.Select)
+		if err != nil {
+			return err
+		}
 	}
-	p.Source = src
-	return nil
+	return m.openUpsert(p.Stmt.Table, func(src schema.ConnUpsert) { p.Source = src })
 }
 
 func (m *PlannerDefault) WalkUpdate(p *Update) error {
 	u.Debugf("VisitUpdate %+v", p.Stmt)
-	src, err := upsertSource(m.Ctx, p.Stmt.Table)
-	if err != nil {
-		return err
+	if p.Stmt.Where != nil && len(p.Stmt.Where.SubQueryConditions()) > 0 {
+		u.Warnf("sub-query in update where not supported: %s", p.Stmt)
+		return ErrNotImplemented
 	}
-	p.Source = src
-	return nil
+	return m.openUpsert(p.Stmt.Table, func(src schema.ConnUpsert) { p.Source = src })
 }
 
 func (m *PlannerDefault) WalkUpsert(p *Upsert) error {
 	u.Debugf("VisitUpsert %+v", p.Stmt)
-	src, err := upsertSource(m.Ctx, p.Stmt.Table)
-	if err != nil {
+	return m.openUpsert(p.Stmt.Table, func(src schema.ConnUpsert) { p.Source = src })
+}
+
+func (m *PlannerDefault) WalkDelete(p *Delete) error {
+	u.Debugf("VisitDelete %+v", p.Stmt)
+	if p.Stmt.Where != nil && len(p.Stmt.Where.SubQueryConditions()) > 0 {
+		u.Warnf("sub-query in delete where not supported: %s", p.Stmt)
+		return ErrNotImplemented
+	}
+	open := func() error {
+		src, err := deleteSource(m.Ctx, p.Stmt.Table)
+		if err != nil {
+			return err
+		}
+		p.Source = src
+		return nil
+	}
+	if err := open(); err != nil {
 		return err
 	}
-	p.Source = src
+	m.Ctx.reopen = append(m.Ctx.reopen, open)
 	return nil
 }
 
-func (m *PlannerDefault) WalkDelete(p *Delete) error {
-	u.Debugf("VisitDelete %+v", p.Stmt)
-	conn, err := m.Ctx.Schema.Open(p.Stmt.Table)
+func deleteSource(ctx *Context, table string) (schema.ConnDeletion, error) {
+
+	conn, err := ctx.Open(table)
 	if err != nil {
-		u.Warnf("%p no schema for %q err=%v", m.Ctx.Schema, p.Stmt.Table, err)
-		return err
+		u.Warnf("%p no schema for %q err=%v", ctx.Schema, table, err)
+		return nil, err
 	}
 
 	mutatorSource, hasMutator := conn.(schema.ConnMutation)
 	if hasMutator {
-		mutator, err := mutatorSource.CreateMutator(m.Ctx)
+		mutator, err := mutatorSource.CreateMutator(ctx)
 		if err != nil {
-			u.Warnf("%p could not create mutator for %q err=%v", m.Ctx.Schema, p.Stmt.Table, err)
+			u.Warnf("%p could not create mutator for %q err=%v", ctx.Schema, table, err)
 			//return nil, err
 		} else {
-			p.Source = mutator
-			return nil
+			return mutator, nil
 		}
 	}
 
 	deleteDs, isDelete := conn.(schema.ConnDeletion)
 	if !isDelete {
-		return fmt.Errorf("%T does not implement required schema.Deletion for deletions", conn)
+		return nil, fmt.Errorf("%T does not implement required schema.Deletion for deletions", conn)
 	}
-	p.Source = deleteDs
-	return nil
+	return deleteDs, nil
 }
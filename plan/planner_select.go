@@ -2,10 +2,15 @@ package plan
 
 import (
 	"fmt"
+	"strings"
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 func (m *PlannerDefault) WalkPreparedStatement(p *PreparedStatement) error {
@@ -18,6 +23,7 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 	//u.Debugf("VisitSelect ctx:%p  %+v", p.Ctx, p.Stmt)
 
 	needsFinalProject := true
+	var scan *PartitionScan
 
 	if len(p.Stmt.From) == 0 {
 
@@ -33,17 +39,28 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 			return err
 		}
 		p.From = append(p.From, srcPlan)
-		p.Add(srcPlan)
 
-		err = m.Planner.WalkSourceSelect(srcPlan)
+		parts, err := m.partitionSources(srcPlan)
 		if err != nil {
-			u.Errorf("no source? %v", err)
 			return err
 		}
+		if len(parts) > 0 {
+			// each partition scanned (and filtered) in parallel
+			scan = NewPartitionScan(srcPlan, parts)
+			p.Add(scan)
+		} else {
+			p.Add(srcPlan)
+
+			err = m.Planner.WalkSourceSelect(srcPlan)
+			if err != nil {
+				u.Errorf("no source? %v", err)
+				return err
+			}
 
-		if srcPlan.Complete {
-			//u.Debugf("subselect visit final returning source plan: %+v", srcPlan)
-			goto finalProjection
+			if srcPlan.Complete {
+				//u.Debugf("subselect visit final returning source plan: %+v", srcPlan)
+				goto finalProjection
+			}
 		}
 
 	} else {
@@ -68,7 +85,7 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 			// now fold into previous task
 			if i != 0 {
-				from.Seekable = true
+				from.Seekable = joinSeekable(prevSource, srcPlan)
 				// fold this source into previous
 				curMergeTask := NewJoinMerge(prevTask, srcPlan, prevSource.Stmt, srcPlan.Stmt)
 				prevTask = curMergeTask
@@ -84,21 +101,50 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 	if p.Stmt.Where != nil {
 		switch {
-		case p.Stmt.Where.Source != nil:
+		case p.Stmt.Where.Expr != nil || p.Stmt.Where.Source != nil || len(p.Stmt.Where.SubQueries) > 0:
+			if p.Stmt.Where.Expr != nil && scan == nil {
+				// a single source is filtered by the where of the statement,
+				// don't re-apply what was pushed down to it
+				var pushed []expr.Node
+				if len(p.From) == 1 && p.From[0].Stmt.Source == p.Stmt {
+					pushed = p.From[0].Pushed
+				}
+				if w := residualWhere(p.Stmt, pushed); w != nil {
+					p.Add(w)
+				}
+			}
 			// SELECT id from article WHERE id in (select article_id from comments where comment_ct > 50);
-			u.Warnf("Found un-supported subquery: %#v", p.Stmt.Where)
-			return ErrNotImplemented
-		case p.Stmt.Where.Expr != nil:
-			p.Add(NewWhere(p.Stmt))
+			for _, cond := range p.Stmt.Where.SubQueryConditions() {
+				sj, err := m.walkSubQuery(p, cond)
+				if err != nil {
+					return err
+				}
+				p.Add(sj)
+			}
 		default:
 			u.Warnf("Found un-supported where type: %#v", p.Stmt.Where)
 			return fmt.Errorf("Unsupported Where Type")
 		}
 	}
 
+	if p.Stmt.IsWindowQuery() {
+		if p.Stmt.IsAggQuery() {
+			return fmt.Errorf("window functions are not supported with GROUP BY or aggregates")
+		}
+		p.Add(NewWindow(p.Stmt))
+	}
+
 	if p.Stmt.IsAggQuery() {
 		//u.Debugf("Adding aggregate/group by? %#v", m.Planner)
-		p.Add(NewGroupBy(p.Stmt))
+		if scan != nil && (p.Stmt.Where == nil || len(p.Stmt.Where.SubQueryConditions()) == 0) {
+			// each partition aggregates its own rows, then merged
+			for _, part := range scan.Parts {
+				part.Add(NewGroupByPartial(p.Stmt))
+			}
+			p.Add(NewGroupByFinal(p.Stmt))
+		} else {
+			p.Add(NewGroupBy(p.Stmt))
+		}
 		needsFinalProject = false
 	}
 
@@ -106,6 +152,10 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		p.Add(NewHaving(p.Stmt))
 	}
 
+	if len(p.Stmt.OrderBy) > 0 {
+		p.Add(NewOrderBy(p.Stmt))
+	}
+
 	//u.Debugf("needs projection? %v", needsFinalProject)
 	if needsFinalProject {
 		err := m.WalkProjectionFinal(p)
@@ -114,6 +164,11 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		}
 	}
 
+	if p.Stmt.Distinct {
+		// de-dupe the projected rows, this also does offset/limit
+		p.Add(NewDistinct(p.Stmt))
+	}
+
 finalProjection:
 	if m.Ctx.Projection == nil {
 		//u.Debugf("%p source plan Nil Projection?", p)
@@ -129,6 +184,328 @@ finalProjection:
 	return nil
 }
 
+// walkSubQuery plans an IN, EXISTS sub-query condition of the where clause
+// as a stand-alone select of its keys, which the outer rows are semi-joined to
+func (m *PlannerDefault) walkSubQuery(p *Select, cond *rel.SqlWhere) (*SemiJoin, error) {
+	sub, left, err := RewriteSubQuery(p.Stmt, cond, m.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx := m.subContext(sub)
+	t, err := WalkStmt(ctx, sub, NewPlanner(ctx))
+	if err != nil {
+		u.Warnf("could not plan sub-query %v  %s", err, sub)
+		return nil, err
+	}
+	return NewSemiJoin(p.Stmt, cond, left, t.(*Select)), nil
+}
+
+// subContext a new Context for planning stmt as its own statement, sharing
+// the schema, session of this request, it is re-run with this one
+func (m *PlannerDefault) subContext(stmt rel.SqlStatement) *Context {
+	ctx := m.stepContext(stmt)
+	m.Ctx.subs = append(m.Ctx.subs, ctx)
+	return ctx
+}
+
+// stepContext a new Context for planning stmt as its own statement while
+// running, ie the recursive step of a cte, sharing the schema, session
+func (m *PlannerDefault) stepContext(stmt rel.SqlStatement) *Context {
+	return &Context{
+		Context:        m.Ctx.Context,
+		SchemaName:     m.Ctx.SchemaName,
+		Raw:            stmt.String(),
+		Stmt:           stmt,
+		Session:        m.Ctx.Session,
+		Schema:         m.Ctx.Schema,
+		Funcs:          m.Ctx.Funcs,
+		Ctes:           m.Ctx.Ctes,
+		Memory:         m.Ctx.Memory,
+		Tx:             m.Ctx.Tx,
+		DisableRecover: m.Ctx.DisableRecover,
+	}
+}
+
+// WalkSetOperation plans each side of a UNION, INTERSECT, EXCEPT as its own
+// statement, both sides must have the same number of columns of compatible
+// types.  Columns are named by the left side.
+func (m *PlannerDefault) WalkSetOperation(p *SetOperation) error {
+
+	left, lcols, err := m.walkSetOperand(p.Stmt.Left)
+	if err != nil {
+		return err
+	}
+	right, rcols, err := m.walkSetOperand(p.Stmt.Right)
+	if err != nil {
+		return err
+	}
+	op := strings.ToUpper(p.Stmt.Op.String())
+	if len(lcols) != len(rcols) {
+		return fmt.Errorf("each SELECT of %s must have same number of columns: %d != %d", op, len(lcols), len(rcols))
+	}
+	for i, lc := range lcols {
+		if !SetOperationTypesMatch(lc.Type, rcols[i].Type) {
+			return fmt.Errorf("%s column %q types do not match: %s != %s", op, lc.As, lc.Type, rcols[i].Type)
+		}
+	}
+	p.Left, p.Right = left, right
+
+	proj := rel.NewProjection()
+	p.Result = rel.NewSqlSelect()
+	for i, col := range lcols {
+		vt := col.Type
+		if rt := rcols[i].Type; vt != rt {
+			vt = value.UnknownType
+			if isNumericType(col.Type) && isNumericType(rt) {
+				vt = value.NumberType
+			}
+		}
+		proj.AddColumnShort(col.As, vt)
+		p.Result.AddColumn(*rel.NewColumn(col.As))
+	}
+	p.Result.OrderBy = p.Stmt.OrderBy
+	p.Result.Limit = p.Stmt.Limit
+	p.Result.Offset = p.Stmt.Offset
+
+	if len(p.Stmt.OrderBy) > 0 {
+		p.Add(NewOrderBy(p.Result))
+	}
+	if m.Ctx.Projection == nil {
+		m.Ctx.Projection = NewProjectionStatic(proj)
+	}
+	return nil
+}
+
+// walkSetOperand plan one side of a set operation, returns the plan and
+// its result columns
+func (m *PlannerDefault) walkSetOperand(stmt rel.SqlStatement) (Task, []*rel.ResultColumn, error) {
+	return m.walkResult(m.subContext(stmt), stmt)
+}
+
+// walkResult plan stmt with its own ctx, returns the plan and its result
+// columns
+func (m *PlannerDefault) walkResult(ctx *Context, stmt rel.SqlStatement) (Task, []*rel.ResultColumn, error) {
+	t, err := WalkStmt(ctx, stmt, NewPlanner(ctx))
+	if err != nil {
+		u.Warnf("could not plan %v  %s", err, stmt)
+		return nil, nil, err
+	}
+	hasProj := ctx.Projection != nil && ctx.Projection.Proj != nil
+	var projCols []*rel.ResultColumn
+	if hasProj {
+		projCols = ctx.Projection.Proj.Columns
+	}
+	sel, ok := stmt.(*rel.SqlSelect)
+	if !ok || sel.Star {
+		if !hasProj {
+			return nil, nil, fmt.Errorf("no result columns for %s", stmt)
+		}
+		if !ok {
+			return t, projCols, nil
+		}
+		// projections type columns they can't infer as string, so
+		// types are those of the source fields
+		flds := stmtFields(ctx, sel, projCols)
+		cols := make([]*rel.ResultColumn, len(projCols))
+		for i, pc := range projCols {
+			col := *pc
+			col.Type = flds[i].Type
+			cols[i] = &col
+		}
+		return t, cols, nil
+	}
+	// projection of a join is in source order (or is in-process without
+	// types), rows are in select column order, types are inferred as the
+	// projection types columns it can't infer as string
+	cols := make([]*rel.ResultColumn, len(sel.Columns))
+	for i, col := range sel.Columns {
+		cols[i] = rel.NewResultColumn(col.As, i, col, exprType(ctx, sel, col.Expr))
+	}
+	return t, cols, nil
+}
+
+// WalkWith plans each common table expression of a WITH as its own
+// statement in order, each may read those before it by name, then the
+// statement using them.
+func (m *PlannerDefault) WalkWith(p *With) error {
+	ctes := m.Ctx.Ctes
+	for _, sc := range p.Stmt.Ctes {
+		cte, err := m.walkCte(p.Stmt, sc, ctes)
+		if err != nil {
+			return err
+		}
+		p.Ctes = append(p.Ctes, cte)
+		ctes = withCte(ctes, cte)
+	}
+
+	ctx := m.subContext(p.Stmt.Stmt)
+	ctx.Ctes = ctes
+	main, err := WalkStmt(ctx, p.Stmt.Stmt, NewPlanner(ctx))
+	if err != nil {
+		u.Warnf("could not plan %v  %s", err, p.Stmt.Stmt)
+		return err
+	}
+	p.Main = main
+	if m.Ctx.Projection == nil {
+		m.Ctx.Projection = ctx.Projection
+	}
+	return nil
+}
+
+// WalkExplain plans the explained statement with its own context, the
+// rows of the explain are one per task of its job.
+func (m *PlannerDefault) WalkExplain(p *Explain) error {
+	switch p.Stmt.Stmt.(type) {
+	case *rel.SqlSelect, *rel.SqlSetOperation, *rel.SqlWith:
+	default:
+		return fmt.Errorf("EXPLAIN not supported for %s", p.Stmt.Stmt.Keyword())
+	}
+	t, _, err := m.walkResult(m.subContext(p.Stmt.Stmt), p.Stmt.Stmt)
+	if err != nil {
+		return err
+	}
+	p.Plan = t
+
+	proj := rel.NewProjection()
+	proj.AddColumnShort("id", value.IntType)
+	proj.AddColumnShort("parent_id", value.IntType)
+	proj.AddColumnShort("operator", value.StringType)
+	proj.AddColumnShort("source", value.StringType)
+	proj.AddColumnShort("predicate", value.StringType)
+	proj.AddColumnShort("est_rows", value.IntType)
+	if p.Stmt.Analyze {
+		proj.AddColumnShort("rows_in", value.IntType)
+		proj.AddColumnShort("rows_out", value.IntType)
+		proj.AddColumnShort("elapsed_ms", value.NumberType)
+		proj.AddColumnShort("bytes", value.IntType)
+	}
+	m.Ctx.Projection = NewProjectionStatic(proj)
+	return nil
+}
+
+// walkCte plan a common table expression, its columns are named by
+// the column list of the WITH if it has one, else by its statement.
+func (m *PlannerDefault) walkCte(with *rel.SqlWith, sc *rel.SqlCte, ctes map[string]*Cte) (*Cte, error) {
+
+	anchor, step, all, err := recursiveCte(with, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := m.subContext(anchor)
+	ctx.Ctes = ctes
+	t, cols, err := m.walkResult(ctx, anchor)
+	if err != nil {
+		return nil, err
+	}
+	if len(sc.Cols) > 0 && len(sc.Cols) != len(cols) {
+		return nil, fmt.Errorf("WITH %s has %d columns but its SELECT has %d", sc.Name, len(sc.Cols), len(cols))
+	}
+	tbl := schema.NewTable(sc.Name, nil)
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.As
+		if len(sc.Cols) > 0 {
+			names[i] = sc.Cols[i]
+		}
+		tbl.AddFieldType(names[i], col.Type)
+	}
+	tbl.SetColumns(names)
+
+	cte := NewCte(sc, tbl)
+	cte.Anchor = t
+	if step == nil {
+		return cte, nil
+	}
+
+	// the step reads the rows of the previous step by our name
+	cte.All = all
+	cte.work = NewCte(sc, tbl)
+	cte.planStep = func() (Task, error) {
+		ctx := m.stepContext(step)
+		ctx.Ctes = withCte(ctes, cte.work)
+		t, stepCols, err := m.walkResult(ctx, step)
+		if err != nil {
+			return nil, err
+		}
+		if len(stepCols) != len(cols) {
+			return nil, fmt.Errorf("each SELECT of WITH RECURSIVE %s must have same number of columns: %d != %d",
+				sc.Name, len(cols), len(stepCols))
+		}
+		return t, nil
+	}
+	// plan it now for any errors, as it will be again when run
+	if _, err := cte.planStep(); err != nil {
+		return nil, err
+	}
+	return cte, nil
+}
+
+// recursiveCte splits the statement of a WITH RECURSIVE cte that reads
+// from its own name into the anchor SELECT and the recursive step
+//
+//    <anchor select> UNION [ALL] <step select FROM name ...>
+//
+// A cte which doesn't read from itself is all anchor.
+func recursiveCte(with *rel.SqlWith, sc *rel.SqlCte) (rel.SqlStatement, *rel.SqlSelect, bool, error) {
+	if !with.Recursive || !readsFrom(sc.Stmt, sc.Name) {
+		return sc.Stmt, nil, false, nil
+	}
+	so, ok := sc.Stmt.(*rel.SqlSetOperation)
+	if !ok || so.Op != lex.TokenUnion {
+		return nil, nil, false, fmt.Errorf("recursive WITH %s must be a UNION of an anchor SELECT and a recursive SELECT", sc.Name)
+	}
+	step, ok := so.Right.(*rel.SqlSelect)
+	if !ok || readsFrom(so.Left, sc.Name) {
+		return nil, nil, false, fmt.Errorf("only the last SELECT of recursive WITH %s may reference %s", sc.Name, sc.Name)
+	}
+	if len(so.OrderBy) > 0 || so.Limit > 0 || so.Offset > 0 {
+		return nil, nil, false, fmt.Errorf("ORDER BY, LIMIT are not supported for recursive WITH %s", sc.Name)
+	}
+	return so.Left, step, so.All, nil
+}
+
+// readsFrom does the statement read from source of given name
+func readsFrom(stmt rel.SqlStatement, name string) bool {
+	switch st := stmt.(type) {
+	case *rel.SqlSelect:
+		for _, from := range st.From {
+			if strings.EqualFold(from.SourceName(), name) {
+				return true
+			}
+			if from.SubQuery != nil && readsFrom(from.SubQuery, name) {
+				return true
+			}
+		}
+	case *rel.SqlSetOperation:
+		return readsFrom(st.Left, name) || readsFrom(st.Right, name)
+	}
+	return false
+}
+
+// SetOperationTypesMatch can columns of these types be combined by a
+// UNION, INTERSECT, EXCEPT, a type that isn't known matches any
+func SetOperationTypesMatch(a, b value.ValueType) bool {
+	isAny := func(t value.ValueType) bool {
+		switch t {
+		case value.NilType, value.UnknownType, value.ValueInterfaceType:
+			return true
+		}
+		return false
+	}
+	switch {
+	case a == b, isAny(a), isAny(b):
+		return true
+	case isNumericType(a) && isNumericType(b):
+		return true
+	}
+	return false
+}
+
+func isNumericType(vt value.ValueType) bool {
+	return vt == value.IntType || vt == value.NumberType
+}
+
 func (m *PlannerDefault) WalkProjectionFinal(p *Select) error {
 	// Add a Final Projection to choose the columns for results
 	//u.Debugf("projection: %p ctx.Projection: %p added  %s", p, m.Ctx.Projection, p.Stmt.String())
@@ -145,6 +522,61 @@ func (m *PlannerDefault) WalkProjectionFinal(p *Select) error {
 	return nil
 }
 
+// joinSeekable decides if the right hand source of a join can be read by
+// looking up rows by key (lookup join) instead of scanning it.  Requires
+//   - inner or left join, all right side rows are not needed
+//   - a single join expression, which on the right side is an identity
+//   - the type of that column is known, the keys are converted to it
+//   - a ConnSeeker source that can seek on that identity
+func joinSeekable(left, p *Source) bool {
+	from := p.Stmt
+	switch from.LeftOrRight {
+	case lex.TokenRight, lex.TokenFull:
+		return false
+	case lex.TokenLeft:
+	default:
+		if from.JoinType == lex.TokenOuter {
+			return false
+		}
+	}
+	if _, hasSourcePlanner := p.Conn.(SourcePlanner); hasSourcePlanner {
+		return false
+	}
+	seeker, ok := p.Conn.(schema.ConnSeeker)
+	if !ok {
+		return false
+	}
+	nodes, leftNodes := from.JoinNodes(), left.Stmt.JoinNodes()
+	if len(nodes) != 1 || len(leftNodes) != 1 {
+		return false
+	}
+	in, ok := nodes[0].(*expr.IdentityNode)
+	if !ok || p.Tbl == nil {
+		return false
+	}
+	_, col, _ := in.LeftRight()
+	fld, ok := p.Tbl.FieldMap[col]
+	if !ok {
+		return false
+	}
+	switch fld.Type {
+	case value.IntType, value.StringType, value.TimeType, value.ByteSliceType:
+		// types value.Cast converts keys to
+	default:
+		return false
+	}
+	eq := lex.Token{T: lex.TokenEqual, V: "="}
+	lookup := &rel.SqlSelect{
+		From:  []*rel.SqlSource{from},
+		Where: rel.NewSqlWhere(expr.NewBinaryNode(eq, in, leftNodes[0])),
+	}
+	if !seeker.CanSeek(lookup) {
+		return false
+	}
+	p.SeekType = fld.Type
+	return true
+}
+
 // Build Column Name to Position index for given *source* (from) used to interpret
 // positional []driver.Value args, mutate the *from* itself to hold this map
 func buildColIndex(colSchema schema.ConnColumns, p *Source) error {
@@ -211,6 +643,7 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 			if err := buildColIndex(schemaCols, p); err != nil {
 				return err
 			}
+			projectColumns(schemaCols, p)
 		} else {
 			return fmt.Errorf("%q Didn't implement schema.ConnColumns: %T", p.Stmt.SourceName(), p.Conn)
 		}
@@ -218,7 +651,11 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 		if p.Stmt.Source != nil && p.Stmt.Source.Where != nil {
 			switch {
 			case p.Stmt.Source.Where.Expr != nil:
-				p.Add(NewWhere(p.Stmt.Source))
+				if w := pushFilters(p); w != nil {
+					p.Add(w)
+				}
+			case p.Stmt.Source.Where.Source != nil || len(p.Stmt.Source.Where.SubQueries) > 0:
+				// sub-queries are semi-joined after the source in WalkSelect
 			default:
 				u.Warnf("Found un-supported where type: %#v", p.Stmt.Source)
 				return fmt.Errorf("Unsupported Where clause:  %q", p.Stmt)
@@ -242,6 +679,220 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 	return nil
 }
 
+// pushFilters push the conjuncts of the where of source p down to its
+// Conn if it can filter (schema.ConnFilter), returns the where of the
+// rest, nil if the Conn handles all of them
+func pushFilters(p *Source) *Where {
+	sel := p.Stmt.Source
+	filter, ok := p.Conn.(schema.ConnFilter)
+	if !ok {
+		return NewWhere(sel)
+	}
+	conds := splitAnd(sel.Where.Expr, nil)
+	handled := filter.PushFilters(conds)
+	for i, cond := range conds {
+		if i < len(handled) && handled[i] {
+			p.Pushed = append(p.Pushed, cond)
+		}
+	}
+	return residualWhere(sel, p.Pushed)
+}
+
+// partitionSources a Source per partition of source s, if its data source
+// is partitionable (schema.SourcePartitionable) into more than one, each
+// is walked as a source select of its own Conn.  Nil if not partitioned,
+// which sources in a transaction are not, as their partition Conns would
+// not be part of it.
+func (m *PlannerDefault) partitionSources(s *Source) ([]*Source, error) {
+	ds, ok := s.DataSource.(schema.SourcePartitionable)
+	if !ok || s.Stmt.Source == nil || len(s.Static) > 0 || m.Ctx.Tx != nil {
+		return nil, nil
+	}
+	var parts []*schema.Partition
+	if s.Tbl != nil && s.Tbl.Partition != nil {
+		parts = s.Tbl.Partition.Partitions
+	} else {
+		parts = ds.Partitions()
+	}
+	if len(parts) < 2 {
+		return nil, nil
+	}
+	sources := make([]*Source, 0, len(parts))
+	closeAll := func() {
+		for _, ps := range sources {
+			ps.Conn.Close()
+		}
+	}
+	for _, part := range parts {
+		conn, err := ds.PartitionSource(part)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		ps := &Source{
+			Stmt:         s.Stmt,
+			Proj:         s.Proj,
+			ctx:          s.ctx,
+			SourcePb:     &SourcePb{Final: s.Final},
+			PlanBase:     NewPlanBase(false),
+			DataSource:   s.DataSource,
+			SchemaSource: s.SchemaSource,
+			Tbl:          s.Tbl,
+			Conn:         conn,
+			Partition:    part,
+		}
+		sources = append(sources, ps)
+		if _, ok := conn.(SourcePlanner); ok {
+			// the source does its own planning, of all partitions
+			closeAll()
+			return nil, nil
+		}
+		if err := m.Planner.WalkSourceSelect(ps); err != nil {
+			closeAll()
+			return nil, err
+		}
+	}
+	for _, ps := range sources {
+		m.Ctx.reopen = append(m.Ctx.reopen, ps.reopenConn)
+	}
+	return sources, nil
+}
+
+// projectColumns tell the Conn of source p which of its columns the query
+// uses if it can read only those (schema.ConnProjection), the columns of
+// the projection, where, join, group by, having and order by
+func projectColumns(colSchema schema.ConnColumns, p *Source) {
+	proj, ok := p.Conn.(schema.ConnProjection)
+	if !ok || p.Stmt.Source == nil {
+		return
+	}
+	sel := p.Stmt.Source
+	if sel.Star {
+		return
+	}
+	var nodes []expr.Node
+	for _, cols := range []rel.Columns{sel.Columns, sel.GroupBy, sel.OrderBy} {
+		for _, col := range cols {
+			if col.Star {
+				return
+			}
+			nodes = append(nodes, col.Expr, col.Guard)
+			if col.Over != nil {
+				nodes = append(nodes, col.Over.PartitionBy...)
+				for _, oc := range col.Over.OrderBy {
+					nodes = append(nodes, oc.Expr)
+				}
+			}
+		}
+	}
+	nodes = append(nodes, sel.Having)
+	if sel.Where != nil {
+		nodes = append(nodes, sel.Where.Expr)
+		for _, sq := range sel.Where.SubQueryConditions() {
+			nodes = append(nodes, sq.Left)
+			if sq.Source != nil && sq.Source.Where != nil {
+				// may be correlated to columns of this source
+				nodes = append(nodes, sq.Source.Where.Expr)
+			}
+		}
+	}
+	nodes = append(nodes, p.Stmt.JoinNodes()...)
+
+	used := make(map[string]bool)
+	for _, n := range nodes {
+		usedColumns(n, colSchema.Columns(), used)
+	}
+	p.Needed = make([]string, 0, len(used))
+	for _, col := range colSchema.Columns() {
+		if used[col] {
+			p.Needed = append(p.Needed, col)
+		}
+	}
+	proj.ProjectColumns(p.Needed)
+}
+
+// usedColumns mark the columns, of cols, that expression n refers to
+func usedColumns(n expr.Node, cols []string, used map[string]bool) {
+	switch nt := n.(type) {
+	case *expr.IdentityNode:
+		_, right, _ := nt.LeftRight()
+		for _, col := range cols {
+			if strings.EqualFold(col, right) || strings.EqualFold(col, nt.Text) {
+				used[col] = true
+			}
+		}
+	case *expr.FuncNode:
+		if strings.ToLower(nt.Name) == "match" {
+			// match("prefix_") reads every column of the prefix
+			for _, arg := range nt.Args {
+				if sn, ok := arg.(*expr.StringNode); ok {
+					for _, col := range cols {
+						if strings.HasPrefix(col, sn.Text) {
+							used[col] = true
+						}
+					}
+				}
+			}
+		}
+		for _, arg := range nt.Args {
+			usedColumns(arg, cols, used)
+		}
+		for _, ob := range nt.OrderBy {
+			usedColumns(ob.Node, cols, used)
+		}
+	case *expr.BinaryNode:
+		for _, arg := range nt.Args {
+			usedColumns(arg, cols, used)
+		}
+	case *expr.TriNode:
+		for _, arg := range nt.Args {
+			usedColumns(arg, cols, used)
+		}
+	case *expr.ArrayNode:
+		for _, arg := range nt.Args {
+			usedColumns(arg, cols, used)
+		}
+	case *expr.UnaryNode:
+		usedColumns(nt.Arg, cols, used)
+	case *expr.CaseNode:
+		usedColumns(nt.Arg, cols, used)
+		for i, when := range nt.Whens {
+			usedColumns(when, cols, used)
+			usedColumns(nt.Thens[i], cols, used)
+		}
+		usedColumns(nt.Else, cols, used)
+	}
+}
+
+// residualWhere the where of the conjuncts of stmt that were not pushed
+// down to its source, nil if there are none
+func residualWhere(stmt *rel.SqlSelect, pushed []expr.Node) *Where {
+	if len(pushed) == 0 {
+		return NewWhere(stmt)
+	}
+	var residual expr.Node
+	for _, cond := range splitAnd(stmt.Where.Expr, nil) {
+		isPushed := false
+		for _, pn := range pushed {
+			if pn == cond {
+				isPushed = true
+				break
+			}
+		}
+		switch {
+		case isPushed:
+		case residual == nil:
+			residual = cond
+		default:
+			residual = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, residual, cond)
+		}
+	}
+	if residual == nil {
+		return nil
+	}
+	return NewWhereFilter(stmt, residual)
+}
+
 func (m *PlannerDefault) WalkProjectionSource(p *Source) error {
 	// Add a Non-Final Projection to choose the columns for results
 	//u.Debugf("exec.projection: %p job.proj: %p added  %s", p, m.Ctx.Projection, p.Stmt.String())
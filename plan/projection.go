@@ -7,7 +7,10 @@ import (
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/value"
 )
 
@@ -56,10 +59,14 @@ func (m *Projection) loadLiteralProjection(ctx *Context) error {
 			return fmt.Errorf("no column info? %#v", col.Expr)
 		}
 		//u.Debugf("col.As=%q  col %#v", col.As, col)
+		vt := exprType(ctx, m.P.Stmt, col.Expr)
+		if vt == value.UnknownType {
+			vt = value.StringType
+		}
 		if col.As == "" {
-			proj.AddColumnShort(col.Expr.String(), value.StringType)
+			proj.AddColumnShort(col.Expr.String(), vt)
 		} else {
-			proj.AddColumnShort(col.As, value.StringType)
+			proj.AddColumnShort(col.As, vt)
 		}
 	}
 
@@ -78,11 +85,12 @@ func (m *Projection) loadFinal(ctx *Context, isFinal bool) error {
 	//u.Debugf("creating plan.Projection final %s", m.Stmt.String())
 
 	m.Proj = rel.NewProjection()
+	m.Proj.Distinct = m.Stmt.Distinct
 
 	for _, from := range m.Stmt.From {
 
 		fromName := strings.ToLower(from.SourceName())
-		tbl, err := ctx.Schema.Table(fromName)
+		tbl, err := ctx.Table(fromName)
 		if err != nil {
 			u.Errorf("could not get table: %v", err)
 			return err
@@ -113,12 +121,16 @@ func (m *Projection) loadFinal(ctx *Context, isFinal bool) error {
 						//u.Debugf("projection: %p add col: %v %v", m.Proj, col.As, schemaCol.Type.String())
 					} else {
 						//u.Warnf("schema col not found: final?%v col: %#v", isFinal, col)
+						vt := exprType(ctx, m.Stmt, col.Expr)
+						if vt == value.UnknownType {
+							vt = value.StringType
+						}
 						if isFinal {
 							if col.InFinalProjection() {
-								m.Proj.AddColumnShort(col.As, value.StringType)
+								m.Proj.AddColumnShort(col.As, vt)
 							}
 						} else {
-							m.Proj.AddColumnShort(col.As, value.StringType)
+							m.Proj.AddColumnShort(col.As, vt)
 						}
 					}
 				}
@@ -185,3 +197,211 @@ func projectionForSourcePlan(plan *Source) error {
 	}
 	return nil
 }
+
+// ResultFields describe the columns of the rows of stmt as planned with ctx,
+// typed as inferred through the plan.  A column that is a field of a source
+// table is described by that field (type, length, nulls), an expression by
+// the type it evaluates to, a set operation by both of its operands.  Any
+// other statement (explain) by its projection.  Types that can't be inferred
+// are unknown.
+func ResultFields(ctx *Context, stmt rel.SqlStatement) []*schema.Field {
+	var cols []*rel.ResultColumn
+	if ctx.Projection != nil && ctx.Projection.Proj != nil {
+		cols = ctx.Projection.Proj.Columns
+	}
+	switch stmt.(type) {
+	case *rel.SqlSelect, *rel.SqlSetOperation:
+		return stmtFields(ctx, stmt, cols)
+	}
+	if cols == nil {
+		return nil
+	}
+	flds := make([]*schema.Field, len(cols))
+	for i, col := range cols {
+		flds[i] = schema.NewFieldBase(col.As, col.Type, 0, "")
+	}
+	return flds
+}
+
+// stmtFields the fields of the result columns of a select, or set
+// operation, the columns of a select * are those of its projection cols.
+// Nil if they can't be known.
+func stmtFields(ctx *Context, stmt rel.SqlStatement, cols []*rel.ResultColumn) []*schema.Field {
+	switch st := stmt.(type) {
+	case *rel.SqlSelect:
+		if st.Star {
+			if cols == nil {
+				return nil
+			}
+			flds := make([]*schema.Field, len(cols))
+			for i, col := range cols {
+				flds[i] = columnField(ctx, st, rel.NewColumn(col.As))
+			}
+			return flds
+		}
+		flds := make([]*schema.Field, len(st.Columns))
+		for i, col := range st.Columns {
+			flds[i] = columnField(ctx, st, col)
+		}
+		return flds
+	case *rel.SqlSetOperation:
+		left, right := stmtFields(ctx, st.Left, nil), stmtFields(ctx, st.Right, nil)
+		if len(left) != len(right) {
+			return nil
+		}
+		for i, lf := range left {
+			left[i] = setOperationField(lf, right[i])
+		}
+		return left
+	}
+	return nil
+}
+
+// setOperationField the field of a column of a set operation whose operands
+// have fields l and r
+func setOperationField(l, r *schema.Field) *schema.Field {
+	vt := value.UnknownType
+	switch {
+	case l.Type == r.Type:
+		vt = l.Type
+	case isNumericType(l.Type) && isNumericType(r.Type):
+		vt = value.NumberType
+	}
+	length := 0
+	if l.Length > 0 && r.Length > 0 {
+		length = int(l.Length)
+		if r.Length > l.Length {
+			length = int(r.Length)
+		}
+	}
+	fld := schema.NewFieldBase(l.Name, vt, length, "")
+	fld.NoNulls = l.NoNulls && r.NoNulls
+	return fld
+}
+
+// columnField the field describing column col of sel
+func columnField(ctx *Context, sel *rel.SqlSelect, col *rel.Column) *schema.Field {
+	if in, ok := col.Expr.(*expr.IdentityNode); ok {
+		if f := sourceField(ctx, sel, in); f != nil {
+			fld := schema.NewFieldBase(col.As, f.Type, int(f.Length), "")
+			fld.NoNulls = f.NoNulls
+			return fld
+		}
+	}
+	fld := schema.NewFieldBase(col.As, exprType(ctx, sel, col.Expr), 0, "")
+	if fn, ok := col.Expr.(*expr.FuncNode); ok && strings.ToLower(fn.Name) == "count" {
+		// count of no rows is 0, not null
+		fld.NoNulls = true
+	}
+	return fld
+}
+
+// sourceField the field of a source table of sel that identity in refers
+// to, nil if it isn't one
+func sourceField(ctx *Context, sel *rel.SqlSelect, in *expr.IdentityNode) *schema.Field {
+	if sel == nil {
+		return nil
+	}
+	left, right, hasLeft := in.LeftRight()
+	for _, from := range sel.From {
+		if hasLeft && !strings.EqualFold(left, from.Alias) && !strings.EqualFold(left, from.Name) {
+			continue
+		}
+		tbl, err := ctx.Table(strings.ToLower(from.SourceName()))
+		if err != nil || tbl == nil {
+			continue
+		}
+		if f, ok := tbl.FieldMap[right]; ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// exprType the value type node evaluates to for rows of the sources of
+// sel, unknown if it can't be inferred
+func exprType(ctx *Context, sel *rel.SqlSelect, node expr.Node) value.ValueType {
+	switch n := node.(type) {
+	case *expr.IdentityNode:
+		if n.IsBooleanIdentity() {
+			return value.BoolType
+		}
+		if f := sourceField(ctx, sel, n); f != nil {
+			return f.Type
+		}
+	case *expr.StringNode:
+		return value.StringType
+	case *expr.NumberNode:
+		if n.IsInt {
+			return value.IntType
+		}
+		return value.NumberType
+	case *expr.ValueNode:
+		if n.Value != nil {
+			return n.Value.Type()
+		}
+	case *expr.ParamNode:
+		if n.Value != nil {
+			return n.Value.Type()
+		}
+	case *expr.FuncNode:
+		switch strings.ToLower(n.Name) {
+		case "min", "max":
+			if len(n.Args) == 1 {
+				return exprType(ctx, sel, n.Args[0])
+			}
+		}
+		switch n.F.ReturnValueType {
+		case value.NilType, value.UnknownType, value.ValueInterfaceType:
+		default:
+			return n.F.ReturnValueType
+		}
+	case *expr.UnaryNode:
+		if n.Operator.T == lex.TokenMinus {
+			return exprType(ctx, sel, n.Arg)
+		}
+		return value.BoolType
+	case *expr.TriNode:
+		return value.BoolType
+	case *expr.CaseNode:
+		// the type all results agree on, a NULL result is of any type
+		vt := value.UnknownType
+		results := n.Thens
+		if n.Else != nil {
+			results = append(results[:len(results):len(results)], n.Else)
+		}
+		for _, result := range results {
+			if _, isNull := result.(*expr.NullNode); isNull {
+				continue
+			}
+			rt := exprType(ctx, sel, result)
+			if rt == value.UnknownType || (vt != value.UnknownType && rt != vt) {
+				return value.UnknownType
+			}
+			vt = rt
+		}
+		return vt
+	case *expr.BinaryNode:
+		switch n.Operator.T {
+		case lex.TokenPlus, lex.TokenMinus, lex.TokenMultiply:
+			if len(n.Args) != 2 {
+				break
+			}
+			lt, rt := exprType(ctx, sel, n.Args[0]), exprType(ctx, sel, n.Args[1])
+			switch {
+			case lt == value.IntType && rt == value.IntType:
+				return value.IntType
+			case isNumericType(lt) && isNumericType(rt):
+				return value.NumberType
+			}
+		case lex.TokenDivide:
+			return value.NumberType
+		case lex.TokenModulus:
+			return value.IntType
+		default:
+			// comparisons, AND, OR, LIKE, IN, etc
+			return value.BoolType
+		}
+	}
+	return value.UnknownType
+}
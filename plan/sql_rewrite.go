@@ -173,3 +173,140 @@ func RewriteDescribeAsSelect(stmt *rel.SqlDescribe, ctx *Context) (*rel.SqlSelec
 	s := &rel.SqlShow{ShowType: "columns", Identity: stmt.Identity, Raw: stmt.Raw}
 	return RewriteShowAsSelect(s, ctx)
 }
+
+// RewriteSubQuery rewrites an IN, EXISTS sub-query condition of a select into
+// a stand-alone select of the keys its rows are matched on, and the outer
+// row expressions those keys are matched against.  Equality predicates in
+// the sub-query where between its own and the outer sources (correlation)
+// are moved from the where into the keys.
+//
+//   SELECT name FROM users AS u WHERE EXISTS (
+//       SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id AND o.price > 10)
+//
+//   keys:  SELECT o.user_id FROM orders AS o WHERE o.price > 10
+//   left:  u.user_id
+//
+// For IN the sub-query column is the first key, and the IN expression the first
+// of the left expressions.  An un-correlated EXISTS has no keys at all.
+func RewriteSubQuery(stmt *rel.SqlSelect, cond *rel.SqlWhere, ctx *Context) (*rel.SqlSelect, []expr.Node, error) {
+
+	sub := cond.Source
+	left := make([]expr.Node, 0)
+	switch cond.Op {
+	case lex.TokenIN:
+		if sub.Star || len(sub.Columns) != 1 {
+			return nil, nil, fmt.Errorf("sub-query of IN must select a single column: %s", sub)
+		}
+		left = append(left, cond.Left)
+	case lex.TokenExists:
+	default:
+		u.Warnf("scalar sub-query not supported: %s", cond)
+		return nil, nil, ErrNotImplemented
+	}
+
+	outer := subQueryOuterAliases(stmt, sub)
+	if len(outer) == 0 || sub.Where == nil || sub.Where.Expr == nil {
+		return sub, left, nil
+	}
+
+	var where expr.Node
+	keys := make([]expr.Node, 0)
+	for _, n := range splitAnd(sub.Where.Expr, nil) {
+		if !refersTo(n, outer, false) {
+			if where == nil {
+				where = n
+			} else {
+				where = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, where, n)
+			}
+			continue
+		}
+		if bn, ok := n.(*expr.BinaryNode); ok && len(bn.Args) == 2 &&
+			(bn.Operator.T == lex.TokenEqual || bn.Operator.T == lex.TokenEqualEqual) {
+			l, r := bn.Args[0], bn.Args[1]
+			switch {
+			case refersTo(r, outer, true) && !refersTo(l, outer, false):
+				keys = append(keys, l)
+				left = append(left, r)
+				continue
+			case refersTo(l, outer, true) && !refersTo(r, outer, false):
+				keys = append(keys, r)
+				left = append(left, l)
+				continue
+			}
+		}
+		return nil, nil, fmt.Errorf("correlated sub-query only supports equality with outer columns: %s", n)
+	}
+	if len(keys) == 0 {
+		return sub, left, nil
+	}
+	if sub.IsAggQuery() || sub.Limit > 0 || sub.Offset > 0 {
+		return nil, nil, fmt.Errorf("correlated sub-query with aggregation or limit not supported: %s", sub)
+	}
+
+	rw := *sub
+	rw.Columns = make(rel.Columns, 0, len(keys)+1)
+	if cond.Op == lex.TokenIN {
+		rw.Columns = append(rw.Columns, sub.Columns[0])
+	}
+	for _, n := range keys {
+		rw.Columns = append(rw.Columns, &rel.Column{Expr: n})
+	}
+	rw.Where = nil
+	if where != nil || len(sub.Where.SubQueries) > 0 {
+		rw.Where = &rel.SqlWhere{Expr: where, SubQueries: sub.Where.SubQueries}
+	}
+	rw.OrderBy = nil
+	sel, err := rel.ParseSqlSelectResolver(rw.String(), ctx.Funcs)
+	if err != nil {
+		u.Warnf("could not rewrite sub-query %v  %s", err, rw.String())
+		return nil, nil, err
+	}
+	return sel, left, nil
+}
+
+// subQueryOuterAliases the (lower-cased) aliases of the outer statement sources
+// a sub-query may refer to, ie not shadowed by its own sources
+func subQueryOuterAliases(stmt, sub *rel.SqlSelect) map[string]struct{} {
+	aliases := make(map[string]struct{})
+	for _, from := range stmt.From {
+		aliases[strings.ToLower(from.Name)] = struct{}{}
+		if from.Alias != "" {
+			aliases[strings.ToLower(from.Alias)] = struct{}{}
+		}
+	}
+	for _, from := range sub.From {
+		delete(aliases, strings.ToLower(from.Name))
+		delete(aliases, strings.ToLower(from.Alias))
+	}
+	delete(aliases, "")
+	return aliases
+}
+
+// splitAnd the conjuncts of an AND'd expression
+func splitAnd(n expr.Node, nodes []expr.Node) []expr.Node {
+	if bn, ok := n.(*expr.BinaryNode); ok && len(bn.Args) == 2 {
+		switch bn.Operator.T {
+		case lex.TokenLogicAnd, lex.TokenAnd:
+			nodes = splitAnd(bn.Args[0], nodes)
+			return splitAnd(bn.Args[1], nodes)
+		}
+	}
+	return append(nodes, n)
+}
+
+// refersTo does this node have an identity qualified by one of the aliases,
+// or if all, are all (and at least one) of its identities qualified by them
+func refersTo(n expr.Node, aliases map[string]struct{}, all bool) bool {
+	idents := expr.FindAllIdentityField(n)
+	for _, ident := range idents {
+		left, _, hasLeft := expr.LeftRight(ident)
+		_, isAlias := aliases[strings.ToLower(left)]
+		isAlias = isAlias && hasLeft
+		if isAlias && !all {
+			return true
+		} else if !isAlias && all {
+			return false
+		}
+	}
+	return all && len(idents) > 0
+}
@@ -0,0 +1,99 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/schema"
+)
+
+// ErrTransactionDone is returned when a transaction that has been committed
+// or rolled back is used again
+var ErrTransactionDone = fmt.Errorf("QLBridge.plan: transaction has already been committed or rolled back")
+
+// Transaction groups the statements of a sql driver Tx.  The first time a
+// statement of it opens a table a transaction of its conn is begun, the
+// statements get conns of that transaction so they see each others writes,
+// and Commit/Rollback apply or discard the writes of all of them.  Sources
+// whose conns do not implement schema.ConnTransaction are not part of it,
+// their writes apply immediately.
+type Transaction struct {
+	mu    sync.Mutex
+	txs   map[string]schema.ConnTx
+	order []string // tables in the order they were begun
+	done  bool
+}
+
+// NewTransaction create an empty transaction, tables are begun as they are opened
+func NewTransaction() *Transaction {
+	return &Transaction{txs: make(map[string]schema.ConnTx)}
+}
+
+// Open a conn of table from ds for a statement of this transaction
+func (m *Transaction) Open(ds schema.Source, table string) (schema.Conn, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done {
+		return nil, ErrTransactionDone
+	}
+	key := strings.ToLower(table)
+	if tx, ok := m.txs[key]; ok {
+		return tx.Conn()
+	}
+	conn, err := ds.Open(table)
+	if err != nil {
+		return nil, err
+	}
+	txConn, ok := conn.(schema.ConnTransaction)
+	if !ok {
+		return conn, nil
+	}
+	tx, err := txConn.Begin()
+	conn.Close()
+	if err != nil {
+		return nil, err
+	}
+	m.txs[key] = tx
+	m.order = append(m.order, key)
+	return tx.Conn()
+}
+
+// Commit the writes of each table, if one fails the ones not yet
+// committed are rolled back
+func (m *Transaction) Commit() error {
+	return m.end(true)
+}
+
+// Rollback discard the writes of each table
+func (m *Transaction) Rollback() error {
+	return m.end(false)
+}
+
+func (m *Transaction) end(commit bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done {
+		return ErrTransactionDone
+	}
+	m.done = true
+	var firstErr error
+	for _, key := range m.order {
+		tx := m.txs[key]
+		var err error
+		if commit && firstErr == nil {
+			err = tx.Commit()
+		} else {
+			err = tx.Rollback()
+		}
+		if err != nil {
+			u.Warnf("could not end transaction of %q err=%v", key, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
@@ -2,6 +2,7 @@ package rel
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -89,7 +90,9 @@ func (m *Sqlbridge) parse() (SqlStatement, error) {
 	case lex.TokenPrepare:
 		return m.parsePrepare()
 	case lex.TokenSelect:
-		return m.parseSqlSelect()
+		return m.parseSelectOrSetOperation(lex.TokenEOF, lex.TokenEOS)
+	case lex.TokenWith:
+		return m.parseSqlWith()
 	case lex.TokenInsert, lex.TokenReplace:
 		return m.parseSqlInsert()
 	case lex.TokenUpdate:
@@ -167,7 +170,7 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 
 	// SPECIAL END CASE for simple selects
 	// SELECT last_insert_id();
-	if m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenEOF {
+	if m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenEOF || m.Cur().T == lex.TokenRightParenthesis || isSetOperation(m.Cur().T) {
 		// valid end
 		return req, nil
 	}
@@ -222,7 +225,9 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 		return nil, err
 	}
 
-	if m.Cur().T == lex.TokenEOF || m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenRightParenthesis {
+	switch m.Cur().T {
+	case lex.TokenEOF, lex.TokenEOS, lex.TokenRightParenthesis,
+		lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 
 		if err := req.Finalize(); err != nil {
 			u.Errorf("Could not finalize: %v", err)
@@ -237,6 +242,201 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 	return nil, fmt.Errorf("Did not complete parsing input: %v", m.LexTokenPager.Cur().V)
 }
 
+func isSetOperation(t lex.TokenType) bool {
+	switch t {
+	case lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
+		return true
+	}
+	return false
+}
+
+// parseSelectOrSetOperation a select, or set operation of selects which
+// must be followed by one of end
+func (m *Sqlbridge) parseSelectOrSetOperation(end ...lex.TokenType) (SqlStatement, error) {
+	sel, err := m.parseSqlSelect()
+	if err != nil {
+		return nil, err
+	}
+	if isSetOperation(m.Cur().T) {
+		return m.parseSqlSetOperation(sel, end...)
+	}
+	return sel, nil
+}
+
+// First keyword was WITH, so parse the common table expressions, then
+// the statement using them
+//
+//    WITH [RECURSIVE] name [(col, ...)] AS (SELECT ...) [, ...] SELECT ...
+//
+func (m *Sqlbridge) parseSqlWith() (*SqlWith, error) {
+
+	req := NewSqlWith(false, nil, nil)
+	req.Raw = m.l.RawInput()
+	m.Next() // Consume WITH
+
+	if m.Cur().T == lex.TokenRecursive {
+		req.Recursive = true
+		m.Next()
+	}
+
+	for {
+		if m.Cur().T != lex.TokenIdentity {
+			return nil, fmt.Errorf("expected name for WITH but got: %v", m.Cur().V)
+		}
+		cte := &SqlCte{Name: m.Cur().V}
+		if req.Cte(cte.Name) != nil {
+			return nil, fmt.Errorf("WITH name %q specified more than once", cte.Name)
+		}
+		m.Next()
+
+		// optional column names
+		if m.Cur().T == lex.TokenLeftParenthesis {
+			m.Next()
+			for m.Cur().T == lex.TokenIdentity {
+				cte.Cols = append(cte.Cols, m.Cur().V)
+				m.Next()
+				if m.Cur().T == lex.TokenComma {
+					m.Next()
+				}
+			}
+			if m.Cur().T != lex.TokenRightParenthesis || len(cte.Cols) == 0 {
+				return nil, fmt.Errorf("expected column names for WITH %s but got: %v", cte.Name, m.Cur().V)
+			}
+			m.Next()
+		}
+
+		if m.Cur().T != lex.TokenAs {
+			return nil, fmt.Errorf("expected AS for WITH %s but got: %v", cte.Name, m.Cur().V)
+		}
+		m.Next()
+		if m.Cur().T != lex.TokenLeftParenthesis {
+			return nil, fmt.Errorf("expected ( after AS for WITH %s but got: %v", cte.Name, m.Cur().V)
+		}
+		m.Next()
+		if m.Cur().T != lex.TokenSelect {
+			return nil, fmt.Errorf("expected SELECT for WITH %s but got: %v", cte.Name, m.Cur().V)
+		}
+		stmt, err := m.parseSelectOrSetOperation(lex.TokenRightParenthesis)
+		if err != nil {
+			return nil, err
+		}
+		if m.Cur().T != lex.TokenRightParenthesis {
+			return nil, fmt.Errorf("expected ) to end WITH %s but got: %v", cte.Name, m.Cur().V)
+		}
+		m.Next()
+		cte.Stmt = stmt
+		req.Ctes = append(req.Ctes, cte)
+
+		if m.Cur().T != lex.TokenComma {
+			break
+		}
+		m.Next()
+	}
+
+	if m.Cur().T != lex.TokenSelect {
+		return nil, fmt.Errorf("expected SELECT after WITH but got: %v", m.Cur().V)
+	}
+	stmt, err := m.parseSelectOrSetOperation(lex.TokenEOF, lex.TokenEOS)
+	if err != nil {
+		return nil, err
+	}
+	req.Stmt = stmt
+
+	// the raw sql of each statement is its part of the WITH
+	for _, cte := range req.Ctes {
+		setRawSql(cte.Stmt)
+	}
+	setRawSql(req.Stmt)
+	return req, nil
+}
+
+func setRawSql(stmt SqlStatement) {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		st.Raw = st.String()
+	case *SqlSetOperation:
+		st.Raw = st.String()
+	}
+}
+
+// First select was followed by UNION, INTERSECT, EXCEPT so parse the
+// remaining selects and combine them, INTERSECT binds tighter than
+// UNION, EXCEPT which are left-associative.  The ORDER BY, LIMIT of the
+// last select apply to the combined rows.
+//
+//    SELECT ... UNION [ALL | DISTINCT] SELECT ... [ORDER BY ...] [LIMIT ...]
+//
+func (m *Sqlbridge) parseSqlSetOperation(first *SqlSelect, end ...lex.TokenType) (*SqlSetOperation, error) {
+
+	sels := []*SqlSelect{first}
+	ops := make([]*SqlSetOperation, 0)
+
+	for isSetOperation(m.Cur().T) {
+		op := NewSqlSetOperation(m.Cur().T, false, nil, nil)
+		m.Next()
+		switch m.Cur().T {
+		case lex.TokenAll:
+			op.All = true
+			m.Next()
+		case lex.TokenDistinct:
+			m.Next()
+		}
+		if m.Cur().T != lex.TokenSelect {
+			return nil, fmt.Errorf("expected SELECT after %s but got %v", op.Op, m.Cur().V)
+		}
+		sel, err := m.parseSqlSelect()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+		ops = append(ops, op)
+	}
+
+	isEnd := false
+	for _, t := range end {
+		isEnd = isEnd || m.Cur().T == t
+	}
+	if !isEnd {
+		return nil, fmt.Errorf("Did not complete parsing input: %v", m.Cur().V)
+	}
+
+	for _, sel := range sels[:len(sels)-1] {
+		if len(sel.OrderBy) > 0 || sel.Limit > 0 || sel.Offset > 0 {
+			return nil, fmt.Errorf("ORDER BY, LIMIT only allowed after the last SELECT of %s", ops[0].Op)
+		}
+	}
+
+	// INTERSECT first, each term is a select or chain of intersects
+	terms := []SqlStatement{first}
+	termOps := make([]*SqlSetOperation, 0, len(ops))
+	for i, op := range ops {
+		if op.Op == lex.TokenIntersect {
+			op.Left, op.Right = terms[len(terms)-1], sels[i+1]
+			terms[len(terms)-1] = op
+			continue
+		}
+		termOps = append(termOps, op)
+		terms = append(terms, sels[i+1])
+	}
+	stmt := terms[0]
+	for i, op := range termOps {
+		op.Left, op.Right = stmt, terms[i+1]
+		stmt = op
+	}
+
+	req := stmt.(*SqlSetOperation)
+	req.Raw = m.l.RawInput()
+
+	// ORDER BY, LIMIT of last select are for the combined rows
+	last := sels[len(sels)-1]
+	req.OrderBy, req.Limit, req.Offset = last.OrderBy, last.Limit, last.Offset
+	last.OrderBy, last.Limit, last.Offset = nil, 0, 0
+	for _, sel := range sels {
+		sel.Raw = sel.String()
+	}
+	return req, nil
+}
+
 // First keyword was INSERT, REPLACE
 func (m *Sqlbridge) parseSqlInsert() (*SqlInsert, error) {
 
@@ -260,20 +460,21 @@ func (m *Sqlbridge) parseSqlInsert() (*SqlInsert, error) {
 		return nil, fmt.Errorf("expected table name but got : %v", m.Cur().V)
 	}
 
-	// list of fields
-	cols, err := m.parseFieldList()
-	if err != nil {
-		u.Error(err)
-		return nil, err
+	// list of fields, optional for insert into mytable select ...
+	if m.Cur().T != lex.TokenSelect {
+		cols, err := m.parseFieldList()
+		if err != nil {
+			u.Error(err)
+			return nil, err
+		}
+		req.Columns = cols
+		m.Next() // left paren starts lisf of values
 	}
-	req.Columns = cols
 
-	m.Next() // left paren starts lisf of values
 	switch m.Cur().T {
 	case lex.TokenValues:
 		m.Next() // Consume Values keyword
 	case lex.TokenSelect:
-		u.Infof("What is cur?%v", m.Cur())
 		sel, err := m.parseSqlSelect()
 		if err != nil {
 			return nil, err
@@ -471,20 +672,27 @@ func (m *Sqlbridge) parseDescribe() (SqlStatement, error) {
 	req.Tok = m.Cur()
 	m.Next() // Consume Describe
 
+	// the lexer doesn't lex the explained statement (SELECT isn't an
+	// expression) so look at the raw text after the keyword
+	// TODO:  make the lexer handle this
+	sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
+	nextWord := ""
+	if words := strings.Fields(sqlText); len(words) > 0 {
+		nextWord = words[0]
+	}
+
 	//u.Debugf("token:  %v", m.Cur())
-	switch nextWord := strings.ToLower(m.Cur().V); nextWord {
-	case "select":
-		// TODO:  make the lexer handle this
-		sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
+	switch strings.ToLower(nextWord) {
+	case "select", "with":
 		sqlSel, err := ParseSql(sqlText)
 		if err != nil {
 			return nil, err
 		}
 		req.Stmt = sqlSel
 		return req, nil
-	case "extended":
-		sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
-		sqlText = strings.Replace(sqlText, m.Cur().V, "", 1)
+	case "extended", "analyze":
+		req.Analyze = strings.ToLower(nextWord) == "analyze"
+		sqlText = strings.Replace(sqlText, nextWord, "", 1)
 		sqlSel, err := ParseSql(sqlText)
 		if err != nil {
 			return nil, err
@@ -670,6 +878,15 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, buildVm bool, stmt Co
 				}
 			}
 			col.Agg = expr.IsAgg(funcName)
+			if m.Cur().T == lex.TokenOver {
+				over, err := parseWindow(m, fr, buildVm)
+				if err != nil {
+					return err
+				}
+				// window funcs are evaluated per row, not aggregated
+				col.Over = over
+				col.Agg = false
+			}
 
 			if m.Cur().T != lex.TokenAs {
 				switch n := col.Expr.(type) {
@@ -693,6 +910,10 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, buildVm bool, stmt Co
 						u.Errorf("could not find as name: %#v", n)
 					}
 				}
+				if col.Over != nil {
+					// name of func, not of its args  ie  lag(price)
+					col.As = funcName
+				}
 			} else {
 				switch n := col.Expr.(type) {
 				case *expr.FuncNode:
@@ -718,6 +939,18 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, buildVm bool, stmt Co
 				return err
 			}
 			col.Expr = tree.Root
+		case lex.TokenCase, lex.TokenParam:
+			// CASE WHEN x THEN y END, or a placeholder ? :name, named by
+			// its expression if no AS
+			col = &Column{}
+			tree := expr.NewTreeFuncs(m, fr)
+			if err := tree.BuildTree(buildVm); err != nil {
+				u.Errorf("could not parse: %v", err)
+				return err
+			}
+			col.Expr = tree.Root
+			col.As = col.Expr.String()
+			col.Agg = expr.HasAggFunc(col.Expr)
 		}
 		//u.Debugf("after colstart?:   %v  ", m.Cur())
 
@@ -734,7 +967,8 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, buildVm bool, stmt Co
 				continue
 			}
 			return fmt.Errorf("expected identity but got: %v", m.Cur().String())
-		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			// This indicates we have come to the End of the columns
 			stmt.AddColumn(*col)
 			//u.Debugf("Ending column ")
@@ -757,6 +991,11 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, buildVm bool, stmt Co
 			m.Next()
 			col.Comment = m.Cur().V
 		case lex.TokenRightParenthesis:
+			if col != nil && col.Expr != nil {
+				// end of columns of a nested select  WITH a AS (SELECT 1)
+				stmt.AddColumn(*col)
+				return nil
+			}
 			// loop on my friend
 		case lex.TokenComma:
 			//u.Infof("? %#v", stmt)
@@ -772,6 +1011,148 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, buildVm bool, stmt Co
 	return nil
 }
 
+// parseWindow parses the OVER clause of a window function column
+//
+//    OVER (PARTITION BY a, b ORDER BY c DESC ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)
+//
+func parseWindow(m expr.TokenPager, fr expr.FuncResolver, buildVm bool) (*SqlWindow, error) {
+	m.Next() // Consume OVER
+	if m.Cur().T != lex.TokenLeftParenthesis {
+		return nil, fmt.Errorf("expected ( after OVER but got: %v", m.Cur())
+	}
+	m.Next()
+
+	w := &SqlWindow{}
+	if m.Cur().T == lex.TokenPartitionBy {
+		for {
+			m.Next() // Consume PARTITION BY, or comma
+			tree := expr.NewTreeFuncs(m, fr)
+			if err := tree.BuildTree(buildVm); err != nil {
+				return nil, err
+			}
+			w.PartitionBy = append(w.PartitionBy, tree.Root)
+			if m.Cur().T != lex.TokenComma {
+				break
+			}
+		}
+	}
+	if m.Cur().T == lex.TokenOrderBy {
+		for {
+			m.Next() // Consume ORDER BY, or comma
+			col := NewColumnFromToken(m.Cur())
+			tree := expr.NewTreeFuncs(m, fr)
+			if err := tree.BuildTree(buildVm); err != nil {
+				return nil, err
+			}
+			col.Expr = tree.Root
+			switch m.Cur().T {
+			case lex.TokenAsc, lex.TokenDesc:
+				col.Order = strings.ToUpper(m.Cur().V)
+				m.Next()
+			}
+			w.OrderBy = append(w.OrderBy, col)
+			if m.Cur().T != lex.TokenComma {
+				break
+			}
+		}
+	}
+	switch m.Cur().T {
+	case lex.TokenRows, lex.TokenRange:
+		frame, err := parseWindowFrame(m)
+		if err != nil {
+			return nil, err
+		}
+		w.Frame = frame
+	}
+	if m.Cur().T != lex.TokenRightParenthesis {
+		return nil, fmt.Errorf("expected ) to end OVER but got: %v", m.Cur())
+	}
+	m.Next()
+	return w, nil
+}
+
+// parseWindowFrame parses the frame of a window
+//
+//    ROWS BETWEEN UNBOUNDED PRECEDING AND 1 FOLLOWING
+//    RANGE UNBOUNDED PRECEDING
+//
+func parseWindowFrame(m expr.TokenPager) (*SqlWindowFrame, error) {
+	frame := &SqlWindowFrame{Range: m.Cur().T == lex.TokenRange}
+	m.Next() // Consume ROWS, RANGE
+	between := m.Cur().T == lex.TokenBetween
+	if between {
+		m.Next()
+	}
+	var err error
+	if frame.Start, err = parseWindowBound(m); err != nil {
+		return nil, err
+	}
+	frame.End = SqlWindowBound{Type: lex.TokenCurrentRow}
+	if between {
+		if m.Cur().T != lex.TokenLogicAnd {
+			return nil, fmt.Errorf("expected AND in window frame but got: %v", m.Cur())
+		}
+		m.Next()
+		if frame.End, err = parseWindowBound(m); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case frame.Start.Unbounded && frame.Start.Type == lex.TokenFollowing:
+		return nil, fmt.Errorf("window frame can not start at %s", frame.Start)
+	case frame.End.Unbounded && frame.End.Type == lex.TokenPreceding:
+		return nil, fmt.Errorf("window frame can not end at %s", frame.End)
+	case windowBoundPos(frame.Start) > windowBoundPos(frame.End):
+		return nil, fmt.Errorf("window frame start %s is after end %s", frame.Start, frame.End)
+	}
+	return frame, nil
+}
+
+// parseWindowBound parses one bound of a window frame
+func parseWindowBound(m expr.TokenPager) (SqlWindowBound, error) {
+	b := SqlWindowBound{}
+	switch m.Cur().T {
+	case lex.TokenCurrentRow:
+		m.Next()
+		b.Type = lex.TokenCurrentRow
+		return b, nil
+	case lex.TokenUnbounded:
+		b.Unbounded = true
+	case lex.TokenInteger:
+		n, err := strconv.Atoi(m.Cur().V)
+		if err != nil || n < 0 {
+			return b, fmt.Errorf("expected window frame offset but got: %v", m.Cur())
+		}
+		b.Offset = n
+	default:
+		return b, fmt.Errorf("expected window frame bound but got: %v", m.Cur())
+	}
+	m.Next()
+	switch m.Cur().T {
+	case lex.TokenPreceding, lex.TokenFollowing:
+		b.Type = m.Cur().T
+	default:
+		return b, fmt.Errorf("expected PRECEDING or FOLLOWING but got: %v", m.Cur())
+	}
+	m.Next()
+	return b, nil
+}
+
+// windowBoundPos orders the bounds of a frame relative to current row
+func windowBoundPos(b SqlWindowBound) int {
+	switch {
+	case b.Type == lex.TokenCurrentRow:
+		return 0
+	case b.Unbounded && b.Type == lex.TokenPreceding:
+		return math.MinInt32
+	case b.Unbounded:
+		return math.MaxInt32
+	case b.Type == lex.TokenPreceding:
+		return -b.Offset
+	}
+	return b.Offset
+}
+
 func (m *Sqlbridge) parseFieldList() (Columns, error) {
 
 	if m.Cur().T != lex.TokenLeftParenthesis {
@@ -811,42 +1192,86 @@ func (m *Sqlbridge) parseFieldList() (Columns, error) {
 func (m *Sqlbridge) parseUpdateList() (map[string]*ValueColumn, error) {
 
 	cols := make(map[string]*ValueColumn)
-	lastColName := ""
 	for {
 
-		//u.Debugf("col:%v    cur:%v", lastColName, m.Cur().String())
+		//u.Debugf("cur:%v", m.Cur().String())
 		switch m.Cur().T {
 		case lex.TokenWhere, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
 			return cols, nil
-		case lex.TokenValue:
-			cols[lastColName] = &ValueColumn{Value: value.NewStringValue(m.Cur().V)}
-		case lex.TokenInteger:
-			iv, _ := strconv.ParseInt(m.Cur().V, 10, 64)
-			cols[lastColName] = &ValueColumn{Value: value.NewIntValue(iv)}
-		case lex.TokenComma, lex.TokenEqual:
-			// don't need to do anything
+		case lex.TokenComma:
+			m.Next()
 		case lex.TokenIdentity:
-			// TODO:  this is a bug in lexer
-			lv := m.Cur().V
-			if bv, err := strconv.ParseBool(lv); err == nil {
-				cols[lastColName] = &ValueColumn{Value: value.NewBoolValue(bv)}
-			} else {
-				lastColName = m.Cur().V
+			colName := m.Cur().V
+			m.Next()
+			if m.Cur().T != lex.TokenEqual {
+				return nil, fmt.Errorf("expected = after %s but got: %v", colName, m.Cur().String())
 			}
-		case lex.TokenUdfExpr:
-			tree := expr.NewTreeFuncs(m.SqlTokenPager, m.funcs)
-			if err := m.parseNode(tree); err != nil {
-				u.Errorf("could not parse: %v", err)
+			m.Next() // Consume =
+			col, err := m.parseUpdateValue()
+			if err != nil {
 				return nil, err
 			}
-			cols[lastColName] = &ValueColumn{Expr: tree.Root}
+			cols[colName] = col
 		default:
 			u.Warnf("don't know how to handle ?  %v", m.Cur())
 			return nil, fmt.Errorf("expected column but got: %v", m.Cur().String())
 		}
-		m.Next()
 	}
-	panic("unreachable")
+}
+
+// parseUpdateValue the value of SET col = value, a single literal is a
+// value, anything else an expression evaluated against each row ie
+// SET x = x + 1, y = lower(y)
+func (m *Sqlbridge) parseUpdateValue() (*ValueColumn, error) {
+	switch m.Peek().T {
+	case lex.TokenComma, lex.TokenWhere, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		var col *ValueColumn
+		switch m.Cur().T {
+		case lex.TokenValue:
+			col = &ValueColumn{Value: value.NewStringValue(m.Cur().V)}
+		case lex.TokenInteger:
+			iv, err := strconv.ParseInt(m.Cur().V, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			col = &ValueColumn{Value: value.NewIntValue(iv)}
+		case lex.TokenFloat:
+			fv, err := strconv.ParseFloat(m.Cur().V, 64)
+			if err != nil {
+				return nil, err
+			}
+			col = &ValueColumn{Value: value.NewNumberValue(fv)}
+		case lex.TokenBool:
+			bv, err := strconv.ParseBool(m.Cur().V)
+			if err != nil {
+				return nil, err
+			}
+			col = &ValueColumn{Value: value.NewBoolValue(bv)}
+		case lex.TokenIdentity:
+			// TODO:  this is a bug in lexer, 'abc' and true are identities
+			if m.Cur().Quote == '\'' {
+				col = &ValueColumn{Value: value.NewStringValue(m.Cur().V)}
+			} else if bv, err := strconv.ParseBool(m.Cur().V); err == nil {
+				col = &ValueColumn{Value: value.NewBoolValue(bv)}
+			}
+		case lex.TokenParam:
+			pn, err := expr.NewParamNode(m.Cur())
+			if err != nil {
+				return nil, err
+			}
+			col = &ValueColumn{Expr: pn}
+		}
+		if col != nil {
+			m.Next()
+			return col, nil
+		}
+	}
+	tree := expr.NewTreeFuncs(m.SqlTokenPager, m.funcs)
+	if err := m.parseNode(tree); err != nil {
+		u.Errorf("could not parse: %v", err)
+		return nil, err
+	}
+	return &ValueColumn{Expr: tree.Root}, nil
 }
 
 func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
@@ -870,6 +1295,7 @@ func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 			row = make([]*ValueColumn, 0)
 		case lex.TokenRightParenthesis:
 			values = append(values, row)
+			row = nil
 		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
 			if len(row) > 0 {
 				values = append(values, row)
@@ -895,6 +1321,12 @@ func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 				return nil, err
 			}
 			row = append(row, &ValueColumn{Value: value.NewBoolValue(bv)})
+		case lex.TokenParam:
+			pn, err := expr.NewParamNode(m.Cur())
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, &ValueColumn{Expr: pn})
 		case lex.TokenIdentity:
 			// TODO:  this is a bug in lexer
 			lv := m.Cur().V
@@ -968,13 +1400,14 @@ func (m *Sqlbridge) parseSources(req *SqlSelect) error {
 			if m.Cur().T == lex.TokenRightParenthesis {
 				m.Next()
 			}
-		case lex.TokenLeft, lex.TokenRight, lex.TokenInner, lex.TokenOuter, lex.TokenJoin:
+		case lex.TokenLeft, lex.TokenRight, lex.TokenFull, lex.TokenInner, lex.TokenOuter, lex.TokenJoin:
 			// JOIN
 			if err := m.parseSourceJoin(src); err != nil {
 				return err
 			}
 		case lex.TokenEOF, lex.TokenEOS, lex.TokenWhere, lex.TokenGroupBy, lex.TokenLimit,
-			lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenOrderBy:
+			lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenOrderBy,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			return nil
 		default:
 
@@ -1030,6 +1463,9 @@ func (m *Sqlbridge) parseSourceSubQuery(src *SqlSource) error {
 	src.SubQuery = subQuery
 	subQuery.Raw = subQuery.String()
 
+	if isSetOperation(m.Cur().T) {
+		return fmt.Errorf("%s is not supported in sub-queries", m.Cur().V)
+	}
 	if m.Cur().T != lex.TokenRightParenthesis {
 		return fmt.Errorf("expected right paren but got: %v", m.Cur())
 	}
@@ -1059,7 +1495,7 @@ func (m *Sqlbridge) parseSourceJoin(src *SqlSource) error {
 	//u.Debugf("parseSourceJoin cur %v", m.Cur())
 
 	switch m.Cur().T {
-	case lex.TokenLeft, lex.TokenRight:
+	case lex.TokenLeft, lex.TokenRight, lex.TokenFull:
 		//u.Debugf("left/right join: %v", m.Cur())
 		src.LeftOrRight = m.Cur().T
 		m.Next()
@@ -1133,10 +1569,193 @@ func (m *Sqlbridge) parseWhereSubSelect(req *SqlSelect) error {
 		return err
 	}
 	//u.Infof("found sub-select %+v", stmt)
-	req = stmt
+	*req = *stmt
 	return nil
 }
 
+// whereCond is the tokens of one of the top level AND'd conditions of a
+// where clause, and if it is a sub-query condition the position of its op
+//
+//     [left] [NOT] (IN|=|EXISTS) ( SELECT ... )
+type whereCond struct {
+	tokens []lex.Token
+	op     int // position of op token if sub-query, else -1
+}
+
+type whereConds []*whereCond
+
+func (m whereConds) hasSubQuery() bool {
+	for _, c := range m {
+		if c.op >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupported is there a sub-query that is not a condition of its own,
+// ie inside of an OR, NOT or function
+func (m whereConds) unsupported() bool {
+	for _, c := range m {
+		if c.op >= 0 {
+			continue
+		}
+		for i := 1; i < len(c.tokens); i++ {
+			if c.tokens[i].T == lex.TokenSelect && c.tokens[i-1].T == lex.TokenLeftParenthesis {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// whereConditions splits the where clause at current token into its top
+// level AND'd conditions, does not consume any tokens
+func (m *Sqlbridge) whereConditions() whereConds {
+	conds := whereConds{}
+	cur := &whereCond{op: -1}
+	depth, n := 0, 0
+tokenLoop:
+	for {
+		tok := m.Cur()
+		switch tok.T {
+		case lex.TokenEOF, lex.TokenEOS, lex.TokenGroupBy, lex.TokenHaving, lex.TokenOrderBy,
+			lex.TokenLimit, lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenError,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
+			if depth == 0 {
+				break tokenLoop
+			}
+		case lex.TokenLeftParenthesis:
+			depth++
+		case lex.TokenRightParenthesis:
+			if depth == 0 {
+				// end of the sub-query this where belongs to
+				break tokenLoop
+			}
+			depth--
+		case lex.TokenLogicAnd:
+			if depth == 0 {
+				conds = append(conds, cur)
+				cur = &whereCond{op: -1}
+				m.Next()
+				n++
+				continue
+			}
+		}
+		cur.tokens = append(cur.tokens, tok)
+		m.Next()
+		n++
+	}
+	conds = append(conds, cur)
+	for ; n > 0; n-- {
+		m.Backup()
+	}
+
+	for _, c := range conds {
+		depth = 0
+	condLoop:
+		for i, tok := range c.tokens {
+			switch tok.T {
+			case lex.TokenLeftParenthesis:
+				depth++
+			case lex.TokenRightParenthesis:
+				depth--
+			case lex.TokenLogicOr, lex.TokenOr:
+				if depth == 0 {
+					// x OR y IN (SELECT ...) is not a sub-query condition
+					c.op = -1
+					break condLoop
+				}
+			case lex.TokenIN, lex.TokenEqual, lex.TokenExists:
+				// sub-query must be the whole remainder of the condition
+				if c.op < 0 && depth == 0 && i+2 < len(c.tokens) && c.tokens[i+1].T == lex.TokenLeftParenthesis &&
+					c.tokens[i+2].T == lex.TokenSelect && c.tokens[len(c.tokens)-1].T == lex.TokenRightParenthesis {
+					c.op = i
+				}
+			}
+		}
+	}
+	return conds
+}
+
+// parseWhereSubQueries parses a where clause with sub-query conditions,
+// which are only allowed AND'd to the rest of the where clause
+func (m *Sqlbridge) parseWhereSubQueries(conds whereConds) (*SqlWhere, error) {
+
+	where := &SqlWhere{}
+	for i, c := range conds {
+		if i > 0 {
+			m.Next() // AND
+		}
+		if c.op < 0 {
+			node, err := m.parseTokens(c.tokens)
+			if err != nil {
+				return nil, err
+			}
+			if where.Expr == nil {
+				where.Expr = node
+			} else {
+				where.Expr = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, where.Expr, node)
+			}
+			for range c.tokens {
+				m.Next()
+			}
+			continue
+		}
+
+		sq := &SqlWhere{Op: c.tokens[c.op].T, Source: &SqlSelect{}}
+		left := c.tokens[:c.op]
+		if len(left) > 0 && left[len(left)-1].T == lex.TokenNegate {
+			sq.Not = true
+			left = left[:len(left)-1]
+		}
+		switch {
+		case sq.Op == lex.TokenExists && len(left) > 0:
+			return nil, fmt.Errorf("unexpected %v before EXISTS", left[0].V)
+		case sq.Op != lex.TokenExists && len(left) == 0:
+			return nil, fmt.Errorf("expected expression before %v (SELECT ...)", c.tokens[c.op].V)
+		case sq.Op == lex.TokenEqual && sq.Not:
+			return nil, fmt.Errorf("unexpected NOT before = (SELECT ...)")
+		case len(left) > 0:
+			node, err := m.parseTokens(left)
+			if err != nil {
+				return nil, err
+			}
+			sq.Left = node
+		}
+
+		// position at SELECT of sub-query
+		for j := 0; j < c.op+2; j++ {
+			m.Next()
+		}
+		if err := m.parseWhereSubSelect(sq.Source); err != nil {
+			return nil, err
+		}
+		if m.Cur().T != lex.TokenRightParenthesis {
+			return nil, fmt.Errorf("expected ) at end of sub-query but got %v", m.Cur())
+		}
+		m.Next() // Consume )
+		where.SubQueries = append(where.SubQueries, sq)
+	}
+	if where.Expr == nil && len(where.SubQueries) == 1 {
+		// where of a single sub-query condition, is that condition
+		return where.SubQueries[0], nil
+	}
+	return where, nil
+}
+
+// parseTokens parses an expression from its already lexed tokens
+func (m *Sqlbridge) parseTokens(tokens []lex.Token) (expr.Node, error) {
+	toks := make([]lex.Token, len(tokens), len(tokens)+1)
+	copy(toks, tokens)
+	pager := &tokenSlicePager{l: m.l, tokens: append(toks, lex.Token{T: lex.TokenEOF})}
+	tree := expr.NewTreeFuncs(pager, m.funcs)
+	if err := tree.BuildTree(m.buildVm); err != nil {
+		return nil, err
+	}
+	return tree.Root, nil
+}
+
 func (m *Sqlbridge) parseWhereSelect(req *SqlSelect) error {
 
 	var err error
@@ -1177,22 +1796,10 @@ func (m *Sqlbridge) parseWhere() (*SqlWhere, error) {
 
 	where := SqlWhere{}
 
-	// We are going to Peek forward at the next 3 tokens used
-	// to determine which type of where clause
-	//t1 := m.Cur().T
-	m.Next() // x
-	t2 := m.Cur().T
-	m.Next()
-	t3 := m.Cur().T
-	m.Next()
-	t4 := m.Cur().T
-	m.Backup()
-	m.Backup()
-	m.Backup()
-
 	// Check for Types of Where
-	//                                 t1            T2      T3     T4
 	//    SELECT x FROM user   WHERE user_id         IN      (      SELECT user_id from orders where ...)
+	//    SELECT x FROM user   WHERE user_id     NOT IN      (      SELECT user_id from orders where ...)
+	//    SELECT x FROM user u WHERE         [NOT] EXISTS    (      SELECT 1 from orders o where o.user_id = u.user_id)
 	//    SELECT * FROM t1     WHERE column1         =       (      SELECT column1 FROM t2);
 	//    select a FROM movies WHERE director        IN      (     "Quentin","copola","Bay","another")
 	//    select b FROM movies WHERE director        =       "bob";
@@ -1200,16 +1807,11 @@ func (m *Sqlbridge) parseWhere() (*SqlWhere, error) {
 	//    select b from movies WHERE director        LIKE    "%bob"
 	// TODO:
 	//    SELECT * FROM t3     WHERE ROW(5*t2.s1,77) =       (      SELECT 50,11*s1 FROM t4)
-	switch {
-	case (t2 == lex.TokenIN || t2 == lex.TokenEqual) && t3 == lex.TokenLeftParenthesis && t4 == lex.TokenSelect:
-		//u.Infof("in parseWhere: %v", m.Cur())
-		m.Next() // T1  ?? this might be udf?
-		m.Next() // t2  (IN | =)
-		m.Next() // t3 = (
-		//m.Next() // t4 = SELECT
-		where.Op = t2
-		where.Source = &SqlSelect{}
-		return &where, m.parseWhereSubSelect(where.Source)
+	conds := m.whereConditions()
+	if conds.unsupported() {
+		return nil, fmt.Errorf("sub-queries are only supported as AND'd IN, EXISTS conditions of the where clause")
+	} else if conds.hasSubQuery() {
+		return m.parseWhereSubQueries(conds)
 	}
 	//u.Debugf("doing Where: %v %v", m.Cur(), m.Peek())
 	tree := expr.NewTreeFuncs(m.SqlTokenPager, m.funcs)
@@ -1296,7 +1898,7 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 			}
 			return fmt.Errorf("expected identity but got: %v", m.Cur().String())
 		case lex.TokenFrom, lex.TokenOrderBy, lex.TokenInto, lex.TokenLimit, lex.TokenHaving,
-			lex.TokenWith, lex.TokenEOS, lex.TokenEOF:
+			lex.TokenWith, lex.TokenEOS, lex.TokenEOF, lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 
 			// This indicates we have come to the End of the columns
 			req.GroupBy = append(req.GroupBy, col)
@@ -1316,6 +1918,11 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 			m.Next()
 			col.Comment = m.Cur().V
 		case lex.TokenRightParenthesis:
+			if col != nil && col.Expr != nil {
+				// end of a nested select  WITH a AS (SELECT ... GROUP BY x)
+				req.GroupBy = append(req.GroupBy, col)
+				return nil
+			}
 			// loop on my friend
 		case lex.TokenComma:
 			req.GroupBy = append(req.GroupBy, col)
@@ -1413,7 +2020,8 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 		case lex.TokenAsc, lex.TokenDesc:
 			col.Order = strings.ToUpper(m.Cur().V)
 
-		case lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			// This indicates we have come to the End of the columns
 			req.OrderBy = append(req.OrderBy, col)
 			//u.Debugf("Ending column ")
@@ -1422,6 +2030,11 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 			m.Next()
 			col.Comment = m.Cur().V
 		case lex.TokenRightParenthesis:
+			if col != nil && col.Expr != nil {
+				// end of a nested select  WITH a AS (SELECT ... ORDER BY x)
+				req.OrderBy = append(req.OrderBy, col)
+				return nil
+			}
 			// loop on my friend
 		case lex.TokenComma:
 			req.OrderBy = append(req.OrderBy, col)
@@ -1829,6 +2442,37 @@ type SqlTokenPager struct {
 	lastKw lex.TokenType
 }
 
+// tokenSlicePager is a TokenPager over already lexed tokens, the last of
+// which must be an EOF
+type tokenSlicePager struct {
+	l      *lex.Lexer
+	tokens []lex.Token
+	cursor int
+}
+
+func (m *tokenSlicePager) Next() lex.Token {
+	tok := m.tokens[m.cursor]
+	if m.cursor < len(m.tokens)-1 {
+		m.cursor++
+	}
+	return tok
+}
+func (m *tokenSlicePager) Cur() lex.Token { return m.tokens[m.cursor] }
+func (m *tokenSlicePager) Peek() lex.Token {
+	if m.cursor < len(m.tokens)-1 {
+		return m.tokens[m.cursor+1]
+	}
+	return m.tokens[m.cursor]
+}
+func (m *tokenSlicePager) Backup() {
+	if m.cursor > 0 {
+		m.cursor--
+	}
+}
+func (m *tokenSlicePager) IsEnd() bool       { return false }
+func (m *tokenSlicePager) ClauseEnd() bool   { return false }
+func (m *tokenSlicePager) Lexer() *lex.Lexer { return m.l }
+
 func NewSqlTokenPager(l *lex.Lexer) *SqlTokenPager {
 	pager := expr.NewLexTokenPager(l)
 	return &SqlTokenPager{LexTokenPager: pager}
@@ -1842,7 +2486,8 @@ func (m *SqlTokenPager) ClauseEnd() bool {
 	//u.Debugf("IsEnd()? tok:  %v", tok)
 	switch tok.T {
 	case lex.TokenEOF, lex.TokenEOS, lex.TokenFrom, lex.TokenHaving, lex.TokenComma,
-		lex.TokenIf, lex.TokenAs, lex.TokenLimit, lex.TokenSelect:
+		lex.TokenIf, lex.TokenAs, lex.TokenLimit, lex.TokenSelect,
+		lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 		return true
 	}
 	return false
@@ -105,9 +105,11 @@ func TestSqlLexOnly(t *testing.T) {
 		INNER JOIN orders AS t3
 			ON t3.id = t2.fake_id;`)
 
+	parseSqlTest(t, `INSERT INTO events (id,event_date,event) SELECT id,last_logon,"last_logon" FROM users;`)
+	parseSqlTest(t, `INSERT INTO events SELECT id,last_logon FROM users WHERE id > 10`)
 	// TODO:
-	//parseSqlTest(t, `INSERT INTO events (id,event_date,event) SELECT id,last_logon,"last_logon" FROM users;`)
 	// parseSqlTest(t, `REPLACE INTO tbl_3 (id,lastname) SELECT id,lastname FROM tbl_1;`)
+	parseSqlTest(t, `SELECT id, lastname INTO tbl_3 FROM tbl_1 WHERE id > 10`)
 	parseSqlTest(t, `insert into mytable (id, str) values (0, "a")`)
 	parseSqlTest(t, `upsert into mytable (id, str) values (0, "a")`)
 	parseSqlTest(t, `insert into mytable (id, str) values (0, "a"),(1,"b");`)
@@ -160,9 +162,15 @@ func TestSqlLexOnly(t *testing.T) {
 	    FROM mockcsv.users
 	    WHERE user_id in
 	    	(select user_id from mockcsv.orders)`)
-	// Currently unsupported
-	//parseSqlTest(t, `select user_id, email FROM mockcsv.users
-	//    WHERE tolower(email) IN (select email from mockcsv.orders)`)
+	parseSqlTest(t, `select user_id, email FROM mockcsv.users
+	    WHERE tolower(email) IN (select email from mockcsv.orders)`)
+	parseSqlTest(t, `select user_id FROM users AS u
+	    WHERE NOT EXISTS (select 1 from orders AS o WHERE o.user_id = u.user_id) LIMIT 10`)
+	parseSqlTest(t, `select user_id FROM users
+	    WHERE email != "x" AND user_id NOT IN (select user_id from orders ORDER BY user_id LIMIT 2) ORDER BY user_id`)
+	// sub-queries only as AND'd conditions
+	parseSqlError(t, `select user_id FROM users WHERE email = "x" OR user_id IN (select user_id from orders)`)
+	parseSqlError(t, `select user_id FROM users WHERE NOT (user_id IN (select user_id from orders))`)
 
 	parseSqlTest(t, `PREPARE stmt1 FROM 'SELECT toint(field) + 4 AS field FROM table1';`)
 
@@ -298,8 +306,33 @@ func TestSqlParseAstCheck(t *testing.T) {
 	assert.Tf(t, ok, "is SqlDescribe: %T", req)
 	sel, ok = desc.Stmt.(*SqlSelect)
 	assert.Tf(t, ok, "is SqlSelect: %T", req)
+	assert.T(t, !desc.Analyze)
 	u.Info(sel.Where.String())
 
+	sql = `EXPLAIN ANALYZE SELECT actor FROM github_watch WHERE repository.forks_count > 1000;`
+	req, err = ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	desc, ok = req.(*SqlDescribe)
+	assert.Tf(t, ok && desc.Analyze, "is SqlDescribe analyze: %T", req)
+	_, ok = desc.Stmt.(*SqlSelect)
+	assert.Tf(t, ok, "is SqlSelect: %T", desc.Stmt)
+
+	sql = `EXPLAIN SELECT actor FROM github_watch ORDER BY actor LIMIT 2;`
+	req, err = ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	desc, ok = req.(*SqlDescribe)
+	assert.Tf(t, ok && !desc.Analyze, "is SqlDescribe: %T", req)
+	_, ok = desc.Stmt.(*SqlSelect)
+	assert.Tf(t, ok, "is SqlSelect: %T", desc.Stmt)
+
+	sql = `EXPLAIN WITH w AS (SELECT actor FROM github_watch) SELECT actor FROM w;`
+	req, err = ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	desc, ok = req.(*SqlDescribe)
+	assert.Tf(t, ok, "is SqlDescribe: %T", req)
+	_, ok = desc.Stmt.(*SqlWith)
+	assert.Tf(t, ok, "is SqlWith: %T", desc.Stmt)
+
 	// Where In Sub-Query Clause
 	sql = `select user_id, email
 				FROM mockcsv.users
@@ -311,6 +344,27 @@ func TestSqlParseAstCheck(t *testing.T) {
 	assert.Tf(t, ok, "is SqlSelect: %T", req)
 	assert.Tf(t, len(sel.From) == 1, "has 1 from: %v", sel.From)
 	assert.Tf(t, sel.Where != nil && sel.Where.Source != nil, "has sub-select: %v", sel.Where)
+
+	// sub-query conditions AND'd with others
+	sql = `select user_id FROM users AS u
+				WHERE referral_count > 2
+					AND tolower(user_id) NOT IN (select user_id from orders WHERE price > 10)
+					AND EXISTS (select 1 from orders AS o WHERE o.user_id = u.user_id)
+				LIMIT 3`
+	req, err = ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	sel, ok = req.(*SqlSelect)
+	assert.Tf(t, ok, "is SqlSelect: %T", req)
+	assert.Tf(t, sel.Limit == 3, "has limit = 3: %v", sel.Limit)
+	assert.Tf(t, sel.Where.Expr.String() == "referral_count > 2", "where expr: %v", sel.Where.Expr)
+	assert.Tf(t, len(sel.Where.SubQueries) == 2, "has 2 sub-queries: %v", sel.Where)
+	sq := sel.Where.SubQueries[0]
+	assert.Tf(t, sq.Op == lex.TokenIN && sq.Not && sq.Left.String() == "tolower(user_id)", "NOT IN: %v", sq)
+	assert.Tf(t, sq.Source.Where.Expr.String() == "price > 10", "sub-query where: %v", sq.Source)
+	sq = sel.Where.SubQueries[1]
+	assert.Tf(t, sq.Op == lex.TokenExists && !sq.Not && sq.Left == nil, "EXISTS: %v", sq)
+	assert.Tf(t, sel.Where.String() == "referral_count > 2 AND tolower(user_id) NOT IN (SELECT user_id FROM orders WHERE price > 10) AND "+
+		"EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id)", "where: %v", sel.Where)
 }
 
 func TestSqlAggregateTypeSelect(t *testing.T) {
@@ -480,6 +534,180 @@ func TestSqlUpdate(t *testing.T) {
 	assert.Tf(t, ok, "is SqlUpdate: %T", req)
 	assert.Tf(t, up.Table == "users", "has users: %v", up.Table)
 	assert.Tf(t, len(up.Values) == 2, "%v", up)
+
+	// expressions are evaluated against each row
+	sql = `UPDATE users SET ct = ct + 1, name = lower(name), email = 'a@b.com', score = 1.5 WHERE id = "user815"`
+	req, err = ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	up = req.(*SqlUpdate)
+	assert.Tf(t, len(up.Values) == 4, "%v", up)
+	assert.Equal(t, "ct + 1", up.Values["ct"].Expr.String())
+	assert.Equal(t, "lower(name)", up.Values["name"].Expr.String())
+	assert.Equal(t, "a@b.com", up.Values["email"].Value.Value())
+	assert.Equal(t, 1.5, up.Values["score"].Value.Value())
+	assert.Equal(t, `id = "user815"`, up.Where.String())
+	parseSqlError(t, `UPDATE users SET ct + 1 WHERE id = "user815"`)
+}
+
+func TestSqlSetOperation(t *testing.T) {
+	t.Parallel()
+	sql := `SELECT a FROM x UNION ALL SELECT b FROM y INTERSECT SELECT c FROM z ORDER BY a DESC LIMIT 10`
+	req, err := ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	so, ok := req.(*SqlSetOperation)
+	assert.Tf(t, ok, "is SqlSetOperation: %T", req)
+	assert.Tf(t, so.Op == lex.TokenUnion && so.All, "has union all: %v", so.Op)
+	assert.Tf(t, len(so.OrderBy) == 1 && so.Limit == 10, "order by, limit on set operation: %v", so)
+	// intersect binds tighter than union
+	right, ok := so.Right.(*SqlSetOperation)
+	assert.Tf(t, ok && right.Op == lex.TokenIntersect && !right.All, "right is intersect: %T", so.Right)
+	assert.Tf(t, len(so.Selects()) == 3, "has 3 selects: %v", so.Selects())
+	for _, sel := range so.Selects() {
+		assert.Tf(t, len(sel.OrderBy) == 0 && sel.Limit == 0, "select has no order by: %s", sel)
+	}
+	so2, err := ParseSql(so.String())
+	assert.Tf(t, err == nil && so2.String() == so.String(), "round trip: %v %s", err, so)
+
+	for _, sql := range []string{
+		`SELECT a FROM x UNION`,
+		`SELECT a FROM x ORDER BY a UNION SELECT b FROM y`,
+		`SELECT a FROM x LIMIT 1 EXCEPT SELECT b FROM y`,
+		`SELECT a FROM (SELECT a FROM x UNION SELECT a FROM y) AS z`,
+	} {
+		_, err := ParseSql(sql)
+		assert.Tf(t, err != nil, "Should have errored: %s", sql)
+	}
+}
+
+func TestSqlWith(t *testing.T) {
+	t.Parallel()
+	sql := `WITH RECURSIVE a (x, y) AS (SELECT x, y FROM t WHERE z = "(" UNION ALL SELECT t.x, t.y FROM t INNER JOIN a ON t.y = a.x),
+		b AS (SELECT count(*) AS ct FROM a GROUP BY x) SELECT x FROM a UNION SELECT ct FROM b ORDER BY x LIMIT 3`
+	req, err := ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	w, ok := req.(*SqlWith)
+	assert.Tf(t, ok, "is SqlWith: %T", req)
+	assert.Tf(t, w.Recursive && len(w.Ctes) == 2, "has 2 recursive ctes: %v", w)
+	a := w.Cte("A")
+	assert.Tf(t, a != nil && len(a.Cols) == 2 && a.Cols[1] == "y", "cte a has cols: %#v", a)
+	so, ok := a.Stmt.(*SqlSetOperation)
+	assert.Tf(t, ok && so.All, "cte a is union all: %T", a.Stmt)
+	b := w.Cte("b")
+	assert.Tf(t, b != nil && len(b.Cols) == 0, "cte b: %#v", b)
+	sel, ok := b.Stmt.(*SqlSelect)
+	assert.Tf(t, ok && len(sel.GroupBy) == 1, "cte b is select: %T", b.Stmt)
+	main, ok := w.Stmt.(*SqlSetOperation)
+	assert.Tf(t, ok && main.Limit == 3, "main is set operation: %T", w.Stmt)
+
+	w2, err := ParseSql(w.String())
+	assert.Tf(t, err == nil && w2.String() == w.String(), "round trip: %v %s", err, w)
+
+	for _, sql := range []string{
+		`WITH a AS (SELECT 1)`,
+		`WITH a (SELECT 1) SELECT 1`,
+		`WITH a AS SELECT 1 SELECT 1`,
+		`WITH a () AS (SELECT 1) SELECT 1`,
+		`WITH a AS (SELECT 1), a AS (SELECT 2) SELECT 1`,
+		`WITH a AS (DELETE FROM x) SELECT 1`,
+	} {
+		_, err := ParseSql(sql)
+		assert.Tf(t, err != nil, "Should have errored: %s", sql)
+	}
+}
+
+func TestSqlCase(t *testing.T) {
+	t.Parallel()
+	sql := `SELECT CASE WHEN a > 1 THEN "x" ELSE "y" END AS c, CASE b WHEN 1 THEN "one" END, sum(CASE WHEN a > 1 THEN 1 END) AS s, CASE WHEN count(*) > 2 THEN "many" END AS m FROM x WHERE CASE b WHEN 2 THEN true ELSE a = 1 END`
+	req, err := ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	sel, ok := req.(*SqlSelect)
+	assert.Tf(t, ok, "is SqlSelect: %T", req)
+	assert.Tf(t, len(sel.Columns) == 4, "has 4 cols: %v", sel.Columns)
+	_, isCase := sel.Columns[0].Expr.(*expr.CaseNode)
+	assert.Tf(t, isCase && sel.Columns[0].As == "c", "case col: %#v", sel.Columns[0])
+	assert.Tf(t, sel.Columns[1].As == `CASE b WHEN 1 THEN "one" END`, "named by expr: %q", sel.Columns[1].As)
+	assert.Tf(t, !sel.Columns[0].Agg, "not agg: %#v", sel.Columns[0])
+	assert.Tf(t, sel.Columns[2].Agg, "agg of case: %#v", sel.Columns[2])
+	assert.Tf(t, sel.Columns[3].Agg, "agg in case: %#v", sel.Columns[3])
+	_, isCase = sel.Where.Expr.(*expr.CaseNode)
+	assert.Tf(t, isCase, "case where: %#v", sel.Where.Expr)
+
+	sel2, err := ParseSql(sel.String())
+	assert.Tf(t, err == nil && sel2.String() == sel.String(), "round trip: %v %s", err, sel)
+}
+
+func TestSqlParams(t *testing.T) {
+	t.Parallel()
+	// the index of each placeholder, -1 for named
+	tests := []struct {
+		sql     string
+		indexes map[string]int
+	}{
+		{`SELECT a, b FROM x WHERE a = ? AND b IN (?, :name)`,
+			map[string]int{"$1": 0, "$2": 1, ":name": -1}},
+		{`SELECT ? AS a, :name, b FROM x`,
+			map[string]int{"$1": 0, ":name": -1}},
+		{`SELECT a FROM x INNER JOIN y ON x.id = y.id WHERE y.b > $2 AND x.c IN (SELECT c FROM z WHERE d = $1)`,
+			map[string]int{"$1": 0, "$2": 1}},
+		{`SELECT a, count(*) FROM x WHERE b = ? GROUP BY a HAVING count(*) > ?`,
+			map[string]int{"$1": 0, "$2": 1}},
+		{`INSERT INTO x (a, b) VALUES (?, ?), (?, "c")`,
+			map[string]int{"$1": 0, "$2": 1, "$3": 2}},
+		{`UPDATE x SET a = ?, b = "c" WHERE id = ?`,
+			map[string]int{"$1": 0, "$2": 1}},
+		{`DELETE FROM x WHERE id = :id`,
+			map[string]int{":id": -1}},
+	}
+	for _, tt := range tests {
+		stmt, err := ParseSql(tt.sql)
+		assert.Tf(t, err == nil && stmt != nil, "Must parse: %s  \n\t%v", tt.sql, err)
+		params := Params(stmt)
+		assert.Tf(t, len(params) == len(tt.indexes), "%d params of %s: %v", len(tt.indexes), tt.sql, params)
+		for _, p := range params {
+			idx, ok := tt.indexes[p.String()]
+			assert.Tf(t, ok && idx == p.Index, "param %s of %s has index %d", p, tt.sql, p.Index)
+		}
+	}
+
+	stmt, err := ParseSql(`UPDATE x SET a = ? WHERE id = ?`)
+	assert.Tf(t, err == nil, "Must parse: %v", err)
+	assert.Equal(t, "UPDATE x SET a = $1 WHERE id = $2", stmt.String())
+}
+
+func TestSqlWindow(t *testing.T) {
+	t.Parallel()
+	sql := `SELECT a, sum(b) OVER (PARTITION BY c, d ORDER BY e DESC ROWS BETWEEN 2 PRECEDING AND UNBOUNDED FOLLOWING) AS s, rank() OVER (ORDER BY e) FROM x`
+	req, err := ParseSql(sql)
+	assert.Tf(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
+	sel, ok := req.(*SqlSelect)
+	assert.Tf(t, ok, "is SqlSelect: %T", req)
+	assert.Tf(t, sel.IsWindowQuery() && !sel.IsAggQuery(), "is window query, not agg query")
+	assert.Tf(t, len(sel.Columns) == 3 && !sel.Columns[0].IsWindow(), "has 3 cols: %v", sel.Columns)
+	s := sel.Columns[1]
+	assert.Tf(t, s.IsWindow() && !s.Agg && s.As == "s", "sum is window col: %#v", s)
+	assert.Tf(t, len(s.Over.PartitionBy) == 2 && len(s.Over.OrderBy) == 1 && s.Over.OrderBy[0].Order == "DESC", "window spec: %s", s.Over)
+	f := s.Over.Frame
+	assert.Tf(t, f != nil && !f.Range, "has rows frame: %v", f)
+	assert.Tf(t, f.Start.Type == lex.TokenPreceding && f.Start.Offset == 2 && !f.Start.Unbounded, "frame start: %v", f.Start)
+	assert.Tf(t, f.End.Type == lex.TokenFollowing && f.End.Unbounded, "frame end: %v", f.End)
+	r := sel.Columns[2]
+	assert.Tf(t, r.IsWindow() && r.As == "rank" && r.Over.Frame == nil, "rank is window col: %#v", r)
+
+	sel2, err := ParseSql(sel.String())
+	assert.Tf(t, err == nil && sel2.String() == sel.String(), "round trip: %v %s", err, sel)
+	assert.Tf(t, sel.Equal(sel2), "equal after round trip")
+
+	for _, sql := range []string{
+		`SELECT rank() OVER FROM x`,
+		`SELECT rank() OVER (ORDER BY a FROM x`,
+		`SELECT sum(a) OVER (ROWS BETWEEN CURRENT ROW) FROM x`,
+		`SELECT sum(a) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND CURRENT ROW) FROM x`,
+		`SELECT sum(a) OVER (ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM x`,
+		`SELECT sum(a) OVER (ROWS BETWEEN 1 FOLLOWING AND UNBOUNDED PRECEDING) FROM x`,
+	} {
+		_, err := ParseSql(sql)
+		assert.Tf(t, err != nil, "Should have errored: %s", sql)
+	}
 }
 
 func TestWithNameValue(t *testing.T) {
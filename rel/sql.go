@@ -21,6 +21,8 @@ var (
 
 	// Ensure SqlSelect and cousins etc are SqlStatements
 	_ SqlStatement = (*SqlSelect)(nil)
+	_ SqlStatement = (*SqlSetOperation)(nil)
+	_ SqlStatement = (*SqlWith)(nil)
 	_ SqlStatement = (*SqlInsert)(nil)
 	_ SqlStatement = (*SqlUpsert)(nil)
 	_ SqlStatement = (*SqlUpdate)(nil)
@@ -111,6 +113,47 @@ type (
 		pb            *SqlStatementPb
 		fingerprintid int64
 	}
+	// SqlSetOperation combines the rows of two select statements (or nested
+	// set operations), INTERSECT binds tighter than UNION, EXCEPT
+	//  - SELECT .. UNION [ALL] SELECT ..
+	//  - SELECT .. INTERSECT [ALL] SELECT ..
+	//  - SELECT .. EXCEPT [ALL] SELECT .. ORDER BY x LIMIT 10
+	SqlSetOperation struct {
+		Raw     string        // full original raw statement
+		Op      lex.TokenType // Union, Intersect, Except
+		All     bool          // ALL keeps duplicate rows
+		Left    SqlStatement  // *SqlSelect or *SqlSetOperation
+		Right   SqlStatement  // *SqlSelect or *SqlSetOperation
+		OrderBy Columns       // Order By of combined rows
+		Limit   int
+		Offset  int
+
+		// Memoized sql, we assume this is an immuteable struct so if this is populated use it
+		pb *SqlStatementPb
+	}
+	// SqlWith common table expressions (CTE), named statements whose rows
+	// are a table of the ctes after it, and of the statement using them
+	//  - WITH a AS (SELECT ..), b AS (SELECT .. FROM a) SELECT .. FROM b
+	//  - WITH RECURSIVE t (id, parent_id) AS (
+	//        SELECT id, parent_id FROM x WHERE parent_id IS NULL
+	//        UNION ALL
+	//        SELECT x.id, x.parent_id FROM x INNER JOIN t ON x.parent_id = t.id
+	//    ) SELECT id FROM t
+	SqlWith struct {
+		Raw       string       // full original raw statement
+		Recursive bool         // ctes may reference themselves
+		Ctes      []*SqlCte    // in order
+		Stmt      SqlStatement // *SqlSelect or *SqlSetOperation using the ctes
+
+		// Memoized sql, we assume this is an immuteable struct so if this is populated use it
+		pb *SqlStatementPb
+	}
+	// SqlCte a named statement of WITH,  name [(col, ...)] AS (statement)
+	SqlCte struct {
+		Name string       // name of table
+		Cols []string     // optional names of columns, else those of statement
+		Stmt SqlStatement // *SqlSelect or *SqlSetOperation
+	}
 	// Source is a table name, sub-query, or join as used in
 	// SELECT <columns> FROM <SQLSOURCE>
 	//  - SELECT .. FROM table_name
@@ -143,13 +186,19 @@ type (
 	// - WHERE x = y
 	// - WHERE x = y AND z = q
 	// - WHERE tolower(x) IN (select name from q)
+	// - WHERE x > 5 AND NOT EXISTS (select 1 from q WHERE q.id = t.id)
 	SqlWhere struct {
 		// Either Op + Source exists
-		Op     lex.TokenType // (In|=|ON)  for Select Clauses operators
+		Op     lex.TokenType // (In|=|ON|EXISTS)  for Select Clauses operators
+		Not    bool          // NOT IN, NOT EXISTS
+		Left   expr.Node     // left side of IN   ie tolower(x) IN (SELECT ...)
 		Source *SqlSelect    // IN (SELECT a,b,c from z)
 
 		// OR expr but not both
 		Expr expr.Node // x = y AND q > 5
+
+		// Sub-query conditions AND'd to Expr, each is an Op + Source where
+		SubQueries []*SqlWhere
 	}
 	// SQL Insert Statement
 	SqlInsert struct {
@@ -199,6 +248,7 @@ type (
 		Identity string    // Describe
 		Tok      lex.Token // Explain, Describe, Desc
 		Stmt     SqlStatement
+		Analyze  bool // EXPLAIN ANALYZE, run Stmt and report what each task did
 	}
 	// SQL INTO statement   (select a,b,c from y INTO z)
 	SqlInto struct {
@@ -216,22 +266,47 @@ type (
 	// Column represents the Column as expressed in a [SELECT]
 	// expression
 	Column struct {
-		sourceQuoteByte byte      // quote mark?   [ or ` etc
-		asQuoteByte     byte      // quote mark   [ or `
-		originalAs      string    // original as string
-		left            string    // users.col_name   = "users"
-		right           string    // users.first_name = "first_name"
-		ParentIndex     int       // slice idx position in parent query cols
-		Index           int       // slice idx position in original query cols
-		SourceIndex     int       // slice idx position in source []driver.Value
-		SourceField     string    // field name of underlying field
-		As              string    // As field, auto-populate the Field Name if exists
-		Comment         string    // optional in-line comments
-		Order           string    // (ASC | DESC)
-		Star            bool      // *
-		Agg             bool      // aggregate function column?   count(*), avg(x) etc
-		Expr            expr.Node // Expression, optional, often Identity.Node
-		Guard           expr.Node // column If guard, non-standard sql column guard
+		sourceQuoteByte byte       // quote mark?   [ or ` etc
+		asQuoteByte     byte       // quote mark   [ or `
+		originalAs      string     // original as string
+		left            string     // users.col_name   = "users"
+		right           string     // users.first_name = "first_name"
+		ParentIndex     int        // slice idx position in parent query cols
+		Index           int        // slice idx position in original query cols
+		SourceIndex     int        // slice idx position in source []driver.Value
+		SourceField     string     // field name of underlying field
+		As              string     // As field, auto-populate the Field Name if exists
+		Comment         string     // optional in-line comments
+		Order           string     // (ASC | DESC)
+		Star            bool       // *
+		Agg             bool       // aggregate function column?   count(*), avg(x) etc
+		Expr            expr.Node  // Expression, optional, often Identity.Node
+		Guard           expr.Node  // column If guard, non-standard sql column guard
+		Over            *SqlWindow // window of a window function column  ie row_number() OVER (..)
+	}
+	// SqlWindow is the OVER clause of a window function column, the partition
+	// of rows, their order and the frame the function is evaluated over.
+	//
+	//    row_number() OVER (PARTITION BY user_id ORDER BY ts DESC)
+	//    sum(price) OVER (ORDER BY ts ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)
+	SqlWindow struct {
+		PartitionBy []expr.Node
+		OrderBy     Columns
+		Frame       *SqlWindowFrame // optional, nil is the default frame
+	}
+	// SqlWindowFrame is the ROWS or RANGE frame of a window, if only a start
+	// bound was given the end is CURRENT ROW
+	SqlWindowFrame struct {
+		Range bool // RANGE frame of peer rows, else ROWS
+		Start SqlWindowBound
+		End   SqlWindowBound
+	}
+	// SqlWindowBound is one bound of a window frame
+	//    UNBOUNDED PRECEDING, 2 PRECEDING, CURRENT ROW, 1 FOLLOWING, UNBOUNDED FOLLOWING
+	SqlWindowBound struct {
+		Type      lex.TokenType // TokenPreceding, TokenFollowing, TokenCurrentRow
+		Unbounded bool
+		Offset    int
 	}
 	// List of Value columns in INSERT into TABLE (colnames) VALUES (valuecolumns)
 	ValueColumn struct {
@@ -587,6 +662,10 @@ func (m *Column) writeBuf(buf *bytes.Buffer) {
 		buf.WriteString(exprStr)
 		//u.Debugf("has expr: %T %#v  str=%s=%s", m.Expr, m.Expr, m.Expr.String(), exprStr)
 	}
+	if m.Over != nil {
+		buf.WriteString(" OVER ")
+		m.Over.writeBuf(buf, func(n expr.Node) string { return n.String() })
+	}
 
 	if m.asQuoteByte != 0 && m.originalAs != "" {
 		as := string(m.asQuoteByte) + m.originalAs + string(m.asQuoteByte)
@@ -617,6 +696,10 @@ func (m *Column) FingerPrint(r rune) string {
 		buf.WriteString(exprStr)
 		//u.Debugf("has expr: %T %#v  str=%s=%s", m.Expr, m.Expr, m.Expr.FingerPrint(r), exprStr)
 	}
+	if m.Over != nil {
+		buf.WriteString(" OVER ")
+		m.Over.writeBuf(&buf, func(n expr.Node) string { return n.FingerPrint(r) })
+	}
 	if m.asQuoteByte != 0 && m.originalAs != "" {
 		as := string(m.asQuoteByte) + m.originalAs + string(m.asQuoteByte)
 		//u.Warnf("%s", as)
@@ -712,6 +795,9 @@ func (m *Column) Equal(c *Column) bool {
 			return false
 		}
 	}
+	if !m.Over.Equal(c.Over) {
+		return false
+	}
 	return true
 }
 
@@ -752,6 +838,7 @@ func (m *Column) Copy() *Column {
 		Star:            m.Star,
 		Expr:            m.Expr,
 		Guard:           m.Guard,
+		Over:            m.Over,
 	}
 }
 func (m *Column) ToPB() *ColumnPb {
@@ -789,6 +876,9 @@ func (m *Column) ToPB() *ColumnPb {
 	if m.Guard != nil {
 		n.Guard = m.Guard.ToPB()
 	}
+	if m.Over != nil {
+		n.Over = m.Over.ToPB()
+	}
 	return &n
 }
 func columnFromPb(c *ColumnPb) *Column {
@@ -807,6 +897,129 @@ func columnFromPb(c *ColumnPb) *Column {
 		Star:            c.GetStar(),
 		Expr:            expr.NodeFromNodePb(c.GetExpr()),
 		Guard:           expr.NodeFromNodePb(c.GetGuard()),
+		Over:            sqlWindowFromPb(c.GetOver()),
+	}
+}
+
+// IsWindow is this a window function column  ie  rank() OVER (ORDER BY x)
+func (m *Column) IsWindow() bool { return m.Over != nil }
+
+func (m *SqlWindow) String() string {
+	buf := bytes.Buffer{}
+	m.writeBuf(&buf, func(n expr.Node) string { return n.String() })
+	return buf.String()
+}
+func (m *SqlWindow) writeBuf(buf *bytes.Buffer, str func(n expr.Node) string) {
+	buf.WriteByte('(')
+	for i, n := range m.PartitionBy {
+		if i == 0 {
+			buf.WriteString("PARTITION BY ")
+		} else {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(str(n))
+	}
+	for i, col := range m.OrderBy {
+		if i == 0 {
+			if len(m.PartitionBy) > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString("ORDER BY ")
+		} else {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(str(col.Expr))
+		if col.Order != "" {
+			buf.WriteString(" " + col.Order)
+		}
+	}
+	if m.Frame != nil {
+		if len(m.PartitionBy) > 0 || len(m.OrderBy) > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(m.Frame.String())
+	}
+	buf.WriteByte(')')
+}
+func (m *SqlWindow) Equal(w *SqlWindow) bool {
+	if m == nil || w == nil {
+		return m == nil && w == nil
+	}
+	if len(m.PartitionBy) != len(w.PartitionBy) || len(m.OrderBy) != len(w.OrderBy) {
+		return false
+	}
+	for i, n := range m.PartitionBy {
+		if !n.Equal(w.PartitionBy[i]) {
+			return false
+		}
+	}
+	for i, col := range m.OrderBy {
+		if !col.Equal(w.OrderBy[i]) {
+			return false
+		}
+	}
+	if m.Frame == nil || w.Frame == nil {
+		return m.Frame == nil && w.Frame == nil
+	}
+	return *m.Frame == *w.Frame
+}
+func (m *SqlWindow) ToPB() *SqlWindowPb {
+	pb := &SqlWindowPb{OrderBy: ColumnsToPb(m.OrderBy)}
+	for _, n := range m.PartitionBy {
+		pb.PartitionBy = append(pb.PartitionBy, n.ToPB())
+	}
+	if m.Frame != nil {
+		pb.Frame = &SqlWindowFramePb{
+			Range: m.Frame.Range,
+			Start: m.Frame.Start.toPB(),
+			End:   m.Frame.End.toPB(),
+		}
+	}
+	return pb
+}
+func sqlWindowFromPb(pb *SqlWindowPb) *SqlWindow {
+	if pb == nil {
+		return nil
+	}
+	w := &SqlWindow{OrderBy: ColumnsFromPb(pb.OrderBy)}
+	for _, n := range pb.PartitionBy {
+		w.PartitionBy = append(w.PartitionBy, expr.NodeFromNodePb(n))
+	}
+	if pb.Frame != nil {
+		w.Frame = &SqlWindowFrame{
+			Range: pb.Frame.Range,
+			Start: sqlWindowBoundFromPb(pb.Frame.Start),
+			End:   sqlWindowBoundFromPb(pb.Frame.End),
+		}
+	}
+	return w
+}
+
+func (m *SqlWindowFrame) String() string {
+	kw := "ROWS"
+	if m.Range {
+		kw = "RANGE"
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", kw, m.Start, m.End)
+}
+
+func (m SqlWindowBound) String() string {
+	switch {
+	case m.Type == lex.TokenCurrentRow:
+		return "CURRENT ROW"
+	case m.Unbounded:
+		return "UNBOUNDED " + strings.ToUpper(m.Type.String())
+	}
+	return fmt.Sprintf("%d %s", m.Offset, strings.ToUpper(m.Type.String()))
+}
+func (m SqlWindowBound) toPB() *SqlWindowBoundPb {
+	return &SqlWindowBoundPb{Type: int32(m.Type), Unbounded: m.Unbounded, Offset: int64(m.Offset)}
+}
+func sqlWindowBoundFromPb(pb *SqlWindowBoundPb) SqlWindowBound {
+	return SqlWindowBound{
+		Type:      lex.TokenType(pb.GetType()),
+		Unbounded: pb.GetUnbounded(),
+		Offset:    int(pb.GetOffset()),
 	}
 }
 
@@ -1041,6 +1254,16 @@ func (m *SqlSelect) IsAggQuery() bool {
 	}
 	return false
 }
+
+// IsWindowQuery does this select have window function columns
+func (m *SqlSelect) IsWindowQuery() bool {
+	for _, col := range m.Columns {
+		if col.Over != nil {
+			return true
+		}
+	}
+	return false
+}
 func (m *SqlSelect) String() string {
 	buf := bytes.Buffer{}
 	m.writeBuf(0, &buf)
@@ -1137,6 +1360,284 @@ func (m *SqlSelect) FingerPrintID() int64 {
 	return m.fingerprintid
 }
 
+func NewSqlSetOperation(op lex.TokenType, all bool, left, right SqlStatement) *SqlSetOperation {
+	return &SqlSetOperation{Op: op, All: all, Left: left, Right: right}
+}
+func (m *SqlSetOperation) Keyword() lex.TokenType { return m.Op }
+
+// Selects all of the select statements of this set operation, in order
+func (m *SqlSetOperation) Selects() []*SqlSelect {
+	sels := make([]*SqlSelect, 0, 2)
+	for _, stmt := range []SqlStatement{m.Left, m.Right} {
+		switch st := stmt.(type) {
+		case *SqlSelect:
+			sels = append(sels, st)
+		case *SqlSetOperation:
+			sels = append(sels, st.Selects()...)
+		}
+	}
+	return sels
+}
+
+// First the left-most select statement, whose columns name the result columns
+func (m *SqlSetOperation) First() *SqlSelect {
+	sels := m.Selects()
+	if len(sels) == 0 {
+		return nil
+	}
+	return sels[0]
+}
+func (m *SqlSetOperation) String() string {
+	buf := bytes.Buffer{}
+	m.writeBuf(&buf, func(s SqlStatement) string { return s.String() })
+	if m.OrderBy != nil {
+		buf.WriteString(fmt.Sprintf(" ORDER BY %s", m.OrderBy.String()))
+	}
+	if m.Limit > 0 {
+		buf.WriteString(fmt.Sprintf(" LIMIT %d", m.Limit))
+	}
+	if m.Offset > 0 {
+		buf.WriteString(fmt.Sprintf(" OFFSET %d", m.Offset))
+	}
+	return buf.String()
+}
+func (m *SqlSetOperation) FingerPrint(r rune) string {
+	buf := bytes.Buffer{}
+	m.writeBuf(&buf, func(s SqlStatement) string { return s.FingerPrint(r) })
+	if m.OrderBy != nil {
+		buf.WriteString(fmt.Sprintf(" ORDER BY %s", m.OrderBy.FingerPrint(r)))
+	}
+	if m.Limit > 0 {
+		buf.WriteString(fmt.Sprintf(" LIMIT %d", m.Limit))
+	}
+	return buf.String()
+}
+func (m *SqlSetOperation) writeBuf(buf *bytes.Buffer, str func(s SqlStatement) string) {
+	for i, stmt := range []SqlStatement{m.Left, m.Right} {
+		if i > 0 {
+			buf.WriteString(fmt.Sprintf(" %s ", strings.ToUpper(m.Op.String())))
+			if m.All {
+				buf.WriteString("ALL ")
+			}
+		}
+		if so, ok := stmt.(*SqlSetOperation); ok {
+			// nested set operations have no order by, limit of their own
+			so.writeBuf(buf, str)
+		} else if stmt != nil {
+			buf.WriteString(str(stmt))
+		}
+	}
+}
+func (m *SqlSetOperation) Equal(ss SqlStatement) bool {
+	s, ok := ss.(*SqlSetOperation)
+	if !ok {
+		return false
+	}
+	if m == nil && s == nil {
+		return true
+	}
+	if m == nil || s == nil {
+		return false
+	}
+	if m.Raw != s.Raw || m.Op != s.Op || m.All != s.All {
+		return false
+	}
+	if m.Limit != s.Limit || m.Offset != s.Offset {
+		return false
+	}
+	if !statementEqual(m.Left, s.Left) || !statementEqual(m.Right, s.Right) {
+		return false
+	}
+	if len(m.OrderBy) != len(s.OrderBy) {
+		return false
+	}
+	for i, c := range m.OrderBy {
+		if !c.Equal(s.OrderBy[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (m *SqlSetOperation) ToPbStatement() *SqlStatementPb {
+	if m.pb == nil {
+		m.pb = &SqlStatementPb{SetOperation: m.ToPB()}
+	}
+	return m.pb
+}
+func (m *SqlSetOperation) ToPB() *SqlSetOperationPb {
+	if m.pb != nil {
+		return m.pb.SetOperation
+	}
+	s := SqlSetOperationPb{}
+	s.Op = int32(m.Op)
+	s.All = m.All
+	s.Left = statementToPb(m.Left)
+	s.Right = statementToPb(m.Right)
+	s.Limit = int32(m.Limit)
+	s.Offset = int32(m.Offset)
+	s.Raw = m.Raw
+	if len(m.OrderBy) > 0 {
+		s.OrderBy = ColumnsToPb(m.OrderBy)
+	}
+	return &s
+}
+func SqlSetOperationFromPb(pb *SqlSetOperationPb) *SqlSetOperation {
+	m := SqlSetOperation{
+		Raw:    pb.GetRaw(),
+		Op:     lex.TokenType(pb.GetOp()),
+		All:    pb.GetAll(),
+		Left:   statementFromPb(pb.GetLeft()),
+		Right:  statementFromPb(pb.GetRight()),
+		Limit:  int(pb.GetLimit()),
+		Offset: int(pb.GetOffset()),
+	}
+	if len(pb.OrderBy) > 0 {
+		m.OrderBy = ColumnsFromPb(pb.GetOrderBy())
+	}
+	return &m
+}
+
+func NewSqlWith(recursive bool, ctes []*SqlCte, stmt SqlStatement) *SqlWith {
+	return &SqlWith{Recursive: recursive, Ctes: ctes, Stmt: stmt}
+}
+func (m *SqlWith) Keyword() lex.TokenType { return lex.TokenWith }
+
+// Cte the common table expression of this name, nil if none
+func (m *SqlWith) Cte(name string) *SqlCte {
+	for _, cte := range m.Ctes {
+		if strings.EqualFold(cte.Name, name) {
+			return cte
+		}
+	}
+	return nil
+}
+func (m *SqlWith) String() string {
+	buf := bytes.Buffer{}
+	m.writeBuf(&buf, func(s SqlStatement) string { return s.String() })
+	return buf.String()
+}
+func (m *SqlWith) FingerPrint(r rune) string {
+	buf := bytes.Buffer{}
+	m.writeBuf(&buf, func(s SqlStatement) string { return s.FingerPrint(r) })
+	return buf.String()
+}
+func (m *SqlWith) writeBuf(buf *bytes.Buffer, str func(s SqlStatement) string) {
+	buf.WriteString("WITH ")
+	if m.Recursive {
+		buf.WriteString("RECURSIVE ")
+	}
+	for i, cte := range m.Ctes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		cte.writeBuf(buf, str)
+	}
+	if m.Stmt != nil {
+		buf.WriteByte(' ')
+		buf.WriteString(str(m.Stmt))
+	}
+}
+func (m *SqlWith) Equal(ss SqlStatement) bool {
+	s, ok := ss.(*SqlWith)
+	if !ok {
+		return false
+	}
+	if m == nil && s == nil {
+		return true
+	}
+	if m == nil || s == nil {
+		return false
+	}
+	if m.Raw != s.Raw || m.Recursive != s.Recursive || len(m.Ctes) != len(s.Ctes) {
+		return false
+	}
+	for i, cte := range m.Ctes {
+		if !cte.Equal(s.Ctes[i]) {
+			return false
+		}
+	}
+	return statementEqual(m.Stmt, s.Stmt)
+}
+func (m *SqlWith) ToPbStatement() *SqlStatementPb {
+	if m.pb == nil {
+		m.pb = &SqlStatementPb{With: m.ToPB()}
+	}
+	return m.pb
+}
+func (m *SqlWith) ToPB() *SqlWithPb {
+	if m.pb != nil {
+		return m.pb.With
+	}
+	s := SqlWithPb{}
+	s.Raw = m.Raw
+	s.Recursive = m.Recursive
+	s.Ctes = make([]*SqlCtePb, len(m.Ctes))
+	for i, cte := range m.Ctes {
+		s.Ctes[i] = cte.ToPB()
+	}
+	s.Stmt = statementToPb(m.Stmt)
+	return &s
+}
+func SqlWithFromPb(pb *SqlWithPb) *SqlWith {
+	m := SqlWith{
+		Raw:       pb.GetRaw(),
+		Recursive: pb.GetRecursive(),
+		Ctes:      make([]*SqlCte, len(pb.Ctes)),
+		Stmt:      statementFromPb(pb.GetStmt()),
+	}
+	for i, cte := range pb.Ctes {
+		m.Ctes[i] = sqlCteFromPb(cte)
+	}
+	return &m
+}
+
+func (m *SqlCte) String() string {
+	buf := bytes.Buffer{}
+	m.writeBuf(&buf, func(s SqlStatement) string { return s.String() })
+	return buf.String()
+}
+func (m *SqlCte) writeBuf(buf *bytes.Buffer, str func(s SqlStatement) string) {
+	buf.WriteString(expr.IdentityMaybeQuote('`', m.Name))
+	if len(m.Cols) > 0 {
+		buf.WriteString(" (")
+		for i, col := range m.Cols {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(expr.IdentityMaybeQuote('`', col))
+		}
+		buf.WriteByte(')')
+	}
+	buf.WriteString(" AS (")
+	if m.Stmt != nil {
+		buf.WriteString(str(m.Stmt))
+	}
+	buf.WriteByte(')')
+}
+func (m *SqlCte) Equal(c *SqlCte) bool {
+	if m == nil && c == nil {
+		return true
+	}
+	if m == nil || c == nil {
+		return false
+	}
+	if m.Name != c.Name || len(m.Cols) != len(c.Cols) {
+		return false
+	}
+	for i, col := range m.Cols {
+		if col != c.Cols[i] {
+			return false
+		}
+	}
+	return statementEqual(m.Stmt, c.Stmt)
+}
+func (m *SqlCte) ToPB() *SqlCtePb {
+	return &SqlCtePb{Name: m.Name, Cols: m.Cols, Stmt: statementToPb(m.Stmt)}
+}
+func sqlCteFromPb(pb *SqlCtePb) *SqlCte {
+	return &SqlCte{Name: pb.GetName(), Cols: pb.GetCols(), Stmt: statementFromPb(pb.GetStmt())}
+}
+
 // Finalize this Query plan by preparing sub-sources
 //  ie we need to rewrite some things into sub-statements
 //  - we need to share the join expression across sources
@@ -1273,6 +1774,10 @@ func (m *SqlSource) writeBuf(depth int, buf *bytes.Buffer) {
 	//u.Warnf("op:%d leftright:%d jointype:%d", m.Op, m.LeftRight, m.JoinType)
 	//   Jointype                Op
 	//  INNER JOIN orders AS o 	ON
+	if int(m.LeftOrRight) != 0 {
+		buf.WriteString(strings.ToTitle(m.LeftOrRight.String())) // left/right/full
+		buf.WriteByte(' ')
+	}
 	if int(m.JoinType) != 0 {
 		buf.WriteString(strings.ToTitle(m.JoinType.String())) // inner/outer
 		buf.WriteByte(' ')
@@ -1323,6 +1828,10 @@ func (m *SqlSource) FingerPrint(r rune) string {
 	//u.Infof("%#v", m)
 	//   Jointype                Op
 	//  INNER JOIN orders AS o 	ON
+	if int(m.LeftOrRight) != 0 {
+		buf.WriteString(strings.ToTitle(m.LeftOrRight.String())) // left/right/full
+		buf.WriteByte(' ')
+	}
 	if int(m.JoinType) != 0 {
 		buf.WriteString(strings.ToTitle(m.JoinType.String()))
 		buf.WriteByte(' ')
@@ -1467,11 +1976,34 @@ func (m *SqlSource) Rewrite(parentStmt *SqlSelect) *SqlSelect {
 	// }
 	//u.Debugf("cols len: %v", len(sql2.Columns))
 	if parentStmt.Where != nil {
-		node, cols := rewriteWhere(parentStmt, m, parentStmt.Where.Expr, make(Columns, 0))
-		if node != nil {
+		var node expr.Node
+		cols := make(Columns, 0)
+		if parentStmt.Where.Expr != nil {
+			node, cols = rewriteWhere(parentStmt, m, parentStmt.Where.Expr, cols)
+		}
+		if node != nil && !m.outerJoined(parentStmt) {
 			//u.Warnf("node string():  %v", node.String())
 			sql2.Where = &SqlWhere{Expr: node}
 		}
+		// columns used by IN, EXISTS sub-queries, are not filtered on here
+		// but are needed to evaluate them after the join
+		sqCols := make(Columns, 0)
+		for _, sq := range parentStmt.Where.SubQueryConditions() {
+			if sq.Left != nil {
+				_, sqCols = rewriteWhere(parentStmt, m, sq.Left, sqCols)
+			}
+			if sq.Source != nil && sq.Source.Where != nil && sq.Source.Where.Expr != nil {
+				_, sqCols = rewriteWhere(parentStmt, m, sq.Source.Where.Expr, sqCols)
+			}
+		}
+		for _, col := range sqCols {
+			if _, has := sql2.Columns.ByName(col.SourceField); has {
+				continue
+			}
+			if _, has := cols.ByName(col.SourceField); !has {
+				cols = append(cols, col)
+			}
+		}
 		if len(cols) > 0 {
 			//u.Warnf("new where cols:   %#v", cols)
 			parentIdx := len(parentStmt.Columns)
@@ -1492,6 +2024,42 @@ func (m *SqlSource) Rewrite(parentStmt *SqlSelect) *SqlSelect {
 	return sql2
 }
 
+// outerJoined is true if the rows of this source may be missing from the
+// rows of an outer join of the parent, a where on its columns is only
+// evaluated after the join, as the missing columns are NULL
+func (m *SqlSource) outerJoined(parentStmt *SqlSelect) bool {
+	// the join type is on the right hand source
+	keeps := func(from *SqlSource) (left, right bool) {
+		switch from.LeftOrRight {
+		case lex.TokenLeft:
+			return true, false
+		case lex.TokenRight:
+			return false, true
+		case lex.TokenFull:
+			return true, true
+		}
+		outer := from.JoinType == lex.TokenOuter
+		return outer, outer
+	}
+	for i, from := range parentStmt.From {
+		if from != m {
+			continue
+		}
+		if i > 0 {
+			if keepLeft, _ := keeps(from); keepLeft {
+				return true
+			}
+		}
+		for _, next := range parentStmt.From[i+1:] {
+			if _, keepRight := keeps(next); keepRight {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
 func (m *SqlSource) findFromAliases() (string, string) {
 	from1, from2 := m.alias, ""
 	if m.JoinExpr != nil {
@@ -1550,7 +2118,8 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 			} else {
 				//u.Warnf("n1=%#v  n2=%#v    %#v", n1, n2, nt)
 			}
-		case lex.TokenEqual, lex.TokenEqualEqual, lex.TokenGT, lex.TokenGE, lex.TokenLE, lex.TokenNE:
+		case lex.TokenEqual, lex.TokenEqualEqual, lex.TokenGT, lex.TokenGE, lex.TokenLE, lex.TokenNE,
+			lex.TokenIs, lex.TokenIsNot:
 			var n1, n2 expr.Node
 			n1, cols = rewriteWhere(stmt, from, nt.Args[0], cols)
 			n2, cols = rewriteWhere(stmt, from, nt.Args[1], cols)
@@ -1635,7 +2204,8 @@ func joinNodesForFrom(stmt *SqlSelect, from *SqlSource, node expr.Node, depth in
 			} else {
 				//u.Warnf("%d n1=%#v  n2=%#v    %#v", depth, n1, n2, nt)
 			}
-		case lex.TokenEqual, lex.TokenEqualEqual, lex.TokenGT, lex.TokenGE, lex.TokenLE, lex.TokenNE:
+		case lex.TokenEqual, lex.TokenEqualEqual, lex.TokenGT, lex.TokenGE, lex.TokenLE, lex.TokenNE,
+			lex.TokenIs, lex.TokenIsNot:
 			n1 := joinNodesForFrom(stmt, from, nt.Args[0], depth+1)
 			n2 := joinNodesForFrom(stmt, from, nt.Args[1], depth+1)
 
@@ -2021,29 +2591,74 @@ func SqlSourceFromPb(pb *SqlSourcePb) *SqlSource {
 
 func (m *SqlWhere) Keyword() lex.TokenType { return m.Op }
 func (m *SqlWhere) writeBuf(buf *bytes.Buffer) {
+	if len(m.SubQueries) > 0 {
+		if m.Expr != nil {
+			buf.WriteString(m.Expr.String())
+			buf.WriteString(" AND ")
+		}
+		for i, sq := range m.SubQueries {
+			if i > 0 {
+				buf.WriteString(" AND ")
+			}
+			sq.writeBuf(buf)
+		}
+		return
+	}
 	if int(m.Op) == 0 && m.Source == nil && m.Expr != nil {
 		buf.WriteString(m.Expr.String())
 		return
 	}
 	// Op = subselect or in etc
 	if int(m.Op) != 0 && m.Source != nil {
-		buf.WriteString(fmt.Sprintf("%s (%s)", m.Op.String(), m.Source.String()))
+		buf.WriteString(m.subQueryPrefix(func() string { return m.Left.String() }))
+		buf.WriteString(fmt.Sprintf("(%s)", m.Source.String()))
 		return
 	}
 	u.Warnf("unexpected SqlWhere string? is this? %#v", m)
 }
+
+// SubQueryConditions the IN, EXISTS sub-query conditions of this where
+func (m *SqlWhere) SubQueryConditions() []*SqlWhere {
+	if m.Source != nil {
+		return []*SqlWhere{m}
+	}
+	return m.SubQueries
+}
+
+// [left] [NOT] op   of a sub-query condition
+func (m *SqlWhere) subQueryPrefix(left func() string) string {
+	s := ""
+	if m.Left != nil {
+		s = left() + " "
+	}
+	if m.Not {
+		s += "NOT "
+	}
+	return s + strings.ToUpper(m.Op.String()) + " "
+}
 func (m *SqlWhere) String() string {
 	buf := bytes.Buffer{}
 	m.writeBuf(&buf)
 	return buf.String()
 }
 func (m *SqlWhere) FingerPrint(r rune) string {
+	if len(m.SubQueries) > 0 {
+		parts := make([]string, 0, len(m.SubQueries)+1)
+		if m.Expr != nil {
+			parts = append(parts, m.Expr.FingerPrint(r))
+		}
+		for _, sq := range m.SubQueries {
+			parts = append(parts, sq.FingerPrint(r))
+		}
+		return strings.Join(parts, " AND ")
+	}
 	if int(m.Op) == 0 && m.Source == nil && m.Expr != nil {
 		return m.Expr.FingerPrint(r)
 	}
 	// Op = subselect or in etc
 	if int(m.Op) != 0 && m.Source != nil {
-		return fmt.Sprintf("%s (%s)", m.Op.String(), m.Source.FingerPrint(r))
+		left := func() string { return m.Left.FingerPrint(r) }
+		return fmt.Sprintf("%s(%s)", m.subQueryPrefix(left), m.Source.FingerPrint(r))
 	}
 	u.Warnf("what is this? %#v", m)
 	return ""
@@ -2058,15 +2673,26 @@ func (m *SqlWhere) Equal(s *SqlWhere) bool {
 	if m != nil && s == nil {
 		return false
 	}
-	if m.Op != s.Op {
+	if m.Op != s.Op || m.Not != s.Not {
 		return false
 	}
 	if !m.Source.Equal(s.Source) {
 		return false
 	}
-	if !m.Expr.Equal(s.Expr) {
+	if (m.Expr == nil) != (s.Expr == nil) || (m.Expr != nil && !m.Expr.Equal(s.Expr)) {
 		return false
 	}
+	if (m.Left == nil) != (s.Left == nil) || (m.Left != nil && !m.Left.Equal(s.Left)) {
+		return false
+	}
+	if len(m.SubQueries) != len(s.SubQueries) {
+		return false
+	}
+	for i, sq := range m.SubQueries {
+		if !sq.Equal(s.SubQueries[i]) {
+			return false
+		}
+	}
 	return true
 }
 func SqlWhereToPb(m *SqlWhere) *SqlWherePb {
@@ -2078,6 +2704,13 @@ func SqlWhereToPb(m *SqlWhere) *SqlWherePb {
 	if m.Expr != nil {
 		s.Expr = m.Expr.ToPB()
 	}
+	s.Not = m.Not
+	if m.Left != nil {
+		s.Left = m.Left.ToPB()
+	}
+	for _, sq := range m.SubQueries {
+		s.SubQueries = append(s.SubQueries, SqlWhereToPb(sq))
+	}
 	return &s
 }
 func SqlWhereFromPb(pb *SqlWherePb) *SqlWhere {
@@ -2090,6 +2723,13 @@ func SqlWhereFromPb(pb *SqlWherePb) *SqlWhere {
 	if pb.Expr != nil {
 		w.Expr = expr.NodeFromNodePb(pb.GetExpr())
 	}
+	w.Not = pb.Not
+	if pb.Left != nil {
+		w.Left = expr.NodeFromNodePb(pb.Left)
+	}
+	for _, sq := range pb.SubQueries {
+		w.SubQueries = append(w.SubQueries, SqlWhereFromPb(sq))
+	}
 	return &w
 }
 
@@ -2115,16 +2755,25 @@ func (m *SqlInto) Equal(s *SqlInto) bool {
 func (m *SqlInsert) Keyword() lex.TokenType { return m.kw }
 func (m *SqlInsert) String() string {
 	buf := bytes.Buffer{}
-	buf.WriteString(fmt.Sprintf("INSERT INTO %s (", m.Table))
+	buf.WriteString(fmt.Sprintf("INSERT INTO %s", m.Table))
 
-	for i, col := range m.Columns {
-		if i > 0 {
-			buf.WriteString(", ")
+	if len(m.Columns) > 0 || m.Select == nil {
+		buf.WriteString(" (")
+		for i, col := range m.Columns {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(col.String())
+			//u.Infof("write:  %q   %#v", col.String(), col)
 		}
-		buf.WriteString(col.String())
-		//u.Infof("write:  %q   %#v", col.String(), col)
+		buf.WriteByte(')')
+	}
+	if m.Select != nil {
+		buf.WriteByte(' ')
+		buf.WriteString(m.Select.String())
+		return buf.String()
 	}
-	buf.WriteString(") VALUES")
+	buf.WriteString(" VALUES")
 	for i, row := range m.Rows {
 		if i > 0 {
 			buf.WriteString("\n\t,")
@@ -2184,6 +2833,10 @@ func (m *SqlUpdate) String() string {
 		}
 		firstCol = false
 		buf.WriteByte(' ')
+		if val.Expr != nil {
+			buf.WriteString(fmt.Sprintf("%s = %s", key, val.Expr.String()))
+			continue
+		}
 		switch vt := val.Value.(type) {
 		case value.StringValue:
 			buf.WriteString(fmt.Sprintf("%s = %q", key, vt.ToString()))
@@ -2260,6 +2913,95 @@ func (m *SqlCommand) Keyword() lex.TokenType    { return m.kw }
 func (m *SqlCommand) FingerPrint(r rune) string { return m.String() }
 func (m *SqlCommand) String() string            { return fmt.Sprintf("%s %s", m.Keyword(), m.Columns.String()) }
 
+// Params all of the parameter placeholders of a statement, which a
+// prepared statement binds the values of its args to, a :name used
+// more than once is in it for each use
+func Params(stmt SqlStatement) []*expr.ParamNode {
+	return stmtParams(stmt, nil)
+}
+
+func stmtParams(stmt SqlStatement, params []*expr.ParamNode) []*expr.ParamNode {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		params = selectParams(st, params)
+	case *SqlSetOperation:
+		params = stmtParams(st.Left, params)
+		params = stmtParams(st.Right, params)
+		params = columnsParams(st.OrderBy, params)
+	case *SqlWith:
+		for _, cte := range st.Ctes {
+			params = stmtParams(cte.Stmt, params)
+		}
+		params = stmtParams(st.Stmt, params)
+	case *SqlDescribe:
+		params = stmtParams(st.Stmt, params)
+	case *SqlInsert:
+		params = rowsParams(st.Rows, params)
+		params = selectParams(st.Select, params)
+	case *SqlUpsert:
+		params = rowsParams(st.Rows, params)
+		for _, vc := range st.Values {
+			params = append(params, expr.FindAllParams(vc.Expr)...)
+		}
+		params = whereParams(st.Where, params)
+	case *SqlUpdate:
+		for _, vc := range st.Values {
+			params = append(params, expr.FindAllParams(vc.Expr)...)
+		}
+		params = whereParams(st.Where, params)
+	case *SqlDelete:
+		params = whereParams(st.Where, params)
+	}
+	return params
+}
+func selectParams(sel *SqlSelect, params []*expr.ParamNode) []*expr.ParamNode {
+	if sel == nil {
+		return params
+	}
+	params = columnsParams(sel.Columns, params)
+	for _, from := range sel.From {
+		params = append(params, expr.FindAllParams(from.JoinExpr)...)
+		params = selectParams(from.SubQuery, params)
+	}
+	params = whereParams(sel.Where, params)
+	params = append(params, expr.FindAllParams(sel.Having)...)
+	params = columnsParams(sel.GroupBy, params)
+	return columnsParams(sel.OrderBy, params)
+}
+func whereParams(where *SqlWhere, params []*expr.ParamNode) []*expr.ParamNode {
+	if where == nil {
+		return params
+	}
+	params = append(params, expr.FindAllParams(where.Expr)...)
+	params = append(params, expr.FindAllParams(where.Left)...)
+	params = selectParams(where.Source, params)
+	for _, sq := range where.SubQueries {
+		params = whereParams(sq, params)
+	}
+	return params
+}
+func columnsParams(cols Columns, params []*expr.ParamNode) []*expr.ParamNode {
+	for _, col := range cols {
+		params = append(params, expr.FindAllParams(col.Expr)...)
+		params = append(params, expr.FindAllParams(col.Guard)...)
+		if col.Over != nil {
+			for _, n := range col.Over.PartitionBy {
+				params = append(params, expr.FindAllParams(n)...)
+			}
+			params = columnsParams(col.Over.OrderBy, params)
+		}
+	}
+	return params
+}
+func rowsParams(rows [][]*ValueColumn, params []*expr.ParamNode) []*expr.ParamNode {
+	for _, row := range rows {
+		for _, vc := range row {
+			params = append(params, expr.FindAllParams(vc.Expr)...)
+		}
+	}
+	return params
+}
+
 // Node serialization helpers
 func tokenFromInt(iv int32) lex.Token {
 	t, ok := lex.TokenNameMap[lex.TokenType(iv)]
@@ -2285,9 +3027,35 @@ func statementFromPb(s *SqlStatementPb) SqlStatement {
 	case s.Source != nil:
 		var ss *SqlSource
 		return ss.FromPB(s.Source)
+	case s.SetOperation != nil:
+		return SqlSetOperationFromPb(s.SetOperation)
+	case s.With != nil:
+		return SqlWithFromPb(s.With)
 	}
 	return nil
 }
+
+// statementToPb the select, or set operation of an operand of a set
+// operation, or of a common table expression
+func statementToPb(stmt SqlStatement) *SqlStatementPb {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		return st.ToPbStatement()
+	case *SqlSetOperation:
+		return st.ToPbStatement()
+	}
+	u.Warnf("unsupported sub-statement %T", stmt)
+	return nil
+}
+func statementEqual(a, b SqlStatement) bool {
+	switch st := a.(type) {
+	case *SqlSelect:
+		return st.Equal(b)
+	case *SqlSetOperation:
+		return st.Equal(b)
+	}
+	return a == nil && b == nil
+}
 func MapIntFromPb(kv []KvInt) map[string]int {
 	m := make(map[string]int, len(kv))
 	for _, kv := range kv {
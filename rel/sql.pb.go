@@ -18,6 +18,12 @@
 		KvInt
 		ColumnPb
 		CommandColumnPb
+		SqlSetOperationPb
+		SqlWindowPb
+		SqlWindowFramePb
+		SqlWindowBoundPb
+		SqlWithPb
+		SqlCtePb
 */
 package rel
 
@@ -41,10 +47,12 @@ const _ = proto.ProtoPackageIsVersion1
 
 // The generic SqlStatement, must be exactly one of these types
 type SqlStatementPb struct {
-	Select           *SqlSelectPb  `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
-	Source           *SqlSourcePb  `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
-	Projection       *ProjectionPb `protobuf:"bytes,4,opt,name=projection" json:"projection,omitempty"`
-	XXX_unrecognized []byte        `json:"-"`
+	Select           *SqlSelectPb       `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	Source           *SqlSourcePb       `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
+	Projection       *ProjectionPb      `protobuf:"bytes,4,opt,name=projection" json:"projection,omitempty"`
+	SetOperation     *SqlSetOperationPb `protobuf:"bytes,5,opt,name=setOperation" json:"setOperation,omitempty"`
+	With             *SqlWithPb         `protobuf:"bytes,6,opt,name=with" json:"with,omitempty"`
+	XXX_unrecognized []byte             `json:"-"`
 }
 
 func (m *SqlStatementPb) Reset()                    { *m = SqlStatementPb{} }
@@ -73,6 +81,20 @@ func (m *SqlStatementPb) GetProjection() *ProjectionPb {
 	return nil
 }
 
+func (m *SqlStatementPb) GetSetOperation() *SqlSetOperationPb {
+	if m != nil {
+		return m.SetOperation
+	}
+	return nil
+}
+
+func (m *SqlStatementPb) GetWith() *SqlWithPb {
+	if m != nil {
+		return m.With
+	}
+	return nil
+}
+
 type SqlSelectPb struct {
 	Db               string         `protobuf:"bytes,1,req,name=db" json:"db"`
 	Raw              string         `protobuf:"bytes,2,req,name=raw" json:"raw"`
@@ -364,10 +386,13 @@ func (m *SqlSourcePb) GetSeekable() bool {
 }
 
 type SqlWherePb struct {
-	Op               int32        `protobuf:"varint,1,req,name=op" json:"op"`
-	Source           *SqlSelectPb `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
-	Expr             *expr.NodePb `protobuf:"bytes,3,opt,name=Expr,json=expr" json:"Expr,omitempty"`
-	XXX_unrecognized []byte       `json:"-"`
+	Op               int32         `protobuf:"varint,1,req,name=op" json:"op"`
+	Source           *SqlSelectPb  `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
+	Expr             *expr.NodePb  `protobuf:"bytes,3,opt,name=Expr,json=expr" json:"Expr,omitempty"`
+	Not              bool          `protobuf:"varint,4,opt,name=not" json:"not"`
+	Left             *expr.NodePb  `protobuf:"bytes,5,opt,name=left" json:"left,omitempty"`
+	SubQueries       []*SqlWherePb `protobuf:"bytes,6,rep,name=sub_queries" json:"sub_queries,omitempty"`
+	XXX_unrecognized []byte        `json:"-"`
 }
 
 func (m *SqlWherePb) Reset()                    { *m = SqlWherePb{} }
@@ -544,6 +569,7 @@ type ColumnPb struct {
 	Agg              bool         `protobuf:"varint,15,opt,name=agg" json:"agg"`
 	Expr             *expr.NodePb `protobuf:"bytes,16,opt,name=Expr,json=expr" json:"Expr,omitempty"`
 	Guard            *expr.NodePb `protobuf:"bytes,17,opt,name=Guard,json=guard" json:"Guard,omitempty"`
+	Over             *SqlWindowPb `protobuf:"bytes,18,opt,name=over" json:"over,omitempty"`
 	XXX_unrecognized []byte       `json:"-"`
 }
 
@@ -664,6 +690,13 @@ func (m *ColumnPb) GetGuard() *expr.NodePb {
 	return nil
 }
 
+func (m *ColumnPb) GetOver() *SqlWindowPb {
+	if m != nil {
+		return m.Over
+	}
+	return nil
+}
+
 type CommandColumnPb struct {
 	Expr             *expr.NodePb `protobuf:"bytes,1,opt,name=Expr,json=expr" json:"Expr,omitempty"`
 	Name             string       `protobuf:"bytes,2,req,name=name" json:"name"`
@@ -689,6 +722,252 @@ func (m *CommandColumnPb) GetName() string {
 	return ""
 }
 
+type SqlSetOperationPb struct {
+	Op               int32           `protobuf:"varint,1,req,name=op" json:"op"`
+	All              bool            `protobuf:"varint,2,req,name=all" json:"all"`
+	Left             *SqlStatementPb `protobuf:"bytes,3,req,name=left" json:"left"`
+	Right            *SqlStatementPb `protobuf:"bytes,4,req,name=right" json:"right"`
+	OrderBy          []*ColumnPb     `protobuf:"bytes,5,rep,name=orderBy" json:"orderBy,omitempty"`
+	Limit            int32           `protobuf:"varint,6,opt,name=limit" json:"limit"`
+	Offset           int32           `protobuf:"varint,7,opt,name=offset" json:"offset"`
+	Raw              string          `protobuf:"bytes,8,opt,name=raw" json:"raw"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlSetOperationPb) Reset()                    { *m = SqlSetOperationPb{} }
+func (m *SqlSetOperationPb) String() string            { return proto.CompactTextString(m) }
+func (*SqlSetOperationPb) ProtoMessage()               {}
+func (*SqlSetOperationPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{9} }
+
+func (m *SqlSetOperationPb) GetOp() int32 {
+	if m != nil {
+		return m.Op
+	}
+	return 0
+}
+
+func (m *SqlSetOperationPb) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+func (m *SqlSetOperationPb) GetLeft() *SqlStatementPb {
+	if m != nil {
+		return m.Left
+	}
+	return nil
+}
+
+func (m *SqlSetOperationPb) GetRight() *SqlStatementPb {
+	if m != nil {
+		return m.Right
+	}
+	return nil
+}
+
+func (m *SqlSetOperationPb) GetOrderBy() []*ColumnPb {
+	if m != nil {
+		return m.OrderBy
+	}
+	return nil
+}
+
+func (m *SqlSetOperationPb) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *SqlSetOperationPb) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *SqlSetOperationPb) GetRaw() string {
+	if m != nil {
+		return m.Raw
+	}
+	return ""
+}
+
+type SqlWindowPb struct {
+	PartitionBy      []*expr.NodePb    `protobuf:"bytes,1,rep,name=partitionBy" json:"partitionBy,omitempty"`
+	OrderBy          []*ColumnPb       `protobuf:"bytes,2,rep,name=orderBy" json:"orderBy,omitempty"`
+	Frame            *SqlWindowFramePb `protobuf:"bytes,3,opt,name=frame" json:"frame,omitempty"`
+	XXX_unrecognized []byte            `json:"-"`
+}
+
+func (m *SqlWindowPb) Reset()                    { *m = SqlWindowPb{} }
+func (m *SqlWindowPb) String() string            { return proto.CompactTextString(m) }
+func (*SqlWindowPb) ProtoMessage()               {}
+func (*SqlWindowPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{10} }
+
+func (m *SqlWindowPb) GetPartitionBy() []*expr.NodePb {
+	if m != nil {
+		return m.PartitionBy
+	}
+	return nil
+}
+
+func (m *SqlWindowPb) GetOrderBy() []*ColumnPb {
+	if m != nil {
+		return m.OrderBy
+	}
+	return nil
+}
+
+func (m *SqlWindowPb) GetFrame() *SqlWindowFramePb {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
+}
+
+type SqlWindowFramePb struct {
+	Range            bool              `protobuf:"varint,1,req,name=range" json:"range"`
+	Start            *SqlWindowBoundPb `protobuf:"bytes,2,req,name=start" json:"start"`
+	End              *SqlWindowBoundPb `protobuf:"bytes,3,req,name=end" json:"end"`
+	XXX_unrecognized []byte            `json:"-"`
+}
+
+func (m *SqlWindowFramePb) Reset()                    { *m = SqlWindowFramePb{} }
+func (m *SqlWindowFramePb) String() string            { return proto.CompactTextString(m) }
+func (*SqlWindowFramePb) ProtoMessage()               {}
+func (*SqlWindowFramePb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{11} }
+
+func (m *SqlWindowFramePb) GetRange() bool {
+	if m != nil {
+		return m.Range
+	}
+	return false
+}
+
+func (m *SqlWindowFramePb) GetStart() *SqlWindowBoundPb {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *SqlWindowFramePb) GetEnd() *SqlWindowBoundPb {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+type SqlWindowBoundPb struct {
+	Type             int32  `protobuf:"varint,1,req,name=type" json:"type"`
+	Unbounded        bool   `protobuf:"varint,2,req,name=unbounded" json:"unbounded"`
+	Offset           int64  `protobuf:"varint,3,req,name=offset" json:"offset"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SqlWindowBoundPb) Reset()                    { *m = SqlWindowBoundPb{} }
+func (m *SqlWindowBoundPb) String() string            { return proto.CompactTextString(m) }
+func (*SqlWindowBoundPb) ProtoMessage()               {}
+func (*SqlWindowBoundPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{12} }
+
+func (m *SqlWindowBoundPb) GetType() int32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *SqlWindowBoundPb) GetUnbounded() bool {
+	if m != nil {
+		return m.Unbounded
+	}
+	return false
+}
+
+func (m *SqlWindowBoundPb) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type SqlWithPb struct {
+	Raw              string          `protobuf:"bytes,1,opt,name=raw" json:"raw"`
+	Recursive        bool            `protobuf:"varint,2,req,name=recursive" json:"recursive"`
+	Ctes             []*SqlCtePb     `protobuf:"bytes,3,rep,name=ctes" json:"ctes,omitempty"`
+	Stmt             *SqlStatementPb `protobuf:"bytes,4,req,name=stmt" json:"stmt"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlWithPb) Reset()                    { *m = SqlWithPb{} }
+func (m *SqlWithPb) String() string            { return proto.CompactTextString(m) }
+func (*SqlWithPb) ProtoMessage()               {}
+func (*SqlWithPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{13} }
+
+type SqlCtePb struct {
+	Name             string          `protobuf:"bytes,1,req,name=name" json:"name"`
+	Cols             []string        `protobuf:"bytes,2,rep,name=cols" json:"cols,omitempty"`
+	Stmt             *SqlStatementPb `protobuf:"bytes,3,req,name=stmt" json:"stmt"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlCtePb) Reset()                    { *m = SqlCtePb{} }
+func (m *SqlCtePb) String() string            { return proto.CompactTextString(m) }
+func (*SqlCtePb) ProtoMessage()               {}
+func (*SqlCtePb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{14} }
+
+func (m *SqlWithPb) GetRaw() string {
+	if m != nil {
+		return m.Raw
+	}
+	return ""
+}
+
+func (m *SqlWithPb) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+func (m *SqlWithPb) GetCtes() []*SqlCtePb {
+	if m != nil {
+		return m.Ctes
+	}
+	return nil
+}
+
+func (m *SqlWithPb) GetStmt() *SqlStatementPb {
+	if m != nil {
+		return m.Stmt
+	}
+	return nil
+}
+
+func (m *SqlCtePb) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SqlCtePb) GetCols() []string {
+	if m != nil {
+		return m.Cols
+	}
+	return nil
+}
+
+func (m *SqlCtePb) GetStmt() *SqlStatementPb {
+	if m != nil {
+		return m.Stmt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SqlStatementPb)(nil), "rel.SqlStatementPb")
 	proto.RegisterType((*SqlSelectPb)(nil), "rel.SqlSelectPb")
@@ -699,6 +978,12 @@ func init() {
 	proto.RegisterType((*KvInt)(nil), "rel.KvInt")
 	proto.RegisterType((*ColumnPb)(nil), "rel.ColumnPb")
 	proto.RegisterType((*CommandColumnPb)(nil), "rel.CommandColumnPb")
+	proto.RegisterType((*SqlSetOperationPb)(nil), "rel.SqlSetOperationPb")
+	proto.RegisterType((*SqlWindowPb)(nil), "rel.SqlWindowPb")
+	proto.RegisterType((*SqlWindowFramePb)(nil), "rel.SqlWindowFramePb")
+	proto.RegisterType((*SqlWindowBoundPb)(nil), "rel.SqlWindowBoundPb")
+	proto.RegisterType((*SqlWithPb)(nil), "rel.SqlWithPb")
+	proto.RegisterType((*SqlCtePb)(nil), "rel.SqlCtePb")
 }
 func (m *SqlStatementPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -745,6 +1030,26 @@ func (m *SqlStatementPb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n3
 	}
+	if m.SetOperation != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.SetOperation.Size()))
+		n17, err := m.SetOperation.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	if m.With != nil {
+		data[i] = 0x32
+		i++
+		i = encodeVarintSql(data, i, uint64(m.With.Size()))
+		n24, err := m.With.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -1098,6 +1403,36 @@ func (m *SqlWherePb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n11
 	}
+	data[i] = 0x20
+	i++
+	if m.Not {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	if m.Left != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Left.Size()))
+		n16, err := m.Left.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if len(m.SubQueries) > 0 {
+		for _, msg := range m.SubQueries {
+			data[i] = 0x32
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -1379,6 +1714,18 @@ func (m *ColumnPb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n14
 	}
+	if m.Over != nil {
+		data[i] = 0x92
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Over.Size()))
+		n20, err := m.Over.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -1420,304 +1767,440 @@ func (m *CommandColumnPb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
-func encodeFixed64Sql(data []byte, offset int, v uint64) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	data[offset+4] = uint8(v >> 32)
-	data[offset+5] = uint8(v >> 40)
-	data[offset+6] = uint8(v >> 48)
-	data[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Sql(data []byte, offset int, v uint32) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintSql(data []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		data[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *SqlSetOperationPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
 	}
-	data[offset] = uint8(v)
-	return offset + 1
+	return data[:n], nil
 }
-func (m *SqlStatementPb) Size() (n int) {
+
+func (m *SqlSetOperationPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Select != nil {
-		l = m.Select.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Projection != nil {
-		l = m.Projection.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	data[i] = 0x8
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Op))
+	data[i] = 0x10
+	i++
+	if m.All {
+		data[i] = 1
+	} else {
+		data[i] = 0
 	}
-	return n
-}
-
-func (m *SqlSelectPb) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Db)
-	n += 1 + l + sovSql(uint64(l))
-	l = len(m.Raw)
-	n += 1 + l + sovSql(uint64(l))
-	n += 2
-	n += 2
-	if len(m.Columns) > 0 {
-		for _, e := range m.Columns {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+	i++
+	if m.Left != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Left.Size()))
+		n18, err := m.Left.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n18
 	}
-	if len(m.From) > 0 {
-		for _, e := range m.From {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+	if m.Right != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Right.Size()))
+		n19, err := m.Right.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
 		}
-	}
-	if m.Into != nil {
-		l = len(*m.Into)
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Where != nil {
-		l = m.Where.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Having != nil {
-		l = m.Having.Size()
-		n += 1 + l + sovSql(uint64(l))
+		i += n19
 	}
 	if len(m.OrderBy) > 0 {
-		for _, e := range m.OrderBy {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
-		}
-	}
-	if len(m.GroupBy) > 0 {
-		for _, e := range m.GroupBy {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+		for _, msg := range m.OrderBy {
+			data[i] = 0x2a
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
 		}
 	}
-	n += 1 + sovSql(uint64(m.Limit))
-	n += 1 + sovSql(uint64(m.Offset))
-	if m.Alias != nil {
-		l = len(*m.Alias)
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Projection != nil {
-		l = m.Projection.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	n += 3
-	n += 3
-	n += 3
-	if m.With != nil {
-		l = len(m.With)
-		n += 2 + l + sovSql(uint64(l))
-	}
+	data[i] = 0x30
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Limit))
+	data[i] = 0x38
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Offset))
+	data[i] = 0x42
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Raw)))
+	i += copy(data[i:], m.Raw)
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(data[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *SqlSourcePb) Size() (n int) {
+func (m *SqlWindowPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlWindowPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	n += 2
-	if m.AliasInner != nil {
-		l = len(*m.AliasInner)
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if len(m.Columns) > 0 {
-		for _, e := range m.Columns {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+	if len(m.PartitionBy) > 0 {
+		for _, msg := range m.PartitionBy {
+			data[i] = 0xa
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
 		}
 	}
-	if len(m.ColIndex) > 0 {
-		for _, e := range m.ColIndex {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+	if len(m.OrderBy) > 0 {
+		for _, msg := range m.OrderBy {
+			data[i] = 0x12
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
 		}
 	}
-	if len(m.JoinNodes) > 0 {
-		for _, e := range m.JoinNodes {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+	if m.Frame != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Frame.Size()))
+		n21, err := m.Frame.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n21
 	}
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	l = len(m.Raw)
-	n += 1 + l + sovSql(uint64(l))
-	l = len(m.Name)
-	n += 1 + l + sovSql(uint64(l))
-	l = len(m.Alias)
-	n += 1 + l + sovSql(uint64(l))
-	n += 1 + sovSql(uint64(m.Op))
-	n += 1 + sovSql(uint64(m.LeftOrRight))
-	n += 1 + sovSql(uint64(m.JoinType))
-	if m.JoinExpr != nil {
-		l = m.JoinExpr.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.SubQuery != nil {
-		l = m.SubQuery.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	n += 2
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(data[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *SqlWherePb) Size() (n int) {
-	var l int
-	_ = l
-	n += 1 + sovSql(uint64(m.Op))
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Expr != nil {
-		l = m.Expr.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+func (m *SqlWindowFramePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return data[:n], nil
 }
 
-func (m *ProjectionPb) Size() (n int) {
+func (m *SqlWindowFramePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	n += 2
-	n += 2
-	if len(m.ColNames) > 0 {
-		for _, s := range m.ColNames {
-			l = len(s)
-			n += 1 + l + sovSql(uint64(l))
+	data[i] = 0x8
+	i++
+	if m.Range {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	if m.Start != nil {
+		data[i] = 0x12
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Start.Size()))
+		n22, err := m.Start.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n22
 	}
-	if len(m.Columns) > 0 {
-		for _, e := range m.Columns {
-			l = e.Size()
-			n += 1 + l + sovSql(uint64(l))
+	if m.End != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.End.Size()))
+		n23, err := m.End.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n23
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(data[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *ResultColumnPb) Size() (n int) {
+func (m *SqlWindowBoundPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlWindowBoundPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Final != nil {
-		n += 2
+	data[i] = 0x8
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Type))
+	data[i] = 0x10
+	i++
+	if m.Unbounded {
+		data[i] = 1
+	} else {
+		data[i] = 0
 	}
-	l = len(m.Name)
-	n += 1 + l + sovSql(uint64(l))
-	n += 1 + sovSql(uint64(m.ColPos))
-	if m.Column != nil {
-		l = m.Column.Size()
-		n += 1 + l + sovSql(uint64(l))
+	i++
+	data[i] = 0x18
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Offset))
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
 	}
-	if m.Star != nil {
-		n += 2
+	return i, nil
+}
+
+func (m *SqlWithPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlWithPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0xa
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Raw)))
+	i += copy(data[i:], m.Raw)
+	data[i] = 0x10
+	i++
+	if m.Recursive {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	if len(m.Ctes) > 0 {
+		for _, msg := range m.Ctes {
+			data[i] = 0x1a
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Stmt != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Stmt.Size()))
+		n25, err := m.Stmt.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n25
 	}
-	l = len(m.As)
-	n += 1 + l + sovSql(uint64(l))
-	n += 1 + sovSql(uint64(m.ValueType))
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(data[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *KvInt) Size() (n int) {
+func (m *SqlCtePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlCtePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.K)
-	n += 1 + l + sovSql(uint64(l))
-	n += 1 + sovSql(uint64(m.V))
+	data[i] = 0xa
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Name)))
+	i += copy(data[i:], m.Name)
+	if len(m.Cols) > 0 {
+		for _, s := range m.Cols {
+			data[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
+	if m.Stmt != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Stmt.Size()))
+		n26, err := m.Stmt.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(data[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *ColumnPb) Size() (n int) {
+func encodeFixed64Sql(data []byte, offset int, v uint64) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	data[offset+4] = uint8(v >> 32)
+	data[offset+5] = uint8(v >> 40)
+	data[offset+6] = uint8(v >> 48)
+	data[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Sql(data []byte, offset int, v uint32) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintSql(data []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		data[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	data[offset] = uint8(v)
+	return offset + 1
+}
+func (m *SqlStatementPb) Size() (n int) {
 	var l int
 	_ = l
-	if m.SourceQuote != nil {
-		l = len(m.SourceQuote)
+	if m.Select != nil {
+		l = m.Select.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
-	if m.AsQuoteByte != nil {
-		l = len(m.AsQuoteByte)
+	if m.Source != nil {
+		l = m.Source.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
-	if m.OriginalAs != nil {
-		l = len(*m.OriginalAs)
+	if m.Projection != nil {
+		l = m.Projection.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
-	if m.Left != nil {
-		l = len(*m.Left)
+	if m.SetOperation != nil {
+		l = m.SetOperation.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
-	if m.Right != nil {
-		l = len(*m.Right)
+	if m.With != nil {
+		l = m.With.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
-	n += 1 + sovSql(uint64(m.ParentIndex))
-	n += 1 + sovSql(uint64(m.Index))
-	n += 1 + sovSql(uint64(m.SourceIndex))
-	if m.SourceField != nil {
-		l = len(*m.SourceField)
-		n += 1 + l + sovSql(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
-	l = len(m.As)
+	return n
+}
+
+func (m *SqlSelectPb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Db)
 	n += 1 + l + sovSql(uint64(l))
-	if m.Comment != nil {
-		l = len(*m.Comment)
+	l = len(m.Raw)
+	n += 1 + l + sovSql(uint64(l))
+	n += 2
+	n += 2
+	if len(m.Columns) > 0 {
+		for _, e := range m.Columns {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if len(m.From) > 0 {
+		for _, e := range m.From {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.Into != nil {
+		l = len(*m.Into)
 		n += 1 + l + sovSql(uint64(l))
 	}
-	if m.Order != nil {
-		l = len(*m.Order)
+	if m.Where != nil {
+		l = m.Where.Size()
 		n += 1 + l + sovSql(uint64(l))
 	}
-	if m.Star != nil {
-		n += 2
+	if m.Having != nil {
+		l = m.Having.Size()
+		n += 1 + l + sovSql(uint64(l))
 	}
-	n += 2
-	if m.Expr != nil {
-		l = m.Expr.Size()
-		n += 2 + l + sovSql(uint64(l))
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
 	}
-	if m.Guard != nil {
-		l = m.Guard.Size()
+	if len(m.GroupBy) > 0 {
+		for _, e := range m.GroupBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	n += 1 + sovSql(uint64(m.Limit))
+	n += 1 + sovSql(uint64(m.Offset))
+	if m.Alias != nil {
+		l = len(*m.Alias)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Projection != nil {
+		l = m.Projection.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	n += 3
+	n += 3
+	n += 3
+	if m.With != nil {
+		l = len(m.With)
 		n += 2 + l + sovSql(uint64(l))
 	}
 	if m.XXX_unrecognized != nil {
@@ -1726,35 +2209,1174 @@ func (m *ColumnPb) Size() (n int) {
 	return n
 }
 
-func (m *CommandColumnPb) Size() (n int) {
+func (m *SqlSourcePb) Size() (n int) {
 	var l int
 	_ = l
-	if m.Expr != nil {
-		l = m.Expr.Size()
+	n += 2
+	if m.AliasInner != nil {
+		l = len(*m.AliasInner)
 		n += 1 + l + sovSql(uint64(l))
 	}
-	l = len(m.Name)
-	n += 1 + l + sovSql(uint64(l))
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if len(m.Columns) > 0 {
+		for _, e := range m.Columns {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
 	}
-	return n
-}
-
-func sovSql(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+	if len(m.ColIndex) > 0 {
+		for _, e := range m.ColIndex {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
 		}
 	}
-	return n
+	if len(m.JoinNodes) > 0 {
+		for _, e := range m.JoinNodes {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.Source != nil {
+		l = m.Source.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	l = len(m.Raw)
+	n += 1 + l + sovSql(uint64(l))
+	l = len(m.Name)
+	n += 1 + l + sovSql(uint64(l))
+	l = len(m.Alias)
+	n += 1 + l + sovSql(uint64(l))
+	n += 1 + sovSql(uint64(m.Op))
+	n += 1 + sovSql(uint64(m.LeftOrRight))
+	n += 1 + sovSql(uint64(m.JoinType))
+	if m.JoinExpr != nil {
+		l = m.JoinExpr.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.SubQuery != nil {
+		l = m.SubQuery.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	n += 2
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
 }
-func sozSql(x uint64) (n int) {
-	return sovSql(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+func (m *SqlWherePb) Size() (n int) {
+	var l int
+	_ = l
+	n += 1 + sovSql(uint64(m.Op))
+	if m.Source != nil {
+		l = m.Source.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Expr != nil {
+		l = m.Expr.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	n += 2
+	if m.Left != nil {
+		l = m.Left.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if len(m.SubQueries) > 0 {
+		for _, e := range m.SubQueries {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ProjectionPb) Size() (n int) {
+	var l int
+	_ = l
+	n += 2
+	n += 2
+	if len(m.ColNames) > 0 {
+		for _, s := range m.ColNames {
+			l = len(s)
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if len(m.Columns) > 0 {
+		for _, e := range m.Columns {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ResultColumnPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Final != nil {
+		n += 2
+	}
+	l = len(m.Name)
+	n += 1 + l + sovSql(uint64(l))
+	n += 1 + sovSql(uint64(m.ColPos))
+	if m.Column != nil {
+		l = m.Column.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Star != nil {
+		n += 2
+	}
+	l = len(m.As)
+	n += 1 + l + sovSql(uint64(l))
+	n += 1 + sovSql(uint64(m.ValueType))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *KvInt) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.K)
+	n += 1 + l + sovSql(uint64(l))
+	n += 1 + sovSql(uint64(m.V))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ColumnPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.SourceQuote != nil {
+		l = len(m.SourceQuote)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.AsQuoteByte != nil {
+		l = len(m.AsQuoteByte)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.OriginalAs != nil {
+		l = len(*m.OriginalAs)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Left != nil {
+		l = len(*m.Left)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Right != nil {
+		l = len(*m.Right)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	n += 1 + sovSql(uint64(m.ParentIndex))
+	n += 1 + sovSql(uint64(m.Index))
+	n += 1 + sovSql(uint64(m.SourceIndex))
+	if m.SourceField != nil {
+		l = len(*m.SourceField)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	l = len(m.As)
+	n += 1 + l + sovSql(uint64(l))
+	if m.Comment != nil {
+		l = len(*m.Comment)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Order != nil {
+		l = len(*m.Order)
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Star != nil {
+		n += 2
+	}
+	n += 2
+	if m.Expr != nil {
+		l = m.Expr.Size()
+		n += 2 + l + sovSql(uint64(l))
+	}
+	if m.Guard != nil {
+		l = m.Guard.Size()
+		n += 2 + l + sovSql(uint64(l))
+	}
+	if m.Over != nil {
+		l = m.Over.Size()
+		n += 2 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CommandColumnPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Expr != nil {
+		l = m.Expr.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	l = len(m.Name)
+	n += 1 + l + sovSql(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlSetOperationPb) Size() (n int) {
+	var l int
+	_ = l
+	n += 1 + sovSql(uint64(m.Op))
+	n += 2
+	if m.Left != nil {
+		l = m.Left.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Right != nil {
+		l = m.Right.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	n += 1 + sovSql(uint64(m.Limit))
+	n += 1 + sovSql(uint64(m.Offset))
+	l = len(m.Raw)
+	n += 1 + l + sovSql(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlWindowPb) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.PartitionBy) > 0 {
+		for _, e := range m.PartitionBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.Frame != nil {
+		l = m.Frame.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlWindowFramePb) Size() (n int) {
+	var l int
+	_ = l
+	n += 2
+	if m.Start != nil {
+		l = m.Start.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.End != nil {
+		l = m.End.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlWindowBoundPb) Size() (n int) {
+	var l int
+	_ = l
+	n += 1 + sovSql(uint64(m.Type))
+	n += 2
+	n += 1 + sovSql(uint64(m.Offset))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlWithPb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Raw)
+	n += 1 + l + sovSql(uint64(l))
+	n += 2
+	if len(m.Ctes) > 0 {
+		for _, e := range m.Ctes {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.Stmt != nil {
+		l = m.Stmt.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlCtePb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovSql(uint64(l))
+	if len(m.Cols) > 0 {
+		for _, s := range m.Cols {
+			l = len(s)
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.Stmt != nil {
+		l = m.Stmt.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovSql(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozSql(x uint64) (n int) {
+	return sovSql(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *SqlStatementPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlStatementPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlStatementPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Source == nil {
+				m.Source = &SqlSourcePb{}
+			}
+			if err := m.Source.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Projection", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Projection == nil {
+				m.Projection = &ProjectionPb{}
+			}
+			if err := m.Projection.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetOperation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SetOperation == nil {
+				m.SetOperation = &SqlSetOperationPb{}
+			}
+			if err := m.SetOperation.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field With", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.With == nil {
+				m.With = &SqlWithPb{}
+			}
+			if err := m.With.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SqlSelectPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlSelectPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlSelectPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Db", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Db = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Raw = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000002)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Star", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Star = bool(v != 0)
+			hasFields[0] |= uint64(0x00000004)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Distinct = bool(v != 0)
+			hasFields[0] |= uint64(0x00000008)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Columns = append(m.Columns, &ColumnPb{})
+			if err := m.Columns[len(m.Columns)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.From = append(m.From, &SqlSourcePb{})
+			if err := m.From[len(m.From)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Into", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(data[iNdEx:postIndex])
+			m.Into = &s
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Where", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Where == nil {
+				m.Where = &SqlWherePb{}
+			}
+			if err := m.Where.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Having", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Having == nil {
+				m.Having = &expr.NodePb{}
+			}
+			if err := m.Having.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = append(m.OrderBy, &ColumnPb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GroupBy = append(m.GroupBy, &ColumnPb{})
+			if err := m.GroupBy[len(m.GroupBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Limit |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Offset |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Alias", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(data[iNdEx:postIndex])
+			m.Alias = &s
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Projection", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Projection == nil {
+				m.Projection = &ProjectionPb{}
+			}
+			if err := m.Projection.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsAgg", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsAgg = bool(v != 0)
+			hasFields[0] |= uint64(0x00000010)
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Finalized", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Finalized = bool(v != 0)
+			hasFields[0] |= uint64(0x00000020)
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schemaqry", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Schemaqry = bool(v != 0)
+			hasFields[0] |= uint64(0x00000040)
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field With", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.With = append(m.With[:0], data[iNdEx:postIndex]...)
+			if m.With == nil {
+				m.With = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000004) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000008) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000010) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000020) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000040) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *SqlStatementPb) Unmarshal(data []byte) error {
+func (m *SqlSourcePb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1764,30 +3386,264 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowSql
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlSourcePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlSourcePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Final = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AliasInner", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(data[iNdEx:postIndex])
+			m.AliasInner = &s
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Columns = append(m.Columns, &ColumnPb{})
+			if err := m.Columns[len(m.Columns)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ColIndex", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ColIndex = append(m.ColIndex, KvInt{})
+			if err := m.ColIndex[len(m.ColIndex)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JoinNodes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JoinNodes = append(m.JoinNodes, &expr.NodePb{})
+			if err := m.JoinNodes[len(m.JoinNodes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Source == nil {
+				m.Source = &SqlSelectPb{}
+			}
+			if err := m.Source.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Raw = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
 			}
-			b := data[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SqlStatementPb: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SqlStatementPb: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.Name = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Alias", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -1797,28 +3653,84 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Select == nil {
-				m.Select = &SqlSelectPb{}
+			m.Alias = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
 			}
-			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			m.Op = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Op |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 2:
+			hasFields[0] |= uint64(0x00000001)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeftOrRight", wireType)
+			}
+			m.LeftOrRight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.LeftOrRight |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000002)
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JoinType", wireType)
+			}
+			m.JoinType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.JoinType |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000004)
+		case 13:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field JoinExpr", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1842,16 +3754,16 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Source == nil {
-				m.Source = &SqlSourcePb{}
+			if m.JoinExpr == nil {
+				m.JoinExpr = &expr.NodePb{}
 			}
-			if err := m.Source.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.JoinExpr.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
+		case 14:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Projection", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SubQuery", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1875,13 +3787,33 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Projection == nil {
-				m.Projection = &ProjectionPb{}
+			if m.SubQuery == nil {
+				m.SubQuery = &SqlSelectPb{}
 			}
-			if err := m.Projection.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.SubQuery.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seekable", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Seekable = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -1898,13 +3830,22 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 			iNdEx += skippy
 		}
 	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000004) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *SqlSelectPb) Unmarshal(data []byte) error {
+func (m *SqlWherePb) Unmarshal(data []byte) error {
 	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
@@ -1928,98 +3869,17 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SqlSelectPb: wiretype end group for non-group")
+			return fmt.Errorf("proto: SqlWherePb: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SqlSelectPb: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Db", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Db = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
-			hasFields[0] |= uint64(0x00000001)
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Raw = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
-			hasFields[0] |= uint64(0x00000002)
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Star", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Star = bool(v != 0)
-			hasFields[0] |= uint64(0x00000004)
-		case 4:
+			return fmt.Errorf("proto: SqlWherePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
 			}
-			var v int
+			m.Op = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2029,16 +3889,15 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.Op |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Distinct = bool(v != 0)
-			hasFields[0] |= uint64(0x00000008)
-		case 5:
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2062,14 +3921,16 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Columns = append(m.Columns, &ColumnPb{})
-			if err := m.Columns[len(m.Columns)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if m.Source == nil {
+				m.Source = &SqlSelectPb{}
+			}
+			if err := m.Source.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 6:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Expr", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2093,16 +3954,18 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.From = append(m.From, &SqlSourcePb{})
-			if err := m.From[len(m.From)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if m.Expr == nil {
+				m.Expr = &expr.NodePb{}
+			}
+			if err := m.Expr.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Into", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Not", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2112,25 +3975,15 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			s := string(data[iNdEx:postIndex])
-			m.Into = &s
-			iNdEx = postIndex
-		case 8:
+			m.Not = bool(v != 0)
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Where", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2154,16 +4007,16 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Where == nil {
-				m.Where = &SqlWherePb{}
+			if m.Left == nil {
+				m.Left = &expr.NodePb{}
 			}
-			if err := m.Where.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.Left.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 9:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Having", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SubQueries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2187,18 +4040,71 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Having == nil {
-				m.Having = &expr.NodePb{}
-			}
-			if err := m.Having.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			m.SubQueries = append(m.SubQueries, &SqlWherePb{})
+			if err := m.SubQueries[len(m.SubQueries)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var msglen int
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ProjectionPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProjectionPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProjectionPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+			}
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2208,28 +4114,39 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.Distinct = bool(v != 0)
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
 			}
-			m.OrderBy = append(m.OrderBy, &ColumnPb{})
-			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 11:
+			m.Final = bool(v != 0)
+			hasFields[0] |= uint64(0x00000002)
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ColNames", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2239,28 +4156,26 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GroupBy = append(m.GroupBy, &ColumnPb{})
-			if err := m.GroupBy[len(m.GroupBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ColNames = append(m.ColNames, string(data[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 12:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
 			}
-			m.Limit = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2270,16 +4185,86 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Limit |= (int32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 13:
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Columns = append(m.Columns, &ResultColumnPb{})
+			if err := m.Columns[len(m.Columns)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResultColumnPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResultColumnPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResultColumnPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
 			}
-			m.Offset = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2289,14 +4274,16 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Offset |= (int32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 14:
+			b := bool(v != 0)
+			m.Final = &b
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Alias", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2321,12 +4308,32 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			s := string(data[iNdEx:postIndex])
-			m.Alias = &s
+			m.Name = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 15:
+			hasFields[0] |= uint64(0x00000001)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ColPos", wireType)
+			}
+			m.ColPos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.ColPos |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000002)
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Projection", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Column", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2350,16 +4357,16 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Projection == nil {
-				m.Projection = &ProjectionPb{}
+			if m.Column == nil {
+				m.Column = &ColumnPb{}
 			}
-			if err := m.Projection.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.Column.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 16:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IsAgg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Star", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -2376,13 +4383,13 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 					break
 				}
 			}
-			m.IsAgg = bool(v != 0)
-			hasFields[0] |= uint64(0x00000010)
-		case 17:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Finalized", wireType)
+			b := bool(v != 0)
+			m.Star = &b
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field As", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2392,39 +4399,27 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Finalized = bool(v != 0)
-			hasFields[0] |= uint64(0x00000020)
-		case 18:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Schemaqry", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
 			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.Schemaqry = bool(v != 0)
-			hasFields[0] |= uint64(0x00000040)
-		case 19:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field With", wireType)
+			m.As = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000004)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueType", wireType)
 			}
-			var byteLen int
+			m.ValueType = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2434,23 +4429,12 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.ValueType |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.With = append(m.With[:0], data[iNdEx:postIndex]...)
-			if m.With == nil {
-				m.With = []byte{}
-			}
-			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000008)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -2479,22 +4463,13 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 	if hasFields[0]&uint64(0x00000008) == 0 {
 		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
 	}
-	if hasFields[0]&uint64(0x00000010) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-	if hasFields[0]&uint64(0x00000020) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-	if hasFields[0]&uint64(0x00000040) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *SqlSourcePb) Unmarshal(data []byte) error {
+func (m *KvInt) Unmarshal(data []byte) error {
 	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
@@ -2518,35 +4493,15 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SqlSourcePb: wiretype end group for non-group")
+			return fmt.Errorf("proto: KvInt: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SqlSourcePb: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: KvInt: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Final = bool(v != 0)
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AliasInner", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field K", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2571,14 +4526,14 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			s := string(data[iNdEx:postIndex])
-			m.AliasInner = &s
+			m.K = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field V", wireType)
 			}
-			var msglen int
+			m.V = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2588,59 +4543,74 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.V |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Columns = append(m.Columns, &ColumnPb{})
-			if err := m.Columns[len(m.Columns)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+			hasFields[0] |= uint64(0x00000002)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ColIndex", wireType)
+			if skippy < 0 {
+				return ErrInvalidLengthSql
 			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSql
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ColumnPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
 			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ColIndex = append(m.ColIndex, KvInt{})
-			if err := m.ColIndex[len(m.ColIndex)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 5:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ColumnPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ColumnPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JoinNodes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceQuote", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2650,28 +4620,28 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.JoinNodes = append(m.JoinNodes, &expr.NodePb{})
-			if err := m.JoinNodes[len(m.JoinNodes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			m.SourceQuote = append(m.SourceQuote[:0], data[iNdEx:postIndex]...)
+			if m.SourceQuote == nil {
+				m.SourceQuote = []byte{}
 			}
 			iNdEx = postIndex
-		case 6:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AsQuoteByte", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2681,28 +4651,26 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Source == nil {
-				m.Source = &SqlSelectPb{}
-			}
-			if err := m.Source.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			m.AsQuoteByte = append(m.AsQuoteByte[:0], data[iNdEx:postIndex]...)
+			if m.AsQuoteByte == nil {
+				m.AsQuoteByte = []byte{}
 			}
 			iNdEx = postIndex
-		case 7:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OriginalAs", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2727,11 +4695,12 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Raw = string(data[iNdEx:postIndex])
+			s := string(data[iNdEx:postIndex])
+			m.OriginalAs = &s
 			iNdEx = postIndex
-		case 8:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2756,11 +4725,12 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(data[iNdEx:postIndex])
+			s := string(data[iNdEx:postIndex])
+			m.Left = &s
 			iNdEx = postIndex
-		case 9:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Alias", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2785,13 +4755,14 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Alias = string(data[iNdEx:postIndex])
+			s := string(data[iNdEx:postIndex])
+			m.Right = &s
 			iNdEx = postIndex
-		case 10:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentIndex", wireType)
 			}
-			m.Op = 0
+			m.ParentIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2801,17 +4772,16 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Op |= (int32(b) & 0x7F) << shift
+				m.ParentIndex |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000001)
-		case 11:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LeftOrRight", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
 			}
-			m.LeftOrRight = 0
+			m.Index = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2821,17 +4791,16 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.LeftOrRight |= (int32(b) & 0x7F) << shift
+				m.Index |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000002)
-		case 12:
+		case 8:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JoinType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceIndex", wireType)
 			}
-			m.JoinType = 0
+			m.SourceIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2841,17 +4810,16 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.JoinType |= (int32(b) & 0x7F) << shift
+				m.SourceIndex |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000004)
-		case 13:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JoinExpr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceField", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2861,30 +4829,27 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.JoinExpr == nil {
-				m.JoinExpr = &expr.NodePb{}
-			}
-			if err := m.JoinExpr.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			s := string(data[iNdEx:postIndex])
+			m.SourceField = &s
 			iNdEx = postIndex
-		case 14:
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SubQuery", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field As", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2894,30 +4859,26 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SubQuery == nil {
-				m.SubQuery = &SqlSelectPb{}
-			}
-			if err := m.SubQuery.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.As = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 15:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Seekable", wireType)
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Comment", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -2927,78 +4888,98 @@ func (m *SqlSourcePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Seekable = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipSql(data[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			if (iNdEx + skippy) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-	if hasFields[0]&uint64(0x00000001) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-	if hasFields[0]&uint64(0x00000002) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-	if hasFields[0]&uint64(0x00000004) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SqlWherePb) Unmarshal(data []byte) error {
-	var hasFields [1]uint64
-	l := len(data)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowSql
+			s := string(data[iNdEx:postIndex])
+			m.Comment = &s
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Order", wireType)
 			}
-			if iNdEx >= l {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			s := string(data[iNdEx:postIndex])
+			m.Order = &s
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Star", wireType)
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SqlWherePb: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SqlWherePb: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.Star = &b
+		case 15:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Agg", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.Op = 0
+			m.Agg = bool(v != 0)
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expr", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3008,15 +4989,28 @@ func (m *SqlWherePb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Op |= (int32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000001)
-		case 2:
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Expr == nil {
+				m.Expr = &expr.NodePb{}
+			}
+			if err := m.Expr.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Guard", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3040,16 +5034,16 @@ func (m *SqlWherePb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Source == nil {
-				m.Source = &SqlSelectPb{}
+			if m.Guard == nil {
+				m.Guard = &expr.NodePb{}
 			}
-			if err := m.Source.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.Guard.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		case 18:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Expr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Over", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3073,10 +5067,10 @@ func (m *SqlWherePb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Expr == nil {
-				m.Expr = &expr.NodePb{}
+			if m.Over == nil {
+				m.Over = &SqlWindowPb{}
 			}
-			if err := m.Expr.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.Over.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -3096,16 +5090,13 @@ func (m *SqlWherePb) Unmarshal(data []byte) error {
 			iNdEx += skippy
 		}
 	}
-	if hasFields[0]&uint64(0x00000001) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *ProjectionPb) Unmarshal(data []byte) error {
+func (m *CommandColumnPb) Unmarshal(data []byte) error {
 	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
@@ -3129,59 +5120,17 @@ func (m *ProjectionPb) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ProjectionPb: wiretype end group for non-group")
+			return fmt.Errorf("proto: CommandColumnPb: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ProjectionPb: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CommandColumnPb: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Distinct = bool(v != 0)
-			hasFields[0] |= uint64(0x00000001)
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Final = bool(v != 0)
-			hasFields[0] |= uint64(0x00000002)
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ColNames", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Expr", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3191,26 +5140,30 @@ func (m *ProjectionPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ColNames = append(m.ColNames, string(data[iNdEx:postIndex]))
+			if m.Expr == nil {
+				m.Expr = &expr.NodePb{}
+			}
+			if err := m.Expr.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3220,23 +5173,22 @@ func (m *ProjectionPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Columns = append(m.Columns, &ResultColumnPb{})
-			if err := m.Columns[len(m.Columns)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Name = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -3256,16 +5208,13 @@ func (m *ProjectionPb) Unmarshal(data []byte) error {
 	if hasFields[0]&uint64(0x00000001) == 0 {
 		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
 	}
-	if hasFields[0]&uint64(0x00000002) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *ResultColumnPb) Unmarshal(data []byte) error {
+func (m *SqlSetOperationPb) Unmarshal(data []byte) error {
 	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
@@ -3289,15 +5238,35 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ResultColumnPb: wiretype end group for non-group")
+			return fmt.Errorf("proto: SqlSetOperationPb: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ResultColumnPb: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SqlSetOperationPb: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+			}
+			m.Op = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Op |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field All", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -3314,13 +5283,13 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 					break
 				}
 			}
-			b := bool(v != 0)
-			m.Final = &b
-		case 2:
+			m.All = bool(v != 0)
+			hasFields[0] |= uint64(0x00000002)
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3330,27 +5299,31 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(data[iNdEx:postIndex])
+			if m.Left == nil {
+				m.Left = &SqlStatementPb{}
+			}
+			if err := m.Left.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-			hasFields[0] |= uint64(0x00000001)
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ColPos", wireType)
+			hasFields[0] |= uint64(0x00000004)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
 			}
-			m.ColPos = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3360,15 +5333,29 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.ColPos |= (int32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000002)
-		case 4:
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Right == nil {
+				m.Right = &SqlStatementPb{}
+			}
+			if err := m.Right.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000008)
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Column", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3392,18 +5379,16 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Column == nil {
-				m.Column = &ColumnPb{}
-			}
-			if err := m.Column.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			m.OrderBy = append(m.OrderBy, &ColumnPb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Star", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			var v int
+			m.Limit = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3413,18 +5398,16 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.Limit |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			b := bool(v != 0)
-			m.Star = &b
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field As", wireType)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			var stringLen uint64
+			m.Offset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3434,27 +5417,16 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Offset |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.As = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
-			hasFields[0] |= uint64(0x00000004)
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ValueType", wireType)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
 			}
-			m.ValueType = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3464,12 +5436,21 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.ValueType |= (int32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000008)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Raw = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -3504,8 +5485,7 @@ func (m *ResultColumnPb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *KvInt) Unmarshal(data []byte) error {
-	var hasFields [1]uint64
+func (m *SqlWindowPb) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3528,17 +5508,17 @@ func (m *KvInt) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: KvInt: wiretype end group for non-group")
+			return fmt.Errorf("proto: SqlWindowPb: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: KvInt: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SqlWindowPb: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field K", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PartitionBy", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3548,27 +5528,28 @@ func (m *KvInt) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.K = string(data[iNdEx:postIndex])
+			m.PartitionBy = append(m.PartitionBy, &expr.NodePb{})
+			if err := m.PartitionBy[len(m.PartitionBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-			hasFields[0] |= uint64(0x00000001)
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field V", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
 			}
-			m.V = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3578,12 +5559,56 @@ func (m *KvInt) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.V |= (int32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			hasFields[0] |= uint64(0x00000002)
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = append(m.OrderBy, &ColumnPb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Frame", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Frame == nil {
+				m.Frame = &SqlWindowFramePb{}
+			}
+			if err := m.Frame.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -3600,19 +5625,14 @@ func (m *KvInt) Unmarshal(data []byte) error {
 			iNdEx += skippy
 		}
 	}
-	if hasFields[0]&uint64(0x00000001) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
-	if hasFields[0]&uint64(0x00000002) == 0 {
-		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
-	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *ColumnPb) Unmarshal(data []byte) error {
+func (m *SqlWindowFramePb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3635,17 +5655,17 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ColumnPb: wiretype end group for non-group")
+			return fmt.Errorf("proto: SqlWindowFramePb: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ColumnPb: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SqlWindowFramePb: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SourceQuote", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
 			}
-			var byteLen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3655,28 +5675,18 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.SourceQuote = append(m.SourceQuote[:0], data[iNdEx:postIndex]...)
-			if m.SourceQuote == nil {
-				m.SourceQuote = []byte{}
-			}
-			iNdEx = postIndex
+			m.Range = bool(v != 0)
+			hasFields[0] |= uint64(0x00000001)
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AsQuoteByte", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3686,28 +5696,31 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AsQuoteByte = append(m.AsQuoteByte[:0], data[iNdEx:postIndex]...)
-			if m.AsQuoteByte == nil {
-				m.AsQuoteByte = []byte{}
+			if m.Start == nil {
+				m.Start = &SqlWindowBoundPb{}
+			}
+			if err := m.Start.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000002)
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OriginalAs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3717,106 +5730,92 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			s := string(data[iNdEx:postIndex])
-			m.OriginalAs = &s
-			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
+			if m.End == nil {
+				m.End = &SqlWindowBoundPb{}
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			if err := m.End.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			s := string(data[iNdEx:postIndex])
-			m.Left = &s
 			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			hasFields[0] |= uint64(0x00000004)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			s := string(data[iNdEx:postIndex])
-			m.Right = &s
-			iNdEx = postIndex
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ParentIndex", wireType)
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000004) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SqlWindowBoundPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
 			}
-			m.ParentIndex = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				m.ParentIndex |= (int32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-		case 7:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlWindowBoundPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlWindowBoundPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			m.Index = 0
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3826,16 +5825,17 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Index |= (int32(b) & 0x7F) << shift
+				m.Type |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SourceIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Unbounded", wireType)
 			}
-			m.SourceIndex = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3845,16 +5845,18 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.SourceIndex |= (int32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SourceField", wireType)
+			m.Unbounded = bool(v != 0)
+			hasFields[0] |= uint64(0x00000002)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			var stringLen uint64
+			m.Offset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -3864,84 +5866,76 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Offset |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			s := string(data[iNdEx:postIndex])
-			m.SourceField = &s
-			iNdEx = postIndex
-		case 11:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field As", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			hasFields[0] |= uint64(0x00000004)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.As = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 12:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Comment", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSql
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000004) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SqlWithPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			s := string(data[iNdEx:postIndex])
-			m.Comment = &s
-			iNdEx = postIndex
-		case 13:
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlWithPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlWithPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Order", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3966,33 +5960,11 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			s := string(data[iNdEx:postIndex])
-			m.Order = &s
+			m.Raw = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 14:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Star", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSql
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			b := bool(v != 0)
-			m.Star = &b
-		case 15:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Agg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Recursive", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -4009,10 +5981,11 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 					break
 				}
 			}
-			m.Agg = bool(v != 0)
-		case 16:
+			m.Recursive = bool(v != 0)
+			hasFields[0] |= uint64(0x00000001)
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Expr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Ctes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4036,16 +6009,14 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Expr == nil {
-				m.Expr = &expr.NodePb{}
-			}
-			if err := m.Expr.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			m.Ctes = append(m.Ctes, &SqlCtePb{})
+			if err := m.Ctes[len(m.Ctes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 17:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Guard", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Stmt", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4069,13 +6040,14 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Guard == nil {
-				m.Guard = &expr.NodePb{}
+			if m.Stmt == nil {
+				m.Stmt = &SqlStatementPb{}
 			}
-			if err := m.Guard.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.Stmt.Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000002)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -4092,13 +6064,19 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 			iNdEx += skippy
 		}
 	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *CommandColumnPb) Unmarshal(data []byte) error {
+func (m *SqlCtePb) Unmarshal(data []byte) error {
 	var hasFields [1]uint64
 	l := len(data)
 	iNdEx := 0
@@ -4122,17 +6100,17 @@ func (m *CommandColumnPb) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CommandColumnPb: wiretype end group for non-group")
+			return fmt.Errorf("proto: SqlCtePb: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CommandColumnPb: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SqlCtePb: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Expr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSql
@@ -4142,28 +6120,25 @@ func (m *CommandColumnPb) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSql
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Expr == nil {
-				m.Expr = &expr.NodePb{}
-			}
-			if err := m.Expr.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Name = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cols", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4188,9 +6163,42 @@ func (m *CommandColumnPb) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(data[iNdEx:postIndex])
+			m.Cols = append(m.Cols, string(data[iNdEx:postIndex]))
 			iNdEx = postIndex
-			hasFields[0] |= uint64(0x00000001)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stmt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stmt == nil {
+				m.Stmt = &SqlStatementPb{}
+			}
+			if err := m.Stmt.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000002)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -4210,6 +6218,9 @@ func (m *CommandColumnPb) Unmarshal(data []byte) error {
 	if hasFields[0]&uint64(0x00000001) == 0 {
 		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
 	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
@@ -4322,72 +6333,94 @@ var (
 )
 
 var fileDescriptorSql = []byte{
-	// 1067 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0x0e, 0x25, 0x51, 0x96, 0x56, 0x8e, 0xed, 0x6c, 0x82, 0x62, 0x61, 0x14, 0xae, 0x41, 0x14,
-	0x81, 0xd1, 0x34, 0x52, 0x91, 0x1e, 0x7a, 0x8e, 0x83, 0xb6, 0x30, 0x0a, 0xa4, 0x8e, 0x5c, 0xa0,
-	0x67, 0x4a, 0x5c, 0x51, 0x8c, 0x29, 0xae, 0xbc, 0x24, 0xe5, 0x38, 0x4f, 0xd2, 0x4b, 0x81, 0x5e,
-	0x7b, 0xeb, 0x33, 0xf4, 0xe4, 0x63, 0x9f, 0xa0, 0xe8, 0x0f, 0xfa, 0x1e, 0x9d, 0x99, 0x25, 0x97,
-	0x6b, 0x47, 0x72, 0x72, 0x10, 0xc0, 0xfd, 0xe6, 0xdb, 0xdd, 0x99, 0xd9, 0x6f, 0x66, 0xc4, 0xfa,
-	0xf9, 0x45, 0x3a, 0x5c, 0x6a, 0x55, 0x28, 0xde, 0xd6, 0x32, 0xdd, 0x7f, 0x12, 0x27, 0xc5, 0xbc,
-	0x9c, 0x0c, 0xa7, 0x6a, 0x31, 0x0a, 0x75, 0x18, 0x45, 0x2a, 0x1b, 0x5d, 0xa4, 0x13, 0x9d, 0x44,
-	0xb1, 0x1c, 0xc9, 0x37, 0x4b, 0x3d, 0xca, 0x54, 0x24, 0xcd, 0x8e, 0xfd, 0xa7, 0x0e, 0x39, 0x56,
-	0xb1, 0x1a, 0x11, 0x3c, 0x29, 0x67, 0xb4, 0xa2, 0x05, 0x7d, 0x19, 0x7a, 0xf0, 0xab, 0xc7, 0x76,
-	0xce, 0x2e, 0xd2, 0xb3, 0x22, 0x2c, 0xe4, 0x42, 0x66, 0xc5, 0xe9, 0x84, 0x0f, 0x59, 0x37, 0x97,
-	0xa9, 0x9c, 0x16, 0xc2, 0x3b, 0xf4, 0x8e, 0x06, 0xcf, 0xf6, 0x86, 0xe0, 0xc4, 0x10, 0x49, 0x84,
-	0x9e, 0x4e, 0x8e, 0x3b, 0xd7, 0x7f, 0x7e, 0xe2, 0x8d, 0x2b, 0x16, 0xf1, 0x55, 0xa9, 0xa7, 0x52,
-	0xb4, 0x6e, 0xf1, 0x09, 0x75, 0xf8, 0xb4, 0xe6, 0x5f, 0x31, 0x06, 0x77, 0xbf, 0x86, 0xad, 0x89,
-	0xca, 0x44, 0x87, 0xf6, 0x3c, 0xa0, 0x3d, 0xa7, 0x16, 0xb6, 0x9b, 0x1c, 0x6a, 0xf0, 0x9b, 0xcf,
-	0x06, 0x8e, 0x1b, 0xfc, 0x11, 0x6b, 0x45, 0x13, 0x70, 0xb2, 0x75, 0xd4, 0x27, 0xf6, 0xbd, 0x31,
-	0xac, 0xf9, 0x47, 0xac, 0xad, 0xc3, 0x4b, 0xf0, 0xa5, 0x81, 0x11, 0xe0, 0x82, 0x75, 0xf2, 0x22,
-	0xd4, 0xa2, 0x0d, 0x86, 0x5e, 0x65, 0x20, 0x84, 0x1f, 0xb2, 0x5e, 0x94, 0xe4, 0x45, 0x92, 0x41,
-	0xc8, 0x1d, 0xc7, 0x6a, 0x51, 0xfe, 0x94, 0x6d, 0x4d, 0x55, 0x5a, 0x2e, 0xb2, 0x5c, 0xf8, 0x87,
-	0x6d, 0xf0, 0xf7, 0x3e, 0xf9, 0xfb, 0x82, 0x30, 0xeb, 0x6b, 0xcd, 0xe1, 0x9f, 0xb1, 0xce, 0x4c,
-	0xab, 0x85, 0xe8, 0x12, 0x77, 0x53, 0x3e, 0x88, 0x83, 0x6e, 0x25, 0x59, 0xa1, 0xc4, 0x16, 0xe4,
-	0xa1, 0x5f, 0x5b, 0x10, 0xe1, 0x4f, 0x98, 0x7f, 0x39, 0x97, 0x5a, 0x8a, 0x1e, 0xa5, 0x68, 0xb7,
-	0x3e, 0xe6, 0x47, 0x04, 0xed, 0x29, 0x86, 0x03, 0x57, 0x76, 0xe7, 0xe1, 0x2a, 0xc9, 0x62, 0xd1,
-	0x27, 0xf6, 0xf6, 0x10, 0x85, 0x31, 0x7c, 0x09, 0xc2, 0x68, 0x1e, 0xc0, 0x30, 0x30, 0x1a, 0xa5,
-	0x23, 0xa9, 0x8f, 0xaf, 0x04, 0xbb, 0x23, 0x9a, 0x8a, 0x83, 0xf4, 0x58, 0xab, 0x72, 0x09, 0xf4,
-	0xc1, 0x1d, 0xf4, 0x8a, 0xc3, 0xf7, 0x99, 0x9f, 0x26, 0x8b, 0xa4, 0x10, 0xdb, 0xe0, 0x88, 0x5f,
-	0xa5, 0xd2, 0x40, 0xfc, 0x63, 0xd6, 0x55, 0xb3, 0x59, 0x2e, 0x0b, 0x71, 0xdf, 0x31, 0x56, 0x18,
-	0xee, 0x0c, 0xd3, 0x24, 0xcc, 0xc5, 0x8e, 0x93, 0x0b, 0x03, 0xdd, 0x12, 0xcd, 0xee, 0x07, 0x8b,
-	0x06, 0x0f, 0x4d, 0xf2, 0xe7, 0x71, 0x2c, 0xf6, 0x9c, 0x97, 0x35, 0x10, 0x0f, 0x58, 0x7f, 0x96,
-	0x64, 0x70, 0xc1, 0x5b, 0x19, 0x89, 0x07, 0x8e, 0xbd, 0x81, 0x91, 0x93, 0x4f, 0xe7, 0x72, 0x11,
-	0x5e, 0xe8, 0x2b, 0xc1, 0x5d, 0x8e, 0x85, 0xf1, 0x0d, 0x2f, 0xa1, 0xea, 0xc4, 0x43, 0x70, 0x6b,
-	0xbb, 0x7e, 0x43, 0x44, 0x82, 0xdf, 0x3b, 0x46, 0xb2, 0xd5, 0xcb, 0xa3, 0x37, 0x74, 0x34, 0x95,
-	0x96, 0xf5, 0x86, 0x20, 0xfe, 0x29, 0x63, 0x14, 0xeb, 0x49, 0x96, 0x49, 0x4d, 0xb5, 0x54, 0xe7,
-	0xc0, 0xc1, 0x5d, 0x29, 0xb6, 0x3f, 0x40, 0x8a, 0x9f, 0xb3, 0x1e, 0x7c, 0x9e, 0x64, 0x91, 0x7c,
-	0x03, 0xda, 0x46, 0x3e, 0x23, 0xfe, 0x77, 0xab, 0x93, 0xac, 0xa8, 0x75, 0x5e, 0x33, 0xf8, 0x17,
-	0xac, 0xff, 0x5a, 0x25, 0x19, 0xaa, 0xa6, 0x56, 0xfa, 0x3a, 0x21, 0x35, 0x24, 0xa7, 0xf8, 0xbb,
-	0xef, 0x69, 0x16, 0xa6, 0xf8, 0xab, 0xea, 0x6c, 0xd4, 0xde, 0x54, 0x67, 0x16, 0x2e, 0x8c, 0xd6,
-	0x6b, 0x03, 0x21, 0x8d, 0x2a, 0xfa, 0x8e, 0xa9, 0x52, 0x05, 0x74, 0x00, 0xb5, 0x04, 0x11, 0xb7,
-	0xac, 0x96, 0x60, 0xcd, 0x1f, 0xb3, 0x41, 0x2a, 0x67, 0xc5, 0xf7, 0x7a, 0x9c, 0xc4, 0xf3, 0x02,
-	0x44, 0xdb, 0x98, 0x5d, 0x03, 0xd6, 0x3d, 0x06, 0xf2, 0xc3, 0xd5, 0x52, 0x82, 0x58, 0x1b, 0x92,
-	0x45, 0x21, 0x3a, 0xfa, 0xfe, 0x1a, 0x32, 0x40, 0x8a, 0x5d, 0x9f, 0x0e, 0xcb, 0xe1, 0xcf, 0x58,
-	0x2f, 0x2f, 0x27, 0xaf, 0x4a, 0x09, 0x5a, 0xd9, 0xb9, 0x33, 0x1f, 0x96, 0x87, 0x5e, 0xe4, 0x52,
-	0x9e, 0x87, 0x93, 0x54, 0x92, 0xae, 0x6d, 0xf7, 0xa9, 0xd1, 0xe0, 0x2d, 0x63, 0x4d, 0xd9, 0x57,
-	0x31, 0x7b, 0xb7, 0x62, 0xde, 0xdc, 0x84, 0xd7, 0xbf, 0xc3, 0x63, 0xd6, 0xa1, 0xa8, 0xda, 0x1b,
-	0xa3, 0xea, 0x20, 0x14, 0xfc, 0xec, 0xb1, 0x6d, 0xb7, 0xc2, 0x6e, 0x34, 0x4b, 0x6f, 0x6d, 0xb3,
-	0xb4, 0x1a, 0x6f, 0xb9, 0x15, 0x67, 0x34, 0xbe, 0x4f, 0x72, 0x7c, 0x09, 0xef, 0x6a, 0xe4, 0xdb,
-	0x1f, 0xdb, 0x35, 0xff, 0xb2, 0x51, 0xb6, 0x51, 0xea, 0x43, 0x8a, 0x61, 0x2c, 0xf3, 0x32, 0x2d,
-	0x36, 0xe8, 0x3b, 0xf8, 0x0f, 0xe6, 0xd7, 0x4d, 0xc6, 0xba, 0x1a, 0xf3, 0xea, 0xfb, 0x6b, 0x99,
-	0xb9, 0xd3, 0xc1, 0xc8, 0x0c, 0x5a, 0x13, 0x9c, 0x79, 0xaa, 0x72, 0x1a, 0x10, 0xb6, 0x35, 0x19,
-	0x0c, 0x7a, 0x71, 0xd7, 0xdc, 0x58, 0xcd, 0xab, 0xb5, 0x45, 0x57, 0x51, 0xec, 0xa4, 0xf1, 0x9d,
-	0xfb, 0xcd, 0xa4, 0x81, 0xb7, 0x03, 0x21, 0x77, 0xdd, 0x89, 0x05, 0x2a, 0x86, 0x16, 0xb3, 0x0a,
-	0xd3, 0x52, 0x92, 0x10, 0xb7, 0x9c, 0xdb, 0x1b, 0x38, 0x18, 0x31, 0x9f, 0x4a, 0x96, 0x73, 0xe6,
-	0x9d, 0xdf, 0x98, 0x79, 0xde, 0x39, 0x62, 0x2b, 0x0a, 0xa9, 0xde, 0xe8, 0xad, 0x82, 0x5f, 0x3a,
-	0xac, 0x67, 0x53, 0x02, 0x15, 0x61, 0xde, 0xfd, 0x55, 0xa9, 0x0a, 0x49, 0x89, 0xa9, 0xfb, 0x94,
-	0x6b, 0x40, 0x5e, 0x98, 0xd3, 0xe7, 0xf1, 0x55, 0x61, 0xa4, 0x64, 0x79, 0x8e, 0x01, 0x5b, 0x95,
-	0xd2, 0x49, 0x8c, 0x29, 0x7d, 0x9e, 0x93, 0x86, 0x6c, 0xab, 0x6a, 0x70, 0xcc, 0x03, 0x96, 0x1b,
-	0xa5, 0xcc, 0x8e, 0x36, 0x44, 0xf0, 0x89, 0x34, 0xd5, 0xa6, 0xef, 0x76, 0x7a, 0x82, 0xd0, 0x87,
-	0x65, 0xa8, 0xe1, 0xaf, 0x88, 0x69, 0x5a, 0x5d, 0x67, 0x50, 0xb8, 0x06, 0x6a, 0xec, 0xc4, 0xd8,
-	0x72, 0xe7, 0x0c, 0x41, 0x4d, 0xbc, 0xe6, 0x8c, 0x9e, 0x7b, 0x86, 0x63, 0x68, 0x78, 0xdf, 0x24,
-	0x32, 0x8d, 0x9c, 0x0e, 0x63, 0xf3, 0x42, 0x86, 0xea, 0xdd, 0x06, 0x4e, 0x03, 0xc2, 0x77, 0x3b,
-	0x40, 0xc1, 0x2e, 0xf0, 0x5f, 0x13, 0xcd, 0xba, 0x7e, 0xa3, 0x4d, 0x02, 0xd1, 0x43, 0x9a, 0xa1,
-	0xd4, 0x3a, 0x6c, 0x94, 0x04, 0x59, 0x8d, 0xec, 0xbc, 0xa3, 0x11, 0xe8, 0x90, 0x21, 0x8c, 0x2b,
-	0xb7, 0x15, 0x20, 0x60, 0x2b, 0x76, 0xef, 0xee, 0x8a, 0xe5, 0x47, 0xcc, 0xff, 0xb6, 0x0c, 0x35,
-	0x0e, 0xb4, 0x4d, 0x44, 0x3f, 0x46, 0x42, 0x70, 0xc6, 0x76, 0x5f, 0x80, 0xab, 0x61, 0x16, 0x39,
-	0x42, 0x31, 0x97, 0x78, 0xef, 0xb9, 0x64, 0x63, 0x1d, 0x1d, 0x3f, 0xba, 0xfe, 0xfb, 0xc0, 0xbb,
-	0xfe, 0xe7, 0xc0, 0xfb, 0x03, 0x7e, 0x7f, 0xc1, 0xef, 0xa7, 0x7f, 0x0f, 0xee, 0xfd, 0x1f, 0x00,
-	0x00, 0xff, 0xff, 0xa8, 0x40, 0xb5, 0x23, 0xd3, 0x0a, 0x00, 0x00,
+	// 1417 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0x51, 0x6f, 0x1b, 0x45,
+	0x10, 0xee, 0x9e, 0xef, 0x1c, 0x7b, 0x9c, 0x26, 0xe9, 0xb6, 0x54, 0xab, 0x08, 0x05, 0xeb, 0x84,
+	0x8a, 0xd5, 0x12, 0x07, 0x0a, 0x82, 0x57, 0x9a, 0x8a, 0xa2, 0x0a, 0xa9, 0x4d, 0x1d, 0xa4, 0x3e,
+	0xa2, 0xb3, 0x6f, 0x73, 0xb9, 0xf6, 0x7c, 0xeb, 0xec, 0xed, 0x25, 0x35, 0xbf, 0x82, 0x17, 0x24,
+	0x5e, 0x10, 0x12, 0x12, 0xef, 0xfc, 0x06, 0x9e, 0xfa, 0xc0, 0x03, 0xbf, 0x00, 0x41, 0x11, 0x7f,
+	0x80, 0x5f, 0x80, 0x76, 0xee, 0x6e, 0x6f, 0xcf, 0xb1, 0x93, 0xf0, 0x16, 0x7f, 0xf3, 0xcd, 0xed,
+	0xec, 0xcc, 0x37, 0x33, 0x1b, 0xe8, 0x66, 0x27, 0xc9, 0x70, 0x26, 0x85, 0x12, 0xb4, 0x25, 0x79,
+	0xb2, 0x7d, 0x2f, 0x8a, 0xd5, 0x71, 0x3e, 0x1e, 0x4e, 0xc4, 0x74, 0x2f, 0x90, 0x41, 0x18, 0x8a,
+	0x74, 0xef, 0x24, 0x19, 0xcb, 0x38, 0x8c, 0xf8, 0x1e, 0x7f, 0x35, 0x93, 0x7b, 0xa9, 0x08, 0x79,
+	0xe1, 0xb1, 0xbd, 0x6b, 0x91, 0x23, 0x11, 0x89, 0x3d, 0x84, 0xc7, 0xf9, 0x11, 0xfe, 0xc2, 0x1f,
+	0xf8, 0x57, 0x41, 0xf7, 0xbf, 0x73, 0x60, 0xe3, 0xf0, 0x24, 0x39, 0x54, 0x81, 0xe2, 0x53, 0x9e,
+	0xaa, 0x83, 0x31, 0x1d, 0x42, 0x3b, 0xe3, 0x09, 0x9f, 0x28, 0x46, 0xfa, 0x64, 0xd0, 0xbb, 0xbf,
+	0x35, 0x94, 0x3c, 0x19, 0x6a, 0x12, 0xa2, 0x07, 0xe3, 0x7d, 0xf7, 0xf5, 0x1f, 0xef, 0x90, 0x51,
+	0xc9, 0x42, 0xbe, 0xc8, 0xe5, 0x84, 0x33, 0x67, 0x81, 0x8f, 0xa8, 0xc5, 0xc7, 0xdf, 0xf4, 0x53,
+	0x80, 0x99, 0x14, 0x2f, 0xf8, 0x44, 0xc5, 0x22, 0x65, 0x2e, 0xfa, 0xdc, 0x40, 0x9f, 0x03, 0x03,
+	0x1b, 0x27, 0x8b, 0x4a, 0x3f, 0x83, 0xf5, 0x8c, 0xab, 0xa7, 0x33, 0x2e, 0x03, 0x74, 0xf5, 0xd0,
+	0xf5, 0x76, 0x1d, 0x5e, 0x6d, 0x33, 0xfe, 0x0d, 0x0f, 0x3a, 0x00, 0xf7, 0x2c, 0x56, 0xc7, 0xac,
+	0x8d, 0x9e, 0x1b, 0x95, 0xe7, 0xf3, 0x58, 0x1d, 0x1b, 0x0f, 0x64, 0xf8, 0xbf, 0x78, 0xd0, 0xb3,
+	0xae, 0x4c, 0x6f, 0x81, 0x13, 0x8e, 0x19, 0xe9, 0x3b, 0x83, 0x2e, 0xf2, 0xae, 0x8d, 0x9c, 0x70,
+	0x4c, 0x6f, 0x43, 0x4b, 0x06, 0x67, 0xcc, 0xb1, 0x60, 0x0d, 0x50, 0x06, 0x6e, 0xa6, 0x02, 0xc9,
+	0x5a, 0x7d, 0x67, 0xd0, 0x29, 0x0d, 0x88, 0xd0, 0x3e, 0x74, 0xc2, 0x38, 0x53, 0x71, 0x3a, 0x51,
+	0xcc, 0xb5, 0xac, 0x06, 0xa5, 0xbb, 0xb0, 0x36, 0x11, 0x49, 0x3e, 0x4d, 0x33, 0xe6, 0xf5, 0x5b,
+	0x83, 0xde, 0xfd, 0xeb, 0x18, 0xe6, 0x43, 0xc4, 0x4c, 0x94, 0x15, 0x87, 0xde, 0x05, 0xf7, 0x48,
+	0x8a, 0x29, 0x6b, 0xf7, 0x5b, 0x17, 0xe4, 0x1e, 0x39, 0x3a, 0xac, 0x38, 0x55, 0x82, 0xad, 0xf5,
+	0x49, 0x19, 0x2f, 0x19, 0x21, 0x42, 0xef, 0x81, 0x77, 0x76, 0xcc, 0x25, 0x67, 0x1d, 0xcc, 0xcc,
+	0xa6, 0xc9, 0x8c, 0x06, 0xcd, 0x57, 0x0a, 0x0e, 0xbd, 0x0b, 0xed, 0xe3, 0xe0, 0x34, 0x4e, 0x23,
+	0xd6, 0x45, 0xf6, 0xfa, 0x50, 0x8b, 0x70, 0xf8, 0x44, 0x84, 0x56, 0xb1, 0x0b, 0x86, 0xbe, 0x4d,
+	0x24, 0x45, 0x3e, 0xdb, 0x9f, 0xb3, 0xde, 0x05, 0xb7, 0x29, 0x39, 0x9a, 0x2e, 0x64, 0xc8, 0xe5,
+	0xfe, 0x9c, 0xc1, 0x05, 0xf4, 0x92, 0x43, 0xb7, 0xc1, 0x4b, 0xe2, 0x69, 0xac, 0xd8, 0x7a, 0x9f,
+	0x0c, 0xbc, 0x32, 0x95, 0x05, 0x44, 0xdf, 0x86, 0xb6, 0x38, 0x3a, 0xca, 0xb8, 0x62, 0xd7, 0x2d,
+	0x63, 0x89, 0x69, 0xcf, 0x20, 0x89, 0x83, 0x8c, 0x6d, 0x58, 0xb9, 0x28, 0xa0, 0x05, 0x81, 0x6e,
+	0x5e, 0x5d, 0xa0, 0xdb, 0xe0, 0xc5, 0xd9, 0x83, 0x28, 0x62, 0x5b, 0x56, 0x65, 0x0b, 0x88, 0xfa,
+	0xd0, 0x3d, 0x8a, 0xd3, 0x20, 0x89, 0xbf, 0xe1, 0x21, 0xbb, 0x61, 0xd9, 0x6b, 0x58, 0x73, 0xb2,
+	0xc9, 0x31, 0x9f, 0x06, 0x27, 0x72, 0xce, 0xa8, 0xcd, 0x31, 0xb0, 0xae, 0x21, 0x4a, 0xf8, 0x66,
+	0x9f, 0x0c, 0xd6, 0x1b, 0x92, 0xfd, 0xd5, 0x85, 0x9e, 0x55, 0x79, 0x1d, 0x0d, 0x7e, 0x1a, 0xdb,
+	0xd8, 0x44, 0x83, 0x10, 0x7d, 0x17, 0x00, 0xef, 0xfa, 0x38, 0x4d, 0xb9, 0x64, 0x8e, 0x95, 0x03,
+	0x0b, 0xb7, 0xa5, 0xd8, 0xba, 0x82, 0x14, 0xdf, 0x87, 0xce, 0x44, 0x24, 0x8f, 0xd3, 0x90, 0xbf,
+	0x62, 0x2e, 0xf2, 0x01, 0xf9, 0x5f, 0x9e, 0x3e, 0x4e, 0x55, 0xa5, 0xf3, 0x8a, 0x41, 0x3f, 0x80,
+	0xee, 0x0b, 0x11, 0xa7, 0x5a, 0x35, 0x95, 0xd2, 0x97, 0x09, 0xa9, 0x26, 0x59, 0x83, 0xa6, 0x7d,
+	0xc9, 0x60, 0x42, 0x56, 0xd5, 0x9d, 0xb5, 0xda, 0xeb, 0xee, 0x4c, 0x83, 0x69, 0xa1, 0xf5, 0xca,
+	0x80, 0x48, 0xad, 0x8a, 0xae, 0x65, 0x2a, 0x20, 0x3d, 0x01, 0xc4, 0x8c, 0x41, 0xdf, 0x31, 0x5a,
+	0x72, 0xc4, 0x8c, 0xde, 0x81, 0x5e, 0xc2, 0x8f, 0xd4, 0x53, 0x39, 0x8a, 0xa3, 0x63, 0xc5, 0x7a,
+	0x96, 0xd9, 0x36, 0xe8, 0xbe, 0xd7, 0x17, 0xf9, 0x6a, 0x3e, 0xe3, 0x6c, 0xdd, 0x22, 0x19, 0x94,
+	0x0e, 0x0b, 0xc6, 0xe7, 0xaf, 0x66, 0x12, 0x15, 0xbb, 0x3c, 0x1d, 0x86, 0x43, 0xef, 0x43, 0x27,
+	0xcb, 0xc7, 0xcf, 0x72, 0x2e, 0xe7, 0x6c, 0xe3, 0xc2, 0x7c, 0x18, 0x9e, 0x8e, 0x22, 0xe3, 0xfc,
+	0x65, 0x30, 0x4e, 0x38, 0xdb, 0xb4, 0x54, 0x61, 0x50, 0xff, 0x5f, 0x02, 0x50, 0xf7, 0x7d, 0x79,
+	0x69, 0xb2, 0x70, 0xe9, 0xd5, 0x13, 0x7f, 0x79, 0x21, 0xee, 0x80, 0x8b, 0xd7, 0x6a, 0xad, 0xbc,
+	0x16, 0xda, 0x75, 0xc1, 0x52, 0xa1, 0x98, 0x6b, 0x45, 0xa6, 0x01, 0xed, 0xaf, 0x73, 0xc9, 0xbc,
+	0xd5, 0xfe, 0xda, 0x4e, 0x3f, 0x81, 0x5e, 0x96, 0x8f, 0xbf, 0x3e, 0xc9, 0xb9, 0x8c, 0x79, 0x56,
+	0x8e, 0xc4, 0x15, 0xb3, 0x0c, 0xca, 0xa4, 0xc4, 0x3c, 0xf3, 0x7f, 0x20, 0xb0, 0x6e, 0xb7, 0x76,
+	0x63, 0x4a, 0x93, 0xa5, 0x53, 0xda, 0x34, 0x97, 0x63, 0xb7, 0x3a, 0x42, 0x74, 0x1b, 0xfb, 0xe0,
+	0x49, 0x30, 0xe5, 0x45, 0xdf, 0x74, 0x47, 0xe6, 0x37, 0xfd, 0xa8, 0x6e, 0xa9, 0xa2, 0x45, 0x6e,
+	0x62, 0x78, 0x23, 0x9e, 0xe5, 0x89, 0x5a, 0xd1, 0x58, 0xfe, 0x3f, 0x04, 0x36, 0x9a, 0x8c, 0x65,
+	0xcd, 0x4d, 0xaa, 0xf3, 0x2b, 0x7d, 0xdb, 0x6b, 0x09, 0x11, 0x3d, 0x13, 0x27, 0x22, 0x39, 0x10,
+	0x19, 0x6b, 0x59, 0x25, 0x2d, 0x31, 0x7a, 0x0f, 0xad, 0xf9, 0xb4, 0x5a, 0xca, 0x4b, 0xbb, 0xbd,
+	0xa4, 0x98, 0x15, 0xe7, 0x59, 0xe7, 0x23, 0xa2, 0x35, 0x13, 0xe8, 0xe4, 0x5b, 0xab, 0x32, 0xc8,
+	0xf4, 0x6c, 0x3b, 0x0d, 0x92, 0x9c, 0x63, 0x07, 0xac, 0x59, 0xa7, 0xd7, 0xb0, 0xbf, 0x07, 0x1e,
+	0xce, 0x0a, 0x4a, 0x81, 0xbc, 0x6c, 0x2c, 0x5b, 0xf2, 0x52, 0x63, 0xa7, 0xcc, 0xb1, 0x1c, 0xc9,
+	0xa9, 0xff, 0x9b, 0x0b, 0x1d, 0x93, 0x92, 0x3b, 0xd0, 0x2b, 0xf4, 0xf6, 0x2c, 0x17, 0x8a, 0x33,
+	0x62, 0x0d, 0x48, 0xdb, 0xa0, 0x79, 0x41, 0x86, 0x7f, 0xee, 0xcf, 0x55, 0x21, 0x61, 0xc3, 0xb3,
+	0x0c, 0x7a, 0x46, 0x0a, 0x19, 0x47, 0x3a, 0xa5, 0x0f, 0x32, 0xd4, 0xae, 0x99, 0x91, 0x35, 0xae,
+	0xf3, 0x80, 0xda, 0x74, 0x2d, 0x3b, 0x22, 0xba, 0x44, 0x12, 0x87, 0x82, 0x67, 0x99, 0x0a, 0x48,
+	0xc7, 0x30, 0x0b, 0x24, 0x4f, 0x55, 0x31, 0x2d, 0xdb, 0xd6, 0x86, 0xb2, 0x0d, 0xb8, 0x51, 0x90,
+	0xb1, 0x66, 0x2f, 0x38, 0x84, 0xea, 0xfb, 0x16, 0xdf, 0xe8, 0xd8, 0xdf, 0xb0, 0x0c, 0x35, 0xef,
+	0x51, 0xcc, 0x93, 0xd0, 0x1a, 0x6d, 0x64, 0x64, 0x1b, 0xca, 0xba, 0xf5, 0xfa, 0xa4, 0x51, 0xb7,
+	0x1d, 0x2d, 0xd8, 0xa9, 0x7e, 0x1a, 0xb2, 0x75, 0x63, 0x22, 0xa3, 0x0a, 0xd4, 0x11, 0xe2, 0x36,
+	0x66, 0xd7, 0x2d, 0x6b, 0x01, 0x19, 0x8d, 0x6c, 0x9c, 0xd3, 0xc8, 0x6d, 0x68, 0x05, 0x51, 0xd4,
+	0x98, 0x41, 0x1a, 0x30, 0x93, 0x62, 0xeb, 0x92, 0x49, 0x31, 0x00, 0xef, 0x8b, 0x3c, 0x90, 0x7a,
+	0x93, 0xae, 0x22, 0x16, 0x04, 0xfd, 0x3e, 0x12, 0xa7, 0x5c, 0x32, 0xda, 0x9c, 0x54, 0xcf, 0xe3,
+	0x34, 0x14, 0x67, 0xf5, 0x57, 0x35, 0xc7, 0x3f, 0x84, 0xcd, 0x87, 0x62, 0x3a, 0x0d, 0xd2, 0xd0,
+	0x12, 0x55, 0x11, 0x10, 0xb9, 0x24, 0xa0, 0x95, 0x3d, 0xe7, 0xff, 0xe4, 0xc0, 0x8d, 0x73, 0xaf,
+	0xd3, 0x15, 0x83, 0x55, 0xa7, 0x25, 0x69, 0xce, 0x14, 0x0d, 0xd0, 0xdd, 0x52, 0x64, 0xba, 0x6b,
+	0xab, 0x91, 0xd1, 0x7c, 0xb5, 0x37, 0x94, 0xb7, 0x57, 0x29, 0xcf, 0xbd, 0x8c, 0x5f, 0xca, 0xd1,
+	0x7a, 0x76, 0x79, 0xff, 0xe7, 0xd9, 0xd5, 0xbe, 0xe8, 0xd9, 0xb5, 0xb6, 0xe4, 0xd9, 0x55, 0xae,
+	0xe4, 0xce, 0xc2, 0x4a, 0xf6, 0x7f, 0x26, 0xd0, 0xb3, 0xaa, 0x42, 0x3f, 0xc6, 0xfe, 0x50, 0xb1,
+	0xce, 0xd6, 0xfe, 0x9c, 0x91, 0x95, 0xcf, 0x03, 0x9b, 0x66, 0x5f, 0xc3, 0xb9, 0xc2, 0x35, 0x3e,
+	0x04, 0xef, 0x48, 0xea, 0xa2, 0x15, 0x7b, 0xe9, 0xad, 0xa6, 0x36, 0x1e, 0x69, 0x53, 0x9d, 0x28,
+	0x64, 0xfa, 0xdf, 0x12, 0xd8, 0x5a, 0x64, 0x60, 0xa3, 0x07, 0x69, 0xc4, 0x1b, 0xab, 0xa2, 0x80,
+	0xf4, 0x19, 0x5a, 0xf0, 0x0a, 0x6b, 0x7a, 0xee, 0x8c, 0x7d, 0x91, 0xa7, 0x61, 0x7d, 0x06, 0x32,
+	0xe9, 0x2e, 0xb4, 0x78, 0x1a, 0xb2, 0xd6, 0xe5, 0x0e, 0x9a, 0xe7, 0xa7, 0xb0, 0xb5, 0x68, 0xd6,
+	0x6a, 0x54, 0xf3, 0x59, 0x11, 0x50, 0x55, 0x02, 0x44, 0xf4, 0x18, 0xce, 0xd3, 0xb1, 0xa6, 0xf1,
+	0xb0, 0xa1, 0xb3, 0x1a, 0xb6, 0x4a, 0xa8, 0x63, 0x68, 0x35, 0x4b, 0xe8, 0xff, 0x48, 0xa0, 0x6b,
+	0xfe, 0x67, 0xaa, 0x0a, 0x4a, 0x16, 0xdf, 0x58, 0x3e, 0x74, 0x25, 0x9f, 0xe4, 0x32, 0x8b, 0x4f,
+	0x79, 0xf3, 0x1c, 0x03, 0xd3, 0xf7, 0xc0, 0x9d, 0x28, 0xde, 0x7c, 0x5b, 0x1e, 0x9e, 0x24, 0x0f,
+	0x95, 0xd5, 0x5c, 0x9a, 0xa0, 0xe5, 0x9f, 0xa9, 0xe9, 0x15, 0xe4, 0x8c, 0x34, 0x3f, 0x82, 0x4e,
+	0xf5, 0x19, 0xd3, 0x97, 0xe4, 0xdc, 0x2e, 0xa4, 0xe0, 0x4e, 0x44, 0x92, 0xa1, 0x52, 0xba, 0x23,
+	0xfc, 0xdb, 0x1c, 0xd4, 0xba, 0xd2, 0x41, 0xfb, 0xb7, 0x5e, 0xff, 0xb5, 0x43, 0x5e, 0xbf, 0xd9,
+	0x21, 0xbf, 0xbf, 0xd9, 0x21, 0x7f, 0xbe, 0xd9, 0x21, 0xdf, 0xff, 0xbd, 0x73, 0xed, 0xbf, 0x01,
+	0x00, 0x0b, 0xc4, 0x65, 0x12, 0xbf, 0x0f, 0x00, 0x00,
 }
@@ -10,6 +10,18 @@ import (
 
 var pbTests = []string{
 	"SELECT hash(a) AS id, `z` FROM nothing;",
+	"SELECT a FROM t WHERE b > 1 AND c NOT IN (SELECT c FROM z) AND EXISTS (SELECT 1 FROM y WHERE y.a = t.a)",
+	"SELECT a, lag(a, 1) OVER (PARTITION BY b ORDER BY c DESC) AS l, sum(d) OVER (ORDER BY c ROWS BETWEEN UNBOUNDED PRECEDING AND 1 FOLLOWING) AS s FROM t",
+}
+
+var pbSetOperationTests = []string{
+	"SELECT a FROM x UNION SELECT b FROM y",
+	"SELECT a FROM x EXCEPT ALL SELECT b FROM y INTERSECT SELECT c FROM z ORDER BY a LIMIT 5 OFFSET 2",
+}
+
+var pbWithTests = []string{
+	"WITH a AS (SELECT x FROM t ORDER BY x LIMIT 2) SELECT x FROM a",
+	"WITH RECURSIVE a (x, y) AS (SELECT x, y FROM t UNION ALL SELECT t.x, t.y FROM t INNER JOIN a ON t.y = a.x), b AS (SELECT x FROM a) SELECT x FROM a UNION SELECT x FROM b ORDER BY x LIMIT 3",
 }
 
 func TestPb(t *testing.T) {
@@ -27,6 +39,27 @@ func TestPb(t *testing.T) {
 		assert.T(t, ss.Equal(ss2), "Equal?")
 		u.Infof("pre/post: \n\t%s\n\t%s", ss, ss2)
 	}
+	for _, sql := range pbSetOperationTests {
+		s, err := ParseSql(sql)
+		assert.Tf(t, err == nil, "Should not error on parse sql but got [%v] for %s", err, sql)
+		so := s.(*SqlSetOperation)
+		pbBytes, err := proto.Marshal(so.ToPbStatement())
+		assert.Tf(t, err == nil, "Should not error on proto.Marshal but got [%v] for %s", err, sql)
+		so2, err := SqlFromPb(pbBytes)
+		assert.Tf(t, err == nil, "Should not error from pb but got [%v] for %s ", err, sql)
+		assert.T(t, so.Equal(so2), "Equal?")
+		assert.Tf(t, so.String() == so2.String(), "pre/post: \n\t%s\n\t%s", so, so2)
+	}
+	for _, sql := range pbWithTests {
+		s, err := ParseSql(sql)
+		assert.Tf(t, err == nil, "Should not error on parse sql but got [%v] for %s", err, sql)
+		w := s.(*SqlWith)
+		pbBytes, err := proto.Marshal(w.ToPbStatement())
+		assert.Tf(t, err == nil, "Should not error on proto.Marshal but got [%v] for %s", err, sql)
+		w2, err := SqlFromPb(pbBytes)
+		assert.Tf(t, err == nil, "Should not error from pb but got [%v] for %s ", err, sql)
+		assert.Tf(t, w.Equal(w2), "pre/post: \n\t%s\n\t%s", w, w2)
+	}
 }
 
 var _ = u.EMPTY
@@ -98,6 +98,30 @@ type (
 		//Conn
 		Columns() []string
 	}
+	// ConnFilter Interface for a data source connection that applies filters
+	//  of the WHERE itself (predicate pushdown), ie a key-value store that can
+	//  look up rows by key instead of a full scan.  The WHERE is split into
+	//  its AND'd conjuncts, the rows the connection scans must match each of
+	//  the conjuncts it reports as handled.  The rest are filtered by a Where
+	//  task after the source.
+	ConnFilter interface {
+		// PushFilters given the conjuncts, returns for each if it's handled
+		PushFilters(conjuncts []expr.Node) []bool
+	}
+	// ConnProjection Interface for a data source connection that can read
+	//  only some of its columns (projection pushdown), ie a csv file that
+	//  doesn't have to materialize every field of a row.  It is told the
+	//  minimal set of columns the query uses, the messages it scans then
+	//  only have values (and col index) of those columns.
+	ConnProjection interface {
+		// ProjectColumns the columns needed, empty if none are
+		ProjectColumns(cols []string)
+	}
+	// ConnRowCount Interface for a data source connection that knows (or
+	//  estimates) how many rows it has, used for EXPLAIN row estimates
+	ConnRowCount interface {
+		RowCount() int64
+	}
 	// ConnScanner is the most basic of data sources, just iterate through
 	//  rows without any optimizations.  Key-Value store like csv, redis, cassandra.
 	ConnScanner interface {
@@ -119,6 +143,8 @@ type (
 		// expressions, find out with CanSeek for given expression
 		CanSeek(*rel.SqlSelect) bool
 		Get(key driver.Value) (Message, error)
+		// MultiGet returns the rows found for keys, keys that are not
+		// found are skipped (not an error), so len(rows) <= len(keys)
 		MultiGet(keys []driver.Value) ([]Message, error)
 	}
 	// ConnMutation creates a Mutator connection similar to Open() connection for select
@@ -143,6 +169,21 @@ type (
 	ConnPatchWhere interface {
 		PatchWhere(ctx context.Context, where expr.Node, patch interface{}) (int64, error)
 	}
+	// ConnTransaction Interface for a data source connection whose writes
+	//  can be grouped into a transaction, ie the statements of a sql driver
+	//  Tx.  The writes of the transaction are not seen by other connections
+	//  until Commit.
+	ConnTransaction interface {
+		Begin() (ConnTx, error)
+	}
+	// ConnTx A transaction begun by ConnTransaction
+	ConnTx interface {
+		// Conn a new connection for a statement of the transaction, it
+		// reads and writes in the transaction, closing it does not end it
+		Conn() (Conn, error)
+		Commit() error
+		Rollback() error
+	}
 	// ConnDeletion deletion interface for data sources
 	ConnDeletion interface {
 		// Delete using this key
@@ -284,6 +284,88 @@ func Equal(itemA, itemB Value) (bool, error) {
 	return false, fmt.Errorf("Could not evaluate equals")
 }
 
+// Compare two values for ordering
+//
+//   returns int, error
+//      -1 if a < b,  0 if a == b,  1 if a > b
+//      error if they could not be compared
+//
+//   nil (sql NULL) sorts before every other value
+//   int, number, bool       =>    compared as float64
+//   time                    =>    compared as time
+//   string, []byte, other   =>    compared as string, unless other side is numeric
+func Compare(itemA, itemB Value) (int, error) {
+	aNil := itemA == nil || itemA.Type() == NilType
+	bNil := itemB == nil || itemB.Type() == NilType
+	switch {
+	case aNil && bNil:
+		return 0, nil
+	case aNil:
+		return -1, nil
+	case bNil:
+		return 1, nil
+	}
+
+	if at, ok := itemA.(TimeValue); ok {
+		if bt, ok := itemB.(TimeValue); ok {
+			switch {
+			case at.Val().Before(bt.Val()):
+				return -1, nil
+			case at.Val().After(bt.Val()):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+
+	if isNumericType(itemA.Type()) || isNumericType(itemB.Type()) {
+		af, aok := compareFloat(itemA)
+		bf, bok := compareFloat(itemB)
+		if !aok || !bok {
+			return 0, fmt.Errorf("Could not compare %s to %s", itemA.Type(), itemB.Type())
+		}
+		switch {
+		case af < bf:
+			return -1, nil
+		case af > bf:
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return strings.Compare(itemA.ToString(), itemB.ToString()), nil
+}
+
+func isNumericType(vt ValueType) bool {
+	switch vt {
+	case IntType, NumberType, BoolType:
+		return true
+	}
+	return false
+}
+
+// float value of item used for ordering comparisons
+func compareFloat(v Value) (float64, bool) {
+	switch vt := v.(type) {
+	case IntValue:
+		return vt.Float(), true
+	case NumberValue:
+		return vt.Float(), true
+	case TimeValue:
+		return vt.Float(), true
+	case BoolValue:
+		if vt.Val() {
+			return 1, true
+		}
+		return 0, true
+	}
+	fv, ok := ToFloat64(v.Rv())
+	if !ok || math.IsNaN(fv) {
+		return 0, false
+	}
+	return fv, true
+}
+
 // ToString convert all reflect.Value-s into string.
 func ToString(v reflect.Value) (string, bool) {
 	if v.Kind() == reflect.Interface {
@@ -5,6 +5,7 @@ import (
 	"github.com/bmizerany/assert"
 	//"reflect"
 	"testing"
+	"time"
 )
 
 var _ = u.EMPTY
@@ -80,3 +81,31 @@ func TestCoerceNumbers(t *testing.T) {
 		assert.Tf(t, CloseEnuf(floatVal, cv.f), "should be == expect %v but was: %v", cv.f, floatVal)
 	}
 }
+
+type compareTest struct {
+	a, b Value
+	c    int
+}
+
+var compareTests = []compareTest{
+	{NewIntValue(1), NewIntValue(2), -1},
+	{NewIntValue(2), NewNumberValue(2.0), 0},
+	{NewNumberValue(3.5), NewIntValue(2), 1},
+	{NewStringValue("abc"), NewStringValue("abd"), -1},
+	{NewStringValue("10"), NewIntValue(9), 1},
+	{NewBoolValue(true), NewBoolValue(false), 1},
+	{NewNilValue(), NewIntValue(-5), -1},
+	{NewStringValue(""), NewNilValue(), 1},
+	{nil, NewNilValue(), 0},
+	{NewTimeValue(time.Unix(100, 0)), NewTimeValue(time.Unix(50, 0)), 1},
+}
+
+func TestCompare(t *testing.T) {
+	for _, ct := range compareTests {
+		c, err := Compare(ct.a, ct.b)
+		assert.Tf(t, err == nil, "Nil err? %v", err)
+		assert.Tf(t, c == ct.c, "expected %d but got %d for %v, %v", ct.c, c, ct.a, ct.b)
+	}
+	_, err := Compare(NewStringValue("abc"), NewIntValue(1))
+	assert.Tf(t, err != nil, "Should error comparing non-numeric string to int")
+}
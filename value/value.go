@@ -855,6 +855,12 @@ func NewNilValue() NilValue {
 	return NilValue{}
 }
 
+// IsNull is true for the sql NULL, a nil Value or one whose Nil() is
+// true: the NilValue and empty strings, slices and maps.
+func IsNull(v Value) bool {
+	return v == nil || v.Nil()
+}
+
 func (m NilValue) Nil() bool                         { return true }
 func (m NilValue) Err() bool                         { return false }
 func (m NilValue) Type() ValueType                   { return NilType }
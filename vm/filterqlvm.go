@@ -162,14 +162,19 @@ func (q *filterql) matchesFilter(cr expr.ContextReader, exp *rel.FilterExpr) (bo
 
 	case exp.Expr != nil:
 		// Hand it off to the single expression Evaluator
-		out, ok := Eval(cr, exp.Expr)
+		out, ok := Eval(cr, filterNullCompare(exp.Expr))
 		//u.Debugf("expr? %q out?%#v  ok?%v", exp.Expr.String(), out, ok)
-		if !ok || out == nil {
-			// VM unable to evaluate expression -> treat it as false
+		if isNull(out, ok) {
+			// VM unable to evaluate expression, or NULL -> treat it as false,
+			// FilterQL is two-valued so a missing field != value is true
+			match := false
+			if bn, isBinary := exp.Expr.(*expr.BinaryNode); isBinary && bn.Operator.T == lex.TokenNE {
+				match = true
+			}
 			if exp.Negate {
-				return true, nil
+				return !match, nil
 			}
-			return false, nil
+			return match, nil
 		}
 		//u.Infof("out? negate?%v  nil?%v err?%v  %#v", exp.Negate, out.Nil(), out.Err(), out.Value())
 		if out.Nil() {
@@ -206,3 +211,22 @@ func (q *filterql) matchesFilter(cr expr.ContextReader, exp *rel.FilterExpr) (bo
 		return false, fmt.Errorf("empty expression")
 	}
 }
+
+// filterNullCompare evaluates  x = NULL  and  x != NULL  as  x IS NULL  and
+// x IS NOT NULL, FilterQL is two-valued so these are never NULL.
+func filterNullCompare(n expr.Node) expr.Node {
+	bn, ok := n.(*expr.BinaryNode)
+	if !ok {
+		return n
+	}
+	if _, isNull := bn.Args[1].(*expr.NullNode); !isNull {
+		return n
+	}
+	switch bn.Operator.T {
+	case lex.TokenEqual, lex.TokenEqualEqual:
+		return expr.NewBinaryNode(lex.Token{T: lex.TokenIs, V: "IS"}, bn.Args[0], bn.Args[1])
+	case lex.TokenNE:
+		return expr.NewBinaryNode(lex.Token{T: lex.TokenIsNot, V: "IS NOT"}, bn.Args[0], bn.Args[1])
+	}
+	return n
+}
@@ -87,6 +87,9 @@ func TestFilterQlVm(t *testing.T) {
 		`FILTER Created < "now-1d"`,                     // Date Math
 		`FILTER Updated > "now-2h"`,                     // Date Math
 		`FILTER *`,                                      // match all
+		`FILTER name != NULL`,                           // is not null
+		`FILTER first_name == NULL`,                     // is null
+		`FILTER first_name IS NULL`,                     // is null
 		`FILTER OR (
 			EXISTS name,       -- inline comments
 			EXISTS not_a_key,  -- more inline comments
@@ -120,6 +123,8 @@ func TestFilterQlVm(t *testing.T) {
 		`FILTER name == "yoda"`, // casing
 		"FILTER OR (false, false, AND (true, false))",
 		`FILTER AND (name == "Yoda", city == "xxx", zip == 5)`,
+		`FILTER first_name != NULL`, // key doesn't exist
+		`FILTER name == NULL`,
 	}
 
 	for _, q := range misses {
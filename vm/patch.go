@@ -0,0 +1,66 @@
+package vm
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/value"
+)
+
+// PatchRow the values of row after applying the patch of an update (see
+// schema.ConnPatchWhere), the new values of columns:
+//
+//     map[string]driver.Value   values of columns
+//     map[string]expr.Node      expressions evaluated against row, ie SET x = x + 1
+//
+// colIndex is the position of each column in row, and reader the row as it
+// was before the update, which is not modified.
+func PatchRow(row []driver.Value, colIndex map[string]int, reader expr.ContextReader, patch interface{}) ([]driver.Value, error) {
+	newRow := make([]driver.Value, len(row))
+	copy(newRow, row)
+	switch patchVals := patch.(type) {
+	case map[string]driver.Value:
+		for key, val := range patchVals {
+			idx, ok := colIndex[key]
+			if !ok {
+				return nil, fmt.Errorf("no column %q to update", key)
+			}
+			newRow[idx] = val
+		}
+	case map[string]expr.Node:
+		for key, node := range patchVals {
+			idx, ok := colIndex[key]
+			if !ok {
+				return nil, fmt.Errorf("no column %q to update", key)
+			}
+			if vn, isValue := node.(*expr.ValueNode); isValue {
+				// values of the update are written as is, ie SET x = NULL
+				newRow[idx] = vn.Value.Value()
+				continue
+			}
+			val, ok := Eval(reader, node)
+			if !ok {
+				return nil, fmt.Errorf("Could not evaluate expression: %v", node)
+			}
+			newRow[idx] = val.Value()
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized patch type: %T", patch)
+	}
+	return newRow, nil
+}
+
+// Matches does the row of reader match the where expression of an update
+// or delete, a nil where matches every row
+func Matches(reader expr.ContextReader, where expr.Node) bool {
+	if where == nil {
+		return true
+	}
+	val, ok := Eval(reader, where)
+	if !ok {
+		return false
+	}
+	bv, isBool := val.(value.BoolValue)
+	return isBool && bv.Val()
+}
@@ -20,10 +20,8 @@ func EvalSql(sel *rel.SqlSelect, writeContext expr.ContextWriter, readContext ex
 
 		whereValue, ok := Eval(readContext, sel.Where.Expr)
 		if !ok {
-			// TODO:  seriously re-think this.   If the where clause is not able to evaluate
-			//     such as  WHERE contains(ip,"10.120.") due to missing IP, does that mean it is
-			//      logically true?   Would we not need to correctly evaluate and = true to filter?
-			//      Marek made a good point, they would need to expand logical statement to include OR
+			// A where clause only matches if it is true, NULL (such as
+			//   WHERE contains(ip,"10.120.") with a missing ip) does not match.
 			return false, nil
 		}
 		switch whereVal := whereValue.(type) {
@@ -181,6 +181,8 @@ func Evaluator(arg expr.Node) EvaluatorFunc {
 		return func(ctx expr.EvalContext) (value.Value, bool) { return walkTri(ctx, argVal) }
 	case *expr.ArrayNode:
 		return func(ctx expr.EvalContext) (value.Value, bool) { return walkArray(ctx, argVal) }
+	case *expr.CaseNode:
+		return func(ctx expr.EvalContext) (value.Value, bool) { return walkCase(ctx, argVal) }
 	default:
 		u.Errorf("Unknonwn node type:  %T", argVal)
 		panic(ErrUnknownNodeType)
@@ -201,6 +203,8 @@ func Eval(ctx expr.EvalContext, arg expr.Node) (value.Value, bool) {
 		return walkTri(ctx, argVal)
 	case *expr.ArrayNode:
 		return walkArray(ctx, argVal)
+	case *expr.CaseNode:
+		return walkCase(ctx, argVal)
 	case *expr.FuncNode:
 		return walkFunc(ctx, argVal)
 	case *expr.IdentityNode:
@@ -212,19 +216,22 @@ func Eval(ctx expr.EvalContext, arg expr.Node) (value.Value, bool) {
 	case *expr.NullNode:
 		// WHERE (`users.user_id` != NULL)
 		return value.NewNilValue(), true
+	case *expr.ParamNode:
+		// WHERE user_id = ?   the value bound at execution
+		if argVal.Value == nil {
+			return nil, false
+		}
+		return argVal.Value, true
 	case *expr.ValueNode:
 		if argVal.Value == nil {
 			return nil, false
 		}
-		switch val := argVal.Value.(type) {
+		switch argVal.Value.(type) {
 		case *value.NilValue, value.NilValue:
 			return nil, false
-		case value.SliceValue:
-			//u.Warnf("got slice? %#v", argVal)
-			return val, true
 		}
-		u.Errorf("Unknonwn node type:  %#v", argVal.Value)
-		panic(ErrUnknownNodeType)
+		// ie slices, or the values of an update set once for every row
+		return argVal.Value, true
 	default:
 		u.Errorf("Unknonwn node type:  %#v", arg)
 		panic(ErrUnknownNodeType)
@@ -243,47 +250,42 @@ func (e *State) Walk(arg expr.Node) (value.Value, bool) {
 //       x > y
 //       x < =
 //
+// NULL follows three-valued logic, see walkIsNull and walkLogical for
+// IS [NOT] NULL and AND/OR, any other operator (including = and !=) with
+// a NULL operand returns NULL.
+//
 func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool) {
+
+	switch node.Operator.T {
+	case lex.TokenLogicAnd, lex.TokenAnd, lex.TokenLogicOr, lex.TokenOr:
+		return walkLogical(ctx, node)
+	case lex.TokenIs, lex.TokenIsNot:
+		return walkIsNull(ctx, node)
+	}
+
 	ar, aok := Eval(ctx, node.Args[0])
 	br, bok := Eval(ctx, node.Args[1])
 
 	//u.Debugf("walkBinary: aok?%v ar:%v %T  node=%s", aok, ar, ar, node.Args[0])
 	//u.Debugf("walkBinary: bok?%v br:%v %T  node=%s", bok, br, br, node.Args[1])
 	//u.Debugf("walkBinary: l:%v  r:%v  %T  %T node=%s", ar, br, ar, br, node)
-	// If we could not evaluate either we can shortcut
-	if !aok && !bok {
-		switch node.Operator.T {
-		case lex.TokenLogicOr, lex.TokenOr:
-			return value.NewBoolValue(false), true
-		case lex.TokenEqualEqual, lex.TokenEqual:
-			// We don't alllow nil == nil here bc we have a NilValue type
-			// that we would use for that
-			return value.NewBoolValue(false), true
-		case lex.TokenNE:
-			return value.NewBoolValue(false), true
-		case lex.TokenGT, lex.TokenGE, lex.TokenLT, lex.TokenLE, lex.TokenLike:
-			return value.NewBoolValue(false), true
-		}
-		//u.Debugf("walkBinary not ok: op=%s %v  l:%v  r:%v  %T  %T", node.Operator, node, ar, br, ar, br)
-		return nil, false
+	if isNull(ar, aok) || isNull(br, bok) {
+		return value.NewNilValue(), true
 	}
 
-	// Else if we can only evaluate one, we can short circuit as well
-	if !aok || !bok {
-		switch node.Operator.T {
-		case lex.TokenAnd, lex.TokenLogicAnd:
-			return value.NewBoolValue(false), true
-		case lex.TokenEqualEqual, lex.TokenEqual:
-			return value.NewBoolValue(false), true
-		case lex.TokenNE:
-			// they are technically not equal?
-			return value.NewBoolValue(true), true
-		case lex.TokenGT, lex.TokenGE, lex.TokenLT, lex.TokenLE, lex.TokenLike:
-			return value.NewBoolValue(false), true
+	v, ok := operateValues(node, ar, br)
+	if ok && node.Operator.T == lex.TokenIN {
+		// x IN (a, NULL) is NULL, not false, when x is not found
+		if bv, isBool := v.(value.BoolValue); isBool && !bv.Val() && hasNull(br) {
+			return value.NewNilValue(), true
 		}
-		//u.Debugf("walkBinary not ok: op=%s %v  l:%v  r:%v  %T  %T", node.Operator, node, ar, br, ar, br)
-		// need to fall through to below
 	}
+	return v, ok
+}
+
+// operateValues evaluates the operator of a binary node against two
+// non-null values
+func operateValues(node *expr.BinaryNode, ar, br value.Value) (value.Value, bool) {
 
 	switch at := ar.(type) {
 	case value.IntValue:
@@ -331,8 +333,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 				u.Debugf("unsupported op for SliceValue op:%v rhT:%T", node.Operator, br)
 				return nil, false
 			}
-		case nil, value.NilValue:
-			return nil, false
 		default:
 			u.Errorf("unknown type:  %T %v", bt, bt)
 		}
@@ -365,8 +365,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 			}
 			return value.BoolValueFalse, true
 		//case value.StringValue:
-		case nil, value.NilValue:
-			return nil, false
 		default:
 			u.Errorf("unknown type:  %T %v", bt, bt)
 		}
@@ -386,22 +384,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 			default:
 				u.Warnf("bool binary?:  %#v  %v %v", node, at, bt)
 			}
-		case nil, value.NilValue:
-			switch node.Operator.T {
-			case lex.TokenLogicAnd:
-				return value.NewBoolValue(false), true
-			case lex.TokenLogicOr, lex.TokenOr:
-				return at, true
-			case lex.TokenEqualEqual, lex.TokenEqual:
-				return value.NewBoolValue(false), true
-			case lex.TokenNE:
-				return value.NewBoolValue(true), true
-			// case lex.TokenGE, lex.TokenGT, lex.TokenLE, lex.TokenLT:
-			// 	return value.NewBoolValue(false), true
-			default:
-				u.Warnf("right side nil binary:  %q", node)
-				return nil, false
-			}
 		default:
 			//u.Warnf("br: %#v", br)
 			//u.Errorf("at?%T  %v  coerce?%v bt? %T     %v", at, at.Value(), at.CanCoerce(stringRv), bt, bt.Value())
@@ -412,22 +394,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 		case value.StringValue:
 			// Nice, both strings
 			return operateStrings(node.Operator, at, bt), true
-		case nil, value.NilValue:
-			switch node.Operator.T {
-			case lex.TokenEqualEqual, lex.TokenEqual:
-				if at.Nil() {
-					return value.NewBoolValue(true), true
-				}
-				return value.NewBoolValue(false), true
-			case lex.TokenNE:
-				if at.Nil() {
-					return value.NewBoolValue(false), true
-				}
-				return value.NewBoolValue(true), true
-			default:
-				u.Debugf("unsupported op: %v", node.Operator)
-				return nil, false
-			}
 		case value.SliceValue:
 			switch node.Operator.T {
 			case lex.TokenIN:
@@ -508,8 +474,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 		switch node.Operator.T {
 		case lex.TokenContains:
 			switch bval := br.(type) {
-			case nil, value.NilValue:
-				return nil, false
 			case value.StringValue:
 				// [x,y,z] contains str
 				for _, val := range at.Val() {
@@ -541,8 +505,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 			}
 		case lex.TokenIntersects:
 			switch bt := br.(type) {
-			case nil, value.NilValue:
-				return nil, false
 			case value.SliceValue:
 				for _, aval := range at.Val() {
 					for _, bval := range bt.Val() {
@@ -594,8 +556,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 			}
 		case lex.TokenIntersects:
 			switch bt := br.(type) {
-			case nil, value.NilValue:
-				return nil, false
 			case value.SliceValue:
 				for _, astr := range at.Val() {
 					for _, bval := range bt.Val() {
@@ -685,35 +645,6 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 			u.Warnf("unhandled date op %v", node.Operator)
 		}
 		return nil, false
-	case nil, value.NilValue:
-		switch node.Operator.T {
-		case lex.TokenLogicAnd:
-			return value.NewBoolValue(false), true
-		case lex.TokenLogicOr, lex.TokenOr:
-			switch bt := br.(type) {
-			case value.BoolValue:
-				return bt, true
-			default:
-				return value.NewBoolValue(false), true
-			}
-		case lex.TokenEqualEqual, lex.TokenEqual:
-			// does nil==nil  = true ??
-			switch br.(type) {
-			case nil, value.NilValue:
-				return value.NewBoolValue(true), true
-			default:
-				return value.NewBoolValue(false), true
-			}
-		case lex.TokenNE:
-			return value.NewBoolValue(true), true
-		// case lex.TokenGE, lex.TokenGT, lex.TokenLE, lex.TokenLT:
-		// 	return value.NewBoolValue(false), true
-		case lex.TokenContains, lex.TokenLike, lex.TokenIN:
-			return value.NewBoolValue(false), true
-		default:
-			//u.Debugf("left side nil binary:  %q", node)
-			return nil, false
-		}
 	default:
 		u.Debugf("Unknown op?  %T  %T  %v", ar, at, ar)
 		return value.NewErrorValue(fmt.Sprintf("unsupported left side value: %T in %s", at, node)), false
@@ -722,6 +653,60 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool)
 	return value.NewErrorValue(fmt.Sprintf("unsupported binary expression: %s", node)), false
 }
 
+// walkLogical evaluates AND, OR with three-valued logic.  The right side
+// is only evaluated if the left side does not already decide the result.
+//
+//     false AND x = false       true OR x = true
+//     NULL AND false = false    NULL OR true = true
+//     NULL AND true = NULL      NULL OR false = NULL
+//
+func walkLogical(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool) {
+
+	or := node.Operator.T == lex.TokenLogicOr || node.Operator.T == lex.TokenOr
+
+	ar, aok := Eval(ctx, node.Args[0])
+	if ab, isBool := ar.(value.BoolValue); aok && isBool && ab.Val() == or {
+		return ab, true
+	}
+	br, bok := Eval(ctx, node.Args[1])
+	if bb, isBool := br.(value.BoolValue); bok && isBool && bb.Val() == or {
+		return bb, true
+	}
+	if isNull(ar, aok) || isNull(br, bok) {
+		return value.NewNilValue(), true
+	}
+	return operateValues(node, ar, br)
+}
+
+// walkIsNull evaluates  x IS NULL  and  x IS NOT NULL, which unlike
+// x = NULL are never NULL.
+func walkIsNull(ctx expr.EvalContext, node *expr.BinaryNode) (value.Value, bool) {
+	v, ok := Eval(ctx, node.Args[0])
+	null := isNull(v, ok)
+	if node.Operator.T == lex.TokenIsNot {
+		return value.NewBoolValue(!null), true
+	}
+	return value.NewBoolValue(null), true
+}
+
+// isNull is true for the SQL NULL, a value which is missing, could
+// not be evaluated, or is nil, see value.IsNull
+func isNull(v value.Value, ok bool) bool {
+	return !ok || value.IsNull(v)
+}
+
+// hasNull is true if a list value contains a NULL
+func hasNull(v value.Value) bool {
+	if sv, ok := v.(value.SliceValue); ok {
+		for _, val := range sv.Val() {
+			if isNull(val, true) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func walkIdentity(ctx expr.EvalContext, node *expr.IdentityNode) (value.Value, bool) {
 
 	if node.IsBooleanIdentity() {
@@ -737,15 +722,13 @@ func walkIdentity(ctx expr.EvalContext, node *expr.IdentityNode) (value.Value, b
 func walkUnary(ctx expr.EvalContext, node *expr.UnaryNode) (value.Value, bool) {
 
 	a, ok := Eval(ctx, node.Arg)
-	if !ok {
+	if isNull(a, ok) {
 		switch node.Operator.T {
 		case lex.TokenExists:
 			return value.NewBoolValue(false), true
-		case lex.TokenNegate:
-			return value.NewBoolValue(true), true
 		}
-		u.Debugf("unary could not evaluate for[ %s ] and %#v", node.String(), node)
-		return a, false
+		// NOT NULL, -NULL are NULL
+		return value.NewNilValue(), true
 	}
 
 	switch node.Operator.T {
@@ -754,8 +737,6 @@ func walkUnary(ctx expr.EvalContext, node *expr.UnaryNode) (value.Value, bool) {
 		case value.BoolValue:
 			//u.Debugf("found unary bool:  res=%v   expr=%v", !argVal.Val(), node)
 			return value.NewBoolValue(!argVal.Val()), true
-		case nil, value.NilValue:
-			return value.NewBoolValue(false), false
 		default:
 			u.LogThrottle(u.WARN, 5, "unary type not implemented. Unknonwn node type: %T:%v node=%s", argVal, argVal, node.String())
 			return value.NewNilValue(), false
@@ -765,10 +746,6 @@ func walkUnary(ctx expr.EvalContext, node *expr.UnaryNode) (value.Value, bool) {
 			return value.NewNumberValue(-an.Float()), true
 		}
 	case lex.TokenExists:
-		switch a.(type) {
-		case nil, value.NilValue:
-			return value.NewBoolValue(false), true
-		}
 		if a.Nil() {
 			return value.NewBoolValue(false), true
 		}
@@ -790,15 +767,9 @@ func walkTri(ctx expr.EvalContext, node *expr.TriNode) (value.Value, bool) {
 	b, bok := Eval(ctx, node.Args[1])
 	c, cok := Eval(ctx, node.Args[2])
 	//u.Infof("tri:  %T:%v  %v  %T:%v   %T:%v", a, a, node.Operator, b, b, c, c)
-	if !aok {
-		return value.BoolValueFalse, false
-	}
-	if !bok || !cok {
-		u.Debugf("Could not evaluate args, %#v", node.String())
-		return value.BoolValueFalse, false
-	}
-	if a == nil || b == nil || c == nil {
-		return value.BoolValueFalse, false
+	if isNull(a, aok) || isNull(b, bok) || isNull(c, cok) {
+		// NULL BETWEEN b AND c is NULL
+		return value.NewNilValue(), true
 	}
 	switch node.Operator.T {
 	case lex.TokenBetween:
@@ -823,7 +794,7 @@ func walkTri(ctx expr.EvalContext, node *expr.TriNode) (value.Value, bool) {
 				if bfv, ok := b.(value.NumberValue); ok {
 					if cfv, ok := c.(value.NumberValue); ok {
 						if afv.Float() > bfv.Float() && afv.Float() < cfv.Float() {
-							return value.NewBoolValue(true), false
+							return value.NewBoolValue(true), true
 						} else {
 							return value.NewBoolValue(false), true
 						}
@@ -850,7 +821,10 @@ func walkArray(ctx expr.EvalContext, node *expr.ArrayNode) (value.Value, bool) {
 	vals := make([]value.Value, len(node.Args))
 
 	for i := 0; i < len(node.Args); i++ {
-		v, _ := Eval(ctx, node.Args[i])
+		v, ok := Eval(ctx, node.Args[i])
+		if isNull(v, ok) {
+			v = value.NewNilValue()
+		}
 		vals[i] = v
 	}
 
@@ -858,9 +832,63 @@ func walkArray(ctx expr.EvalContext, node *expr.ArrayNode) (value.Value, bool) {
 	return value.NewSliceValues(vals), true
 }
 
+// CaseNode evaluator, the WHENs are evaluated in order until one matches
+//   and only its THEN (or the ELSE if none match) is evaluated.  A WHEN
+//   which can not be evaluated does not match, no match and no ELSE is NULL.
+//
+//     CASE WHEN a THEN b ELSE c END      a is true
+//     CASE x WHEN a THEN b ELSE c END    x = a
+//
+func walkCase(ctx expr.EvalContext, node *expr.CaseNode) (value.Value, bool) {
+
+	var arg value.Value
+	if node.Arg != nil {
+		av, ok := Eval(ctx, node.Arg)
+		if isNull(av, ok) {
+			// NULL is not equal to any WHEN
+			return walkCaseElse(ctx, node)
+		}
+		arg = av
+	}
+	for i, when := range node.Whens {
+		w, ok := Eval(ctx, when)
+		if isNull(w, ok) {
+			continue
+		}
+		if arg == nil {
+			if bv, isBool := w.(value.BoolValue); isBool && bv.Val() {
+				return Eval(ctx, node.Thens[i])
+			}
+			continue
+		}
+		if eq, err := value.Equal(arg, w); err == nil && eq {
+			return Eval(ctx, node.Thens[i])
+		}
+	}
+	return walkCaseElse(ctx, node)
+}
+
+func walkCaseElse(ctx expr.EvalContext, node *expr.CaseNode) (value.Value, bool) {
+	if node.Else == nil {
+		return value.NewNilValue(), true
+	}
+	return Eval(ctx, node.Else)
+}
+
 func walkFunc(ctx expr.EvalContext, node *expr.FuncNode) (value.Value, bool) {
 
 	//u.Debugf("walkFunc node: %v", node.String())
+	if !node.Missing {
+		// NULL handling funcs are evaluated here so that only the args needed
+		// for the result are evaluated, the builtins of the same name are
+		// not called
+		switch strings.ToLower(node.F.Name) {
+		case "coalesce", "ifnull":
+			return walkCoalesce(ctx, node)
+		case "nullif":
+			return walkNullIf(ctx, node)
+		}
+	}
 
 	// we create a set of arguments to pass to the function, first arg
 	// is this Context
@@ -917,7 +945,10 @@ func walkFunc(ctx expr.EvalContext, node *expr.FuncNode) (value.Value, bool) {
 		case *expr.ValueNode:
 			v = t.Value
 		default:
-			u.Errorf("expr: unknown func arg type %T %v", a, a)
+			v, ok = Eval(ctx, a)
+			if !ok {
+				v = value.NewNilValue()
+			}
 		}
 
 		if v == nil {
@@ -956,6 +987,35 @@ func walkFunc(ctx expr.EvalContext, node *expr.FuncNode) (value.Value, bool) {
 	return fnRet[0].Interface().(value.Value), true
 }
 
+// coalesce(a, b, ...), ifnull(a, b)  return the first arg which is not NULL
+func walkCoalesce(ctx expr.EvalContext, node *expr.FuncNode) (value.Value, bool) {
+	for _, arg := range node.Args {
+		if v, ok := Eval(ctx, arg); !isNull(v, ok) {
+			return v, true
+		}
+	}
+	return value.NewNilValue(), true
+}
+
+// nullif(a, b)  is NULL if a = b, else a.  b is not evaluated if a is NULL.
+func walkNullIf(ctx expr.EvalContext, node *expr.FuncNode) (value.Value, bool) {
+	if len(node.Args) != 2 {
+		return value.NewErrorValue(fmt.Sprintf("nullif requires 2 args: %s", node)), false
+	}
+	a, aok := Eval(ctx, node.Args[0])
+	if isNull(a, aok) {
+		return value.NewNilValue(), true
+	}
+	b, bok := Eval(ctx, node.Args[1])
+	if isNull(b, bok) {
+		return a, true
+	}
+	if eq, err := value.Equal(a, b); err == nil && eq {
+		return value.NewNilValue(), true
+	}
+	return a, true
+}
+
 func operateNumbers(op lex.Token, av, bv value.NumberValue) value.Value {
 	switch op.T {
 	case lex.TokenPlus, lex.TokenStar, lex.TokenMultiply, lex.TokenDivide, lex.TokenMinus,
@@ -48,6 +48,8 @@ var (
 		"urls":    value.NewStringsValue([]string{"abc", "123"}),
 		"hits":    value.NewMapIntValue(map[string]int64{"google.com": 5, "bing.com": 1}),
 		"email":   value.NewStringValue("bob@bob.com"),
+		"empty":   value.NewStringValue(""),
+		"nourls":  value.NewStringsValue(nil),
 	})
 	vmTestsx = []vmTest{
 		// Native LIKE keyword
@@ -62,7 +64,7 @@ var (
 		vmt(`todate("now+3d") > now()`, true, noError),
 		vmt(`created < 2032220220175`, true, noError), // Really not sure i want to support this?
 
-		vmt(`!exists(user_id) OR toint(not_a_field) > 21`, false, noError),
+		vmt(`!exists(user_id) OR toint(not_a_field) > 21`, nil, noError), // false OR NULL
 		vmt(`exists(user_id) OR toint(not_a_field) > 21`, true, noError),
 		vmt(`!exists(user_id) OR toint(str5) >= 1`, true, noError),
 		vmt(`!exists(user_id) OR toint(str5) < 1`, false, noError),
@@ -76,8 +78,9 @@ var (
 		vmt(`not(contains(key,"-")) AND not(contains(email,"@"))`, false, noError),
 		vmt(`not(contains(key,"-")) OR not(contains(email,"@"))`, true, noError),
 		vmt(`not(contains(key,"-")) OR not(contains(not_real,"@"))`, true, noError),
-		// one of these fields doesn't exist
-		vmt(`str5 NOT IN ("nope") AND userid NOT IN ("abc") AND email NOT IN ("jane@bob.com")`, true, noError),
+		// one of these fields doesn't exist, NULL NOT IN (...) is NULL
+		vmt(`str5 NOT IN ("nope") AND userid NOT IN ("abc") AND email NOT IN ("jane@bob.com")`, nil, noError),
+		vmt(`str5 NOT IN ("nope") AND user_id NOT IN ("abc") AND email NOT IN ("jane@bob.com")`, false, noError),
 
 		// Native LIKE keyword
 		vmt(`["portland"] LIKE "*land"`, true, noError),
@@ -119,7 +122,7 @@ var (
 		vmtall(`"a" NOT IN ("a","b" 4.5)`, false, parseOk, evalError),
 		vmt(`email NOT IN ("bob@bob.com")`, false, noError),
 		// true because negated
-		vmtall(`toint(not_a_field) NOT IN ("a","b" 4.5)`, true, parseOk, noError),
+		vmtall(`toint(not_a_field) NOT IN ("a","b" 4.5)`, nil, parseOk, noError), // NOT NULL
 
 		vmt(`"a" IN urls`, false, noError),
 		vmt(`"abc" IN urls`, true, noError),
@@ -228,7 +231,7 @@ func TestRunExpr(t *testing.T) {
 		if err != nil && test.evalok {
 			t.Errorf("\n%s \n\t%v\nexpected\n\t'%v'", test.qlText, results, test.result)
 		}
-		if test.result == nil && results != nil {
+		if test.result == nil && !isNull(results, results != nil) {
 			t.Errorf("%s - should have nil result, but got: %v", test.qlText, results)
 			continue
 		}
@@ -246,6 +249,185 @@ func TestRunExpr(t *testing.T) {
 	}
 }
 
+func TestCase(t *testing.T) {
+
+	// count evaluations of a THEN/ELSE, only the matching one is evaluated
+	evaluated := 0
+	expr.FuncAdd("casecount", func(ctx expr.EvalContext, v value.Value) (value.Value, bool) {
+		evaluated++
+		return v, true
+	})
+	tests := []struct {
+		qlText string
+		result interface{}
+		evals  int
+	}{
+		{`CASE WHEN int5 > 10 THEN "big" WHEN int5 > 1 THEN "medium" ELSE "small" END`, "medium", 0},
+		{`CASE WHEN int5 > 10 THEN "big" ELSE "small" END`, "small", 0},
+		{`CASE WHEN int5 > 10 THEN "big" END`, nil, 0},
+		{`CASE WHEN not_a_field > 1 THEN "a" WHEN bvalt THEN "b" END`, "b", 0},
+		{`CASE int5 WHEN 4 THEN "four" WHEN 5 THEN "five" END`, "five", 0},
+		{`CASE str5 WHEN 5 THEN "five" ELSE "other" END`, "five", 0},
+		{`CASE not_a_field WHEN 5 THEN "five" ELSE "other" END`, "other", 0},
+		{`CASE user_id WHEN "abc" THEN int5 * 2 END`, int64(10), 0},
+		{`CASE WHEN int5 == 5 THEN casecount(1) WHEN true THEN casecount(2) ELSE casecount(3) END`, int64(1), 1},
+		{`CASE int5 WHEN 6 THEN casecount(1) ELSE casecount(3) END`, int64(3), 1},
+		{`toint(CASE WHEN bvalf THEN "1" ELSE "2" END) + 1`, int64(3), 0},
+		{`CASE WHEN bvalt THEN CASE int5 WHEN 5 THEN "nested" END END`, "nested", 0},
+	}
+	for _, test := range tests {
+		evaluated = 0
+		exprVm, err := NewVm(test.qlText)
+		if err != nil {
+			t.Errorf("%s: could not parse %v", test.qlText, err)
+			continue
+		}
+		v, ok := Eval(msgContext, exprVm.Tree.Root)
+		if !ok {
+			t.Errorf("%s: could not evaluate", test.qlText)
+			continue
+		}
+		if test.result == nil {
+			if !v.Nil() {
+				t.Errorf("%s: expected nil got %v", test.qlText, v)
+			}
+		} else if v.Value() != test.result {
+			t.Errorf("%s: expected %v %T got %v %T", test.qlText, test.result, test.result, v.Value(), v.Value())
+		}
+		if evaluated != test.evals {
+			t.Errorf("%s: expected %d evaluations got %d", test.qlText, test.evals, evaluated)
+		}
+	}
+}
+
+// Three-valued logic conformance, a nil result is NULL
+func TestNullLogic(t *testing.T) {
+
+	// count evaluations of args, to ensure AND, OR, coalesce etc short-circuit
+	evaluated := 0
+	expr.FuncAdd("nullcount", func(ctx expr.EvalContext, v value.Value) (value.Value, bool) {
+		evaluated++
+		return v, true
+	})
+	tests := []struct {
+		qlText string
+		result interface{}
+		evals  int
+	}{
+		// IS NULL, IS NOT NULL are never NULL
+		{`not_a_field IS NULL`, true, 0},
+		{`not_a_field IS NOT NULL`, false, 0},
+		{`int5 IS NULL`, false, 0},
+		{`int5 IS NOT NULL`, true, 0},
+		{`bvalf IS NOT NULL`, true, 0},
+		{`NULL IS NULL`, true, 0},
+
+		// comparison to a literal NULL is NULL
+		{`not_a_field = NULL`, nil, 0},
+		{`not_a_field == NULL`, nil, 0},
+		{`not_a_field != NULL`, nil, 0},
+		{`int5 = NULL`, nil, 0},
+		{`int5 != NULL`, nil, 0},
+		{`NULL = NULL`, nil, 0},
+		{`int5 > NULL`, nil, 0},
+		{`int5 + NULL`, nil, 0},
+
+		// any other operator with a NULL operand is NULL
+		{`not_a_field = 5`, nil, 0},
+		{`not_a_field != 5`, nil, 0},
+		{`not_a_field > 5`, nil, 0},
+		{`not_a_field + 1`, nil, 0},
+		{`int5 = not_a_field`, nil, 0},
+		{`not_a_field LIKE "a*"`, nil, 0},
+		{`not_a_field CONTAINS "a"`, nil, 0},
+		{`not_a_field IN ("a","b")`, nil, 0},
+		{`not_a_field NOT IN ("a","b")`, nil, 0},
+		{`int5 IN (5, not_a_field)`, true, 0},
+		{`int5 IN (4, not_a_field)`, nil, 0},
+		{`int5 NOT IN (4, not_a_field)`, nil, 0},
+		{`not_a_field BETWEEN 1 AND 10`, nil, 0},
+		{`int5 BETWEEN 1 AND not_a_field`, nil, 0},
+		{`int5 BETWEEN 1 AND 10`, true, 0},
+
+		// NOT
+		{`NOT (not_a_field > 5)`, nil, 0},
+		{`NOT (int5 > 5)`, true, 0},
+		{`EXISTS not_a_field`, false, 0},
+		{`NOT EXISTS not_a_field`, true, 0},
+
+		// AND, OR
+		{`not_a_field > 5 AND false`, false, 0},
+		{`not_a_field > 5 AND true`, nil, 0},
+		{`not_a_field > 5 OR true`, true, 0},
+		{`not_a_field > 5 OR false`, nil, 0},
+		{`not_a_field > 5 OR not_a_field < 5`, nil, 0},
+		{`NOT (not_a_field > 5) OR bvalt`, true, 0},
+		{`bvalf AND nullcount(bvalt)`, false, 0},
+		{`bvalt OR nullcount(bvalf)`, true, 0},
+		{`bvalt AND nullcount(bvalt)`, true, 1},
+
+		// coalesce, ifnull, nullif
+		{`coalesce(not_a_field, int5)`, int64(5), 0},
+		{`coalesce(not_a_field, NULL, "x")`, "x", 0},
+		{`coalesce(not_a_field > 1, bvalf)`, false, 0},
+		{`coalesce(not_a_field)`, nil, 0},
+		{`coalesce(not_a_field, nullcount(1), nullcount(2))`, int64(1), 1},
+		{`coalesce(not_a_field, 1) + 1`, int64(2), 0},
+		{`ifnull(not_a_field, "x")`, "x", 0},
+		{`ifnull(user_id, nullcount("x"))`, "abc", 0},
+		{`nullif(int5, 5)`, nil, 0},
+		{`nullif(int5, 4)`, int64(5), 0},
+		{`nullif(str5, not_a_field)`, "5", 0},
+		{`nullif(not_a_field, nullcount(1))`, nil, 0},
+		{`coalesce(nullif(user_id, "abc"), "default")`, "default", 0},
+
+		// a NULL WHEN does not match
+		{`CASE WHEN not_a_field > 5 THEN "a" ELSE "b" END`, "b", 0},
+
+		// empty values are NULL, the same as missing ones
+		{`empty IS NULL`, true, 0},
+		{`empty IS NOT NULL`, false, 0},
+		{`nourls IS NULL`, true, 0},
+		{`empty = ""`, nil, 0},
+		{`empty != "x"`, nil, 0},
+		{`empty > "a"`, nil, 0},
+		{`NOT (empty = "x")`, nil, 0},
+		{`empty IN ("", "x")`, nil, 0},
+		{`empty LIKE "*"`, nil, 0},
+		{`coalesce(empty, "d")`, "d", 0},
+		{`coalesce(not_a_field, empty, "d")`, "d", 0},
+		{`ifnull(empty, "d")`, "d", 0},
+		{`nullif(empty, "x")`, nil, 0},
+		{`nullif(str5, empty)`, "5", 0},
+		{`CASE empty WHEN "" THEN "a" ELSE "b" END`, "b", 0},
+		{`CASE str5 WHEN empty THEN "a" ELSE "b" END`, "b", 0},
+		{`CASE WHEN empty IS NULL AND coalesce(empty, "d") = "d" THEN "a" ELSE "b" END`, "a", 0},
+	}
+	for _, test := range tests {
+		evaluated = 0
+		exprVm, err := NewVm(test.qlText)
+		if err != nil {
+			t.Errorf("%s: could not parse %v", test.qlText, err)
+			continue
+		}
+		v, ok := Eval(msgContext, exprVm.Tree.Root)
+		if !ok {
+			t.Errorf("%s: could not evaluate", test.qlText)
+			continue
+		}
+		if test.result == nil {
+			if !isNull(v, ok) {
+				t.Errorf("%s: expected NULL got %v", test.qlText, v)
+			}
+		} else if v.Value() != test.result {
+			t.Errorf("%s: expected %v %T got %v %T", test.qlText, test.result, test.result, v.Value(), v.Value())
+		}
+		if evaluated != test.evals {
+			t.Errorf("%s: expected %d evaluations got %d", test.qlText, test.evals, evaluated)
+		}
+	}
+}
+
 type vmTest struct {
 	qlText  string
 	parseok bool